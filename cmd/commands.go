@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+	"okx-market-sentry/internal/notifier"
+	"okx-market-sentry/pkg/config"
+	"okx-market-sentry/pkg/logger"
+	"okx-market-sentry/pkg/types"
+)
+
+// configCheck 校验配置，通过时输出生效的配置（密钥已打码），返回进程退出码
+func configCheck(opts config.Options) int {
+	cfg, err := config.Load(opts)
+	if err == nil {
+		err = notifier.ValidateTemplates(cfg.Alert.Templates)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "❌", err)
+		return 1
+	}
+
+	file := viper.ConfigFileUsed()
+	if file == "" {
+		file = "（未找到配置文件，使用默认值）"
+	}
+	fmt.Println("✅ 配置校验通过:", file)
+
+	settings := viper.AllSettings()
+	maskSecrets(settings)
+	out, err := yaml.Marshal(settings)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "❌ 输出配置失败:", err)
+		return 1
+	}
+	fmt.Print(string(out))
+	return 0
+}
+
+// logConfigSummary 启动时记录生效的配置（密钥已打码），便于排查问题时确认运行参数
+func logConfigSummary() {
+	settings := viper.AllSettings()
+	maskSecrets(settings)
+	file := viper.ConfigFileUsed()
+	if file == "" {
+		file = "(defaults)"
+	}
+	zap.L().Info("📋 生效配置", zap.String("config_file", file), zap.Any("config", settings))
+}
+
+// healthcheck 请求本机HTTP API的 /healthz，服务正常时返回0
+// 未启用HTTP API时无法探测，视为正常，避免默认配置下容器被判定为不健康
+func healthcheck(cfg *types.Config) int {
+	if !cfg.Server.Enabled {
+		fmt.Println("HTTP API未启用（server.enabled=false），跳过健康检查")
+		return 0
+	}
+
+	client := &http.Client{Timeout: 3 * time.Second}
+	resp, err := client.Get(fmt.Sprintf("http://127.0.0.1:%d/healthz", cfg.Server.Port))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "❌ 健康检查失败:", err)
+		return 1
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintln(os.Stderr, "❌ 健康检查失败: HTTP", resp.StatusCode)
+		return 1
+	}
+	fmt.Println("✅ ok")
+	return 0
+}
+
+// apiRequest 以配置的auth_token请求本机HTTP API，返回状态码和响应体
+func apiRequest(cfg *types.Config, method, path string, body interface{}) (int, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, fmt.Sprintf("http://127.0.0.1:%d%s", cfg.Server.Port, path), reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+cfg.Server.AuthToken)
+
+	client := &http.Client{Timeout: time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, data, err
+}
+
+// requireControlAPI 控制类命令需要运行中的服务开启HTTP API并配置auth_token
+func requireControlAPI(cfg *types.Config) bool {
+	if !cfg.Server.Enabled || cfg.Server.AuthToken == "" {
+		fmt.Fprintln(os.Stderr, "❌ 需要开启HTTP API（server.enabled）并配置 server.auth_token")
+		return false
+	}
+	return true
+}
+
+// triggerAnalysis 请求运行中的服务立即执行一次分析，symbols为逗号分隔的交易对，为空时分析全部
+func triggerAnalysis(cfg *types.Config, symbols string) int {
+	if !requireControlAPI(cfg) {
+		return 1
+	}
+
+	var req struct {
+		Symbols []string `json:"symbols,omitempty"`
+	}
+	for _, symbol := range strings.Split(symbols, ",") {
+		if symbol = strings.TrimSpace(symbol); symbol != "" {
+			req.Symbols = append(req.Symbols, symbol)
+		}
+	}
+
+	status, body, err := apiRequest(cfg, http.MethodPost, "/analyze", req)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "❌ 触发分析失败:", err)
+		return 1
+	}
+	if status != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "❌ 触发分析失败: HTTP %d %s\n", status, strings.TrimSpace(string(body)))
+		return 1
+	}
+
+	var resp struct {
+		Duration string             `json:"duration"`
+		Alerts   []*types.AlertData `json:"alerts"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		fmt.Fprintln(os.Stderr, "❌ 解析响应失败:", err)
+		return 1
+	}
+	fmt.Printf("✅ 分析完成，耗时 %s，触发 %d 条预警\n", resp.Duration, len(resp.Alerts))
+	for _, alert := range resp.Alerts {
+		fmt.Printf("  %-16s %+.2f%%  %s\n", alert.Symbol, alert.ChangePercent, alert.Profile)
+	}
+	return 0
+}
+
+// pauseAlerts 请求运行中的服务暂停或恢复预警，profile为空时作用于全部配置组，duration为空时直到手动恢复
+func pauseAlerts(cfg *types.Config, resume bool, profile, duration string) int {
+	if !requireControlAPI(cfg) {
+		return 1
+	}
+
+	path, action := "/pause", "暂停"
+	if resume {
+		path, action = "/resume", "恢复"
+	}
+	req := map[string]string{"profile": profile, "duration": duration}
+	status, body, err := apiRequest(cfg, http.MethodPost, path, req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %s预警失败: %v\n", action, err)
+		return 1
+	}
+	if status != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "❌ %s预警失败: HTTP %d %s\n", action, status, strings.TrimSpace(string(body)))
+		return 1
+	}
+
+	fmt.Printf("✅ 已%s预警，当前暂停状态: %s\n", action, strings.TrimSpace(string(body)))
+	return 0
+}
+
+// secretKeyParts 配置项名称包含这些片段时视为密钥，输出时打码
+var secretKeyParts = []string{"secret", "token", "password", "webhook_url", "device_key"}
+
+// maskSecrets 递归将密钥类配置项的值替换为掩码，*_file 为文件路径不做处理
+func maskSecrets(settings map[string]interface{}) {
+	for key, value := range settings {
+		if nested, ok := value.(map[string]interface{}); ok {
+			maskSecrets(nested)
+			continue
+		}
+		if strings.HasSuffix(key, "_file") {
+			continue
+		}
+		for _, part := range secretKeyParts {
+			if strings.Contains(key, part) {
+				if s, ok := value.(string); !ok || s != "" {
+					settings[key] = "******"
+				}
+				break
+			}
+		}
+	}
+}
+
+// notifyTest 通过当前配置的通知渠道发送测试消息，返回进程退出码
+func notifyTest(cfg *types.Config) int {
+	logger.InitLogger(cfg.Log)
+	notifyService := notifier.FromConfig(cfg)
+
+	now := time.Now()
+	alert := &types.AlertData{
+		Symbol:        "BTC-USDT",
+		CurrentPrice:  103000,
+		PastPrice:     100000,
+		ChangePercent: 3,
+		AlertTime:     now,
+		MonitorPeriod: cfg.Alert.MonitorPeriod,
+		PriceTime:     now,
+	}
+	if err := notifyService.SendAlert(alert); err != nil {
+		fmt.Fprintln(os.Stderr, "❌ 发送测试预警失败:", err)
+		return 1
+	}
+
+	opsAlert := &types.OpsAlert{
+		Component: "notify-test",
+		Message:   "这是一条测试运维告警，收到说明通知渠道配置正确",
+		AlertTime: now,
+	}
+	if err := notifyService.SendOpsAlert(opsAlert); err != nil {
+		fmt.Fprintln(os.Stderr, "❌ 发送测试运维告警失败:", err)
+		return 1
+	}
+
+	// 远程渠道失败时会降级为控制台输出并返回nil，通过投递统计判断是否真正送达
+	if reporter, ok := notifyService.(notifier.HealthReporter); ok && reporter.FailureStreak() > 0 {
+		fmt.Fprintln(os.Stderr, "❌ 通知渠道投递失败，已降级为控制台输出，请检查日志")
+		return 1
+	}
+	fmt.Println("✅ 测试消息已发送")
+	return 0
+}
@@ -1,86 +1,154 @@
 package main
 
 import (
-	"context"
+	"flag"
+	"fmt"
 	"log"
 	"os"
-	"os/signal"
-	"sync"
-	"syscall"
+	"runtime"
+	"runtime/debug"
 	"time"
+	_ "time/tzdata" // 内置时区数据库，精简镜像中没有 /usr/share/zoneinfo 时 timezone 配置仍可用
 
-	"go.uber.org/zap"
-	"okx-market-sentry/internal/analyzer"
-	"okx-market-sentry/internal/fetcher"
 	"okx-market-sentry/internal/notifier"
-	"okx-market-sentry/internal/scheduler"
-	"okx-market-sentry/internal/storage"
 	"okx-market-sentry/pkg/config"
-	"okx-market-sentry/pkg/logger"
+	"okx-market-sentry/pkg/types"
 )
 
-func main() {
-	// 加载配置
-	cfg, err := config.Load()
-	if err != nil {
-		log.Fatal("加载配置失败:", err)
-	}
-
-	// 初始化zap日志系统
-	logger.InitLogger(cfg.Log)
-	zap.L().Info("OKX Market Sentry 启动中...")
+// 构建信息，通过 -ldflags "-X main.version=... -X main.commit=... -X main.buildDate=..." 注入
+var (
+	version   = "dev"
+	commit    = ""
+	buildDate = ""
+)
 
-	// 创建上下文
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+const usage = `OKX Market Sentry - OKX 市场价格监控预警
 
-	// 初始化各模块
-	stateManager := storage.NewStateManager(cfg.Redis, cfg.Alert.MonitorPeriod)
-	dataFetcher := fetcher.NewDataFetcher(stateManager, cfg.Network)
+用法:
+  okx-sentry [command] [flags]
 
-	// 根据配置选择通知服务（优先级：钉钉 > PushPlus > 控制台）
-	var notifyService notifier.Interface
-	if cfg.DingTalk.WebhookURL != "" {
-		notifyService = notifier.NewDingTalkNotifier(cfg.DingTalk.WebhookURL, cfg.DingTalk.Secret)
-	} else if cfg.PushPlus.UserToken != "" {
-		notifyService = notifier.NewPushPlusNotifier(cfg.PushPlus.UserToken, cfg.PushPlus.To)
-	} else {
-		notifyService = notifier.NewConsoleNotifier()
-	}
+命令:
+  run              启动监控服务（默认）
+  config-check     校验配置文件并输出生效的配置（密钥已打码）
+  notify-test      通过当前配置的通知渠道发送一条测试预警和运维告警
+  healthcheck      请求本机HTTP API的 /healthz，用于Docker HEALTHCHECK等进程监管
+  analyze          请求运行中的服务立即执行一次分析（需开启HTTP API并配置auth_token）
+  pause            暂停预警，行情照常获取（需开启HTTP API并配置auth_token）
+  resume           恢复预警
+  simulate-alerts  用候选的阈值和监控周期回放历史行情CSV，按天统计本应触发的预警数量，便于上线前调整阈值
+  version          显示版本信息
 
-	analysisEngine := analyzer.NewAnalysisEngine(stateManager, notifyService, cfg.Alert.Threshold, cfg.Alert.MonitorPeriod)
-	taskScheduler := scheduler.NewScheduler(dataFetcher, analysisEngine, stateManager, cfg.Alert.MonitorPeriod)
+参数:
+  --config string      配置文件路径，默认依次查找 configs/config.local.yaml、configs/config.yaml
+  --log-level string   覆盖配置文件中的日志级别 (debug/info/warn/error)
+  --dry-run            演练模式：通知只记录将要发送的内容，不实际推送
+  --no-emoji           控制台纯文本输出，去掉emoji并使用ASCII边框，适合重定向到文件
+  --symbol string      analyze 只分析指定交易对，多个用逗号分隔，如 BTC-USDT,ETH-USDT
+  --profile string     pause/resume 指定预警配置组，为空时作用于全部
+  --duration string    pause 暂停时长，如 2h，到期自动恢复，为空时直到手动恢复
+  --input string       simulate-alerts 历史行情CSV文件，每行为 时间,交易对,价格
+  --threshold float    simulate-alerts 候选阈值（百分比），覆盖所有预警配置组
+  --period string      simulate-alerts 候选监控周期，如 15m，覆盖所有预警配置组
+`
 
-	// 启动服务
-	var wg sync.WaitGroup
+func main() {
+	command := "run"
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		command, args = args[0], args[1:]
+	}
 
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		taskScheduler.Start(ctx)
-	}()
+	var opts config.Options
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	fs.StringVar(&opts.ConfigFile, "config", "", "配置文件路径")
+	fs.StringVar(&opts.LogLevel, "log-level", "", "覆盖配置文件中的日志级别")
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "演练模式，通知不实际推送")
+	fs.BoolVar(&opts.NoEmoji, "no-emoji", false, "控制台纯文本输出")
+	symbols := fs.String("symbol", "", "analyze 只分析指定交易对")
+	profile := fs.String("profile", "", "pause/resume 指定预警配置组")
+	duration := fs.String("duration", "", "pause 暂停时长")
+	input := fs.String("input", "", "simulate-alerts 历史行情CSV文件")
+	threshold := fs.Float64("threshold", 0, "simulate-alerts 候选阈值")
+	period := fs.String("period", "", "simulate-alerts 候选监控周期")
+	_ = fs.Parse(args)
 
-	// 等待中断信号
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	switch command {
+	case "run":
+		runService(mustLoadConfig(opts))
+	case "config-check":
+		os.Exit(configCheck(opts))
+	case "notify-test":
+		os.Exit(notifyTest(mustLoadConfig(opts)))
+	case "healthcheck":
+		os.Exit(healthcheck(mustLoadConfig(opts)))
+	case "analyze":
+		os.Exit(triggerAnalysis(mustLoadConfig(opts), *symbols))
+	case "pause", "resume":
+		os.Exit(pauseAlerts(mustLoadConfig(opts), command == "resume", *profile, *duration))
+	case "simulate-alerts":
+		os.Exit(simulateAlerts(mustLoadConfig(opts), *input, *threshold, *period))
+	case "version":
+		fmt.Println(versionString())
+	case "help":
+		fs.Usage()
+	default:
+		fmt.Fprintf(os.Stderr, "未知命令: %s\n\n", command)
+		fs.Usage()
+		os.Exit(2)
+	}
+}
 
-	zap.L().Info("OKX Market Sentry 已启动")
-	<-sigCh
+// buildInfo 返回版本、提交和构建时间，未通过ldflags注入时从Go模块构建信息中读取
+func buildInfo() (string, string, string) {
+	rev, date := commit, buildDate
+	if info, ok := debug.ReadBuildInfo(); ok && (rev == "" || date == "") {
+		for _, setting := range info.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && rev == "":
+				rev = setting.Value
+			case setting.Key == "vcs.time" && date == "":
+				date = setting.Value
+			}
+		}
+	}
+	if rev == "" {
+		rev = "unknown"
+	}
+	if date == "" {
+		date = "unknown"
+	}
+	return version, rev, date
+}
 
-	zap.L().Info("收到停止信号，正在优雅关闭...")
-	cancel()
+func versionString() string {
+	v, rev, date := buildInfo()
+	return fmt.Sprintf("okx-sentry %s (commit %s, built %s, %s %s/%s)",
+		v, rev, date, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}
 
-	// 等待所有goroutine结束，最多等待30秒
-	done := make(chan struct{})
-	go func() {
-		wg.Wait()
-		close(done)
-	}()
+func mustLoadConfig(opts config.Options) *types.Config {
+	cfg, err := config.Load(opts)
+	if err == nil {
+		// 正文模板的函数由通知模块提供，在此校验语法
+		err = notifier.ValidateTemplates(cfg.Alert.Templates)
+	}
+	if err != nil {
+		log.Fatal("加载配置失败:", err)
+	}
+	applyTimezone(cfg.Timezone)
+	return cfg
+}
 
-	select {
-	case <-done:
-		zap.L().Info("OKX Market Sentry 已安全关闭")
-	case <-time.After(30 * time.Second):
-		zap.L().Warn("强制关闭超时")
+// applyTimezone 将配置的时区设为进程本地时区，预警时间、日志、K线对齐和SLO日报均按该时区计算
+// 需在启动任何后台任务之前调用，运行中修改需重启生效
+func applyTimezone(name string) {
+	if name == "" {
+		return
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		log.Fatal("加载时区失败:", err)
 	}
+	time.Local = loc
 }
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"runtime"
+	"sync"
+	"syscall"
+
+	"go.uber.org/zap"
+	"okx-market-sentry/internal/analyzer"
+	"okx-market-sentry/internal/announcement"
+	"okx-market-sentry/internal/api"
+	"okx-market-sentry/internal/calendar"
+	"okx-market-sentry/internal/fetcher"
+	"okx-market-sentry/internal/instruments"
+	"okx-market-sentry/internal/monitor"
+	"okx-market-sentry/internal/notifier"
+	"okx-market-sentry/internal/scheduler"
+	"okx-market-sentry/internal/shard"
+	"okx-market-sentry/internal/slo"
+	"okx-market-sentry/internal/storage"
+	"okx-market-sentry/internal/subscription"
+	"okx-market-sentry/pkg/config"
+	"okx-market-sentry/pkg/logger"
+	"okx-market-sentry/pkg/systemd"
+	"okx-market-sentry/pkg/tracing"
+	"okx-market-sentry/pkg/types"
+)
+
+// runService 启动监控服务，阻塞直到收到停止信号
+func runService(cfg *types.Config) {
+	// 初始化zap日志系统，控制台输出JSON预警时日志改写到标准错误，便于用jq等工具处理标准输出
+	if cfg.Console.Mode == notifier.ConsoleModeJSON {
+		cfg.Log.ConsoleOutput = "stderr"
+	}
+	logger.InitLogger(cfg.Log)
+	// 退出前刷新日志，Loki/OTLP输出目标会批量缓存尚未推送的日志
+	defer func() { _ = zap.L().Sync() }()
+	v, rev, date := buildInfo()
+	zap.L().Info("OKX Market Sentry 启动中...",
+		zap.String("version", v),
+		zap.String("commit", rev),
+		zap.String("build_date", date),
+		zap.String("go_version", runtime.Version()))
+	logConfigSummary()
+	if cfg.DryRun {
+		zap.L().Warn("📝 演练模式已开启，预警和运维告警只记录日志，不会实际推送")
+	}
+
+	// 初始化链路追踪（可选）
+	shutdownTracing, err := tracing.Init(cfg.Tracing)
+	if err != nil {
+		zap.L().Fatal("初始化链路追踪失败", zap.Error(err))
+	}
+
+	// 创建上下文
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 初始化各模块
+	// 分析频率跟随最短的监控周期，内存中保留最长的监控周期
+	// 启用用户订阅时内存中至少保留订阅可设置的最长周期，启用热力图摘要时至少保留摘要的统计周期
+	shortestPeriod, longestPeriod := config.MonitorPeriodRange(cfg.Alert)
+	retention := cfg.Alert.CorrelationLookback
+	if cfg.Subscription.Enabled {
+		retention = max(retention, cfg.Subscription.MaxPeriod)
+	}
+	if cfg.Digest.Enabled {
+		retention = max(retention, cfg.Digest.Window)
+	}
+	stateManager := storage.NewStateManager(cfg.Redis, longestPeriod, retention)
+	sloTracker := slo.NewTracker()
+	dataFetcher := fetcher.NewDataFetcher(stateManager, cfg.Network, cfg.Fetch, sloTracker)
+
+	// 根据配置选择通知服务（优先级：钉钉 > PushPlus > 飞书 > 邮件 > Bark > Webhook > 控制台），支持热加载替换
+	// 预警配置组可通过channel指定其他已配置的渠道
+	channels := notifier.ChannelsFromConfig(cfg)
+	notifier.SetBatchLimit(cfg.Alert.Batch)
+	notifyService := notifier.NewDynamicNotifier(notifier.Preferred(channels))
+	notifyService.SetChannels(channels)
+
+	analysisEngine := analyzer.NewAnalysisEngine(stateManager, notifyService, cfg.Alert, sloTracker)
+	instrumentRegistry := instruments.NewRegistry(cfg.SymbolNames, fetcher.NewHTTPClient(cfg.Network))
+	analysisEngine.SetInstrumentInfo(instrumentRegistry)
+
+	// 多实例分片（可选），各实例只获取和分析自己负责的交易对，相关性基准由每个实例存储
+	var shardRing *shard.Ring
+	switch cfg.Shard.Mode {
+	case shard.ModeStatic:
+		index := cfg.Shard.Index
+		if index < 0 {
+			if index, err = shard.IndexFromHostname(); err != nil {
+				zap.L().Fatal("获取分片序号失败", zap.Error(err))
+			}
+			if index >= cfg.Shard.Count {
+				zap.L().Fatal("主机名中的分片序号超出实例总数", zap.Int("index", index), zap.Int("count", cfg.Shard.Count))
+			}
+		}
+		static := shard.NewStatic(index, cfg.Shard.Count)
+		dataFetcher.SetShard(static, cfg.Alert.Benchmark)
+		analysisEngine.SetShard(static)
+	case shard.ModeRedis:
+		client := stateManager.Redis()
+		if client == nil {
+			zap.L().Fatal("Redis分片需要可用的Redis连接")
+		}
+		shardRing = shard.NewRing(client, cfg.Shard)
+		dataFetcher.SetShard(shardRing, cfg.Alert.Benchmark)
+		analysisEngine.SetShard(shardRing)
+	}
+	taskScheduler := scheduler.NewScheduler(dataFetcher, analysisEngine, stateManager, shortestPeriod, cfg.Schedule)
+
+	// 配置热加载：日志级别、预警阈值、通知渠道、交易对名称即时生效，其余配置需重启
+	configWatcher := config.NewWatcher(cfg)
+	configWatcher.Subscribe(func(oldConfig, newConfig *types.Config) {
+		if newConfig.Log.Level != oldConfig.Log.Level {
+			if err := logger.SetLevel(newConfig.Log.Level); err == nil {
+				zap.L().Info("🔧 日志级别已更新", zap.String("level", newConfig.Log.Level))
+			}
+		}
+		logger.SetModuleLevels(newConfig.Log.Modules)
+		if err := notifier.ValidateTemplates(newConfig.Alert.Templates); err != nil {
+			zap.L().Warn("⚠️ 预警正文模板有误，引用这些模板的预警将使用默认格式", zap.Error(err))
+		}
+		analysisEngine.UpdateAlertConfig(newConfig.Alert)
+		notifier.SetBatchLimit(newConfig.Alert.Batch)
+		if !reflect.DeepEqual(newConfig.SymbolNames, oldConfig.SymbolNames) {
+			instrumentRegistry.SetNames(newConfig.SymbolNames)
+			zap.L().Info("🔧 交易对名称已更新", zap.Int("count", len(newConfig.SymbolNames)))
+		}
+		if !reflect.DeepEqual(newConfig.DingTalk, oldConfig.DingTalk) || !reflect.DeepEqual(newConfig.PushPlus, oldConfig.PushPlus) ||
+			newConfig.Feishu != oldConfig.Feishu || !reflect.DeepEqual(newConfig.Email, oldConfig.Email) ||
+			newConfig.Bark != oldConfig.Bark || !reflect.DeepEqual(newConfig.Webhook, oldConfig.Webhook) || newConfig.Console != oldConfig.Console || newConfig.DryRun != oldConfig.DryRun {
+			channels := notifier.ChannelsFromConfig(newConfig)
+			notifyService.Set(notifier.Preferred(channels))
+			notifyService.SetChannels(channels)
+			zap.L().Info("🔧 通知渠道已更新")
+		}
+		// 最短周期决定分析频率、最长周期决定数据保留时长，二者变化需重启
+		oldShortest, oldLongest := config.MonitorPeriodRange(oldConfig.Alert)
+		newShortest, newLongest := config.MonitorPeriodRange(newConfig.Alert)
+		if oldShortest != newShortest || oldLongest != newLongest || newConfig.Redis != oldConfig.Redis ||
+			newConfig.Server != oldConfig.Server || newConfig.Network != oldConfig.Network || newConfig.Fetch != oldConfig.Fetch ||
+			newConfig.Tracing != oldConfig.Tracing || newConfig.OpsAlert != oldConfig.OpsAlert || newConfig.Shutdown != oldConfig.Shutdown ||
+			newConfig.Timezone != oldConfig.Timezone || newConfig.Schedule != oldConfig.Schedule ||
+			!reflect.DeepEqual(newConfig.Announcement, oldConfig.Announcement) || newConfig.Calendar != oldConfig.Calendar ||
+			newConfig.Subscription != oldConfig.Subscription || newConfig.Shard != oldConfig.Shard || newConfig.Digest != oldConfig.Digest {
+			zap.L().Warn("⚠️ 监控周期、获取间隔、Redis、网络、HTTP API、gRPC、链路追踪、系统自检、优雅关闭、时区、定时任务、OKX公告、经济日历、用户订阅、分片、热力图摘要配置的变更需重启后生效")
+		}
+	})
+
+	// 启动服务
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		configWatcher.Start(ctx)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		taskScheduler.Start(ctx)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sloTracker.Start(ctx, cfg.Schedule.SLOReport)
+	}()
+
+	// Redis分片定期续期本实例的登记并刷新成员
+	if shardRing != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			shardRing.Start(ctx)
+		}()
+	}
+
+	// 定期获取交易对的价格精度，通知按tickSz显示价格
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		instrumentRegistry.Start(ctx)
+	}()
+
+	// 启动系统自检（可选）
+	if cfg.OpsAlert.Enabled {
+		opsMonitor := monitor.NewOpsMonitor(cfg.OpsAlert, dataFetcher, taskScheduler, stateManager, notifyService)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			opsMonitor.Start(ctx)
+		}()
+	}
+
+	// 启动经济日历（可选）
+	if cfg.Calendar.Enabled {
+		economicCalendar := calendar.New(cfg.Calendar, fetcher.NewHTTPClient(cfg.Network))
+		analysisEngine.SetEventSource(economicCalendar, cfg.Calendar.ThresholdMultiplier)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			economicCalendar.Start(ctx)
+		}()
+	}
+
+	// 启动OKX公告监控（可选）
+	if cfg.Announcement.Enabled {
+		watcher := announcement.NewWatcher(cfg.Announcement, fetcher.NewHTTPClient(cfg.Network), stateManager,
+			notifyService.Channel(cfg.Announcement.Channel))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			watcher.Start(ctx)
+		}()
+	}
+
+	// 启动涨跌幅热力图摘要（可选），定期通过PushPlus或邮件发送按颜色分级的涨跌幅网格
+	if cfg.Digest.Enabled {
+		digestJob := scheduler.NewDigestJob(cfg.Digest, analysisEngine, notifyService.Channel(cfg.Digest.Channel))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			digestJob.Start(ctx)
+		}()
+	}
+
+	// 启动用户订阅（可选），用户通过Telegram机器人或API订阅，订阅预警只推送给订阅的用户
+	var subscriptions *subscription.Manager
+	if cfg.Subscription.Enabled {
+		bot := subscription.NewBot(cfg.Subscription.Telegram, cfg.Network.Proxy)
+		subscriptions = subscription.NewManager(cfg.Subscription, subscription.NewStore(stateManager.Redis()), stateManager, bot, cfg.DryRun)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			subscriptions.Start(ctx, cfg.Fetch.Interval)
+		}()
+		go func() {
+			defer wg.Done()
+			bot.Start(ctx, subscriptions)
+		}()
+	}
+
+	// 启动HTTP API服务（可选）
+	if cfg.Server.Enabled {
+		apiServer := api.NewServer(cfg.Server, dataFetcher, analysisEngine, taskScheduler, stateManager, sloTracker)
+		if subscriptions != nil {
+			apiServer.SetSubscriptions(subscriptions)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			apiServer.Start(ctx)
+		}()
+
+		// gRPC接口（可选），与HTTP API共用鉴权令牌
+		if cfg.Server.GRPCPort > 0 {
+			grpcServer := api.NewGRPCServer(cfg.Server, dataFetcher, analysisEngine, taskScheduler)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				grpcServer.Start(ctx)
+			}()
+		}
+	}
+
+	// 等待中断信号
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+	// SIGUSR1 在debug和原日志级别之间切换，便于排查线上问题
+	watchDebugToggle()
+
+	// 在systemd下运行时通知启动完成，并按WatchdogSec发送看门狗心跳
+	if _, err := systemd.Notify(systemd.Ready); err != nil {
+		zap.L().Warn("通知systemd启动完成失败", zap.Error(err))
+	}
+	if watchdog := monitor.NewSystemdWatchdog(dataFetcher, taskScheduler, cfg.OpsAlert.StallGrace); watchdog != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			watchdog.Start(ctx)
+		}()
+	}
+
+	zap.L().Info("OKX Market Sentry 已启动")
+	<-sigCh
+
+	zap.L().Info("收到停止信号，正在优雅关闭...")
+	_, _ = systemd.Notify(systemd.Stopping)
+	plan := &shutdownPlan{
+		config:          cfg.Shutdown,
+		cancel:          cancel,
+		workers:         &wg,
+		stateManager:    stateManager,
+		notifyService:   notifyService,
+		shutdownTracing: shutdownTracing,
+	}
+	plan.run()
+}
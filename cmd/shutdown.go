@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"okx-market-sentry/internal/notifier"
+	"okx-market-sentry/internal/storage"
+	"okx-market-sentry/pkg/types"
+)
+
+// shutdownPlan 按顺序关闭的各个组件
+type shutdownPlan struct {
+	config          types.ShutdownConfig
+	cancel          context.CancelFunc // 停止行情获取、分析和其他后台循环
+	workers         *sync.WaitGroup
+	stateManager    *storage.StateManager
+	notifyService   *notifier.DynamicNotifier
+	shutdownTracing func(context.Context) error
+}
+
+// shutdownStage 单个关闭阶段的执行结果
+type shutdownStage struct {
+	name     string
+	elapsed  time.Duration
+	timedOut bool
+}
+
+// run 依次执行：停止数据接入 → 等待Redis写入 → 等待通知发送 → 关闭Redis和链路追踪
+// 每个阶段单独计时，超时后记录未完成的内容并继续下一阶段，最后输出关闭报告
+func (p *shutdownPlan) run() {
+	var stages []shutdownStage
+	var unflushedWrites, pendingNotifications int
+	runStage := func(name string, timeout time.Duration, fn func(ctx context.Context) bool) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		start := time.Now()
+		ok := fn(ctx)
+		stage := shutdownStage{name: name, elapsed: time.Since(start), timedOut: !ok}
+		stages = append(stages, stage)
+		if stage.timedOut {
+			zap.L().Warn("⚠️ 关闭阶段超时，继续下一阶段",
+				zap.String("stage", name),
+				zap.Duration("timeout", timeout))
+		}
+	}
+
+	runStage("intake", p.config.IntakeTimeout, func(ctx context.Context) bool {
+		p.cancel()
+		done := make(chan struct{})
+		go func() {
+			p.workers.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	})
+
+	runStage("persist", p.config.PersistTimeout, func(ctx context.Context) bool {
+		unflushedWrites = p.stateManager.Flush(ctx)
+		return unflushedWrites == 0
+	})
+
+	runStage("notify", p.config.NotifyTimeout, func(ctx context.Context) bool {
+		pendingNotifications = p.notifyService.Drain(ctx)
+		return pendingNotifications == 0
+	})
+
+	runStage("close", p.config.CloseTimeout, func(ctx context.Context) bool {
+		ok := true
+		if err := p.stateManager.Close(); err != nil {
+			zap.L().Warn("关闭Redis连接失败", zap.Error(err))
+			ok = false
+		}
+		if err := p.shutdownTracing(ctx); err != nil {
+			zap.L().Warn("上报剩余链路数据失败", zap.Error(err))
+			ok = false
+		}
+		return ok
+	})
+
+	fields := []zap.Field{
+		zap.Int("unflushed_redis_writes", unflushedWrites),
+		zap.Int("pending_notifications", pendingNotifications),
+	}
+	clean := unflushedWrites == 0 && pendingNotifications == 0
+	for _, stage := range stages {
+		fields = append(fields,
+			zap.Duration(stage.name+"_elapsed", stage.elapsed),
+			zap.Bool(stage.name+"_timed_out", stage.timedOut))
+		clean = clean && !stage.timedOut
+	}
+
+	if clean {
+		zap.L().Info("🏁 OKX Market Sentry 已安全关闭", fields...)
+	} else {
+		zap.L().Warn("🏁 OKX Market Sentry 已关闭，部分数据未能完成处理", fields...)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+	"okx-market-sentry/pkg/logger"
+)
+
+// watchDebugToggle 收到SIGUSR1时在debug和原全局日志级别之间切换
+func watchDebugToggle() {
+	usr1Ch := make(chan os.Signal, 1)
+	signal.Notify(usr1Ch, syscall.SIGUSR1)
+	go func() {
+		for range usr1Ch {
+			level := logger.ToggleDebug()
+			zap.L().Info("🔧 收到SIGUSR1，全局日志级别已切换", zap.String("level", level))
+		}
+	}()
+}
@@ -0,0 +1,6 @@
+//go:build windows
+
+package main
+
+// watchDebugToggle Windows没有SIGUSR1，可通过 PUT /log/level 调整日志级别
+func watchDebugToggle() {}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"okx-market-sentry/internal/backtest"
+	"okx-market-sentry/internal/scheduler"
+	"okx-market-sentry/pkg/config"
+	"okx-market-sentry/pkg/types"
+)
+
+// simulateAlerts 用候选的阈值和监控周期回放历史行情，按天输出本应触发的预警数量，便于上线前调整阈值
+// threshold、period 非零时覆盖配置文件中所有预警配置组的阈值和监控周期
+func simulateAlerts(cfg *types.Config, input string, threshold float64, period string) int {
+	if input == "" {
+		fmt.Fprintln(os.Stderr, "❌ 请通过 --input 指定历史行情CSV文件")
+		return 2
+	}
+	alertConfig := cfg.Alert
+	if period != "" {
+		d, err := time.ParseDuration(period)
+		if err != nil || d <= 0 {
+			fmt.Fprintf(os.Stderr, "❌ 无效的监控周期 %q\n", period)
+			return 2
+		}
+		alertConfig.MonitorPeriod = d
+	}
+	if threshold < 0 {
+		fmt.Fprintln(os.Stderr, "❌ 阈值不能为负数")
+		return 2
+	}
+	if threshold > 0 {
+		alertConfig.Threshold = threshold
+	}
+	// 覆盖配置组时复制一份，不修改原配置
+	alertConfig.Profiles = append([]types.AlertProfile(nil), alertConfig.Profiles...)
+	for i := range alertConfig.Profiles {
+		if threshold > 0 {
+			alertConfig.Profiles[i].Threshold = threshold
+		}
+		if period != "" {
+			alertConfig.Profiles[i].MonitorPeriod = alertConfig.MonitorPeriod
+		}
+	}
+
+	file, err := os.Open(input)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "❌ 打开历史行情失败:", err)
+		return 1
+	}
+	defer file.Close()
+	ticks, err := backtest.ReadCSV(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ 读取历史行情失败: %s: %v\n", input, err)
+		return 1
+	}
+
+	shortestPeriod, _ := config.MonitorPeriodRange(alertConfig)
+	spec, schedule := scheduler.AnalysisSchedule(shortestPeriod, cfg.Schedule)
+	result := backtest.Replay(context.Background(), ticks, alertConfig, schedule)
+
+	fmt.Printf("📊 回放 %s ~ %s，%d 个交易对，%d 个价格点，分析 %d 轮（%s）\n",
+		result.Start.Local().Format("2006-01-02 15:04"), result.End.Local().Format("2006-01-02 15:04"),
+		result.Symbols, result.Ticks, result.Cycles, spec)
+	for _, profile := range config.AlertProfiles(alertConfig) {
+		fmt.Printf("   配置组 %s: 阈值 %.2f%%，监控周期 %s\n", profile.Name, profile.Threshold, profile.MonitorPeriod)
+	}
+	for _, rule := range alertConfig.Rules {
+		fmt.Printf("   规则 %s: 周期 %s\n", rule.Name, rule.Period)
+	}
+	fmt.Println()
+
+	days := result.Days()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DATE\tALERTS\tSYMBOLS\tTOP")
+	busiest := 0
+	for _, day := range days {
+		top := "-"
+		if day.Top != "" {
+			top = fmt.Sprintf("%s (%d)", day.Top, day.TopCount)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", day.Date.Format("2006-01-02"), day.Alerts, day.Symbols, top)
+		busiest = max(busiest, day.Alerts)
+	}
+	_ = w.Flush()
+
+	byProfile := make(map[string]int)
+	for _, alert := range result.Alerts {
+		byProfile[alert.Profile]++
+	}
+	var parts []string
+	for name, count := range byProfile {
+		parts = append(parts, fmt.Sprintf("%s %d", name, count))
+	}
+	fmt.Printf("\n合计 %d 个预警，平均每天 %.1f 个，最多一天 %d 个",
+		len(result.Alerts), float64(len(result.Alerts))/float64(max(len(days), 1)), busiest)
+	if len(byProfile) > 1 {
+		slices.Sort(parts)
+		fmt.Printf("（%s）", strings.Join(parts, "，"))
+	}
+	fmt.Println()
+	return 0
+}
@@ -1,75 +1,517 @@
 package analyzer
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 	"okx-market-sentry/internal/notifier"
+	"okx-market-sentry/internal/slo"
 	"okx-market-sentry/internal/storage"
+	"okx-market-sentry/internal/strategy/indicators"
+	"okx-market-sentry/pkg/config"
+	"okx-market-sentry/pkg/logger"
+	"okx-market-sentry/pkg/tracing"
 	"okx-market-sentry/pkg/types"
 )
 
+// log 模块日志器，级别可通过 log.modules.analyzer 单独配置
+func log() *zap.Logger {
+	return logger.Named("analyzer")
+}
+
 // AnalysisEngine 分析引擎
 type AnalysisEngine struct {
-	stateManager  *storage.StateManager
-	notifier      notifier.Interface
-	threshold     float64
-	monitorPeriod time.Duration        // 监控周期
-	alertHistory  map[string]time.Time // 防止重复预警
-	mutex         sync.RWMutex
+	stateManager *storage.StateManager
+	notifier     notifier.Interface
+	profiles     []types.AlertProfile            // 预警配置组，受settingsMutex保护，支持热加载
+	rules        []types.AlertRule               // 组合条件预警规则，受settingsMutex保护，支持热加载
+	templates    map[string]string               // 预警正文模板，受settingsMutex保护，支持热加载
+	benchmark    string                          // 相关性计算的基准交易对
+	corrLookback time.Duration                   // 相关性计算的回看周期
+	alertHistory map[string]map[string]time.Time // 配置组/规则 -> 交易对 -> 上次预警时间，各配置组的冷却互不影响；启用Redis时预警发送前占用Redis中的冷却
+	mutex        sync.RWMutex
+
+	decisionLog *zap.Logger // 决策审计日志，未启用时为nil
+	nearRatio   float64     // 达到阈值的该比例时记录决策，受settingsMutex保护
+	workers     int         // 并发分析的协程数，受settingsMutex保护
+
+	settingsMutex sync.RWMutex
+	sloTracker    *slo.Tracker
+
+	instruments InstrumentInfo // 交易对的展示信息，未设置时为nil
+	shard       Shard          // 多实例分片，未启用时为nil
+
+	eventSource     EventSource // 经济日历，未启用时为nil
+	eventMultiplier float64     // 重要经济事件前后的阈值倍数
+
+	pauses      map[string]time.Time // 暂停预警的配置组 -> 自动恢复时间（零值为不自动恢复），空名称表示全部
+	pausesMutex sync.Mutex
+
+	mutes      map[string]time.Time // 静音的交易对 -> 自动恢复时间（零值为不自动恢复）
+	mutesMutex sync.Mutex
+
+	recentAlerts []*types.AlertData   // 最近触发的预警，供API查询
+	lastAnalysis time.Time            // 最近一次分析完成的时间
+	cycleHistory []types.CycleMetrics // 分析指标历史
+	recentMutex  sync.RWMutex
+
+	subscribers      map[chan *types.AlertData]struct{} // 实时预警订阅者
+	subscribersMutex sync.Mutex
+
+	now func() time.Time // 当前时间，回放历史行情时替换为回放时钟
 }
 
-func NewAnalysisEngine(stateManager *storage.StateManager, notifyService notifier.Interface, threshold float64, monitorPeriod time.Duration) *AnalysisEngine {
-	return &AnalysisEngine{
-		stateManager:  stateManager,
-		notifier:      notifyService,
-		threshold:     threshold,
-		monitorPeriod: monitorPeriod,
-		alertHistory:  make(map[string]time.Time),
+const (
+	maxRecentAlerts = 100  // 保留的最近预警数量
+	maxCycleHistory = 1440 // 保留的分析指标条数（1分钟一轮约1天）
+
+	slowSymbolMin    = 50 * time.Millisecond // 单个交易对分析耗时超过该值且远高于中位数时视为异常
+	slowSymbolFactor = 10                    // 异常耗时相对中位数的倍数
+	maxSlowSymbols   = 5                     // 日志中最多列出的异常交易对数量
+)
+
+func NewAnalysisEngine(stateManager *storage.StateManager, notifyService notifier.Interface, alertConfig types.AlertConfig, sloTracker *slo.Tracker) *AnalysisEngine {
+	ae := &AnalysisEngine{
+		stateManager: stateManager,
+		notifier:     notifyService,
+		profiles:     config.AlertProfiles(alertConfig),
+		rules:        alertConfig.Rules,
+		templates:    alertConfig.Templates,
+		benchmark:    alertConfig.Benchmark,
+		corrLookback: alertConfig.CorrelationLookback,
+		alertHistory: stateManager.LoadCooldowns(),
+		pauses:       make(map[string]time.Time),
+		mutes:        make(map[string]time.Time),
+		cycleHistory: stateManager.LoadCycleMetrics(maxCycleHistory),
+		nearRatio:    alertConfig.DecisionLog.NearRatio,
+		workers:      alertConfig.Workers,
+		sloTracker:   sloTracker,
+		now:          time.Now,
+	}
+
+	if alertConfig.DecisionLog.Enabled {
+		ae.decisionLog = logger.NewDecisionLogger(alertConfig.DecisionLog)
+		log().Info("📝 已启用决策审计日志",
+			zap.String("file_path", alertConfig.DecisionLog.FilePath),
+			zap.Float64("near_ratio", alertConfig.DecisionLog.NearRatio))
 	}
+	return ae
 }
 
 // AnalyzeAll 分析所有交易对的价格变化
-func (ae *AnalysisEngine) AnalyzeAll() {
-	symbols := ae.stateManager.GetAllSymbols()
+func (ae *AnalysisEngine) AnalyzeAll(ctx context.Context) {
+	ae.analyze(ctx, ae.stateManager.GetAllSymbols(), false)
+}
+
+// AnalyzePartial 分析所有交易对，数据不足完整监控周期时按已有的最早数据计算，预警标记为部分数据
+// 用于启动后立即分析，不必等待窗口数据收集完整
+func (ae *AnalysisEngine) AnalyzePartial(ctx context.Context) {
+	ae.analyze(ctx, ae.stateManager.GetAllSymbols(), true)
+}
+
+// AnalyzeSymbols 立即分析指定交易对并返回触发的预警，symbols为空时分析全部交易对
+// 用于手动触发，预警同样受冷却期限制并发送到各配置组的通知渠道
+func (ae *AnalysisEngine) AnalyzeSymbols(ctx context.Context, symbols []string) []*types.AlertData {
 	if len(symbols) == 0 {
-		return
+		symbols = ae.stateManager.GetAllSymbols()
+	}
+	return ae.analyze(ctx, symbols, false)
+}
+
+func (ae *AnalysisEngine) analyze(ctx context.Context, symbols []string, allowPartial bool) []*types.AlertData {
+	ctx, span := tracing.Tracer().Start(ctx, "analyzer.analyze_all")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("symbols", len(symbols)), attribute.Bool("partial", allowPartial))
+	if len(symbols) == 0 {
+		return nil
 	}
 
-	zap.L().Info("开始分析价格变化", zap.Int("symbol_count", len(symbols)))
+	log().Info("开始分析价格变化", zap.Int("symbol_count", len(symbols)))
+	startTime := time.Now()
+	profiles, rules := ae.activeSettings()
+	var event *types.CalendarEvent
+	if ae.eventSource != nil {
+		if event = ae.eventSource.Nearby(ae.now()); event != nil {
+			log().Info("📅 处于重要经济事件前后，预警将附加事件说明",
+				zap.String("event", event.Title),
+				zap.Time("event_time", event.Time),
+				zap.Float64("threshold_multiplier", ae.eventMultiplier))
+		}
+	}
 
-	// 并发分析各个交易对，收集预警
+	// 由固定数量的协程并发分析各个交易对，每个交易对按其所属的各配置组分别判断，收集预警和耗时
 	var wg sync.WaitGroup
-	var alertMutex sync.Mutex
+	var resultMutex sync.Mutex
 	alerts := make([]*types.AlertData, 0)
+	timings := make([]symbolTiming, 0, len(symbols))
 
-	for _, symbol := range symbols {
+	jobs := make(chan string)
+	workers := min(ae.workerCount(), len(symbols))
+	for i := 0; i < workers; i++ {
 		wg.Add(1)
-		go func(sym string) {
+		go func() {
 			defer wg.Done()
-			if alert := ae.analyzeSymbol(sym); alert != nil {
-				alertMutex.Lock()
-				alerts = append(alerts, alert)
-				alertMutex.Unlock()
+			for sym := range jobs {
+				symbolStart := time.Now()
+				var symbolAlerts []*types.AlertData
+				for _, profile := range profiles {
+					if !profileMatches(profile, sym) {
+						continue
+					}
+					if alert := ae.analyzeSymbol(profile, sym, allowPartial, event); alert != nil {
+						symbolAlerts = append(symbolAlerts, alert)
+					}
+				}
+				for _, rule := range rules {
+					if !symbolMatches(rule.Symbols, sym) {
+						continue
+					}
+					if alert := ae.analyzeRule(rule, sym, event); alert != nil {
+						symbolAlerts = append(symbolAlerts, alert)
+					}
+				}
+				elapsed := time.Since(symbolStart)
+
+				resultMutex.Lock()
+				alerts = append(alerts, symbolAlerts...)
+				timings = append(timings, symbolTiming{symbol: sym, elapsed: elapsed})
+				resultMutex.Unlock()
 			}
-		}(symbol)
+		}()
+	}
+	muted := ae.GetMutes()
+	for _, symbol := range symbols {
+		if _, isMuted := muted[symbol]; isMuted {
+			continue
+		}
+		// 分片成员变化后，内存中可能仍保留其他实例负责的交易对
+		if ae.shard != nil && !ae.shard.Owns(symbol) {
+			continue
+		}
+		jobs <- symbol
 	}
+	close(jobs)
 	wg.Wait()
+	alerts = ae.claimAlerts(alerts, profiles, rules)
 
-	// 批量发送预警
+	metrics := types.CycleMetrics{
+		Time:     startTime,
+		Symbols:  len(symbols),
+		Alerts:   len(alerts),
+		Duration: time.Since(startTime),
+	}
+	slowest := slowSymbols(timings)
+	if len(timings) > 0 {
+		metrics.SlowestSymbol = timings[0].symbol
+		metrics.SlowestDuration = timings[0].elapsed
+	}
+	if len(slowest) > 0 {
+		var details []string
+		for _, timing := range slowest[:min(len(slowest), maxSlowSymbols)] {
+			details = append(details, fmt.Sprintf("%s %s", timing.symbol, timing.elapsed.Round(time.Millisecond)))
+		}
+		log().Warn("🐢 部分交易对分析耗时异常",
+			zap.Int("slow_count", len(slowest)),
+			zap.Duration("median", timings[len(timings)/2].elapsed),
+			zap.Strings("slowest", details))
+	}
+	log().Debug("⏱️ 本轮分析耗时",
+		zap.Duration("duration", metrics.Duration),
+		zap.Int("workers", workers),
+		zap.String("slowest_symbol", metrics.SlowestSymbol),
+		zap.Duration("slowest_duration", metrics.SlowestDuration))
+
+	ae.recordRecentAlerts(alerts)
+	ae.recordCycleMetrics(metrics)
 	if len(alerts) > 0 {
-		ae.sendBatchAlerts(alerts)
-		zap.L().Info("✅ 分析完成，触发预警", zap.Int("alert_count", len(alerts)))
+		ae.publish(alerts)
+	}
+
+	// 按配置组和规则分别批量发送到各自的通知渠道
+	if len(alerts) > 0 {
+		type target struct{ name, channel string }
+		targets := make([]target, 0, len(profiles)+len(rules))
+		for _, profile := range profiles {
+			targets = append(targets, target{profile.Name, profile.Channel})
+		}
+		for _, rule := range rules {
+			targets = append(targets, target{rule.Name, rule.Channel})
+		}
+		for _, t := range targets {
+			var group []*types.AlertData
+			for _, alert := range alerts {
+				if alert.Profile == t.name {
+					group = append(group, alert)
+				}
+			}
+			// 发送失败的预警释放Redis中的冷却，其他实例或重启后可以重新发送
+			for _, alert := range ae.sendBatchAlerts(ctx, ae.notifierFor(t.channel), group) {
+				ae.stateManager.ReleaseCooldown(alert.Profile, alert.Symbol, alert.AlertTime)
+			}
+		}
+		log().Info("✅ 分析完成，触发预警", zap.Int("alert_count", len(alerts)))
+	} else {
+		log().Info("✅ 分析完成，暂无异常波动")
+	}
+	return alerts
+}
+
+// symbolTiming 单个交易对的分析耗时
+type symbolTiming struct {
+	symbol  string
+	elapsed time.Duration
+}
+
+// slowSymbols 将耗时按从慢到快排序，返回耗时超过下限且远高于中位数的交易对
+func slowSymbols(timings []symbolTiming) []symbolTiming {
+	if len(timings) == 0 {
+		return nil
+	}
+	sort.Slice(timings, func(i, j int) bool { return timings[i].elapsed > timings[j].elapsed })
+	median := timings[len(timings)/2].elapsed
+
+	var slow []symbolTiming
+	for _, timing := range timings {
+		if timing.elapsed < slowSymbolMin || timing.elapsed < median*slowSymbolFactor {
+			break
+		}
+		slow = append(slow, timing)
+	}
+	return slow
+}
+
+// InstrumentInfo 提供交易对的展示信息
+type InstrumentInfo interface {
+	Name(symbol string) string
+	TickSize(symbol string) float64
+}
+
+// SetInstrumentInfo 设置交易对的展示信息，预警和状态查询附带项目名称，预警附带价格精度
+// 需在开始分析前调用
+func (ae *AnalysisEngine) SetInstrumentInfo(info InstrumentInfo) {
+	ae.instruments = info
+}
+
+// Shard 多实例分片，判断交易对是否由本实例分析
+type Shard interface {
+	Owns(symbol string) bool
+}
+
+// SetShard 启用分片，只分析本实例负责的交易对，需在开始分析前调用
+func (ae *AnalysisEngine) SetShard(shard Shard) {
+	ae.shard = shard
+}
+
+// symbolName 交易对的项目名称，未知时返回空
+func (ae *AnalysisEngine) symbolName(symbol string) string {
+	if ae.instruments == nil {
+		return ""
+	}
+	return ae.instruments.Name(symbol)
+}
+
+// tickSize 交易对的价格最小变动单位，未知时返回0
+func (ae *AnalysisEngine) tickSize(symbol string) float64 {
+	if ae.instruments == nil {
+		return 0
+	}
+	return ae.instruments.TickSize(symbol)
+}
+
+// EventSource 提供指定时间前后的重要经济事件
+type EventSource interface {
+	Nearby(t time.Time) *types.CalendarEvent
+}
+
+// SetEventSource 设置经济日历，事件前后的预警附加事件说明，阈值乘以multiplier
+// 需在开始分析前调用
+func (ae *AnalysisEngine) SetEventSource(source EventSource, multiplier float64) {
+	ae.eventSource = source
+	ae.eventMultiplier = max(multiplier, 1)
+}
+
+// SetClock 设置预警时间和冷却期使用的当前时间，用于回放历史行情，需在开始分析前调用
+func (ae *AnalysisEngine) SetClock(now func() time.Time) {
+	ae.now = now
+}
+
+// ErrUnknownProfile 暂停/恢复时指定的配置组不存在
+var ErrUnknownProfile = errors.New("unknown alert profile")
+
+// Pause 暂停配置组的预警，profile为空时暂停全部；duration>0时到期自动恢复
+// 暂停期间行情照常获取，被暂停的配置组不做判断、不发送通知，也不进入冷却
+func (ae *AnalysisEngine) Pause(profile string, duration time.Duration) error {
+	if profile != "" && !ae.hasProfile(profile) {
+		return fmt.Errorf("%w: %s", ErrUnknownProfile, profile)
+	}
+
+	var until time.Time
+	if duration > 0 {
+		until = time.Now().Add(duration)
+	}
+	ae.pausesMutex.Lock()
+	ae.pauses[profile] = until
+	ae.pausesMutex.Unlock()
+
+	log().Warn("⏸️ 预警已暂停",
+		zap.String("profile", pauseTarget(profile)),
+		zap.Duration("duration", duration))
+	return nil
+}
+
+// Resume 恢复配置组的预警，profile为空时恢复全部（包括单独暂停的配置组）
+func (ae *AnalysisEngine) Resume(profile string) error {
+	if profile != "" && !ae.hasProfile(profile) {
+		return fmt.Errorf("%w: %s", ErrUnknownProfile, profile)
+	}
+
+	ae.pausesMutex.Lock()
+	if profile == "" {
+		clear(ae.pauses)
 	} else {
-		zap.L().Info("✅ 分析完成，暂无异常波动")
+		delete(ae.pauses, profile)
+	}
+	ae.pausesMutex.Unlock()
+
+	log().Info("▶️ 预警已恢复", zap.String("profile", pauseTarget(profile)))
+	return nil
+}
+
+// GetPauses 当前暂停的配置组及自动恢复时间，键为空字符串表示全部暂停，已到期的暂停会被清理
+func (ae *AnalysisEngine) GetPauses() map[string]*time.Time {
+	ae.pausesMutex.Lock()
+	defer ae.pausesMutex.Unlock()
+
+	now := time.Now()
+	result := make(map[string]*time.Time, len(ae.pauses))
+	for profile, until := range ae.pauses {
+		if !until.IsZero() && now.After(until) {
+			delete(ae.pauses, profile)
+			log().Info("▶️ 预警暂停已到期，自动恢复", zap.String("profile", pauseTarget(profile)))
+			continue
+		}
+		if until.IsZero() {
+			result[profile] = nil
+		} else {
+			result[profile] = &until
+		}
+	}
+	return result
+}
+
+// activeSettings 当前未暂停的预警配置组和规则
+func (ae *AnalysisEngine) activeSettings() ([]types.AlertProfile, []types.AlertRule) {
+	profiles, _ := ae.settings()
+	rules := ae.ruleSettings()
+	pauses := ae.GetPauses()
+	if _, all := pauses[""]; all {
+		log().Info("⏸️ 预警已全部暂停，本轮只更新数据")
+		return nil, nil
+	}
+	if len(pauses) == 0 {
+		return profiles, rules
+	}
+
+	activeProfiles := make([]types.AlertProfile, 0, len(profiles))
+	for _, profile := range profiles {
+		if _, paused := pauses[profile.Name]; !paused {
+			activeProfiles = append(activeProfiles, profile)
+		}
+	}
+	activeRules := make([]types.AlertRule, 0, len(rules))
+	for _, rule := range rules {
+		if _, paused := pauses[rule.Name]; !paused {
+			activeRules = append(activeRules, rule)
+		}
+	}
+	return activeProfiles, activeRules
+}
+
+// hasProfile 是否存在该名称的配置组或规则
+func (ae *AnalysisEngine) hasProfile(name string) bool {
+	profiles, _ := ae.settings()
+	for _, profile := range profiles {
+		if profile.Name == name {
+			return true
+		}
+	}
+	for _, rule := range ae.ruleSettings() {
+		if rule.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// pauseTarget 日志中显示的暂停对象
+func pauseTarget(profile string) string {
+	if profile == "" {
+		return "全部"
 	}
+	return profile
 }
 
-// analyzeSymbol 分析单个交易对，返回预警数据或nil
-func (ae *AnalysisEngine) analyzeSymbol(symbol string) *types.AlertData {
+// profileMatches 交易对是否属于配置组，未指定交易对的配置组匹配全部交易对
+func profileMatches(profile types.AlertProfile, symbol string) bool {
+	return symbolMatches(profile.Symbols, symbol)
+}
+
+// symbolMatches 交易对是否在列表中，列表为空时匹配全部交易对
+func symbolMatches(symbols []string, symbol string) bool {
+	if len(symbols) == 0 {
+		return true
+	}
+	for _, s := range symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// primaryProfile 交易对匹配的第一个配置组，用于状态查询和预热进度
+func primaryProfile(profiles []types.AlertProfile, symbol string) (types.AlertProfile, bool) {
+	for _, profile := range profiles {
+		if profileMatches(profile, symbol) {
+			return profile, true
+		}
+	}
+	return types.AlertProfile{}, false
+}
+
+// notifierFor 返回配置组使用的通知渠道，未指定渠道时使用默认通知器
+func (ae *AnalysisEngine) notifierFor(channel string) notifier.Interface {
+	if router, ok := ae.notifier.(notifier.Router); ok && channel != "" {
+		return router.Channel(channel)
+	}
+	return ae.notifier
+}
+
+// analyzeSymbol 按配置组分析单个交易对，返回预警数据或nil
+// allowPartial为true时，数据不足完整监控周期的交易对按已有的最早数据计算
+// event不为nil时处于重要经济事件前后，阈值按倍数提高并在预警中附加事件
+func (ae *AnalysisEngine) analyzeSymbol(profile types.AlertProfile, symbol string, allowPartial bool, event *types.CalendarEvent) *types.AlertData {
+	if event != nil {
+		profile.Threshold *= ae.eventMultiplier
+	}
+
 	// 获取价格数据
-	current, past := ae.stateManager.GetPriceData(symbol)
+	current, past := ae.stateManager.GetPriceData(symbol, profile.MonitorPeriod)
+	period, partial := profile.MonitorPeriod, false
+	if current != nil && past == nil && allowPartial {
+		past, period = ae.partialWindow(current, symbol, profile.MonitorPeriod)
+		partial = past != nil
+	}
 	if current == nil || past == nil {
 		return nil // 数据不足，跳过分析
 	}
@@ -83,20 +525,40 @@ func (ae *AnalysisEngine) analyzeSymbol(symbol string) *types.AlertData {
 		absChange = -absChange
 	}
 
-	if absChange > ae.threshold {
+	if absChange > profile.Threshold {
 		// 检查是否在短时间内已经预警过（避免重复预警）
-		if ae.shouldAlert(symbol) {
-			alert := &types.AlertData{
-				Symbol:        symbol,
-				CurrentPrice:  current.Price,
-				PastPrice:     past.Price,
-				ChangePercent: changePercent,
-				AlertTime:     time.Now(),
-				MonitorPeriod: ae.monitorPeriod,
+		allowed := ae.shouldAlert(profile, symbol)
+		ae.logDecision(profile, symbol, current, past, changePercent, true, !allowed)
+		if allowed {
+			alert := ae.newAlert(profile, symbol, current, past.Price, changePercent, period, partial, event)
+			if !partial {
+				alert.Momentum = ae.momentum(symbol, current, past, period)
 			}
+			return alert
+		}
+		return nil
+	}
+	ae.logDecision(profile, symbol, current, past, changePercent, false, false)
+
+	// 首尾对比未触发时，检查窗口内的高点回撤/低点反弹
+	if profile.Trailing > 0 && !partial {
+		kind, extreme, trailingChange := ae.trailingChange(symbol, current, profile.MonitorPeriod)
+		if kind != "" && math.Abs(trailingChange) > profile.Trailing && ae.shouldAlert(profile, symbol) {
+			alert := ae.newAlert(profile, symbol, current, extreme, trailingChange, period, false, event)
+			alert.Kind = kind
+			return alert
+		}
+	}
 
-			// 记录预警历史
-			ae.recordAlert(symbol)
+	// 检查近1/3窗口是否明显加速
+	if profile.Acceleration > 0 && !partial {
+		m := ae.momentum(symbol, current, past, period)
+		if m != nil && m.Accelerating && (m.Ratio == 0 || m.Ratio >= profile.Acceleration) &&
+			math.Abs(m.RecentChange) >= profile.Threshold/3 && ae.shouldAlert(profile, symbol) {
+			midPrice := current.Price / (1 + m.RecentChange/100)
+			alert := ae.newAlert(profile, symbol, current, midPrice, m.RecentChange, m.RecentPeriod, false, event)
+			alert.Kind = types.AlertKindAcceleration
+			alert.Momentum = m
 			return alert
 		}
 	}
@@ -104,64 +566,603 @@ func (ae *AnalysisEngine) analyzeSymbol(symbol string) *types.AlertData {
 	return nil
 }
 
-// sendBatchAlerts 批量发送预警
-func (ae *AnalysisEngine) sendBatchAlerts(alerts []*types.AlertData) {
-	if len(alerts) == 0 {
+// momentum 以窗口2/3处的价格把窗口分为前后两段，比较两段的涨跌速度，数据不足时返回nil
+func (ae *AnalysisEngine) momentum(symbol string, current, past *types.PriceDataPoint, window time.Duration) *types.Momentum {
+	_, mid := ae.stateManager.GetPriceData(symbol, window/3)
+	if mid == nil || !mid.Timestamp.After(past.Timestamp) || !current.Timestamp.After(mid.Timestamp) {
+		return nil
+	}
+
+	m := &types.Momentum{
+		EarlyChange:  (mid.Price - past.Price) / past.Price * 100,
+		EarlyPeriod:  mid.Timestamp.Sub(past.Timestamp),
+		RecentChange: (current.Price - mid.Price) / mid.Price * 100,
+		RecentPeriod: current.Timestamp.Sub(mid.Timestamp),
+	}
+	if m.EarlyChange == 0 {
+		m.Accelerating = m.RecentChange != 0
+		return m
+	}
+	earlyRate := m.EarlyChange / m.EarlyPeriod.Minutes()
+	recentRate := m.RecentChange / m.RecentPeriod.Minutes()
+	m.Ratio = recentRate / earlyRate
+	m.Accelerating = m.Ratio > 1
+	return m
+}
+
+// newAlert 创建预警并记录预警历史，pastPrice为对比的价格
+func (ae *AnalysisEngine) newAlert(profile types.AlertProfile, symbol string, current *types.PriceDataPoint, pastPrice, changePercent float64,
+	period time.Duration, partial bool, event *types.CalendarEvent) *types.AlertData {
+	alert := &types.AlertData{
+		Symbol:        symbol,
+		Name:          ae.symbolName(symbol),
+		TickSize:      ae.tickSize(symbol),
+		CurrentPrice:  current.Price,
+		PastPrice:     pastPrice,
+		ChangePercent: changePercent,
+		AlertTime:     ae.now(),
+		MonitorPeriod: period,
+		PriceTime:     current.Timestamp,
+		Profile:       profile.Name,
+		Partial:       partial,
+		Event:         event,
+		Stats24h:      ae.stateManager.GetTicker24h(symbol),
+		Correlation:   ae.CalculateCorrelation(symbol),
+		Style:         ae.alertStyle(profile.Title, profile.Emoji, profile.Template),
+	}
+
+	// 记录预警历史
+	ae.recordAlert(profile, symbol)
+	return alert
+}
+
+// trailingChange 计算当前价格相对窗口内高点的回撤和相对低点的反弹，返回幅度较大的一个
+// 只统计先涨后跌（高点高于窗口起点）的回撤和先跌后涨（低点低于窗口起点）的反弹，
+// 单边行情由首尾对比覆盖；没有反转时kind为空
+func (ae *AnalysisEngine) trailingChange(symbol string, current *types.PriceDataPoint, window time.Duration) (kind string, extreme, changePercent float64) {
+	series := ae.stateManager.GetPriceSeries(symbol, window)
+	if len(series) < 3 {
+		return "", 0, 0
+	}
+
+	start := series[0].Price
+	high, low := start, start
+	for _, point := range series {
+		high = max(high, point.Price)
+		low = min(low, point.Price)
+	}
+
+	var drawdown, bounce float64
+	if high > start {
+		drawdown = (current.Price - high) / high * 100
+	}
+	if low < start {
+		bounce = (current.Price - low) / low * 100
+	}
+	switch {
+	case drawdown == 0 && bounce == 0:
+		return "", 0, 0
+	case -drawdown >= bounce:
+		return types.AlertKindDrawdown, high, drawdown
+	default:
+		return types.AlertKindBounce, low, bounce
+	}
+}
+
+// partialWindow 返回不足完整监控周期时可用的最早数据及其覆盖时长
+// 已有数据覆盖完整周期（只是中间有缺口）时不使用，避免用超出监控周期的数据计算
+func (ae *AnalysisEngine) partialWindow(current *types.PriceDataPoint, symbol string, window time.Duration) (*types.PriceDataPoint, time.Duration) {
+	oldest := ae.stateManager.GetOldestPriceData(symbol)
+	if oldest == nil {
+		return nil, 0
+	}
+	covered := current.Timestamp.Sub(oldest.Timestamp)
+	if covered <= 0 || covered >= window {
+		return nil, 0
+	}
+	return oldest, covered
+}
+
+// logDecision 记录接近或超过阈值的分析决策，便于根据数据调整阈值
+func (ae *AnalysisEngine) logDecision(profile types.AlertProfile, symbol string, current, past *types.PriceDataPoint, changePercent float64, exceeded, suppressed bool) {
+	if ae.decisionLog == nil {
 		return
 	}
+	_, nearRatio := ae.settings()
+	threshold := profile.Threshold
+	if math.Abs(changePercent) < threshold*nearRatio {
+		return
+	}
+
+	ae.decisionLog.Info("decision",
+		zap.String("profile", profile.Name),
+		zap.String("symbol", symbol),
+		zap.Float64("current_price", current.Price),
+		zap.Time("current_time", current.Timestamp),
+		zap.Float64("past_price", past.Price),
+		zap.Time("past_time", past.Timestamp),
+		zap.Float64("change_percent", changePercent),
+		zap.Float64("threshold", threshold),
+		zap.Float64("threshold_ratio", math.Abs(changePercent)/threshold),
+		zap.Duration("monitor_period", profile.MonitorPeriod),
+		zap.Bool("exceeded", exceeded),
+		zap.Bool("suppressed_by_cooldown", suppressed))
+}
+
+// workerCount 并发分析的协程数
+func (ae *AnalysisEngine) workerCount() int {
+	ae.settingsMutex.RLock()
+	defer ae.settingsMutex.RUnlock()
+	return max(ae.workers, 1)
+}
+
+// alertStyle 配置组/规则指定的展示样式，均未指定时返回nil
+func (ae *AnalysisEngine) alertStyle(title, emoji, template string) *types.AlertStyle {
+	if title == "" && emoji == "" && template == "" {
+		return nil
+	}
+	ae.settingsMutex.RLock()
+	defer ae.settingsMutex.RUnlock()
+	// 配置键不区分大小写，viper读取的模板名称均为小写
+	return &types.AlertStyle{Title: title, Emoji: emoji, Body: ae.templates[strings.ToLower(template)]}
+}
+
+// ruleSettings 获取当前的组合条件规则
+func (ae *AnalysisEngine) ruleSettings() []types.AlertRule {
+	ae.settingsMutex.RLock()
+	defer ae.settingsMutex.RUnlock()
+	return ae.rules
+}
+
+// settings 获取当前的预警配置组和决策日志记录比例
+func (ae *AnalysisEngine) settings() (profiles []types.AlertProfile, nearRatio float64) {
+	ae.settingsMutex.RLock()
+	defer ae.settingsMutex.RUnlock()
+	return ae.profiles, ae.nearRatio
+}
+
+// UpdateAlertConfig 热加载预警配置组的交易对、阈值和通知渠道
+// 最短/最长监控周期影响调度频率和存储窗口，变化时需重启生效
+func (ae *AnalysisEngine) UpdateAlertConfig(alertConfig types.AlertConfig) {
+	ae.settingsMutex.Lock()
+	defer ae.settingsMutex.Unlock()
+
+	profiles := config.AlertProfiles(alertConfig)
+	if !reflect.DeepEqual(ae.profiles, profiles) || ae.nearRatio != alertConfig.DecisionLog.NearRatio {
+		log().Info("🔧 预警配置已更新",
+			zap.Int("profiles", len(profiles)),
+			zap.Float64("threshold", profiles[0].Threshold),
+			zap.Float64("near_ratio", alertConfig.DecisionLog.NearRatio))
+	}
+	if !reflect.DeepEqual(ae.rules, alertConfig.Rules) {
+		log().Info("🔧 组合条件规则已更新", zap.Int("rules", len(alertConfig.Rules)))
+	}
+	ae.rules = alertConfig.Rules
+	ae.templates = alertConfig.Templates
+	if ae.workers != alertConfig.Workers {
+		log().Info("🔧 分析协程数已更新", zap.Int("workers", alertConfig.Workers))
+	}
+	ae.profiles = profiles
+	ae.nearRatio = alertConfig.DecisionLog.NearRatio
+	ae.workers = alertConfig.Workers
+}
+
+// CalculateCorrelation 计算交易对相对基准的相关性和Beta，数据不足时返回nil
+func (ae *AnalysisEngine) CalculateCorrelation(symbol string) *types.CorrelationData {
+	if ae.benchmark == "" || ae.corrLookback <= 0 || symbol == ae.benchmark {
+		return nil
+	}
+
+	assetSeries := ae.stateManager.GetPriceSeries(symbol, ae.corrLookback)
+	benchSeries := ae.stateManager.GetPriceSeries(ae.benchmark, ae.corrLookback)
+	if len(assetSeries) < 3 || len(benchSeries) < 3 {
+		return nil
+	}
+
+	// 按时间戳对齐两个序列，只保留双方都有数据的时间点
+	benchPrices := make(map[int64]float64, len(benchSeries))
+	for _, p := range benchSeries {
+		benchPrices[p.Timestamp.UnixNano()] = p.Price
+	}
+
+	var assetPrices, alignedBench []float64
+	for _, p := range assetSeries {
+		if bp, ok := benchPrices[p.Timestamp.UnixNano()]; ok {
+			assetPrices = append(assetPrices, p.Price)
+			alignedBench = append(alignedBench, bp)
+		}
+	}
+
+	assetReturns := indicators.Returns(assetPrices)
+	benchReturns := indicators.Returns(alignedBench)
+	if len(assetReturns) < 2 {
+		return nil
+	}
+
+	corr := indicators.Correlation(assetReturns, benchReturns)
+	beta := indicators.Beta(assetReturns, benchReturns)
+	if math.IsNaN(corr) || math.IsNaN(beta) {
+		return nil
+	}
+
+	return &types.CorrelationData{
+		Symbol:      symbol,
+		Benchmark:   ae.benchmark,
+		Correlation: corr,
+		Beta:        beta,
+		Samples:     len(assetReturns),
+		Lookback:    ae.corrLookback,
+	}
+}
+
+// GetWarmupStatus 获取所有交易对的数据预热状态，按交易对排序
+func (ae *AnalysisEngine) GetWarmupStatus() []types.WarmupStatus {
+	symbols := ae.stateManager.GetAllSymbols()
+	sort.Strings(symbols)
+	profiles, _ := ae.settings()
+
+	statuses := make([]types.WarmupStatus, 0, len(symbols))
+	for _, symbol := range symbols {
+		points, covered := ae.stateManager.GetCoverage(symbol)
+
+		indicators := map[string]types.WarmupProgress{}
+		if profile, ok := primaryProfile(profiles, symbol); ok {
+			indicators["price_change"] = warmupProgress(covered, profile.MonitorPeriod)
+		}
+		if ae.benchmark != "" && ae.corrLookback > 0 && symbol != ae.benchmark {
+			indicators["correlation"] = warmupProgress(covered, ae.corrLookback)
+		}
+
+		statuses = append(statuses, types.WarmupStatus{
+			Symbol:     symbol,
+			Points:     points,
+			Covered:    covered,
+			Indicators: indicators,
+		})
+	}
+	return statuses
+}
+
+// warmupProgress 根据已覆盖时长计算预热进度
+func warmupProgress(covered, required time.Duration) types.WarmupProgress {
+	progress := types.WarmupProgress{Required: required}
+	if required <= 0 || covered >= required {
+		progress.Percent = 100
+		progress.Ready = true
+		return progress
+	}
+	progress.Percent = float64(covered) / float64(required) * 100
+	return progress
+}
+
+// sendBatchAlerts 通过指定通知器批量发送预警，返回最终发送失败的预警
+func (ae *AnalysisEngine) sendBatchAlerts(ctx context.Context, notifyService notifier.Interface, alerts []*types.AlertData) []*types.AlertData {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	_, span := tracing.Tracer().Start(ctx, "notifier.send")
+	defer func() {
+		// 记录从行情获取到通知发出的最大延迟，衡量端到端时效
+		var maxLatency time.Duration
+		for _, alert := range alerts {
+			if latency := time.Since(alert.PriceTime); !alert.PriceTime.IsZero() && latency > maxLatency {
+				maxLatency = latency
+			}
+		}
+		span.SetAttributes(
+			attribute.Int("alert_count", len(alerts)),
+			attribute.Int64("max_price_to_notify_ms", maxLatency.Milliseconds()))
+		span.End()
+	}()
 
 	// 如果只有一个预警，使用单个发送
 	if len(alerts) == 1 {
-		err := ae.notifier.SendAlert(alerts[0])
+		err := notifyService.SendAlert(alerts[0])
 		if err != nil {
-			zap.L().Error("发送预警失败",
+			log().Error("发送预警失败",
 				zap.String("symbol", alerts[0].Symbol),
 				zap.Error(err))
+			return alerts
 		}
-		return
+		ae.recordNotifyLatency(alerts[0])
+		return nil
 	}
 
 	// 批量发送多个预警
-	err := ae.notifier.SendBatchAlerts(alerts)
+	err := notifyService.SendBatchAlerts(alerts)
 	if err != nil {
-		zap.L().Error("批量发送预警失败", zap.Error(err))
+		log().Error("批量发送预警失败", zap.Error(err))
 		// 降级为单个发送
+		var failed []*types.AlertData
 		for _, alert := range alerts {
-			if singleErr := ae.notifier.SendAlert(alert); singleErr != nil {
-				zap.L().Error("单个预警发送失败",
+			if singleErr := notifyService.SendAlert(alert); singleErr != nil {
+				log().Error("单个预警发送失败",
 					zap.String("symbol", alert.Symbol),
 					zap.Error(singleErr))
+				failed = append(failed, alert)
+				continue
 			}
+			ae.recordNotifyLatency(alert)
 		}
+		return failed
+	}
+
+	for _, alert := range alerts {
+		ae.recordNotifyLatency(alert)
+	}
+	return nil
+}
+
+// recordNotifyLatency 记录从行情获取到通知成功的延迟
+func (ae *AnalysisEngine) recordNotifyLatency(alert *types.AlertData) {
+	if alert.PriceTime.IsZero() {
+		return
+	}
+	ae.sloTracker.RecordNotifyLatency(time.Since(alert.PriceTime))
+}
+
+// recordRecentAlerts 记录本轮预警和分析时间
+func (ae *AnalysisEngine) recordRecentAlerts(alerts []*types.AlertData) {
+	ae.recentMutex.Lock()
+	defer ae.recentMutex.Unlock()
+
+	ae.lastAnalysis = ae.now()
+	ae.recentAlerts = append(ae.recentAlerts, alerts...)
+	if overflow := len(ae.recentAlerts) - maxRecentAlerts; overflow > 0 {
+		ae.recentAlerts = ae.recentAlerts[overflow:]
+	}
+}
+
+// recordCycleMetrics 记录单轮分析指标，并持久化到Redis（如已启用）
+func (ae *AnalysisEngine) recordCycleMetrics(metrics types.CycleMetrics) {
+	ae.recentMutex.Lock()
+	ae.cycleHistory = append(ae.cycleHistory, metrics)
+	if overflow := len(ae.cycleHistory) - maxCycleHistory; overflow > 0 {
+		ae.cycleHistory = ae.cycleHistory[overflow:]
+	}
+	ae.recentMutex.Unlock()
+
+	ae.stateManager.SaveCycleMetricsAsync(metrics, maxCycleHistory)
+}
+
+// GetCycleHistory 获取最近的分析指标（按时间升序），limit<=0时返回全部
+func (ae *AnalysisEngine) GetCycleHistory(limit int) []types.CycleMetrics {
+	ae.recentMutex.RLock()
+	defer ae.recentMutex.RUnlock()
+
+	start := 0
+	if limit > 0 && limit < len(ae.cycleHistory) {
+		start = len(ae.cycleHistory) - limit
 	}
+	result := make([]types.CycleMetrics, len(ae.cycleHistory)-start)
+	copy(result, ae.cycleHistory[start:])
+	return result
 }
 
-// shouldAlert 检查是否应该发送预警（防止短时间内重复预警）
-func (ae *AnalysisEngine) shouldAlert(symbol string) bool {
+// GetRecentAlerts 获取最近的预警，按时间倒序，limit<=0时返回全部
+func (ae *AnalysisEngine) GetRecentAlerts(limit int) []*types.AlertData {
+	ae.recentMutex.RLock()
+	defer ae.recentMutex.RUnlock()
+
+	count := len(ae.recentAlerts)
+	if limit > 0 && limit < count {
+		count = limit
+	}
+
+	result := make([]*types.AlertData, 0, count)
+	for i := len(ae.recentAlerts) - 1; i >= 0 && len(result) < count; i-- {
+		result = append(result, ae.recentAlerts[i])
+	}
+	return result
+}
+
+// GetLastAlertTime 获取交易对在所有配置组中最近一次预警时间
+func (ae *AnalysisEngine) GetLastAlertTime(symbol string) (time.Time, bool) {
 	ae.mutex.RLock()
 	defer ae.mutex.RUnlock()
 
-	lastAlert, exists := ae.alertHistory[symbol]
-	if !exists {
-		return true
+	var latest time.Time
+	for _, history := range ae.alertHistory {
+		if t, ok := history[symbol]; ok && t.After(latest) {
+			latest = t
+		}
 	}
+	return latest, !latest.IsZero()
+}
+
+// GetSymbolState 获取单个交易对的当前分析状态，按其匹配的第一个配置组计算，交易对不存在时返回nil
+func (ae *AnalysisEngine) GetSymbolState(symbol string) *types.SymbolState {
+	profiles, _ := ae.settings()
+	profile, monitored := primaryProfile(profiles, symbol)
 
+	current, past := ae.stateManager.GetPriceData(symbol, profile.MonitorPeriod)
+	if current == nil {
+		return nil
+	}
+
+	state := &types.SymbolState{
+		Symbol:        symbol,
+		Name:          ae.symbolName(symbol),
+		CurrentPrice:  current.Price,
+		UpdatedAt:     current.Timestamp,
+		MonitorPeriod: profile.MonitorPeriod,
+		Threshold:     profile.Threshold,
+		Profile:       profile.Name,
+		Stats24h:      ae.stateManager.GetTicker24h(symbol),
+		Correlation:   ae.CalculateCorrelation(symbol),
+	}
+	if monitored {
+		state.Alerting = !ae.shouldAlert(profile, symbol)
+	}
+	if past != nil && monitored {
+		state.PastPrice = past.Price
+		state.ChangePercent = ((current.Price - past.Price) / past.Price) * 100
+		state.HasWindow = true
+	}
+	if t, ok := ae.GetLastAlertTime(symbol); ok {
+		state.LastAlertTime = &t
+	}
+	return state
+}
+
+// GetAllSymbolStates 获取所有交易对的分析状态，按涨跌幅绝对值从大到小排序
+func (ae *AnalysisEngine) GetAllSymbolStates() []*types.SymbolState {
+	symbols := ae.stateManager.GetAllSymbols()
+
+	states := make([]*types.SymbolState, 0, len(symbols))
+	for _, symbol := range symbols {
+		if state := ae.GetSymbolState(symbol); state != nil {
+			states = append(states, state)
+		}
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return math.Abs(states[i].ChangePercent) > math.Abs(states[j].ChangePercent)
+	})
+	return states
+}
+
+// Snapshot 获取全部交易对的即时快照，按交易对名称排序
+func (ae *AnalysisEngine) Snapshot() *types.MarketSnapshot {
+	symbols := ae.stateManager.GetAllSymbols()
+	sort.Strings(symbols)
+
+	snapshot := &types.MarketSnapshot{
+		GeneratedAt: time.Now(),
+		Paused:      ae.GetPauses(),
+		Symbols:     make([]*types.SymbolState, 0, len(symbols)),
+	}
+	for _, symbol := range symbols {
+		if state := ae.GetSymbolState(symbol); state != nil {
+			snapshot.Symbols = append(snapshot.Symbols, state)
+		}
+	}
+	snapshot.Count = len(snapshot.Symbols)
+	return snapshot
+}
+
+// GetStats 获取分析引擎的运行统计
+func (ae *AnalysisEngine) GetStats() map[string]interface{} {
+	ae.recentMutex.RLock()
+	lastAnalysis := ae.lastAnalysis
+	recentCount := len(ae.recentAlerts)
+	ae.recentMutex.RUnlock()
+
+	ae.mutex.RLock()
+	cooldownSymbols := 0
+	for _, history := range ae.alertHistory {
+		cooldownSymbols += len(history)
+	}
+	ae.mutex.RUnlock()
+
+	// threshold/monitor_period 为第一个配置组的设置
+	profiles, _ := ae.settings()
+	stats := map[string]interface{}{
+		"threshold":        profiles[0].Threshold,
+		"monitor_period":   profiles[0].MonitorPeriod.String(),
+		"profiles":         len(profiles),
+		"recent_alerts":    recentCount,
+		"cooldown_symbols": cooldownSymbols,
+		"paused":           ae.GetPauses(),
+		"muted":            ae.GetMutes(),
+	}
+	if !lastAnalysis.IsZero() {
+		stats["last_analysis_time"] = lastAnalysis
+	}
+	return stats
+}
+
+// shouldAlert 检查配置组是否应该发送预警（防止短时间内重复预警）
+func (ae *AnalysisEngine) shouldAlert(profile types.AlertProfile, symbol string) bool {
 	// 如果距离上次预警超过监控周期，则可以再次预警
-	return time.Since(lastAlert) > ae.monitorPeriod
+	return ae.cooledDown(profile.Name, symbol, profile.MonitorPeriod)
+}
+
+// cooledDown 检查配置组或规则距离该交易对上次预警是否已超过冷却期，只读取不占用冷却
+// 本地已过冷却期时再查询Redis，其他实例或重启前已预警过时同样视为冷却中
+// 状态查询也通过这里判断，Redis中的冷却只在预警即将发送时由claimAlerts占用
+func (ae *AnalysisEngine) cooledDown(name, symbol string, cooldown time.Duration) bool {
+	now := ae.now()
+	ae.mutex.RLock()
+	lastAlert, exists := ae.alertHistory[name][symbol]
+	ae.mutex.RUnlock()
+	if exists && now.Sub(lastAlert) <= cooldown {
+		return false
+	}
+
+	active, last := ae.stateManager.PeekCooldown(name, symbol)
+	if active {
+		// 记录其他实例的预警时间，冷却期内不再访问Redis
+		ae.setHistory(name, symbol, last)
+	}
+	return !active
 }
 
-// recordAlert 记录预警历史
-func (ae *AnalysisEngine) recordAlert(symbol string) {
+// claimAlerts 在Redis中占用本轮各预警的冷却，其他实例已抢先占用的预警不再推送和发送
+// 在本轮分析结束、预警即将推送时才占用，未启用Redis时原样返回
+func (ae *AnalysisEngine) claimAlerts(alerts []*types.AlertData, profiles []types.AlertProfile, rules []types.AlertRule) []*types.AlertData {
+	if len(alerts) == 0 {
+		return alerts
+	}
+
+	cooldowns := make(map[string]time.Duration, len(profiles)+len(rules))
+	for _, profile := range profiles {
+		cooldowns[profile.Name] = profile.MonitorPeriod
+	}
+	for _, rule := range rules {
+		cooldowns[rule.Name] = ruleCooldown(rule)
+	}
+
+	claimed := alerts[:0]
+	for _, alert := range alerts {
+		ok, last := ae.stateManager.ClaimCooldown(alert.Profile, alert.Symbol, alert.AlertTime, cooldowns[alert.Profile])
+		if !ok {
+			ae.setHistory(alert.Profile, alert.Symbol, last)
+			log().Debug("其他实例已发送该预警，跳过", zap.String("profile", alert.Profile), zap.String("symbol", alert.Symbol))
+			continue
+		}
+		claimed = append(claimed, alert)
+	}
+	return claimed
+}
+
+// recordAlert 记录配置组的预警历史
+func (ae *AnalysisEngine) recordAlert(profile types.AlertProfile, symbol string) {
+	ae.recordHistory(profile.Name, symbol, profile.MonitorPeriod)
+}
+
+// setHistory 将配置组或规则对交易对的上次预警时间设为at
+func (ae *AnalysisEngine) setHistory(name, symbol string, at time.Time) {
 	ae.mutex.Lock()
 	defer ae.mutex.Unlock()
 
-	ae.alertHistory[symbol] = time.Now()
+	history := ae.alertHistory[name]
+	if history == nil {
+		history = make(map[string]time.Time)
+		ae.alertHistory[name] = history
+	}
+	history[symbol] = at
+}
+
+// recordHistory 记录配置组或规则的预警历史
+func (ae *AnalysisEngine) recordHistory(name, symbol string, cooldown time.Duration) {
+	ae.mutex.Lock()
+	defer ae.mutex.Unlock()
+
+	history := ae.alertHistory[name]
+	if history == nil {
+		history = make(map[string]time.Time)
+		ae.alertHistory[name] = history
+	}
+	now := ae.now()
+	history[symbol] = now
 
-	// 清理超过1小时的预警历史
-	cutoff := time.Now().Add(-1 * time.Hour)
-	for sym, alertTime := range ae.alertHistory {
+	// 清理超过冷却期（至少1小时）的预警历史
+	cutoff := now.Add(-max(time.Hour, cooldown))
+	for sym, alertTime := range history {
 		if alertTime.Before(cutoff) {
-			delete(ae.alertHistory, sym)
+			delete(history, sym)
 		}
 	}
 }
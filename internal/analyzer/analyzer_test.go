@@ -0,0 +1,636 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"okx-market-sentry/internal/notifier"
+	"okx-market-sentry/internal/slo"
+	"okx-market-sentry/internal/storage"
+	"okx-market-sentry/internal/storage/redistest"
+	"okx-market-sentry/pkg/types"
+)
+
+// recordingNotifier 记录发送的预警，err不为nil时发送失败
+type recordingNotifier struct {
+	mutex  sync.Mutex
+	alerts []*types.AlertData
+	err    error
+}
+
+func (n *recordingNotifier) SendAlert(alert *types.AlertData) error {
+	return n.SendBatchAlerts([]*types.AlertData{alert})
+}
+
+func (n *recordingNotifier) SendBatchAlerts(alerts []*types.AlertData) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.err != nil {
+		return n.err
+	}
+	n.alerts = append(n.alerts, alerts...)
+	return nil
+}
+
+func (n *recordingNotifier) SendOpsAlert(*types.OpsAlert) error { return nil }
+
+func (n *recordingNotifier) SendNotice(*types.Notice) error { return nil }
+
+func (n *recordingNotifier) symbols() map[string]bool {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	result := make(map[string]bool)
+	for _, alert := range n.alerts {
+		result[alert.Profile+"/"+alert.Symbol] = true
+	}
+	return result
+}
+
+// newTestEngine 创建纯内存存储的分析引擎，交易对在5分钟内按给定涨幅变化
+func newTestEngine(t testing.TB, notifyService notifier.Interface, alertConfig types.AlertConfig, changes map[string]float64) *AnalysisEngine {
+	t.Helper()
+	stateManager := storage.NewStateManager(types.RedisConfig{}, time.Hour, time.Hour)
+	storeChanges(stateManager, changes)
+	return NewAnalysisEngine(stateManager, notifyService, alertConfig, slo.NewTracker())
+}
+
+// newRedisTestEngine 与newTestEngine相同，但冷却保存在测试Redis中，模拟共享Redis的多个实例
+func newRedisTestEngine(t testing.TB, server *redistest.Server, notifyService notifier.Interface, alertConfig types.AlertConfig, changes map[string]float64) *AnalysisEngine {
+	t.Helper()
+	stateManager := storage.NewStateManager(types.RedisConfig{URL: server.Addr()}, time.Hour, time.Hour)
+	if !stateManager.RedisEnabled() {
+		t.Fatal("连接测试Redis失败")
+	}
+	t.Cleanup(func() { stateManager.Close() })
+	storeChanges(stateManager, changes)
+	return NewAnalysisEngine(stateManager, notifyService, alertConfig, slo.NewTracker())
+}
+
+// storeChanges 写入交易对5分钟前和当前的价格，使其按给定涨幅变化
+func storeChanges(stateManager *storage.StateManager, changes map[string]float64) {
+	now := time.Now()
+	for symbol, change := range changes {
+		stateManager.Store(symbol, 100, now.Add(-5*time.Minute))
+		stateManager.Store(symbol, 100*(1+change/100), now)
+	}
+}
+
+func TestAnalyzeAllProfiles(t *testing.T) {
+	majors, alts := &recordingNotifier{}, &recordingNotifier{}
+	dynamic := notifier.NewDynamicNotifier(&recordingNotifier{})
+	dynamic.SetChannels(map[string]notifier.Interface{"dingtalk": majors, "pushplus": alts})
+
+	alertConfig := types.AlertConfig{
+		Profiles: []types.AlertProfile{
+			{Name: "majors", Symbols: []string{"BTC-USDT", "ETH-USDT"}, Threshold: 1, MonitorPeriod: 5 * time.Minute, Channel: "dingtalk"},
+			{Name: "alts", Threshold: 5, MonitorPeriod: 5 * time.Minute, Channel: "pushplus"},
+		},
+	}
+	engine := newTestEngine(t, dynamic, alertConfig, map[string]float64{
+		"BTC-USDT":  2,  // 仅超过majors阈值
+		"ETH-USDT":  -6, // 同时超过两组阈值
+		"DOGE-USDT": 3,  // 不属于majors，未超过alts阈值
+		"PEPE-USDT": 8,  // 仅alts
+	})
+
+	engine.AnalyzeAll(context.Background())
+
+	wantMajors := map[string]bool{"majors/BTC-USDT": true, "majors/ETH-USDT": true}
+	wantAlts := map[string]bool{"alts/ETH-USDT": true, "alts/PEPE-USDT": true}
+	if got := majors.symbols(); len(got) != len(wantMajors) || !got["majors/BTC-USDT"] || !got["majors/ETH-USDT"] {
+		t.Errorf("majors got %v, want %v", got, wantMajors)
+	}
+	if got := alts.symbols(); len(got) != len(wantAlts) || !got["alts/ETH-USDT"] || !got["alts/PEPE-USDT"] {
+		t.Errorf("alts got %v, want %v", got, wantAlts)
+	}
+
+	// 各配置组的冷却互不影响：majors冷却中不影响alts对同一交易对的判断
+	engine.mutex.Lock()
+	delete(engine.alertHistory["alts"], "ETH-USDT")
+	engine.mutex.Unlock()
+	majors.alerts, alts.alerts = nil, nil
+
+	engine.AnalyzeAll(context.Background())
+	if len(majors.alerts) != 0 {
+		t.Errorf("majors应处于冷却期, got %d alerts", len(majors.alerts))
+	}
+	if got := alts.symbols(); len(got) != 1 || !got["alts/ETH-USDT"] {
+		t.Errorf("alts got %v, want only ETH-USDT", got)
+	}
+}
+
+func TestAnalyzeAllDefaultProfile(t *testing.T) {
+	recorder := &recordingNotifier{}
+	engine := newTestEngine(t, recorder, types.AlertConfig{Threshold: 3, MonitorPeriod: 5 * time.Minute}, map[string]float64{
+		"BTC-USDT": 4,
+		"ETH-USDT": 1,
+	})
+
+	engine.AnalyzeAll(context.Background())
+	if got := recorder.symbols(); len(got) != 1 || !got["default/BTC-USDT"] {
+		t.Errorf("got %v, want only default/BTC-USDT", got)
+	}
+}
+
+func TestAnalyzePartial(t *testing.T) {
+	recorder := &recordingNotifier{}
+	stateManager := storage.NewStateManager(types.RedisConfig{}, time.Hour, time.Hour)
+	now := time.Now()
+	stateManager.Store("BTC-USDT", 100, now.Add(-2*time.Minute))
+	stateManager.Store("BTC-USDT", 105, now)
+	engine := NewAnalysisEngine(stateManager, recorder, types.AlertConfig{Threshold: 3, MonitorPeriod: 15 * time.Minute}, slo.NewTracker())
+
+	// 数据不足15分钟，正常分析不触发
+	engine.AnalyzeAll(context.Background())
+	if len(recorder.alerts) != 0 {
+		t.Fatalf("数据不足时不应预警, got %d", len(recorder.alerts))
+	}
+
+	engine.AnalyzePartial(context.Background())
+	if len(recorder.alerts) != 1 {
+		t.Fatalf("got %d alerts, want 1", len(recorder.alerts))
+	}
+	alert := recorder.alerts[0]
+	if !alert.Partial || alert.MonitorPeriod != 2*time.Minute {
+		t.Errorf("got partial=%v period=%s, want partial=true period=2m", alert.Partial, alert.MonitorPeriod)
+	}
+}
+
+func TestSlowSymbols(t *testing.T) {
+	timings := []symbolTiming{
+		{"ETH-USDT", time.Millisecond},
+		{"BTC-USDT", 2 * time.Millisecond},
+		{"PEPE-USDT", 200 * time.Millisecond},
+		{"DOGE-USDT", time.Millisecond},
+		{"SOL-USDT", 20 * time.Millisecond}, // 远高于中位数但未超过下限
+	}
+
+	slow := slowSymbols(timings)
+	if len(slow) != 1 || slow[0].symbol != "PEPE-USDT" {
+		t.Errorf("got %v, want only PEPE-USDT", slow)
+	}
+	if timings[0].symbol != "PEPE-USDT" {
+		t.Errorf("timings应按耗时从慢到快排序, got first %s", timings[0].symbol)
+	}
+}
+
+func TestUpdateAlertConfig(t *testing.T) {
+	recorder := &recordingNotifier{}
+	engine := newTestEngine(t, recorder, types.AlertConfig{Threshold: 3, MonitorPeriod: 5 * time.Minute}, map[string]float64{
+		"BTC-USDT": 2,
+	})
+
+	engine.AnalyzeAll(context.Background())
+	if len(recorder.alerts) != 0 {
+		t.Fatalf("未超过阈值时不应预警, got %d", len(recorder.alerts))
+	}
+
+	// 热加载降低阈值后下一轮即生效
+	engine.UpdateAlertConfig(types.AlertConfig{Threshold: 1, MonitorPeriod: 5 * time.Minute})
+	engine.AnalyzeAll(context.Background())
+	if got := recorder.symbols(); len(got) != 1 || !got["default/BTC-USDT"] {
+		t.Errorf("got %v, want default/BTC-USDT", got)
+	}
+}
+
+func TestPauseResume(t *testing.T) {
+	recorder := &recordingNotifier{}
+	alertConfig := types.AlertConfig{
+		Profiles: []types.AlertProfile{
+			{Name: "majors", Symbols: []string{"BTC-USDT"}, Threshold: 1, MonitorPeriod: 5 * time.Minute},
+			{Name: "alts", Threshold: 5, MonitorPeriod: 5 * time.Minute},
+		},
+	}
+	engine := newTestEngine(t, recorder, alertConfig, map[string]float64{"BTC-USDT": 2, "PEPE-USDT": 8})
+
+	if err := engine.Pause("unknown", 0); !errors.Is(err, ErrUnknownProfile) {
+		t.Fatalf("got %v, want ErrUnknownProfile", err)
+	}
+
+	// 暂停全部：不预警，也不进入冷却
+	if err := engine.Pause("", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	engine.AnalyzeAll(context.Background())
+	if len(recorder.alerts) != 0 {
+		t.Fatalf("全部暂停时不应预警, got %v", recorder.symbols())
+	}
+
+	// 只暂停majors
+	if err := engine.Resume(""); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Pause("majors", 0); err != nil {
+		t.Fatal(err)
+	}
+	engine.AnalyzeAll(context.Background())
+	if got := recorder.symbols(); len(got) != 1 || !got["alts/PEPE-USDT"] {
+		t.Fatalf("got %v, want only alts/PEPE-USDT", got)
+	}
+
+	// 恢复后majors立即预警
+	if err := engine.Resume("majors"); err != nil {
+		t.Fatal(err)
+	}
+	engine.AnalyzeAll(context.Background())
+	if got := recorder.symbols(); !got["majors/BTC-USDT"] {
+		t.Errorf("恢复后应预警majors/BTC-USDT, got %v", got)
+	}
+	if pauses := engine.GetPauses(); len(pauses) != 0 {
+		t.Errorf("got pauses %v, want none", pauses)
+	}
+}
+
+func TestMuteUnmute(t *testing.T) {
+	recorder := &recordingNotifier{}
+	alertConfig := types.AlertConfig{
+		Profiles: []types.AlertProfile{{Name: "default", Threshold: 1, MonitorPeriod: 5 * time.Minute}},
+	}
+	engine := newTestEngine(t, recorder, alertConfig, map[string]float64{"BTC-USDT": 2, "ETH-USDT": -3})
+
+	// 静音的交易对不预警，也不进入冷却
+	engine.Mute("BTC-USDT", time.Hour)
+	engine.AnalyzeAll(context.Background())
+	if got := recorder.symbols(); len(got) != 1 || !got["default/ETH-USDT"] {
+		t.Fatalf("got %v, want only default/ETH-USDT", got)
+	}
+	if mutes := engine.GetMutes(); mutes["BTC-USDT"] == nil {
+		t.Fatalf("got mutes %v, want BTC-USDT with expiry", mutes)
+	}
+
+	engine.Unmute("")
+	engine.AnalyzeAll(context.Background())
+	if got := recorder.symbols(); !got["default/BTC-USDT"] {
+		t.Errorf("取消静音后应预警default/BTC-USDT, got %v", got)
+	}
+	if mutes := engine.GetMutes(); len(mutes) != 0 {
+		t.Errorf("got mutes %v, want none", mutes)
+	}
+}
+
+// fixedEvents 固定返回同一事件的经济日历
+type fixedEvents struct{ event *types.CalendarEvent }
+
+func (f fixedEvents) Nearby(time.Time) *types.CalendarEvent { return f.event }
+
+func TestAnalyzeNearEvent(t *testing.T) {
+	recorder := &recordingNotifier{}
+	engine := newTestEngine(t, recorder, types.AlertConfig{Threshold: 3, MonitorPeriod: 5 * time.Minute}, map[string]float64{
+		"BTC-USDT": 4,
+		"ETH-USDT": 8,
+	})
+	cpi := &types.CalendarEvent{Title: "CPI m/m", Country: "USD", Time: time.Now(), Impact: "high"}
+	engine.SetEventSource(fixedEvents{cpi}, 2)
+
+	// 阈值临时提高到6%，只有ETH触发，且预警附带事件
+	engine.AnalyzeAll(context.Background())
+	if len(recorder.alerts) != 1 || recorder.alerts[0].Symbol != "ETH-USDT" {
+		t.Fatalf("got %v, want only ETH-USDT", recorder.symbols())
+	}
+	if event := recorder.alerts[0].Event; event == nil || event.Title != "CPI m/m" {
+		t.Errorf("got event %v, want CPI m/m", event)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	recorder := &recordingNotifier{}
+	engine := newTestEngine(t, recorder, types.AlertConfig{Threshold: 3, MonitorPeriod: 5 * time.Minute}, map[string]float64{
+		"PEPE-USDT": 8,
+		"BTC-USDT":  1,
+	})
+	engine.AnalyzeAll(context.Background())
+
+	snapshot := engine.Snapshot()
+	if snapshot.Count != 2 || snapshot.Symbols[0].Symbol != "BTC-USDT" || snapshot.Symbols[1].Symbol != "PEPE-USDT" {
+		t.Fatalf("got %+v, want BTC-USDT, PEPE-USDT sorted by name", snapshot.Symbols)
+	}
+	if snapshot.Symbols[0].Alerting || !snapshot.Symbols[1].Alerting {
+		t.Errorf("got alerting BTC=%v PEPE=%v, want false/true", snapshot.Symbols[0].Alerting, snapshot.Symbols[1].Alerting)
+	}
+}
+
+func TestDigest(t *testing.T) {
+	engine := newTestEngine(t, &recordingNotifier{}, types.AlertConfig{Threshold: 3, MonitorPeriod: 5 * time.Minute}, map[string]float64{
+		"PEPE-USDT": 8,
+		"BTC-USDT":  1,
+		"SOL-USDT":  -4,
+		"DOGE-USDT": -0.5,
+	})
+
+	digest := engine.Digest(5*time.Minute, 0)
+	var symbols []string
+	for _, cell := range digest.Cells {
+		symbols = append(symbols, cell.Symbol)
+	}
+	if fmt.Sprint(symbols) != "[PEPE-USDT BTC-USDT DOGE-USDT SOL-USDT]" {
+		t.Errorf("got %v, want sorted by change descending", symbols)
+	}
+	if digest.Total != 4 || digest.Up != 2 || digest.Down != 2 {
+		t.Errorf("got total=%d up=%d down=%d, want 4/2/2", digest.Total, digest.Up, digest.Down)
+	}
+
+	// 超出上限时只保留波动最大的，统计仍包含全部交易对
+	digest = engine.Digest(5*time.Minute, 2)
+	if len(digest.Cells) != 2 || digest.Cells[0].Symbol != "PEPE-USDT" || digest.Cells[1].Symbol != "SOL-USDT" || digest.Total != 4 {
+		t.Errorf("got %+v, want PEPE-USDT, SOL-USDT of 4", digest)
+	}
+
+	// 数据不足一个统计周期的交易对不计入
+	if digest := engine.Digest(time.Hour, 0); digest.Total != 0 || len(digest.Cells) != 0 {
+		t.Errorf("got %+v, want empty digest", digest)
+	}
+}
+
+func TestAnalyzeTrailing(t *testing.T) {
+	recorder := &recordingNotifier{}
+	stateManager := storage.NewStateManager(types.RedisConfig{}, time.Hour, time.Hour)
+	now := time.Now()
+	prices := map[string][]float64{
+		"PEPE-USDT": {100, 106, 103, 101}, // 先涨6%后回落，首尾只差1%
+		"BTC-USDT":  {100, 101, 102, 102.5},
+	}
+	for symbol, series := range prices {
+		for i, price := range series {
+			stateManager.Store(symbol, price, now.Add(time.Duration(i-len(series)+1)*time.Minute))
+		}
+	}
+	engine := NewAnalysisEngine(stateManager, recorder, types.AlertConfig{Threshold: 3, Trailing: 4, MonitorPeriod: 5 * time.Minute}, slo.NewTracker())
+
+	engine.AnalyzeAll(context.Background())
+	if len(recorder.alerts) != 1 {
+		t.Fatalf("got %v, want only PEPE-USDT", recorder.symbols())
+	}
+	alert := recorder.alerts[0]
+	if alert.Symbol != "PEPE-USDT" || alert.Kind != types.AlertKindDrawdown || alert.PastPrice != 106 {
+		t.Errorf("got %s kind=%q past=%v, want PEPE-USDT drawdown from 106", alert.Symbol, alert.Kind, alert.PastPrice)
+	}
+}
+
+func TestAnalyzeAcceleration(t *testing.T) {
+	recorder := &recordingNotifier{}
+	stateManager := storage.NewStateManager(types.RedisConfig{}, time.Hour, time.Hour)
+	now := time.Now()
+	prices := map[string][]float64{
+		"PEPE-USDT": {100, 100.2, 100.4, 101.6}, // 近2分钟明显加速
+		"BTC-USDT":  {100, 100.5, 101, 101.5},   // 匀速上涨
+	}
+	for symbol, series := range prices {
+		for i, price := range series {
+			stateManager.Store(symbol, price, now.Add(time.Duration(i-len(series)+1)*2*time.Minute))
+		}
+	}
+	engine := NewAnalysisEngine(stateManager, recorder, types.AlertConfig{Threshold: 3, Acceleration: 3, MonitorPeriod: 6 * time.Minute}, slo.NewTracker())
+
+	engine.AnalyzeAll(context.Background())
+	if len(recorder.alerts) != 1 {
+		t.Fatalf("got %v, want only PEPE-USDT", recorder.symbols())
+	}
+	alert := recorder.alerts[0]
+	if alert.Symbol != "PEPE-USDT" || alert.Kind != types.AlertKindAcceleration || alert.Momentum == nil || !alert.Momentum.Accelerating {
+		t.Fatalf("got %s kind=%q momentum=%+v, want PEPE-USDT acceleration", alert.Symbol, alert.Kind, alert.Momentum)
+	}
+	if alert.MonitorPeriod != 2*time.Minute || alert.Momentum.Ratio < 3 {
+		t.Errorf("got period=%s ratio=%.2f, want 2m and ratio >= 3", alert.MonitorPeriod, alert.Momentum.Ratio)
+	}
+}
+
+func TestAnalyzeRule(t *testing.T) {
+	recorder := &recordingNotifier{}
+	stateManager := storage.NewStateManager(types.RedisConfig{}, time.Hour, time.Hour)
+	now := time.Now()
+	volumes := map[string][2]float64{
+		"PEPE-USDT": {100000, 101000}, // 5分钟成交1000 USDT，约为24h平均水平的4倍
+		"BTC-USDT":  {100000, 100000},
+	}
+	for symbol, quote := range volumes {
+		stateManager.Store(symbol, 100, now.Add(-5*time.Minute))
+		stateManager.Store(symbol, 104, now)
+		stateManager.StoreTicker24h(symbol, types.Ticker24h{Open: 100, High: 105, Low: 99, Volume: 1000, QuoteVolume: quote[0]}, now.Add(-5*time.Minute))
+		stateManager.StoreTicker24h(symbol, types.Ticker24h{Open: 100, High: 105, Low: 99, Volume: 1000, QuoteVolume: quote[1]}, now)
+	}
+	alertConfig := types.AlertConfig{
+		Threshold:     10,
+		MonitorPeriod: 5 * time.Minute,
+		Rules: []types.AlertRule{{
+			Name:   "pump_with_volume",
+			Period: 5 * time.Minute,
+			Conditions: []types.RuleCondition{
+				{Metric: "change", Op: ">=", Value: 3},
+				{Metric: "volume_ratio", Op: ">=", Value: 2},
+				{Metric: "vwap_distance", Op: ">", Value: 0},
+			},
+		}},
+	}
+	engine := NewAnalysisEngine(stateManager, recorder, alertConfig, slo.NewTracker())
+
+	engine.AnalyzeAll(context.Background())
+	if len(recorder.alerts) != 1 {
+		t.Fatalf("got %v, want only PEPE-USDT", recorder.symbols())
+	}
+	alert := recorder.alerts[0]
+	if alert.Symbol != "PEPE-USDT" || alert.Kind != types.AlertKindRule || alert.Profile != "pump_with_volume" || len(alert.Conditions) != 3 {
+		t.Fatalf("got %s kind=%q profile=%q conditions=%v", alert.Symbol, alert.Kind, alert.Profile, alert.Conditions)
+	}
+
+	// 冷却期内不重复预警
+	engine.AnalyzeAll(context.Background())
+	if len(recorder.alerts) != 1 {
+		t.Errorf("got %d alerts after second run, want 1 (cooldown)", len(recorder.alerts))
+	}
+}
+
+func TestAnalyzeTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	engine := newTestEngine(t, &recordingNotifier{}, types.AlertConfig{Threshold: 1, MonitorPeriod: 5 * time.Minute}, map[string]float64{
+		"BTC-USDT": 2,
+		"ETH-USDT": 0.5,
+	})
+	engine.AnalyzeAll(context.Background())
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	analyze, notify := spans["analyzer.analyze_all"], spans["notifier.send"]
+	if analyze == nil || notify == nil {
+		t.Fatalf("spans = %v", spans)
+	}
+	// 通知是分析的子span，同一条链路可以算出从分析到通知的耗时
+	if notify.Parent().SpanID() != analyze.SpanContext().SpanID() {
+		t.Errorf("notifier.send 的父span不是 analyzer.analyze_all")
+	}
+	attributes := make(map[string]int64)
+	for _, kv := range append(analyze.Attributes(), notify.Attributes()...) {
+		attributes[string(kv.Key)] = kv.Value.AsInt64()
+	}
+	if attributes["symbols"] != 2 || attributes["alert_count"] != 1 {
+		t.Errorf("attributes = %v", attributes)
+	}
+	if _, ok := attributes["max_price_to_notify_ms"]; !ok {
+		t.Errorf("缺少 max_price_to_notify_ms: %v", attributes)
+	}
+}
+
+func TestDecisionLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "decisions.jsonl")
+	engine := newTestEngine(t, &recordingNotifier{}, types.AlertConfig{
+		Threshold:     1,
+		MonitorPeriod: 5 * time.Minute,
+		DecisionLog:   types.DecisionLogConfig{Enabled: true, FilePath: path, NearRatio: 0.8},
+	}, map[string]float64{
+		"BTC-USDT": 2,    // 超过阈值
+		"ETH-USDT": -0.9, // 达到阈值的80%
+		"SOL-USDT": 0.5,  // 不记录
+	})
+
+	// 第二轮BTC-USDT处于冷却期
+	engine.AnalyzeAll(context.Background())
+	engine.AnalyzeAll(context.Background())
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type decision struct {
+		Symbol     string  `json:"symbol"`
+		Change     float64 `json:"change_percent"`
+		Ratio      float64 `json:"threshold_ratio"`
+		Exceeded   bool    `json:"exceeded"`
+		Suppressed bool    `json:"suppressed_by_cooldown"`
+	}
+	got := make(map[string][]decision)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var d decision
+		if err := json.Unmarshal([]byte(line), &d); err != nil {
+			t.Fatalf("解析决策日志 %q: %v", line, err)
+		}
+		got[d.Symbol] = append(got[d.Symbol], d)
+	}
+
+	if btc := got["BTC-USDT"]; len(btc) != 2 || !btc[0].Exceeded || btc[0].Suppressed || !btc[1].Suppressed {
+		t.Errorf("BTC-USDT = %+v", btc)
+	}
+	if eth := got["ETH-USDT"]; len(eth) != 2 || eth[0].Exceeded || math.Abs(eth[0].Ratio-0.9) > 1e-9 {
+		t.Errorf("ETH-USDT = %+v", eth)
+	}
+	if _, ok := got["SOL-USDT"]; ok {
+		t.Errorf("未接近阈值的交易对不应记录: %+v", got["SOL-USDT"])
+	}
+}
+
+// BenchmarkAnalyzeAll 200个交易对的一轮分析，其中10个触发预警（冷却期后继续计算）
+func TestSharedCooldown(t *testing.T) {
+	server := redistest.NewServer(t)
+	alertConfig := types.AlertConfig{Threshold: 1, MonitorPeriod: 5 * time.Minute}
+	changes := map[string]float64{"BTC-USDT": 2}
+	recorders := []*recordingNotifier{{}, {}}
+	engines := []*AnalysisEngine{
+		newRedisTestEngine(t, server, recorders[0], alertConfig, changes),
+		newRedisTestEngine(t, server, recorders[1], alertConfig, changes),
+	}
+	const key = "okx:cooldown:default:BTC-USDT"
+
+	// 状态查询只读取冷却，不占用，不影响之后的预警
+	for _, engine := range engines {
+		if states := engine.GetAllSymbolStates(); len(states) != 1 || states[0].Alerting {
+			t.Fatalf("states = %+v", states)
+		}
+		engine.GetSymbolState("BTC-USDT")
+	}
+	if server.Exists(key) {
+		t.Fatal("状态查询占用了预警冷却")
+	}
+
+	// 两个实例同时分析，只有占用到冷却的一个发送
+	var wg sync.WaitGroup
+	for _, engine := range engines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			engine.AnalyzeAll(context.Background())
+		}()
+	}
+	wg.Wait()
+	if total := len(recorders[0].alerts) + len(recorders[1].alerts); total != 1 || !server.Exists(key) {
+		t.Fatalf("发送了%d条预警，冷却存在=%v，want 1条", total, server.Exists(key))
+	}
+
+	// 之后两个实例都处于冷却中
+	for i, engine := range engines {
+		engine.AnalyzeAll(context.Background())
+		if state := engine.GetSymbolState("BTC-USDT"); state == nil || !state.Alerting {
+			t.Errorf("实例%d 状态 = %+v, want 冷却中", i, state)
+		}
+	}
+	if total := len(recorders[0].alerts) + len(recorders[1].alerts); total != 1 {
+		t.Errorf("冷却期内发送了%d条预警, want 1", total)
+	}
+}
+
+func TestSharedCooldownHeldByOtherReplica(t *testing.T) {
+	server := redistest.NewServer(t)
+	// 其他实例1分钟前已预警，冷却还剩4分钟
+	server.Set("okx:cooldown:default:BTC-USDT", strconv.FormatInt(time.Now().Add(-time.Minute).UnixMilli(), 10), 4*time.Minute)
+
+	recorder := &recordingNotifier{}
+	engine := newRedisTestEngine(t, server, recorder, types.AlertConfig{Threshold: 1, MonitorPeriod: 5 * time.Minute},
+		map[string]float64{"BTC-USDT": 2, "ETH-USDT": 3})
+	engine.AnalyzeAll(context.Background())
+	if got := recorder.symbols(); len(got) != 1 || !got["default/ETH-USDT"] {
+		t.Errorf("got %v, want only ETH-USDT", got)
+	}
+}
+
+func TestCooldownReleasedOnFailure(t *testing.T) {
+	server := redistest.NewServer(t)
+	alertConfig := types.AlertConfig{Threshold: 1, MonitorPeriod: 5 * time.Minute}
+	changes := map[string]float64{"BTC-USDT": 2}
+
+	failing := newRedisTestEngine(t, server, &recordingNotifier{err: errors.New("网络错误")}, alertConfig, changes)
+	failing.AnalyzeAll(context.Background())
+	if server.Exists("okx:cooldown:default:BTC-USDT") {
+		t.Fatal("发送失败后未释放预警冷却")
+	}
+
+	// 其他实例可以重新发送
+	recorder := &recordingNotifier{}
+	newRedisTestEngine(t, server, recorder, alertConfig, changes).AnalyzeAll(context.Background())
+	if got := recorder.symbols(); len(got) != 1 || !got["default/BTC-USDT"] {
+		t.Errorf("got %v, want BTC-USDT", got)
+	}
+}
+
+func BenchmarkAnalyzeAll(b *testing.B) {
+	changes := make(map[string]float64, 200)
+	for i := 0; i < 200; i++ {
+		change := float64(i%30) / 10
+		if i%20 == 0 {
+			change = 5
+		}
+		changes[fmt.Sprintf("SYM%d-USDT", i)] = change
+	}
+	engine := newTestEngine(b, &recordingNotifier{}, types.AlertConfig{Threshold: 3, MonitorPeriod: 5 * time.Minute, Workers: 8}, changes)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		engine.AnalyzeAll(ctx)
+	}
+}
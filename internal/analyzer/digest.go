@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// Digest 汇总各交易对在window内的涨跌幅，生成热力图摘要；limit大于0时只保留涨跌幅绝对值最大的前limit个
+// 启用分片时只汇总本实例负责的交易对，价格数据不足一个统计周期的交易对不计入
+func (ae *AnalysisEngine) Digest(window time.Duration, limit int) *types.Digest {
+	digest := &types.Digest{Window: window, Time: time.Now()}
+	for _, symbol := range ae.stateManager.GetAllSymbols() {
+		if ae.shard != nil && !ae.shard.Owns(symbol) {
+			continue
+		}
+		current, past := ae.stateManager.GetPriceData(symbol, window)
+		if current == nil || past == nil || past.Price == 0 {
+			continue
+		}
+
+		change := (current.Price - past.Price) / past.Price * 100
+		switch {
+		case change > 0:
+			digest.Up++
+		case change < 0:
+			digest.Down++
+		}
+		digest.Cells = append(digest.Cells, types.DigestCell{
+			Symbol:        symbol,
+			Name:          ae.symbolName(symbol),
+			Price:         current.Price,
+			TickSize:      ae.tickSize(symbol),
+			ChangePercent: change,
+		})
+	}
+	digest.Total = len(digest.Cells)
+
+	// 超出展示上限时保留波动最大的交易对
+	if limit > 0 && len(digest.Cells) > limit {
+		slices.SortFunc(digest.Cells, func(a, b types.DigestCell) int {
+			return cmp.Compare(math.Abs(b.ChangePercent), math.Abs(a.ChangePercent))
+		})
+		digest.Cells = digest.Cells[:limit]
+	}
+
+	// 网格按涨跌幅从高到低排列，涨跌幅相同时按交易对名称
+	slices.SortFunc(digest.Cells, func(a, b types.DigestCell) int {
+		return cmp.Or(cmp.Compare(b.ChangePercent, a.ChangePercent), cmp.Compare(a.Symbol, b.Symbol))
+	})
+	return digest
+}
@@ -0,0 +1,58 @@
+package analyzer
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Mute 静音交易对的预警，duration>0时到期自动恢复
+// 静音期间行情照常获取，该交易对不做判断、不发送通知，也不进入冷却
+func (ae *AnalysisEngine) Mute(symbol string, duration time.Duration) {
+	var until time.Time
+	if duration > 0 {
+		until = time.Now().Add(duration)
+	}
+	ae.mutesMutex.Lock()
+	ae.mutes[symbol] = until
+	ae.mutesMutex.Unlock()
+
+	log().Warn("🔇 交易对预警已静音",
+		zap.String("symbol", symbol),
+		zap.Duration("duration", duration))
+}
+
+// Unmute 取消交易对的静音，symbol为空时取消全部
+func (ae *AnalysisEngine) Unmute(symbol string) {
+	ae.mutesMutex.Lock()
+	if symbol == "" {
+		clear(ae.mutes)
+	} else {
+		delete(ae.mutes, symbol)
+	}
+	ae.mutesMutex.Unlock()
+
+	log().Info("🔔 交易对预警已取消静音", zap.String("symbol", pauseTarget(symbol)))
+}
+
+// GetMutes 当前静音的交易对及自动恢复时间（nil为直到手动取消），已到期的静音会被清理
+func (ae *AnalysisEngine) GetMutes() map[string]*time.Time {
+	ae.mutesMutex.Lock()
+	defer ae.mutesMutex.Unlock()
+
+	now := time.Now()
+	result := make(map[string]*time.Time, len(ae.mutes))
+	for symbol, until := range ae.mutes {
+		if !until.IsZero() && now.After(until) {
+			delete(ae.mutes, symbol)
+			log().Info("🔔 交易对静音已到期，自动恢复", zap.String("symbol", symbol))
+			continue
+		}
+		if until.IsZero() {
+			result[symbol] = nil
+		} else {
+			result[symbol] = &until
+		}
+	}
+	return result
+}
@@ -0,0 +1,113 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// analyzeRule 按组合条件规则分析单个交易对，全部条件满足且不在冷却期时返回预警
+func (ae *AnalysisEngine) analyzeRule(rule types.AlertRule, symbol string, event *types.CalendarEvent) *types.AlertData {
+	current, past := ae.stateManager.GetPriceData(symbol, rule.Period)
+	if current == nil || past == nil {
+		return nil // 数据不足，跳过分析
+	}
+	changePercent := (current.Price - past.Price) / past.Price * 100
+
+	matched := make([]string, 0, len(rule.Conditions))
+	for _, condition := range rule.Conditions {
+		value, ok := ae.ruleMetric(condition.Metric, symbol, rule.Period, current.Price, changePercent)
+		if !ok || !compare(value, condition.Op, condition.Value) {
+			return nil
+		}
+		matched = append(matched, fmt.Sprintf("%s %.2f %s %v", condition.Metric, value, condition.Op, condition.Value))
+	}
+
+	cooldown := ruleCooldown(rule)
+	if !ae.cooledDown(rule.Name, symbol, cooldown) {
+		return nil
+	}
+
+	alert := &types.AlertData{
+		Symbol:        symbol,
+		Name:          ae.symbolName(symbol),
+		TickSize:      ae.tickSize(symbol),
+		CurrentPrice:  current.Price,
+		PastPrice:     past.Price,
+		ChangePercent: changePercent,
+		AlertTime:     ae.now(),
+		MonitorPeriod: rule.Period,
+		PriceTime:     current.Timestamp,
+		Profile:       rule.Name,
+		Kind:          types.AlertKindRule,
+		Conditions:    matched,
+		Event:         event,
+		Stats24h:      ae.stateManager.GetTicker24h(symbol),
+		Correlation:   ae.CalculateCorrelation(symbol),
+		Style:         ae.alertStyle(rule.Title, rule.Emoji, rule.Template),
+	}
+	ae.recordHistory(rule.Name, symbol, cooldown)
+	return alert
+}
+
+// ruleCooldown 规则的冷却期，未配置时等于规则的时间窗口
+func ruleCooldown(rule types.AlertRule) time.Duration {
+	if rule.Cooldown > 0 {
+		return rule.Cooldown
+	}
+	return rule.Period
+}
+
+// ruleMetric 计算规则条件的指标值，依赖的数据尚未获取到时返回false
+func (ae *AnalysisEngine) ruleMetric(metric, symbol string, period time.Duration, price, changePercent float64) (float64, bool) {
+	switch metric {
+	case "change":
+		return changePercent, true
+	case "abs_change":
+		return math.Abs(changePercent), true
+	}
+
+	stats := ae.stateManager.GetTicker24h(symbol)
+	if stats == nil {
+		return 0, false
+	}
+	switch metric {
+	case "change_24h":
+		return (price - stats.Open) / stats.Open * 100, true
+	case "volume_24h":
+		return stats.QuoteVolume, true
+	case "vwap_distance":
+		// 24h成交均价 = 成交额 / 成交量
+		if stats.Volume <= 0 || stats.QuoteVolume <= 0 {
+			return 0, false
+		}
+		vwap := stats.QuoteVolume / stats.Volume
+		return (price - vwap) / vwap * 100, true
+	case "volume_ratio":
+		// 窗口成交额相对24h平均水平（24h成交额按窗口时长折算）的倍数
+		volume, ok := ae.stateManager.GetWindowVolume(symbol, period)
+		average := stats.QuoteVolume * float64(period) / float64(24*time.Hour)
+		if !ok || average <= 0 {
+			return 0, false
+		}
+		return volume / average, true
+	}
+	return 0, false
+}
+
+// compare 按比较符比较指标值与条件数值
+func compare(value float64, op string, target float64) bool {
+	switch op {
+	case ">":
+		return value > target
+	case ">=":
+		return value >= target
+	case "<":
+		return value < target
+	case "<=":
+		return value <= target
+	}
+	return false
+}
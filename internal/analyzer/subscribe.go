@@ -0,0 +1,47 @@
+package analyzer
+
+import (
+	"go.uber.org/zap"
+	"okx-market-sentry/pkg/types"
+)
+
+// Subscribe 订阅新触发的预警，供gRPC等接口实时推送，buffer为通道缓冲
+// 订阅者处理不及时、缓冲已满时丢弃该预警，不阻塞分析；返回的取消函数移除订阅并关闭通道
+func (ae *AnalysisEngine) Subscribe(buffer int) (<-chan *types.AlertData, func()) {
+	ch := make(chan *types.AlertData, buffer)
+	ae.subscribersMutex.Lock()
+	if ae.subscribers == nil {
+		ae.subscribers = make(map[chan *types.AlertData]struct{})
+	}
+	ae.subscribers[ch] = struct{}{}
+	ae.subscribersMutex.Unlock()
+
+	return ch, func() {
+		ae.subscribersMutex.Lock()
+		defer ae.subscribersMutex.Unlock()
+		if _, ok := ae.subscribers[ch]; ok {
+			delete(ae.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// publish 将本轮预警推送给所有订阅者
+func (ae *AnalysisEngine) publish(alerts []*types.AlertData) {
+	ae.subscribersMutex.Lock()
+	defer ae.subscribersMutex.Unlock()
+
+	for ch := range ae.subscribers {
+		dropped := 0
+		for _, alert := range alerts {
+			select {
+			case ch <- alert:
+			default:
+				dropped++
+			}
+		}
+		if dropped > 0 {
+			log().Warn("⚠️ 预警订阅者处理不及时，已丢弃部分预警", zap.Int("dropped", dropped))
+		}
+	}
+}
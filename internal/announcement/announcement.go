@@ -0,0 +1,250 @@
+package announcement
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.uber.org/zap"
+	"okx-market-sentry/internal/notifier"
+	"okx-market-sentry/internal/storage"
+	"okx-market-sentry/pkg/logger"
+	"okx-market-sentry/pkg/types"
+)
+
+// log 模块日志器，级别可通过 log.modules.announcement 单独配置
+func log() *zap.Logger {
+	return logger.Named("announcement")
+}
+
+// 公告分类，announcement.types 中配置
+const (
+	CategoryMaintenance = "maintenance" // 系统维护计划
+	CategoryNewListing  = "new_listing" // 上新币种
+	CategoryDelisting   = "delisting"   // 下架币种
+)
+
+// annTypes 公告分类对应的OKX公告类型
+var annTypes = map[string]string{
+	CategoryNewListing: "announcements-new-listings",
+	CategoryDelisting:  "announcements-delistings",
+}
+
+// categoryNames 通知标题中的分类名称
+var categoryNames = map[string]string{
+	CategoryMaintenance: "OKX维护",
+	CategoryNewListing:  "OKX上新",
+	CategoryDelisting:   "OKX下架",
+}
+
+const defaultBaseURL = "https://www.okx.com"
+
+// Watcher 轮询OKX系统状态和公告，将维护计划、上新和下架公告通过通知渠道推送
+type Watcher struct {
+	config       types.AnnouncementConfig
+	httpClient   *http.Client
+	baseURL      string
+	stateManager *storage.StateManager
+	notifier     notifier.Interface
+
+	startTime time.Time
+	seen      map[string]bool // 已通知的公告，仅在轮询goroutine中访问
+}
+
+func NewWatcher(config types.AnnouncementConfig, httpClient *http.Client, stateManager *storage.StateManager, notifyService notifier.Interface) *Watcher {
+	return &Watcher{
+		config:       config,
+		httpClient:   httpClient,
+		baseURL:      defaultBaseURL,
+		stateManager: stateManager,
+		notifier:     notifyService,
+		startTime:    time.Now(),
+		seen:         make(map[string]bool),
+	}
+}
+
+// Start 按轮询间隔检查公告，直到ctx取消
+func (w *Watcher) Start(ctx context.Context) {
+	log().Info("📢 OKX公告监控已启动",
+		zap.Strings("types", w.config.Types),
+		zap.Duration("poll_interval", w.config.PollInterval))
+
+	ticker := time.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+
+	w.poll(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			log().Info("📴 OKX公告监控已停止")
+			return
+		case <-ticker.C:
+			w.poll(ctx)
+		}
+	}
+}
+
+// poll 拉取各类公告，逐条发送尚未通知过的公告
+func (w *Watcher) poll(ctx context.Context) {
+	for _, category := range w.config.Types {
+		var notices []*types.Notice
+		var err error
+		if category == CategoryMaintenance {
+			notices, err = w.fetchMaintenance(ctx)
+		} else {
+			notices, err = w.fetchAnnouncements(ctx, category)
+		}
+		if err != nil {
+			log().Warn("⚠️ 获取OKX公告失败", zap.String("category", category), zap.Error(err))
+			continue
+		}
+
+		for _, notice := range notices {
+			key := notice.Category + "|" + notice.Title + "|" + notice.URL
+			if w.seen[key] {
+				continue
+			}
+			w.seen[key] = true
+			if err := w.notifier.SendNotice(notice); err != nil {
+				log().Error("发送OKX公告通知失败", zap.String("title", notice.Title), zap.Error(err))
+			}
+		}
+	}
+}
+
+// fetchMaintenance 获取计划中和进行中的系统维护
+func (w *Watcher) fetchMaintenance(ctx context.Context) ([]*types.Notice, error) {
+	var data []struct {
+		Title       string `json:"title"`
+		State       string `json:"state"`
+		Begin       string `json:"begin"`
+		End         string `json:"end"`
+		Href        string `json:"href"`
+		ServiceType string `json:"serviceType"`
+		ScheDesc    string `json:"scheDesc"`
+	}
+	if err := w.get(ctx, "/api/v5/system/status", &data); err != nil {
+		return nil, err
+	}
+
+	var notices []*types.Notice
+	for _, item := range data {
+		if item.State != "scheduled" && item.State != "ongoing" {
+			continue
+		}
+		begin, end := parseMillis(item.Begin), parseMillis(item.End)
+		message := fmt.Sprintf("%s ~ %s", begin.Format("01-02 15:04"), end.Format("01-02 15:04"))
+		if item.State == "ongoing" {
+			message = "维护进行中，" + message
+		}
+		if item.ScheDesc != "" {
+			message += "，" + item.ScheDesc
+		}
+		notices = append(notices, &types.Notice{
+			Source:   "okx",
+			Category: CategoryMaintenance,
+			Title:    categoryNames[CategoryMaintenance] + ": " + item.Title,
+			Message:  message,
+			URL:      item.Href,
+			Time:     begin,
+		})
+	}
+	return notices, nil
+}
+
+// fetchAnnouncements 获取上新/下架公告第一页中，服务启动后发布的公告
+// 启动前发布的公告视为已知，避免每次重启重复推送
+func (w *Watcher) fetchAnnouncements(ctx context.Context, category string) ([]*types.Notice, error) {
+	var data []struct {
+		Details []struct {
+			Title string `json:"title"`
+			URL   string `json:"url"`
+			PTime string `json:"pTime"`
+		} `json:"details"`
+	}
+	if err := w.get(ctx, "/api/v5/support/announcements?annType="+annTypes[category], &data); err != nil {
+		return nil, err
+	}
+
+	symbols := w.stateManager.GetAllSymbols()
+	var notices []*types.Notice
+	for _, page := range data {
+		for _, item := range page.Details {
+			published := parseMillis(item.PTime)
+			if published.Before(w.startTime) {
+				continue
+			}
+			notices = append(notices, &types.Notice{
+				Source:   "okx",
+				Category: category,
+				Title:    categoryNames[category] + ": " + item.Title,
+				URL:      item.URL,
+				Symbols:  MatchSymbols(item.Title, symbols),
+				Time:     published,
+			})
+		}
+	}
+	return notices, nil
+}
+
+// get 请求OKX公开接口并解析data字段
+func (w *Watcher) get(ctx context.Context, path string, data interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP状态码错误: %d", resp.StatusCode)
+	}
+
+	var apiResp struct {
+		Code string          `json:"code"`
+		Msg  string          `json:"msg"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return fmt.Errorf("解析API响应失败: %w", err)
+	}
+	if apiResp.Code != "0" {
+		return fmt.Errorf("API返回错误: %s - %s", apiResp.Code, apiResp.Msg)
+	}
+	return json.Unmarshal(apiResp.Data, data)
+}
+
+// MatchSymbols 返回公告标题中提到的被监控交易对，按标题中的币种名称匹配交易对的基础币种
+func MatchSymbols(title string, symbols []string) []string {
+	words := strings.FieldsFunc(strings.ToUpper(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var matched []string
+	for _, symbol := range symbols {
+		base, _, _ := strings.Cut(symbol, "-")
+		if slices.Contains(words, base) {
+			matched = append(matched, symbol)
+		}
+	}
+	slices.Sort(matched)
+	return matched
+}
+
+// parseMillis 解析OKX接口中的毫秒时间戳字符串，无效时返回零值
+func parseMillis(s string) time.Time {
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
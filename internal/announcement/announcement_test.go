@@ -0,0 +1,89 @@
+package announcement
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"okx-market-sentry/internal/storage"
+	"okx-market-sentry/pkg/types"
+)
+
+// recordingNotifier 记录发送的资讯通知
+type recordingNotifier struct {
+	notices []*types.Notice
+}
+
+func (n *recordingNotifier) SendAlert(*types.AlertData) error         { return nil }
+func (n *recordingNotifier) SendBatchAlerts([]*types.AlertData) error { return nil }
+func (n *recordingNotifier) SendOpsAlert(*types.OpsAlert) error       { return nil }
+func (n *recordingNotifier) SendNotice(notice *types.Notice) error {
+	n.notices = append(n.notices, notice)
+	return nil
+}
+
+func TestMatchSymbols(t *testing.T) {
+	symbols := []string{"BTC-USDT", "ETH-USDT", "OM-USDT", "MOVE-USDT"}
+	got := MatchSymbols("OKX to delist OM, MOVE spot trading pairs", symbols)
+	if want := []string{"MOVE-USDT", "OM-USDT"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	// 只按完整币种名称匹配，不匹配单词中的片段
+	if got := MatchSymbols("OKX will list MOMENT", symbols); len(got) != 0 {
+		t.Errorf("got %v, want none", got)
+	}
+}
+
+func TestWatcherPoll(t *testing.T) {
+	start := time.Now()
+	oldAnn := start.Add(-time.Hour).UnixMilli()
+	newAnn := start.Add(time.Minute).UnixMilli()
+	begin := start.Add(24 * time.Hour).UnixMilli()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v5/system/status", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"code":"0","data":[
+			{"title":"Spot system upgrade","state":"scheduled","begin":"%d","end":"%d","href":"https://okx.com/m1"},
+			{"title":"Finished upgrade","state":"completed","begin":"1","end":"2"}]}`, begin, begin+3600000)
+	})
+	mux.HandleFunc("/api/v5/support/announcements", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("annType") != "announcements-delistings" {
+			t.Errorf("unexpected annType %q", r.URL.Query().Get("annType"))
+		}
+		fmt.Fprintf(w, `{"code":"0","data":[{"details":[
+			{"title":"OKX to delist OM","url":"https://okx.com/a1","pTime":"%d"},
+			{"title":"OKX to delist OLD","url":"https://okx.com/a0","pTime":"%d"}]}]}`, newAnn, oldAnn)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	stateManager := storage.NewStateManager(types.RedisConfig{}, time.Hour, time.Hour)
+	stateManager.Store("OM-USDT", 1, start)
+	recorder := &recordingNotifier{}
+	config := types.AnnouncementConfig{Types: []string{CategoryMaintenance, CategoryDelisting}}
+	watcher := NewWatcher(config, server.Client(), stateManager, recorder)
+	watcher.baseURL = server.URL
+	watcher.startTime = start
+
+	watcher.poll(context.Background())
+	if len(recorder.notices) != 2 {
+		t.Fatalf("got %d notices, want 2", len(recorder.notices))
+	}
+	maintenance, delisting := recorder.notices[0], recorder.notices[1]
+	if maintenance.Category != CategoryMaintenance || maintenance.URL != "https://okx.com/m1" {
+		t.Errorf("unexpected maintenance notice %+v", maintenance)
+	}
+	if delisting.Category != CategoryDelisting || !reflect.DeepEqual(delisting.Symbols, []string{"OM-USDT"}) {
+		t.Errorf("unexpected delisting notice %+v", delisting)
+	}
+
+	// 已通知过的公告不再重复推送
+	watcher.poll(context.Background())
+	if len(recorder.notices) != 2 {
+		t.Errorf("重复推送, got %d notices", len(recorder.notices))
+	}
+}
@@ -0,0 +1,263 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"okx-market-sentry/internal/notifier"
+	"okx-market-sentry/pkg/types"
+)
+
+const (
+	dingTalkSignWindow = time.Hour // 钉钉要求回调时间戳与当前时间相差不超过1小时
+	defaultTopMovers   = 10
+	maxTopMovers       = 20
+)
+
+// dingTalkCallback 钉钉机器人回调的消息体，只解析用到的字段
+type dingTalkCallback struct {
+	MsgType string `json:"msgtype"`
+	Text    struct {
+		Content string `json:"content"`
+	} `json:"text"`
+	SenderNick    string `json:"senderNick"`
+	SenderStaffID string `json:"senderStaffId"`
+	IsAdmin       bool   `json:"isAdmin"`
+}
+
+// dingTalkHelp 机器人支持的命令
+const dingTalkHelp = `**可用命令**（@机器人 后输入）：
+
+- **状态** / status：运行状态、暂停和静音情况
+- **涨跌榜** [数量] / top [n]：当前监控周期内涨跌幅最大的交易对
+- **静音** 交易对 [时长] / mute BTC 2h：静音交易对的预警，不指定时长直到取消
+- **取消静音** [交易对] / unmute [BTC]：不指定交易对时取消全部
+- **帮助** / help`
+
+// handleDingTalkCallback 处理钉钉机器人回调，群成员@机器人发送命令，回复以Markdown消息写入响应
+func (s *Server) handleDingTalkCallback(w http.ResponseWriter, r *http.Request) {
+	if !verifyDingTalkSign(r.Header.Get("timestamp"), r.Header.Get("sign"), s.config.DingTalkCallback.AppSecret, time.Now()) {
+		log().Warn("⚠️ 钉钉回调签名校验失败", zap.String("remote", r.RemoteAddr))
+		writeError(w, http.StatusUnauthorized, "invalid signature")
+		return
+	}
+
+	var callback dingTalkCallback
+	if err := json.NewDecoder(r.Body).Decode(&callback); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	log().Info("💬 收到钉钉机器人命令",
+		zap.String("sender", callback.SenderNick),
+		zap.String("content", strings.TrimSpace(callback.Text.Content)))
+	title, text := s.dingTalkCommand(callback)
+	writeJSON(w, http.StatusOK, &notifier.DingTalkMessage{
+		MsgType:  "markdown",
+		Markdown: &notifier.DingTalkMarkdown{Title: title, Text: text},
+	})
+}
+
+// verifyDingTalkSign 校验钉钉回调签名：以AppSecret为密钥对 timestamp + "\n" + AppSecret 做HMAC-SHA256，再Base64编码
+func verifyDingTalkSign(timestamp, sign, secret string, now time.Time) bool {
+	ms, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || sign == "" {
+		return false
+	}
+	if diff := now.Sub(time.UnixMilli(ms)); diff > dingTalkSignWindow || diff < -dingTalkSignWindow {
+		return false
+	}
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(timestamp + "\n" + secret))
+	expected := base64.StdEncoding.EncodeToString(h.Sum(nil))
+	return hmac.Equal([]byte(sign), []byte(expected))
+}
+
+// dingTalkCommand 执行命令，返回回复的标题和Markdown正文
+func (s *Server) dingTalkCommand(callback dingTalkCallback) (string, string) {
+	fields := strings.Fields(callback.Text.Content)
+	if len(fields) == 0 {
+		return "帮助", dingTalkHelp
+	}
+
+	args := fields[1:]
+	switch strings.ToLower(fields[0]) {
+	case "状态", "status":
+		return "运行状态", s.dingTalkStatus()
+	case "涨跌榜", "top", "movers":
+		return "涨跌榜", s.dingTalkTopMovers(args)
+	case "静音", "mute":
+		return "静音", s.dingTalkMute(callback, args)
+	case "取消静音", "unmute":
+		return "取消静音", s.dingTalkUnmute(callback, args)
+	case "帮助", "help":
+		return "帮助", dingTalkHelp
+	default:
+		return "帮助", fmt.Sprintf("未知命令 `%s`\n\n%s", fields[0], dingTalkHelp)
+	}
+}
+
+func (s *Server) dingTalkStatus() string {
+	states := s.analysisEngine.GetAllSymbolStates()
+	warming := 0
+	for _, state := range states {
+		if !state.HasWindow {
+			warming++
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("### 📊 OKX Sentry 运行状态\n\n")
+	fmt.Fprintf(&b, "- **运行时长**: %s\n", time.Since(s.startTime).Round(time.Second))
+	fmt.Fprintf(&b, "- **监控交易对**: %d 个（数据不足完整周期 %d 个）\n", len(states), warming)
+	if last, ok := s.analysisEngine.GetStats()["last_analysis_time"].(time.Time); ok {
+		fmt.Fprintf(&b, "- **最近分析**: %s\n", last.Format("01-02 15:04:05"))
+	}
+	if recent := s.analysisEngine.GetRecentAlerts(1); len(recent) > 0 {
+		fmt.Fprintf(&b, "- **最近预警**: %s %+.2f%%（%s）\n",
+			recent[0].Symbol, recent[0].ChangePercent, recent[0].AlertTime.Format("01-02 15:04"))
+	}
+	fmt.Fprintf(&b, "- **暂停**: %s\n", untilList(s.analysisEngine.GetPauses(), "全部配置组"))
+	fmt.Fprintf(&b, "- **静音**: %s\n", untilList(s.analysisEngine.GetMutes(), "全部"))
+	return b.String()
+}
+
+// untilList 列出暂停/静音的对象及自动恢复时间，键为空字符串表示全部，显示为 all 指定的名称
+func untilList(items map[string]*time.Time, all string) string {
+	if len(items) == 0 {
+		return "无"
+	}
+	names := make([]string, 0, len(items))
+	for name := range items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		label := name
+		if label == "" {
+			label = all
+		}
+		if until := items[name]; until != nil {
+			label += "（至 " + until.Format("01-02 15:04") + "）"
+		}
+		parts = append(parts, label)
+	}
+	return strings.Join(parts, "、")
+}
+
+func (s *Server) dingTalkTopMovers(args []string) string {
+	limit := defaultTopMovers
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			return fmt.Sprintf("无效的数量 `%s`", args[0])
+		}
+		limit = min(n, maxTopMovers)
+	}
+
+	states := s.analysisEngine.GetAllSymbolStates()
+	movers := make([]*types.SymbolState, 0, len(states))
+	for _, state := range states {
+		if state.HasWindow {
+			movers = append(movers, state)
+		}
+	}
+	if len(movers) == 0 {
+		return "暂无完整监控周期的数据，请稍后再试"
+	}
+	sort.Slice(movers, func(i, j int) bool {
+		return math.Abs(movers[i].ChangePercent) > math.Abs(movers[j].ChangePercent)
+	})
+	movers = movers[:min(limit, len(movers))]
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "### 🏆 涨跌幅前 %d\n\n", len(movers))
+	for i, state := range movers {
+		arrow := "📈"
+		if state.ChangePercent < 0 {
+			arrow = "📉"
+		}
+		fmt.Fprintf(&b, "%d. %s **%s** %+.2f%%  $%s（%s）\n", i+1, arrow, state.Symbol, state.ChangePercent,
+			strconv.FormatFloat(state.CurrentPrice, 'f', -1, 64), state.MonitorPeriod)
+	}
+	return b.String()
+}
+
+func (s *Server) dingTalkMute(callback dingTalkCallback, args []string) string {
+	if s.config.DingTalkCallback.AdminOnly && !callback.IsAdmin {
+		return "⛔ 仅群管理员可以静音交易对"
+	}
+	if len(args) == 0 {
+		return "请指定交易对，如 `静音 BTC-USDT 2h`"
+	}
+	symbol, ok := s.resolveSymbol(args[0])
+	if !ok {
+		return fmt.Sprintf("未监控交易对 `%s`", args[0])
+	}
+
+	var duration time.Duration
+	if len(args) > 1 {
+		d, err := time.ParseDuration(args[1])
+		if err != nil || d <= 0 {
+			return fmt.Sprintf("无效的时长 `%s`，如 30m、2h", args[1])
+		}
+		duration = d
+	}
+
+	s.analysisEngine.Mute(symbol, duration)
+	log().Info("🔇 已通过钉钉静音交易对",
+		zap.String("symbol", symbol),
+		zap.Duration("duration", duration),
+		zap.String("sender", callback.SenderNick))
+	if duration > 0 {
+		return fmt.Sprintf("🔇 已静音 **%s**，%s 后自动恢复", symbol, duration)
+	}
+	return fmt.Sprintf("🔇 已静音 **%s**，发送 `取消静音 %s` 恢复", symbol, symbol)
+}
+
+func (s *Server) dingTalkUnmute(callback dingTalkCallback, args []string) string {
+	if s.config.DingTalkCallback.AdminOnly && !callback.IsAdmin {
+		return "⛔ 仅群管理员可以取消静音"
+	}
+
+	symbol := ""
+	if len(args) > 0 {
+		resolved, ok := s.resolveSymbol(args[0])
+		if !ok {
+			return fmt.Sprintf("未监控交易对 `%s`", args[0])
+		}
+		symbol = resolved
+	}
+	s.analysisEngine.Unmute(symbol)
+	log().Info("🔔 已通过钉钉取消静音",
+		zap.String("symbol", symbol),
+		zap.String("sender", callback.SenderNick))
+	if symbol == "" {
+		return "🔔 已取消全部静音"
+	}
+	return fmt.Sprintf("🔔 已取消静音 **%s**", symbol)
+}
+
+// resolveSymbol 将用户输入的交易对转为监控中的交易对名称，只输入基础币种时按USDT交易对查找
+func (s *Server) resolveSymbol(input string) (string, bool) {
+	symbol := strings.ToUpper(input)
+	if s.analysisEngine.GetSymbolState(symbol) != nil {
+		return symbol, true
+	}
+	if !strings.Contains(symbol, "-") && s.analysisEngine.GetSymbolState(symbol+"-USDT") != nil {
+		return symbol + "-USDT", true
+	}
+	return "", false
+}
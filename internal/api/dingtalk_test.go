@@ -0,0 +1,105 @@
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"okx-market-sentry/internal/notifier"
+	"okx-market-sentry/pkg/types"
+)
+
+// dingTalkSign 按钉钉的规则生成回调签名
+func dingTalkSign(timestamp, secret string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(timestamp + "\n" + secret))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+func TestVerifyDingTalkSign(t *testing.T) {
+	now := time.Now()
+	timestamp := strconv.FormatInt(now.UnixMilli(), 10)
+	if !verifyDingTalkSign(timestamp, dingTalkSign(timestamp, "app-secret"), "app-secret", now) {
+		t.Error("有效签名校验失败")
+	}
+	if verifyDingTalkSign(timestamp, dingTalkSign(timestamp, "other"), "app-secret", now) {
+		t.Error("错误密钥的签名不应通过")
+	}
+	// 超过1小时的时间戳视为重放
+	if verifyDingTalkSign(timestamp, dingTalkSign(timestamp, "app-secret"), "app-secret", now.Add(2*time.Hour)) {
+		t.Error("过期时间戳不应通过")
+	}
+}
+
+func TestDingTalkCallback(t *testing.T) {
+	engine := newTestEngine(t)
+	server := NewServer(types.ServerConfig{DingTalkCallback: types.DingTalkCallbackConfig{
+		Enabled: true, AppSecret: "app-secret", AdminOnly: true,
+	}}, nil, engine, nil, nil, nil)
+	ts := httptest.NewServer(server.httpServer.Handler)
+	defer ts.Close()
+
+	// send 以群成员身份@机器人发送命令，返回回复的Markdown正文
+	send := func(content string, admin bool, secret string) (int, string) {
+		t.Helper()
+		body, _ := json.Marshal(map[string]any{
+			"msgtype":    "text",
+			"text":       map[string]string{"content": " " + content},
+			"senderNick": "tester",
+			"isAdmin":    admin,
+		})
+		req, _ := http.NewRequest(http.MethodPost, ts.URL+"/dingtalk/callback", bytes.NewReader(body))
+		timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		req.Header.Set("timestamp", timestamp)
+		req.Header.Set("sign", dingTalkSign(timestamp, secret))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var reply notifier.DingTalkMessage
+		_ = json.NewDecoder(resp.Body).Decode(&reply)
+		if reply.Markdown == nil {
+			return resp.StatusCode, ""
+		}
+		return resp.StatusCode, reply.Markdown.Text
+	}
+
+	if status, _ := send("状态", false, "wrong"); status != http.StatusUnauthorized {
+		t.Fatalf("错误签名 status = %d, want 401", status)
+	}
+	if _, text := send("status", false, "app-secret"); !strings.Contains(text, "监控交易对**: 2 个") {
+		t.Errorf("状态回复: %s", text)
+	}
+	if _, text := send("top 1", false, "app-secret"); !strings.Contains(text, "ETH-USDT") || strings.Contains(text, "BTC-USDT") {
+		t.Errorf("涨跌榜应只包含跌幅最大的ETH-USDT: %s", text)
+	}
+
+	// 非管理员不能静音；只输入基础币种时按USDT交易对查找
+	if _, text := send("静音 btc 2h", false, "app-secret"); !strings.Contains(text, "仅群管理员") {
+		t.Errorf("非管理员静音: %s", text)
+	}
+	if _, text := send("静音 btc 2h", true, "app-secret"); !strings.Contains(text, "BTC-USDT") {
+		t.Errorf("静音回复: %s", text)
+	}
+	if mutes := engine.GetMutes(); mutes["BTC-USDT"] == nil {
+		t.Fatalf("got mutes %v, want BTC-USDT", mutes)
+	}
+	if _, text := send("静音 DOGE", true, "app-secret"); !strings.Contains(text, "未监控") {
+		t.Errorf("未知交易对: %s", text)
+	}
+	if _, text := send("取消静音", true, "app-secret"); !strings.Contains(text, "全部") || len(engine.GetMutes()) != 0 {
+		t.Errorf("取消静音: %s", text)
+	}
+	if _, text := send("hello", false, "app-secret"); !strings.Contains(text, "未知命令") {
+		t.Errorf("未知命令: %s", text)
+	}
+}
@@ -0,0 +1,372 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"okx-market-sentry/internal/analyzer"
+	"okx-market-sentry/internal/fetcher"
+	"okx-market-sentry/internal/scheduler"
+	"okx-market-sentry/pkg/logger"
+	"okx-market-sentry/pkg/sentrypb"
+	"okx-market-sentry/pkg/types"
+)
+
+// streamBuffer 每个实时预警流的缓冲，客户端接收不及时超过该数量时丢弃预警
+const streamBuffer = 64
+
+// mutatingMethods 修改服务行为的方法，未配置令牌时不开放
+var mutatingMethods = []string{
+	sentrypb.Sentry_Analyze_FullMethodName,
+	sentrypb.Sentry_Pause_FullMethodName,
+	sentrypb.Sentry_Resume_FullMethodName,
+	sentrypb.Sentry_SetLogLevel_FullMethodName,
+}
+
+// GRPCServer gRPC接口服务，与HTTP API提供相同的查询和控制能力，并可实时推送预警
+type GRPCServer struct {
+	sentrypb.UnimplementedSentryServer
+
+	config         types.ServerConfig
+	dataFetcher    *fetcher.DataFetcher
+	analysisEngine *analyzer.AnalysisEngine
+	taskScheduler  *scheduler.Scheduler
+	startTime      time.Time
+	grpcServer     *grpc.Server
+	stopping       chan struct{} // 关闭时结束所有实时预警流
+}
+
+func NewGRPCServer(serverConfig types.ServerConfig, dataFetcher *fetcher.DataFetcher, analysisEngine *analyzer.AnalysisEngine, taskScheduler *scheduler.Scheduler) *GRPCServer {
+	s := &GRPCServer{
+		config:         serverConfig,
+		dataFetcher:    dataFetcher,
+		analysisEngine: analysisEngine,
+		taskScheduler:  taskScheduler,
+		startTime:      time.Now(),
+		stopping:       make(chan struct{}),
+	}
+	s.grpcServer = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.unaryAuth),
+		grpc.ChainStreamInterceptor(s.streamAuth),
+	)
+	sentrypb.RegisterSentryServer(s.grpcServer, s)
+	return s
+}
+
+// Start 启动gRPC服务，ctx取消时优雅关闭
+func (s *GRPCServer) Start(ctx context.Context) {
+	addr := fmt.Sprintf(":%d", s.config.GRPCPort)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		log().Error("❌ gRPC服务监听失败", zap.String("addr", addr), zap.Error(err))
+		return
+	}
+
+	go func() {
+		<-ctx.Done()
+		close(s.stopping)
+		stopped := make(chan struct{})
+		go func() {
+			s.grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			s.grpcServer.Stop()
+		}
+	}()
+
+	log().Info("🌐 gRPC服务启动", zap.String("addr", addr))
+	if err := s.grpcServer.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		log().Error("❌ gRPC服务异常退出", zap.Error(err))
+		return
+	}
+	log().Info("📴 gRPC服务已停止")
+}
+
+// authorize 校验metadata中的Bearer令牌，修改类方法在未配置令牌时拒绝
+func (s *GRPCServer) authorize(ctx context.Context, method string) error {
+	if s.config.AuthToken == "" {
+		if slices.Contains(mutatingMethods, method) {
+			return status.Error(codes.PermissionDenied, "auth_token not configured")
+		}
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	token := strings.TrimPrefix(values[0], "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.config.AuthToken)) != 1 {
+		return status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	return nil
+}
+
+func (s *GRPCServer) unaryAuth(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *GRPCServer) streamAuth(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.authorize(stream.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+func (s *GRPCServer) GetStatus(ctx context.Context, req *sentrypb.GetStatusRequest) (*sentrypb.GetStatusResponse, error) {
+	states := s.analysisEngine.GetAllSymbolStates()
+	warming := 0
+	for _, state := range states {
+		if !state.HasWindow {
+			warming++
+		}
+	}
+	return &sentrypb.GetStatusResponse{
+		Uptime:         durationpb.New(time.Since(s.startTime).Round(time.Second)),
+		Ready:          s.dataFetcher.HasFetched(),
+		Symbols:        int32(len(states)),
+		WarmingSymbols: int32(warming),
+		Pauses:         pausesToProto(s.analysisEngine.GetPauses()),
+	}, nil
+}
+
+func (s *GRPCServer) ListSymbols(ctx context.Context, req *sentrypb.ListSymbolsRequest) (*sentrypb.ListSymbolsResponse, error) {
+	resp := &sentrypb.ListSymbolsResponse{}
+	if len(req.Symbols) == 0 {
+		for _, state := range s.analysisEngine.GetAllSymbolStates() {
+			resp.Symbols = append(resp.Symbols, symbolStateToProto(state))
+		}
+		return resp, nil
+	}
+	for _, symbol := range req.Symbols {
+		state := s.analysisEngine.GetSymbolState(strings.ToUpper(symbol))
+		if state == nil {
+			return nil, status.Errorf(codes.NotFound, "symbol not found: %s", symbol)
+		}
+		resp.Symbols = append(resp.Symbols, symbolStateToProto(state))
+	}
+	return resp, nil
+}
+
+func (s *GRPCServer) ListRecentAlerts(ctx context.Context, req *sentrypb.ListRecentAlertsRequest) (*sentrypb.ListRecentAlertsResponse, error) {
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 20
+	}
+	return &sentrypb.ListRecentAlertsResponse{Alerts: alertsToProto(s.analysisEngine.GetRecentAlerts(limit))}, nil
+}
+
+// Analyze 立即执行一次分析，与 POST /analyze 相同
+func (s *GRPCServer) Analyze(ctx context.Context, req *sentrypb.AnalyzeRequest) (*sentrypb.AnalyzeResponse, error) {
+	symbols := make([]string, len(req.Symbols))
+	for i, symbol := range req.Symbols {
+		symbols[i] = strings.ToUpper(symbol)
+		if s.analysisEngine.GetSymbolState(symbols[i]) == nil {
+			return nil, status.Errorf(codes.NotFound, "symbol not found: %s", symbols[i])
+		}
+	}
+
+	start := time.Now()
+	alerts, err := s.taskScheduler.Trigger(ctx, symbols)
+	if errors.Is(err, scheduler.ErrAnalysisRunning) {
+		return nil, status.Error(codes.Aborted, err.Error())
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	log().Info("👆 已通过gRPC触发分析",
+		zap.Strings("symbols", symbols),
+		zap.Int("alerts", len(alerts)))
+	return &sentrypb.AnalyzeResponse{
+		Duration: durationpb.New(time.Since(start)),
+		Alerts:   alertsToProto(alerts),
+	}, nil
+}
+
+func (s *GRPCServer) GetPauses(ctx context.Context, req *sentrypb.GetPausesRequest) (*sentrypb.PausesResponse, error) {
+	return &sentrypb.PausesResponse{Pauses: pausesToProto(s.analysisEngine.GetPauses())}, nil
+}
+
+func (s *GRPCServer) Pause(ctx context.Context, req *sentrypb.PauseRequest) (*sentrypb.PausesResponse, error) {
+	var duration time.Duration
+	if req.Duration != nil {
+		duration = req.Duration.AsDuration()
+		if duration <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid duration")
+		}
+	}
+	if err := s.analysisEngine.Pause(req.Profile, duration); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return s.GetPauses(ctx, nil)
+}
+
+func (s *GRPCServer) Resume(ctx context.Context, req *sentrypb.ResumeRequest) (*sentrypb.PausesResponse, error) {
+	if err := s.analysisEngine.Resume(req.Profile); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return s.GetPauses(ctx, nil)
+}
+
+func (s *GRPCServer) GetLogLevels(ctx context.Context, req *sentrypb.GetLogLevelsRequest) (*sentrypb.LogLevelsResponse, error) {
+	return levelsToProto(logger.Levels()), nil
+}
+
+// SetLogLevel 运行时调整日志级别，module为空时调整全局级别，level为空时模块恢复跟随全局
+func (s *GRPCServer) SetLogLevel(ctx context.Context, req *sentrypb.SetLogLevelRequest) (*sentrypb.LogLevelsResponse, error) {
+	var err error
+	if req.Module == "" {
+		err = logger.SetLevel(req.Level)
+	} else {
+		err = logger.SetModuleLevel(req.Module, req.Level)
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	log().Info("🔧 日志级别已通过gRPC调整",
+		zap.String("module", req.Module),
+		zap.String("level", req.Level))
+	return levelsToProto(logger.Levels()), nil
+}
+
+// StreamAlerts 实时推送新触发的预警，按交易对和配置组过滤，直到客户端取消或服务关闭
+func (s *GRPCServer) StreamAlerts(req *sentrypb.StreamAlertsRequest, stream sentrypb.Sentry_StreamAlertsServer) error {
+	filter := newAlertFilter(req.Symbols, req.Profiles)
+	alerts, cancel := s.analysisEngine.Subscribe(streamBuffer)
+	defer cancel()
+	// 订阅后立即发送响应头，客户端收到响应头即可确认此后的预警不会遗漏
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	log().Debug("📡 gRPC预警流已连接", zap.Strings("symbols", req.Symbols), zap.Strings("profiles", req.Profiles))
+	defer log().Debug("📡 gRPC预警流已断开")
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-s.stopping:
+			return status.Error(codes.Unavailable, "server shutting down")
+		case alert := <-alerts:
+			if !filter.match(alert) {
+				continue
+			}
+			if err := stream.Send(alertToProto(alert)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// alertFilter 实时预警流的过滤条件，为空时不过滤
+type alertFilter struct {
+	symbols  []string
+	profiles []string
+}
+
+func newAlertFilter(symbols, profiles []string) alertFilter {
+	filter := alertFilter{profiles: profiles}
+	for _, symbol := range symbols {
+		filter.symbols = append(filter.symbols, strings.ToUpper(symbol))
+	}
+	return filter
+}
+
+func (f alertFilter) match(alert *types.AlertData) bool {
+	return (len(f.symbols) == 0 || slices.Contains(f.symbols, alert.Symbol)) &&
+		(len(f.profiles) == 0 || slices.Contains(f.profiles, alert.Profile))
+}
+
+func alertToProto(alert *types.AlertData) *sentrypb.Alert {
+	return &sentrypb.Alert{
+		Symbol:        alert.Symbol,
+		Name:          alert.Name,
+		CurrentPrice:  alert.CurrentPrice,
+		PastPrice:     alert.PastPrice,
+		ChangePercent: alert.ChangePercent,
+		AlertTime:     timestamppb.New(alert.AlertTime),
+		MonitorPeriod: durationpb.New(alert.MonitorPeriod),
+		PriceTime:     timestamppb.New(alert.PriceTime),
+		Profile:       alert.Profile,
+		Partial:       alert.Partial,
+		Kind:          alert.Kind,
+		Conditions:    alert.Conditions,
+		TickSize:      alert.TickSize,
+	}
+}
+
+func alertsToProto(alerts []*types.AlertData) []*sentrypb.Alert {
+	result := make([]*sentrypb.Alert, len(alerts))
+	for i, alert := range alerts {
+		result[i] = alertToProto(alert)
+	}
+	return result
+}
+
+func symbolStateToProto(state *types.SymbolState) *sentrypb.SymbolState {
+	result := &sentrypb.SymbolState{
+		Symbol:        state.Symbol,
+		Name:          state.Name,
+		CurrentPrice:  state.CurrentPrice,
+		PastPrice:     state.PastPrice,
+		ChangePercent: state.ChangePercent,
+		HasWindow:     state.HasWindow,
+		UpdatedAt:     timestamppb.New(state.UpdatedAt),
+		MonitorPeriod: durationpb.New(state.MonitorPeriod),
+		Threshold:     state.Threshold,
+		Profile:       state.Profile,
+		Alerting:      state.Alerting,
+	}
+	if state.LastAlertTime != nil {
+		result.LastAlertTime = timestamppb.New(*state.LastAlertTime)
+	}
+	return result
+}
+
+// pausesToProto 暂停状态按配置组名称排序，全部暂停（空名称）在最前
+func pausesToProto(pauses map[string]*time.Time) []*sentrypb.Pause {
+	result := make([]*sentrypb.Pause, 0, len(pauses))
+	for profile, until := range pauses {
+		pause := &sentrypb.Pause{Profile: profile}
+		if until != nil {
+			pause.Until = timestamppb.New(*until)
+		}
+		result = append(result, pause)
+	}
+	slices.SortFunc(result, func(a, b *sentrypb.Pause) int {
+		return strings.Compare(a.Profile, b.Profile)
+	})
+	return result
+}
+
+// levelsToProto 日志级别按模块名称排序，global 为全局级别
+func levelsToProto(levels map[string]string) *sentrypb.LogLevelsResponse {
+	resp := &sentrypb.LogLevelsResponse{}
+	for module, level := range levels {
+		resp.Levels = append(resp.Levels, &sentrypb.LogLevel{Module: module, Level: level})
+	}
+	slices.SortFunc(resp.Levels, func(a, b *sentrypb.LogLevel) int {
+		return strings.Compare(a.Module, b.Module)
+	})
+	return resp
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"okx-market-sentry/internal/analyzer"
+	"okx-market-sentry/internal/slo"
+	"okx-market-sentry/internal/storage"
+	"okx-market-sentry/pkg/sentrypb"
+	"okx-market-sentry/pkg/types"
+)
+
+// discardNotifier 丢弃所有通知
+type discardNotifier struct{}
+
+func (discardNotifier) SendAlert(*types.AlertData) error         { return nil }
+func (discardNotifier) SendBatchAlerts([]*types.AlertData) error { return nil }
+func (discardNotifier) SendOpsAlert(*types.OpsAlert) error       { return nil }
+func (discardNotifier) SendNotice(*types.Notice) error           { return nil }
+
+// newTestEngine 创建纯内存存储的分析引擎，BTC-USDT、ETH-USDT在5分钟内分别上涨2%、下跌3%
+func newTestEngine(t *testing.T) *analyzer.AnalysisEngine {
+	t.Helper()
+	stateManager := storage.NewStateManager(types.RedisConfig{}, time.Hour, time.Hour)
+	now := time.Now()
+	for symbol, change := range map[string]float64{"BTC-USDT": 2, "ETH-USDT": -3} {
+		stateManager.Store(symbol, 100, now.Add(-5*time.Minute))
+		stateManager.Store(symbol, 100*(1+change/100), now)
+	}
+	return analyzer.NewAnalysisEngine(stateManager, discardNotifier{},
+		types.AlertConfig{Threshold: 1, MonitorPeriod: 5 * time.Minute}, slo.NewTracker())
+}
+
+// newTestGRPC 启动内存连接的gRPC服务
+func newTestGRPC(t *testing.T, authToken string) (sentrypb.SentryClient, *analyzer.AnalysisEngine) {
+	t.Helper()
+	engine := newTestEngine(t)
+	server := NewGRPCServer(types.ServerConfig{AuthToken: authToken}, nil, engine, nil)
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = server.grpcServer.Serve(listener) }()
+	t.Cleanup(server.grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return sentrypb.NewSentryClient(conn), engine
+}
+
+func TestGRPCAuth(t *testing.T) {
+	client, _ := newTestGRPC(t, "secret")
+	ctx := context.Background()
+
+	if _, err := client.ListSymbols(ctx, &sentrypb.ListSymbolsRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("without token: got %v, want Unauthenticated", err)
+	}
+	authCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer secret")
+	resp, err := client.ListSymbols(authCtx, &sentrypb.ListSymbolsRequest{Symbols: []string{"btc-usdt"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Symbols) != 1 || resp.Symbols[0].Symbol != "BTC-USDT" {
+		t.Errorf("unexpected symbols: %v", resp.Symbols)
+	}
+	if _, err := client.ListSymbols(authCtx, &sentrypb.ListSymbolsRequest{Symbols: []string{"DOGE-USDT"}}); status.Code(err) != codes.NotFound {
+		t.Errorf("unknown symbol: got %v, want NotFound", err)
+	}
+
+	// 未配置令牌时查询接口开放，修改类接口拒绝
+	open, _ := newTestGRPC(t, "")
+	if _, err := open.GetPauses(ctx, &sentrypb.GetPausesRequest{}); err != nil {
+		t.Errorf("GetPauses without auth_token: %v", err)
+	}
+	if _, err := open.Pause(ctx, &sentrypb.PauseRequest{}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Pause without auth_token: got %v, want PermissionDenied", err)
+	}
+}
+
+func TestGRPCStreamAlerts(t *testing.T) {
+	client, engine := newTestGRPC(t, "")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamAlerts(ctx, &sentrypb.StreamAlertsRequest{Symbols: []string{"eth-usdt"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Header(); err != nil {
+		t.Fatal(err)
+	}
+
+	engine.AnalyzeAll(ctx)
+	alert, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alert.Symbol != "ETH-USDT" || alert.ChangePercent > -2.9 || alert.Profile != "default" {
+		t.Errorf("unexpected alert: %v", alert)
+	}
+	if got := alert.AlertTime.AsTime(); time.Since(got) > time.Minute {
+		t.Errorf("alert_time = %s", got)
+	}
+}
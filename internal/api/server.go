@@ -0,0 +1,405 @@
+package api
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"okx-market-sentry/internal/analyzer"
+	"okx-market-sentry/internal/fetcher"
+	"okx-market-sentry/internal/scheduler"
+	"okx-market-sentry/internal/slo"
+	"okx-market-sentry/internal/storage"
+	"okx-market-sentry/internal/subscription"
+	"okx-market-sentry/pkg/logger"
+	"okx-market-sentry/pkg/types"
+)
+
+// log 模块日志器，级别可通过 log.modules.api 单独配置
+func log() *zap.Logger {
+	return logger.Named("api")
+}
+
+// Server HTTP API服务
+type Server struct {
+	config         types.ServerConfig
+	dataFetcher    *fetcher.DataFetcher
+	analysisEngine *analyzer.AnalysisEngine
+	taskScheduler  *scheduler.Scheduler
+	stateManager   *storage.StateManager
+	sloTracker     *slo.Tracker
+	subscriptions  *subscription.Manager // 用户订阅，未启用时为nil
+	startTime      time.Time
+	httpServer     *http.Server
+	stopping       chan struct{} // 关闭时结束所有实时预警流
+}
+
+func NewServer(serverConfig types.ServerConfig, dataFetcher *fetcher.DataFetcher, analysisEngine *analyzer.AnalysisEngine, taskScheduler *scheduler.Scheduler, stateManager *storage.StateManager, sloTracker *slo.Tracker) *Server {
+	s := &Server{
+		config:         serverConfig,
+		dataFetcher:    dataFetcher,
+		analysisEngine: analysisEngine,
+		taskScheduler:  taskScheduler,
+		stateManager:   stateManager,
+		sloTracker:     sloTracker,
+		startTime:      time.Now(),
+		stopping:       make(chan struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleDashboard)
+	mux.HandleFunc("GET /healthz", s.handleHealthz)
+	mux.HandleFunc("GET /readyz", s.handleReadyz)
+	mux.Handle("GET /status", s.auth(s.handleStatus))
+	mux.Handle("GET /alerts/recent", s.auth(s.handleRecentAlerts))
+	mux.Handle("GET /alerts/stream", s.auth(s.handleAlertStream))
+	mux.Handle("GET /symbols", s.auth(s.handleSymbols))
+	mux.Handle("GET /symbols/{symbol}/state", s.auth(s.handleSymbolState))
+	mux.Handle("GET /snapshot", s.auth(s.handleSnapshot))
+	mux.Handle("GET /warmup", s.auth(s.handleWarmup))
+	mux.Handle("GET /metrics/history", s.auth(s.handleMetricsHistory))
+	mux.Handle("GET /slo", s.auth(s.handleSLO))
+	mux.Handle("GET /log/level", s.auth(s.handleGetLogLevel))
+	mux.Handle("GET /pause", s.auth(s.handleGetPauses))
+	mux.Handle("GET /subscriptions", s.auth(s.handleListSubscriptions))
+	// 修改类接口会改变服务行为，未配置令牌时不开放
+	if serverConfig.AuthToken != "" {
+		mux.Handle("PUT /log/level", s.auth(s.handleSetLogLevel))
+		mux.Handle("POST /analyze", s.auth(s.handleAnalyze))
+		mux.Handle("POST /pause", s.auth(s.handlePause))
+		mux.Handle("POST /resume", s.auth(s.handleResume))
+		mux.Handle("POST /subscriptions", s.auth(s.handleAddSubscription))
+		mux.Handle("DELETE /subscriptions/{id}", s.auth(s.handleDeleteSubscription))
+	}
+	// 钉钉机器人回调以AppSecret签名鉴权，不使用auth_token
+	if serverConfig.DingTalkCallback.Enabled && serverConfig.DingTalkCallback.AppSecret != "" {
+		mux.HandleFunc("POST /dingtalk/callback", s.handleDingTalkCallback)
+	}
+
+	s.httpServer = &http.Server{
+		Addr:              fmt.Sprintf(":%d", serverConfig.Port),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	// Shutdown 不会中断长连接，通知实时预警流自行结束
+	s.httpServer.RegisterOnShutdown(func() { close(s.stopping) })
+	return s
+}
+
+// Start 启动HTTP服务，ctx取消时优雅关闭
+func (s *Server) Start(ctx context.Context) {
+	if s.config.AuthToken == "" {
+		log().Warn("⚠️ HTTP API未配置auth_token，查询接口将不做鉴权，PUT /log/level、POST /analyze、POST /pause 等修改类接口已禁用")
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
+			log().Warn("HTTP API关闭失败", zap.Error(err))
+		}
+	}()
+
+	log().Info("🌐 HTTP API服务启动", zap.String("addr", s.httpServer.Addr))
+	if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log().Error("❌ HTTP API服务异常退出", zap.Error(err))
+		return
+	}
+	log().Info("📴 HTTP API服务已停止")
+}
+
+// auth 校验Bearer令牌，未配置令牌时直接放行
+func (s *Server) auth(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.config.AuthToken != "" {
+			// 只接受请求头，查询参数中的令牌会留在代理日志、浏览器历史和Referer中
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(token), []byte(s.config.AuthToken)) != 1 {
+				writeError(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+		}
+		next(w, r)
+	})
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "ok",
+	})
+}
+
+// handleReadyz 就绪检查：所有已启用的组件均可用时返回200，否则返回503
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	components := map[string]string{
+		"config": "ok", // 服务能启动说明配置已加载
+	}
+	ready := true
+
+	if s.dataFetcher.HasFetched() {
+		components["fetcher"] = "ok"
+	} else {
+		components["fetcher"] = "waiting for first successful fetch"
+		ready = false
+	}
+
+	if s.stateManager.RedisEnabled() {
+		ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+		defer cancel()
+		if err := s.stateManager.PingRedis(ctx); err != nil {
+			components["redis"] = err.Error()
+			ready = false
+		} else {
+			components["redis"] = "ok"
+		}
+	} else {
+		components["redis"] = "disabled"
+	}
+
+	status := http.StatusOK
+	statusText := "ready"
+	if !ready {
+		status = http.StatusServiceUnavailable
+		statusText = "not ready"
+	}
+	writeJSON(w, status, map[string]interface{}{
+		"status":     statusText,
+		"components": components,
+	})
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	warming := 0
+	for _, status := range s.analysisEngine.GetWarmupStatus() {
+		if progress, ok := status.Indicators["price_change"]; ok && !progress.Ready {
+			warming++
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"uptime":          time.Since(s.startTime).Round(time.Second).String(),
+		"fetcher":         s.dataFetcher.GetStats(),
+		"analyzer":        s.analysisEngine.GetStats(),
+		"storage":         s.stateManager.GetRedisStats(),
+		"warming_symbols": warming,
+	})
+}
+
+func (s *Server) handleRecentAlerts(w http.ResponseWriter, r *http.Request) {
+	limit, ok := parseLimit(w, r, 20)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.analysisEngine.GetRecentAlerts(limit))
+}
+
+func (s *Server) handleMetricsHistory(w http.ResponseWriter, r *http.Request) {
+	limit, ok := parseLimit(w, r, 60)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.analysisEngine.GetCycleHistory(limit))
+}
+
+func (s *Server) handleSLO(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.sloTracker.GetReport())
+}
+
+func (s *Server) handleGetLogLevel(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, logger.Levels())
+}
+
+// handleSetLogLevel 运行时调整日志级别，module为空时调整全局级别，level为空时模块恢复跟随全局
+func (s *Server) handleSetLogLevel(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Module string `json:"module"`
+		Level  string `json:"level"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	var err error
+	if req.Module == "" {
+		err = logger.SetLevel(req.Level)
+	} else {
+		err = logger.SetModuleLevel(req.Module, req.Level)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log().Info("🔧 日志级别已通过API调整",
+		zap.String("module", req.Module),
+		zap.String("level", req.Level))
+	writeJSON(w, http.StatusOK, logger.Levels())
+}
+
+// handleAnalyze 立即执行一次分析，请求体可选 {"symbols": ["BTC-USDT"]}，为空时分析全部交易对
+func (s *Server) handleAnalyze(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Symbols []string `json:"symbols"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	for i, symbol := range req.Symbols {
+		req.Symbols[i] = strings.ToUpper(symbol)
+		if s.analysisEngine.GetSymbolState(req.Symbols[i]) == nil {
+			writeError(w, http.StatusNotFound, "symbol not found: "+req.Symbols[i])
+			return
+		}
+	}
+
+	start := time.Now()
+	alerts, err := s.taskScheduler.Trigger(r.Context(), req.Symbols)
+	if errors.Is(err, scheduler.ErrAnalysisRunning) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	log().Info("👆 已通过API触发分析",
+		zap.Strings("symbols", req.Symbols),
+		zap.Int("alerts", len(alerts)))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"duration": time.Since(start).String(),
+		"alerts":   alerts,
+	})
+}
+
+func (s *Server) handleGetPauses(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.analysisEngine.GetPauses())
+}
+
+// pauseRequest 暂停/恢复请求，profile为空时作用于全部配置组
+type pauseRequest struct {
+	Profile  string `json:"profile"`
+	Duration string `json:"duration"` // 暂停时长，如 "2h"，为空时直到手动恢复
+}
+
+// decodePauseRequest 解析暂停/恢复请求，请求体可为空，非法时直接写入400响应并返回false
+func decodePauseRequest(w http.ResponseWriter, r *http.Request) (pauseRequest, time.Duration, bool) {
+	var req pauseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return req, 0, false
+	}
+
+	var duration time.Duration
+	if req.Duration != "" {
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil || d <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid duration")
+			return req, 0, false
+		}
+		duration = d
+	}
+	return req, duration, true
+}
+
+// handlePause 暂停预警，行情获取和数据存储照常进行
+func (s *Server) handlePause(w http.ResponseWriter, r *http.Request) {
+	req, duration, ok := decodePauseRequest(w, r)
+	if !ok {
+		return
+	}
+	if err := s.analysisEngine.Pause(req.Profile, duration); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, s.analysisEngine.GetPauses())
+}
+
+func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
+	req, _, ok := decodePauseRequest(w, r)
+	if !ok {
+		return
+	}
+	if err := s.analysisEngine.Resume(req.Profile); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, s.analysisEngine.GetPauses())
+}
+
+// parseLimit 解析limit查询参数，非法时直接写入400响应并返回false
+func parseLimit(w http.ResponseWriter, r *http.Request, defaultLimit int) (int, bool) {
+	v := r.URL.Query().Get("limit")
+	if v == "" {
+		return defaultLimit, true
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid limit")
+		return 0, false
+	}
+	return n, true
+}
+
+func (s *Server) handleSymbols(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.analysisEngine.GetAllSymbolStates())
+}
+
+func (s *Server) handleSymbolState(w http.ResponseWriter, r *http.Request) {
+	symbol := strings.ToUpper(r.PathValue("symbol"))
+	state := s.analysisEngine.GetSymbolState(symbol)
+	if state == nil {
+		writeError(w, http.StatusNotFound, "symbol not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, state)
+}
+
+// handleSnapshot 一次返回全部交易对的快照，客户端支持时使用gzip压缩
+func (s *Server) handleSnapshot(w http.ResponseWriter, r *http.Request) {
+	snapshot := s.analysisEngine.Snapshot()
+	w.Header().Add("Vary", "Accept-Encoding")
+	if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		writeJSON(w, http.StatusOK, snapshot)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Encoding", "gzip")
+	w.WriteHeader(http.StatusOK)
+	gz := gzip.NewWriter(w)
+	if err := json.NewEncoder(gz).Encode(snapshot); err != nil {
+		log().Warn("写入HTTP响应失败", zap.Error(err))
+	}
+	if err := gz.Close(); err != nil {
+		log().Warn("写入HTTP响应失败", zap.Error(err))
+	}
+}
+
+func (s *Server) handleWarmup(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.analysisEngine.GetWarmupStatus())
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log().Warn("写入HTTP响应失败", zap.Error(err))
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
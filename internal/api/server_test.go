@@ -0,0 +1,165 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"okx-market-sentry/internal/analyzer"
+	"okx-market-sentry/internal/fetcher"
+	"okx-market-sentry/internal/scheduler"
+	"okx-market-sentry/internal/slo"
+	"okx-market-sentry/internal/storage"
+	"okx-market-sentry/internal/storage/redistest"
+	"okx-market-sentry/pkg/types"
+)
+
+// doRequest 发送请求并返回状态码和响应体，token为空时不带Authorization头
+func doRequest(t *testing.T, ts *httptest.Server, method, path, token, body string) (int, []byte) {
+	t.Helper()
+	req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, raw
+}
+
+func TestServerEndpoints(t *testing.T) {
+	stateManager := storage.NewStateManager(types.RedisConfig{}, time.Hour, time.Hour)
+	dataFetcher := fetcher.NewDataFetcher(stateManager, types.NetworkConfig{}, types.FetchConfig{}, nil)
+	server := NewServer(types.ServerConfig{AuthToken: "secret"}, dataFetcher, newTestEngine(t), nil, stateManager, nil)
+	ts := httptest.NewServer(server.httpServer.Handler)
+	defer ts.Close()
+
+	// 健康检查不需要鉴权，其他接口需要
+	if status, _ := doRequest(t, ts, http.MethodGet, "/healthz", "", ""); status != http.StatusOK {
+		t.Errorf("/healthz: %d", status)
+	}
+	for _, token := range []string{"", "wrong"} {
+		if status, _ := doRequest(t, ts, http.MethodGet, "/status", token, ""); status != http.StatusUnauthorized {
+			t.Errorf("令牌 %q 访问 /status: %d", token, status)
+		}
+	}
+
+	status, body := doRequest(t, ts, http.MethodGet, "/status", "secret", "")
+	var stats map[string]json.RawMessage
+	if err := json.Unmarshal(body, &stats); err != nil || status != http.StatusOK {
+		t.Fatalf("/status: %d %s", status, body)
+	}
+	for _, key := range []string{"uptime", "fetcher", "analyzer", "storage"} {
+		if _, ok := stats[key]; !ok {
+			t.Errorf("/status 缺少 %s: %s", key, body)
+		}
+	}
+
+	if status, _ := doRequest(t, ts, http.MethodGet, "/alerts/recent?limit=0", "secret", ""); status != http.StatusBadRequest {
+		t.Errorf("非法limit: %d", status)
+	}
+	status, body = doRequest(t, ts, http.MethodGet, "/alerts/recent?limit=5", "secret", "")
+	if status != http.StatusOK || strings.TrimSpace(string(body)) != "[]" {
+		t.Errorf("/alerts/recent: %d %s", status, body)
+	}
+
+	// 交易对名称不区分大小写
+	status, body = doRequest(t, ts, http.MethodGet, "/symbols/btc-usdt/state", "secret", "")
+	var state types.SymbolState
+	if err := json.Unmarshal(body, &state); err != nil || status != http.StatusOK {
+		t.Fatalf("/symbols/btc-usdt/state: %d %s", status, body)
+	}
+	if state.Symbol != "BTC-USDT" || !state.HasWindow || math.Abs(state.ChangePercent-2) > 1e-9 {
+		t.Errorf("BTC-USDT 状态: %+v", state)
+	}
+	if status, _ := doRequest(t, ts, http.MethodGet, "/symbols/DOGE-USDT/state", "secret", ""); status != http.StatusNotFound {
+		t.Errorf("未知交易对: %d", status)
+	}
+}
+
+func TestAnalyzeEndpoint(t *testing.T) {
+	engine := newTestEngine(t)
+	taskScheduler := scheduler.NewScheduler(nil, engine, nil, 5*time.Minute, types.ScheduleConfig{})
+
+	// 未配置令牌时不开放
+	open := httptest.NewServer(NewServer(types.ServerConfig{}, nil, engine, taskScheduler, nil, nil).httpServer.Handler)
+	defer open.Close()
+	if status, _ := doRequest(t, open, http.MethodPost, "/analyze", "", ""); status == http.StatusOK {
+		t.Error("未配置auth_token时 POST /analyze 不应可用")
+	}
+
+	ts := httptest.NewServer(NewServer(types.ServerConfig{AuthToken: "secret"}, nil, engine, taskScheduler, nil, nil).httpServer.Handler)
+	defer ts.Close()
+
+	if status, _ := doRequest(t, ts, http.MethodPost, "/analyze", "", ""); status != http.StatusUnauthorized {
+		t.Errorf("未带令牌: %d", status)
+	}
+	if status, _ := doRequest(t, ts, http.MethodPost, "/analyze", "secret", `{"symbols": ["DOGE-USDT"]}`); status != http.StatusNotFound {
+		t.Errorf("未知交易对: %d", status)
+	}
+	if status, _ := doRequest(t, ts, http.MethodPost, "/analyze", "secret", `{"symbols":`); status != http.StatusBadRequest {
+		t.Errorf("非法请求体: %d", status)
+	}
+
+	// 交易对名称不区分大小写，请求体为空时分析全部交易对
+	var resp struct {
+		Alerts []*types.AlertData `json:"alerts"`
+	}
+	status, body := doRequest(t, ts, http.MethodPost, "/analyze", "secret", `{"symbols": ["eth-usdt"]}`)
+	if err := json.Unmarshal(body, &resp); err != nil || status != http.StatusOK || len(resp.Alerts) != 1 || resp.Alerts[0].Symbol != "ETH-USDT" {
+		t.Fatalf("分析ETH-USDT: %d %s", status, body)
+	}
+	status, body = doRequest(t, ts, http.MethodPost, "/analyze", "secret", "")
+	if err := json.Unmarshal(body, &resp); err != nil || status != http.StatusOK || len(resp.Alerts) != 1 || resp.Alerts[0].Symbol != "BTC-USDT" {
+		t.Errorf("分析全部: %d %s", status, body)
+	}
+}
+
+func TestStateQueriesKeepCooldown(t *testing.T) {
+	redisServer := redistest.NewServer(t)
+	stateManager := storage.NewStateManager(types.RedisConfig{URL: redisServer.Addr()}, time.Hour, time.Hour)
+	if !stateManager.RedisEnabled() {
+		t.Fatal("连接测试Redis失败")
+	}
+	defer stateManager.Close()
+	now := time.Now()
+	stateManager.Store("BTC-USDT", 100, now.Add(-5*time.Minute))
+	stateManager.Store("BTC-USDT", 102, now)
+
+	engine := analyzer.NewAnalysisEngine(stateManager, discardNotifier{},
+		types.AlertConfig{Threshold: 1, MonitorPeriod: 5 * time.Minute}, slo.NewTracker())
+	taskScheduler := scheduler.NewScheduler(nil, engine, nil, 5*time.Minute, types.ScheduleConfig{})
+	ts := httptest.NewServer(NewServer(types.ServerConfig{AuthToken: "secret"}, nil, engine, taskScheduler, stateManager, nil).httpServer.Handler)
+	defer ts.Close()
+
+	// 看板轮询的状态接口只读取冷却，不占用
+	for _, path := range []string{"/symbols", "/snapshot", "/symbols/BTC-USDT/state"} {
+		if status, body := doRequest(t, ts, http.MethodGet, path, "secret", ""); status != http.StatusOK {
+			t.Fatalf("%s: %d %s", path, status, body)
+		}
+	}
+
+	// 手动分析前检查交易对是否存在同样不占用，本次分析照常预警
+	var resp struct {
+		Alerts []*types.AlertData `json:"alerts"`
+	}
+	status, body := doRequest(t, ts, http.MethodPost, "/analyze", "secret", `{"symbols": ["BTC-USDT"]}`)
+	if err := json.Unmarshal(body, &resp); err != nil || status != http.StatusOK || len(resp.Alerts) != 1 {
+		t.Fatalf("状态查询后分析BTC-USDT: %d %s", status, body)
+	}
+
+	// 预警发送后状态显示为冷却中
+	status, body = doRequest(t, ts, http.MethodGet, "/symbols/BTC-USDT/state", "secret", "")
+	var state types.SymbolState
+	if err := json.Unmarshal(body, &state); err != nil || status != http.StatusOK || !state.Alerting {
+		t.Errorf("预警后状态: %d %s", status, body)
+	}
+}
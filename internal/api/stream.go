@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// sseHeartbeat 实时预警流的心跳间隔，避免代理和负载均衡因连接空闲而断开
+const sseHeartbeat = 30 * time.Second
+
+// handleAlertStream 以Server-Sent Events实时推送新触发的预警，每个预警为一个 alert 事件，data 为预警JSON
+// 查询参数 symbols、profiles 为逗号分隔的过滤条件，为空时推送全部
+func (s *Server) handleAlertStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+	query := r.URL.Query()
+	filter := newAlertFilter(splitList(query.Get("symbols")), splitList(query.Get("profiles")))
+	alerts, cancel := s.analysisEngine.Subscribe(streamBuffer)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // 关闭nginx的响应缓冲
+	w.WriteHeader(http.StatusOK)
+	// 订阅后立即返回，客户端收到响应即可确认此后的预警不会遗漏；断线后EventSource 5秒后重连
+	fmt.Fprint(w, "retry: 5000\n\n")
+	flusher.Flush()
+
+	log().Debug("📡 SSE预警流已连接", zap.String("remote", r.RemoteAddr), zap.String("query", r.URL.RawQuery))
+	defer log().Debug("📡 SSE预警流已断开", zap.String("remote", r.RemoteAddr))
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.stopping:
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case alert := <-alerts:
+			if !filter.match(alert) {
+				continue
+			}
+			data, err := json.Marshal(alert)
+			if err != nil {
+				log().Warn("序列化预警失败", zap.String("symbol", alert.Symbol), zap.Error(err))
+				continue
+			}
+			fmt.Fprintf(w, "event: alert\ndata: %s\n\n", data)
+		}
+		flusher.Flush()
+	}
+}
+
+// splitList 解析逗号分隔的列表，忽略空项
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"okx-market-sentry/pkg/types"
+)
+
+func TestAlertStream(t *testing.T) {
+	engine := newTestEngine(t)
+	server := NewServer(types.ServerConfig{AuthToken: "secret"}, nil, engine, nil, nil, nil)
+	ts := httptest.NewServer(server.httpServer.Handler)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/alerts/stream?symbols=btc-usdt", nil)
+	if resp, err := http.DefaultClient.Do(req); err != nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("without token: %v %v", resp.StatusCode, err)
+	}
+
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	// 收到响应头时已订阅，此后触发的预警按过滤条件推送
+	engine.AnalyzeAll(ctx)
+	scanner := bufio.NewScanner(resp.Body)
+	var event string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name, ok := strings.CutPrefix(line, "event: "); ok {
+			event = name
+		}
+		if data, ok := strings.CutPrefix(line, "data: "); ok {
+			var alert types.AlertData
+			if err := json.Unmarshal([]byte(data), &alert); err != nil {
+				t.Fatal(err)
+			}
+			if event != "alert" || alert.Symbol != "BTC-USDT" {
+				t.Errorf("unexpected event %q: %+v", event, alert)
+			}
+			return
+		}
+	}
+	t.Fatalf("stream ended without alert: %v", scanner.Err())
+}
+
+func TestSplitList(t *testing.T) {
+	got := splitList(" BTC-USDT,,eth-usdt ,")
+	if len(got) != 2 || got[0] != "BTC-USDT" || got[1] != "eth-usdt" {
+		t.Errorf("splitList = %q", got)
+	}
+	if got := splitList(""); got != nil {
+		t.Errorf("splitList(\"\") = %q", got)
+	}
+}
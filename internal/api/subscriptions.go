@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"okx-market-sentry/internal/subscription"
+)
+
+// SetSubscriptions 启用用户订阅接口，未设置时订阅接口返回404
+func (s *Server) SetSubscriptions(manager *subscription.Manager) {
+	s.subscriptions = manager
+}
+
+// subscriptionRequest 新增订阅的请求，user_id为接收预警的Telegram chat_id
+type subscriptionRequest struct {
+	UserID    string  `json:"user_id"`
+	Symbol    string  `json:"symbol"`
+	Threshold float64 `json:"threshold"`
+	Period    string  `json:"period"` // 监控周期，如 "15m"，为空时使用默认周期
+}
+
+// handleListSubscriptions 列出订阅，可按 user_id 查询参数过滤
+func (s *Server) handleListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	if s.subscriptions == nil {
+		writeError(w, http.StatusNotFound, "subscriptions not enabled")
+		return
+	}
+	subs, err := s.subscriptions.List(r.Context(), r.URL.Query().Get("user_id"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, subs)
+}
+
+// handleAddSubscription 为用户新增订阅，同一用户重复订阅同一交易对时更新阈值和周期
+func (s *Server) handleAddSubscription(w http.ResponseWriter, r *http.Request) {
+	if s.subscriptions == nil {
+		writeError(w, http.StatusNotFound, "subscriptions not enabled")
+		return
+	}
+	var req subscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.UserID == "" || req.Symbol == "" {
+		writeError(w, http.StatusBadRequest, "user_id and symbol are required")
+		return
+	}
+	var period time.Duration
+	if req.Period != "" {
+		d, err := time.ParseDuration(req.Period)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid period")
+			return
+		}
+		period = d
+	}
+
+	sub, err := s.subscriptions.Add(r.Context(), req.UserID, req.Symbol, req.Threshold, period)
+	switch {
+	case errors.Is(err, subscription.ErrUnknownSymbol), errors.Is(err, subscription.ErrThreshold),
+		errors.Is(err, subscription.ErrPeriod), errors.Is(err, subscription.ErrLimit):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeJSON(w, http.StatusOK, sub)
+	}
+}
+
+func (s *Server) handleDeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	if s.subscriptions == nil {
+		writeError(w, http.StatusNotFound, "subscriptions not enabled")
+		return
+	}
+	err := s.subscriptions.Delete(r.Context(), r.PathValue("id"))
+	switch {
+	case errors.Is(err, subscription.ErrNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+	}
+}
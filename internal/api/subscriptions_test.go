@@ -0,0 +1,61 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"okx-market-sentry/internal/storage"
+	"okx-market-sentry/internal/subscription"
+	"okx-market-sentry/pkg/types"
+)
+
+func TestSubscriptionsAPI(t *testing.T) {
+	stateManager := storage.NewStateManager(types.RedisConfig{}, time.Hour, time.Hour)
+	stateManager.Store("BTC-USDT", 100, time.Now())
+	server := NewServer(types.ServerConfig{AuthToken: "secret"}, nil, newTestEngine(t), nil, nil, nil)
+	server.SetSubscriptions(subscription.NewManager(types.SubscriptionConfig{
+		MaxPerUser: 5, MinThreshold: 1, DefaultPeriod: 5 * time.Minute, MaxPeriod: time.Hour,
+	}, subscription.NewMemoryStore(), stateManager, nil, false))
+	ts := httptest.NewServer(server.httpServer.Handler)
+	defer ts.Close()
+
+	do := func(method, path, body string) (*http.Response, []byte) {
+		t.Helper()
+		req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var raw json.RawMessage
+		_ = json.NewDecoder(resp.Body).Decode(&raw)
+		return resp, raw
+	}
+
+	if resp, _ := do(http.MethodPost, "/subscriptions", `{"user_id": "1001", "symbol": "DOGE", "threshold": 3}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("未监控的交易对: %d", resp.StatusCode)
+	}
+	resp, body := do(http.MethodPost, "/subscriptions", `{"user_id": "1001", "symbol": "btc", "threshold": 3, "period": "15m"}`)
+	var sub types.Subscription
+	if err := json.Unmarshal(body, &sub); err != nil || resp.StatusCode != http.StatusOK || sub.Symbol != "BTC-USDT" {
+		t.Fatalf("新增订阅: %d %s", resp.StatusCode, body)
+	}
+
+	_, body = do(http.MethodGet, "/subscriptions?user_id=1001", "")
+	var subs []types.Subscription
+	if err := json.Unmarshal(body, &subs); err != nil || len(subs) != 1 || subs[0].Period != 15*time.Minute {
+		t.Errorf("订阅列表: %s", body)
+	}
+
+	if resp, _ := do(http.MethodDelete, "/subscriptions/"+sub.ID, ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("删除订阅: %d", resp.StatusCode)
+	}
+	if resp, _ := do(http.MethodDelete, "/subscriptions/"+sub.ID, ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("重复删除: %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,93 @@
+package backtest
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tick 历史行情中的一个价格点
+type Tick struct {
+	Symbol string
+	Time   time.Time
+	Price  float64
+}
+
+// ReadCSV 读取历史行情，每行为 时间,交易对,价格，第一行的首列不是时间时视为表头跳过，# 开头的行为注释
+// 时间支持RFC3339、"2006-01-02 15:04:05"（按本地时区）和Unix秒/毫秒时间戳；K线数据取收盘时间和收盘价即可
+// 返回按时间排序的价格点
+func ReadCSV(r io.Reader) ([]Tick, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var ticks []Tick
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, err := parseTime(strings.TrimSpace(record[0])); err != nil && first {
+			continue // 表头
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) < 3 {
+			return nil, fmt.Errorf("第%d行: 需要 时间,交易对,价格 三列", line)
+		}
+		tick, err := parseTick(record)
+		if err != nil {
+			return nil, fmt.Errorf("第%d行: %w", line, err)
+		}
+		ticks = append(ticks, tick)
+	}
+	if len(ticks) == 0 {
+		return nil, errors.New("没有价格数据")
+	}
+
+	slices.SortStableFunc(ticks, func(a, b Tick) int {
+		return a.Time.Compare(b.Time)
+	})
+	return ticks, nil
+}
+
+func parseTick(record []string) (Tick, error) {
+	t, err := parseTime(strings.TrimSpace(record[0]))
+	if err != nil {
+		return Tick{}, err
+	}
+	symbol := strings.ToUpper(strings.TrimSpace(record[1]))
+	if symbol == "" {
+		return Tick{}, errors.New("交易对为空")
+	}
+	price, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+	if err != nil || price <= 0 {
+		return Tick{}, fmt.Errorf("无效的价格 %q", record[2])
+	}
+	return Tick{Symbol: symbol, Time: t, Price: price}, nil
+}
+
+// parseTime 解析时间，纯数字按Unix时间戳处理，超过1e12视为毫秒
+func parseTime(value string) (time.Time, error) {
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if n > 1e12 {
+			return time.UnixMilli(n), nil
+		}
+		return time.Unix(n, 0), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateTime, value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("无法解析时间 %q", value)
+}
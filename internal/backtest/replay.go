@@ -0,0 +1,146 @@
+package backtest
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"okx-market-sentry/internal/analyzer"
+	"okx-market-sentry/internal/slo"
+	"okx-market-sentry/internal/storage"
+	"okx-market-sentry/pkg/config"
+	"okx-market-sentry/pkg/types"
+)
+
+// Schedule 分析时间点，与运行服务时的调度一致
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+// Result 回放结果
+type Result struct {
+	Start, End time.Time // 历史行情的时间范围
+	Ticks      int       // 价格点数量
+	Symbols    int       // 交易对数量
+	Cycles     int       // 分析轮数
+	Alerts     []*types.AlertData
+}
+
+// Day 某一天触发的预警统计
+type Day struct {
+	Date     time.Time // 当天零点（本地时区）
+	Alerts   int
+	Symbols  int    // 触发预警的交易对数量
+	Top      string // 当天预警最多的交易对
+	TopCount int
+}
+
+// Replay 将历史行情按时间顺序写入纯内存存储，在每个分析时间点运行分析引擎，收集本应触发的预警
+// 存储和分析引擎使用回放时钟，监控窗口、冷却期和预警时间均按历史时间计算；ticks需按时间排序
+func Replay(ctx context.Context, ticks []Tick, alertConfig types.AlertConfig, schedule Schedule) *Result {
+	result := &Result{Ticks: len(ticks)}
+	if len(ticks) == 0 {
+		return result
+	}
+	result.Start, result.End = ticks[0].Time, ticks[len(ticks)-1].Time
+
+	// 回放不写决策审计日志，避免覆盖运行中服务的日志文件
+	alertConfig.DecisionLog.Enabled = false
+	var now time.Time
+	clock := func() time.Time { return now }
+	_, longestPeriod := config.MonitorPeriodRange(alertConfig)
+	stateManager := storage.NewStateManager(types.RedisConfig{}, longestPeriod, alertConfig.CorrelationLookback)
+	stateManager.SetClock(clock)
+	collector := &collector{}
+	engine := analyzer.NewAnalysisEngine(stateManager, collector, alertConfig, slo.NewTracker())
+	engine.SetClock(clock)
+
+	symbols := make(map[string]bool)
+	next := schedule.Next(result.Start)
+	analyze := func() {
+		now = next
+		engine.AnalyzeAll(ctx)
+		result.Cycles++
+		next = schedule.Next(next)
+	}
+	for _, tick := range ticks {
+		// 与分析时间点相同的价格点先写入再分析
+		for tick.Time.After(next) {
+			if ctx.Err() != nil {
+				return result
+			}
+			analyze()
+		}
+		now = tick.Time
+		stateManager.Store(tick.Symbol, tick.Price, tick.Time)
+		symbols[tick.Symbol] = true
+	}
+	if !next.After(result.End) {
+		analyze()
+	}
+
+	result.Symbols = len(symbols)
+	result.Alerts = collector.alerts
+	return result
+}
+
+// Days 按天统计预警数量，覆盖历史行情的每一天，没有预警的日期计为0
+func (r *Result) Days() []Day {
+	if r.Start.IsZero() {
+		return nil
+	}
+	bySymbol := make(map[time.Time]map[string]int)
+	for _, alert := range r.Alerts {
+		date := startOfDay(alert.AlertTime)
+		if bySymbol[date] == nil {
+			bySymbol[date] = make(map[string]int)
+		}
+		bySymbol[date][alert.Symbol]++
+	}
+
+	var days []Day
+	for date := startOfDay(r.Start); !date.After(r.End); date = date.AddDate(0, 0, 1) {
+		day := Day{Date: date, Symbols: len(bySymbol[date])}
+		symbols := make([]string, 0, len(bySymbol[date]))
+		for symbol, count := range bySymbol[date] {
+			day.Alerts += count
+			symbols = append(symbols, symbol)
+		}
+		// 数量相同时按交易对名称排序，保证输出稳定
+		slices.SortFunc(symbols, func(a, b string) int {
+			return cmp.Or(cmp.Compare(bySymbol[date][b], bySymbol[date][a]), cmp.Compare(a, b))
+		})
+		if len(symbols) > 0 {
+			day.Top, day.TopCount = symbols[0], bySymbol[date][symbols[0]]
+		}
+		days = append(days, day)
+	}
+	return days
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// collector 收集回放中触发的预警，不实际推送
+type collector struct {
+	mutex  sync.Mutex
+	alerts []*types.AlertData
+}
+
+func (c *collector) SendAlert(alert *types.AlertData) error {
+	return c.SendBatchAlerts([]*types.AlertData{alert})
+}
+
+func (c *collector) SendBatchAlerts(alerts []*types.AlertData) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.alerts = append(c.alerts, alerts...)
+	return nil
+}
+
+func (c *collector) SendOpsAlert(*types.OpsAlert) error { return nil }
+func (c *collector) SendNotice(*types.Notice) error     { return nil }
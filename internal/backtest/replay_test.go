@@ -0,0 +1,77 @@
+package backtest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// everyMinute 每分钟整点分析
+type everyMinute struct{}
+
+func (everyMinute) Next(t time.Time) time.Time { return t.Truncate(time.Minute).Add(time.Minute) }
+
+func TestReadCSV(t *testing.T) {
+	input := `time,symbol,price
+# 注释
+2024-03-01T00:01:00Z,eth-usdt,3000.5
+1709251200000,BTC-USDT,61000
+1709251200,ETH-USDT,2990
+`
+	ticks, err := ReadCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ticks) != 3 {
+		t.Fatalf("got %d ticks, want 3", len(ticks))
+	}
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	if !ticks[0].Time.Equal(start) || !ticks[1].Time.Equal(start) || !ticks[2].Time.Equal(start.Add(time.Minute)) {
+		t.Errorf("ticks not sorted by time: %+v", ticks)
+	}
+	if ticks[2].Symbol != "ETH-USDT" || ticks[2].Price != 3000.5 {
+		t.Errorf("unexpected tick: %+v", ticks[2])
+	}
+
+	for _, bad := range []string{"", "time,symbol,price\n", "2024-03-01T00:00:00Z,BTC-USDT,abc\n2024-03-01T00:01:00Z,BTC-USDT,1\n", "2024-03-01T00:00:00Z,BTC-USDT,1\nyesterday,BTC-USDT,1\n"} {
+		if _, err := ReadCSV(strings.NewReader(bad)); err == nil {
+			t.Errorf("ReadCSV(%q) should fail", bad)
+		}
+	}
+}
+
+func TestReplay(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)
+	pump := start.Add(10 * time.Hour)
+	var ticks []Tick
+	for ts := start; ts.Before(start.Add(48 * time.Hour)); ts = ts.Add(time.Minute) {
+		btc := 100.0
+		if !ts.Before(pump) {
+			btc = 105 // 拉升后横盘，冷却期过后不应重复预警
+		}
+		ticks = append(ticks, Tick{Symbol: "BTC-USDT", Time: ts, Price: btc}, Tick{Symbol: "ETH-USDT", Time: ts, Price: 50})
+	}
+
+	result := Replay(context.Background(), ticks,
+		types.AlertConfig{Threshold: 3, MonitorPeriod: 5 * time.Minute}, everyMinute{})
+	if result.Symbols != 2 || result.Cycles != 48*60-1 {
+		t.Errorf("symbols = %d, cycles = %d", result.Symbols, result.Cycles)
+	}
+	if len(result.Alerts) != 1 {
+		t.Fatalf("got %d alerts, want 1", len(result.Alerts))
+	}
+	if alert := result.Alerts[0]; alert.Symbol != "BTC-USDT" || !alert.AlertTime.Equal(pump) {
+		t.Errorf("unexpected alert %s at %s", alert.Symbol, alert.AlertTime)
+	}
+
+	days := result.Days()
+	if len(days) != 2 {
+		t.Fatalf("got %d days, want 2", len(days))
+	}
+	if days[0].Alerts != 1 || days[0].Top != "BTC-USDT" || days[1].Alerts != 0 || !days[1].Date.Equal(start.AddDate(0, 0, 1)) {
+		t.Errorf("unexpected days: %+v", days)
+	}
+}
@@ -0,0 +1,160 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"okx-market-sentry/pkg/logger"
+	"okx-market-sentry/pkg/types"
+)
+
+// log 模块日志器，级别可通过 log.modules.calendar 单独配置
+func log() *zap.Logger {
+	return logger.Named("calendar")
+}
+
+// impactRanks 影响程度排序，未列出的（如 holiday）不参与判断
+var impactRanks = map[string]int{
+	"low":    1,
+	"medium": 2,
+	"high":   3,
+}
+
+// Calendar 定期从JSON日历源加载经济事件，供分析时判断预警是否发生在重要事件前后
+type Calendar struct {
+	config     types.CalendarConfig
+	httpClient *http.Client
+
+	mutex  sync.RWMutex
+	events []types.CalendarEvent
+}
+
+func New(config types.CalendarConfig, httpClient *http.Client) *Calendar {
+	return &Calendar{
+		config:     config,
+		httpClient: httpClient,
+	}
+}
+
+// Start 立即加载一次日历，之后按刷新间隔重新加载，直到ctx取消
+func (c *Calendar) Start(ctx context.Context) {
+	log().Info("📅 经济日历已启用",
+		zap.String("source", c.config.Source),
+		zap.Duration("window", c.config.Window),
+		zap.String("min_impact", c.config.MinImpact))
+
+	ticker := time.NewTicker(c.config.RefreshInterval)
+	defer ticker.Stop()
+
+	c.refresh(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.refresh(ctx)
+		}
+	}
+}
+
+// refresh 重新加载日历，失败时保留上一次的事件
+func (c *Calendar) refresh(ctx context.Context) {
+	data, err := c.load(ctx)
+	if err == nil {
+		var events []types.CalendarEvent
+		if events, err = ParseEvents(data); err == nil {
+			c.mutex.Lock()
+			c.events = events
+			c.mutex.Unlock()
+			log().Debug("📅 经济日历已更新", zap.Int("events", len(events)))
+			return
+		}
+	}
+	log().Warn("⚠️ 加载经济日历失败，继续使用上一次的数据", zap.String("source", c.config.Source), zap.Error(err))
+}
+
+// load 读取日历源，http(s)地址通过网络获取，否则视为本地文件
+func (c *Calendar) load(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(c.config.Source, "http://") && !strings.HasPrefix(c.config.Source, "https://") {
+		return os.ReadFile(c.config.Source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.Source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP状态码错误: %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// ParseEvents 解析JSON日历，格式为事件数组：
+// [{"title": "CPI m/m", "country": "USD", "date": "2024-01-11T08:30:00-05:00", "impact": "High"}]
+func ParseEvents(data []byte) ([]types.CalendarEvent, error) {
+	var raw []struct {
+		Title   string `json:"title"`
+		Country string `json:"country"`
+		Date    string `json:"date"`
+		Impact  string `json:"impact"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("解析经济日历失败: %w", err)
+	}
+
+	events := make([]types.CalendarEvent, 0, len(raw))
+	for _, item := range raw {
+		t, err := time.Parse(time.RFC3339, item.Date)
+		if err != nil {
+			continue // 全天事件等没有具体时间的条目
+		}
+		events = append(events, types.CalendarEvent{
+			Title:   item.Title,
+			Country: item.Country,
+			Time:    t,
+			Impact:  strings.ToLower(item.Impact),
+		})
+	}
+	return events, nil
+}
+
+// Nearby 返回t前后窗口内、影响程度不低于min_impact且距离最近的事件，没有时返回nil
+func (c *Calendar) Nearby(t time.Time) *types.CalendarEvent {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	minRank := impactRanks[c.config.MinImpact]
+	var nearest *types.CalendarEvent
+	var nearestDiff time.Duration
+	for i := range c.events {
+		event := &c.events[i]
+		if rank, ok := impactRanks[event.Impact]; !ok || rank < minRank {
+			continue
+		}
+		diff := t.Sub(event.Time)
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff <= c.config.Window && (nearest == nil || diff < nearestDiff) {
+			nearest, nearestDiff = event, diff
+		}
+	}
+	if nearest == nil {
+		return nil
+	}
+	event := *nearest
+	return &event
+}
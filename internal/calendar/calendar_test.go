@@ -0,0 +1,41 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"okx-market-sentry/pkg/types"
+)
+
+const feed = `[
+	{"title": "CPI m/m", "country": "USD", "date": "2024-01-11T08:30:00-05:00", "impact": "High"},
+	{"title": "Unemployment Claims", "country": "USD", "date": "2024-01-11T08:40:00-05:00", "impact": "Medium"},
+	{"title": "Bank Holiday", "country": "JPY", "date": "All Day", "impact": "Holiday"}
+]`
+
+func TestNearby(t *testing.T) {
+	events, err := ParseEvents([]byte(feed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2 (全天事件应跳过)", len(events))
+	}
+
+	c := New(types.CalendarConfig{Window: 15 * time.Minute, MinImpact: "high"}, nil)
+	c.events = events
+	cpi := time.Date(2024, 1, 11, 13, 30, 0, 0, time.UTC)
+
+	if event := c.Nearby(cpi.Add(10 * time.Minute)); event == nil || event.Title != "CPI m/m" {
+		t.Errorf("CPI后10分钟: got %v, want CPI m/m", event)
+	}
+	if event := c.Nearby(cpi.Add(-20 * time.Minute)); event != nil {
+		t.Errorf("超出窗口: got %v, want nil", event)
+	}
+
+	// 放宽到medium时返回最近的事件
+	c.config.MinImpact = "medium"
+	if event := c.Nearby(cpi.Add(9 * time.Minute)); event == nil || event.Title != "Unemployment Claims" {
+		t.Errorf("got %v, want Unemployment Claims", event)
+	}
+}
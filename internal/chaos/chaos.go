@@ -0,0 +1,125 @@
+package chaos
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// 注入的故障类型
+const (
+	FaultNone      = ""
+	FaultTimeout   = "timeout"   // 请求挂起，直到客户端超时或ctx取消
+	FaultError     = "error"     // 返回HTTP 503
+	FaultMalformed = "malformed" // 返回截断的响应体，模拟损坏的JSON
+)
+
+// Transport 包装HTTP传输层，按配置随机注入延迟、超时、服务错误和损坏的响应体，
+// 用于在测试或预发环境中演练重试、超时和解析错误的处理
+type Transport struct {
+	next   http.RoundTripper
+	config types.ChaosConfig
+
+	mutex sync.Mutex
+	rng   *rand.Rand
+}
+
+// NewTransport 创建注入故障的传输层，next为nil时使用http.DefaultTransport
+func NewTransport(next http.RoundTripper, config types.ChaosConfig) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Transport{
+		next:   next,
+		config: config,
+		rng:    rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	delay, fault := t.draw()
+	if delay > 0 {
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+
+	switch fault {
+	case FaultTimeout:
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	case FaultError:
+		return &http.Response{
+			Status:     "503 Service Unavailable",
+			StatusCode: http.StatusServiceUnavailable,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"code":"50001","msg":"chaos: service unavailable","data":[]}`)),
+			Request:    req,
+		}, nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || fault != FaultMalformed {
+		return resp, err
+	}
+
+	// 读取真实响应后只保留前一半，得到无法解析的JSON
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	body = body[:len(body)/2]
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Del("Content-Length")
+	return resp, nil
+}
+
+// draw 随机决定本次请求的附加延迟和注入的故障
+func (t *Transport) draw() (time.Duration, string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var delay time.Duration
+	if t.config.Latency > 0 {
+		delay = time.Duration(t.rng.Int63n(int64(t.config.Latency) + 1))
+	}
+
+	r := t.rng.Float64()
+	switch {
+	case r < t.config.TimeoutRate:
+		return delay, FaultTimeout
+	case r < t.config.TimeoutRate+t.config.ErrorRate:
+		return delay, FaultError
+	case r < t.config.TimeoutRate+t.config.ErrorRate+t.config.MalformedRate:
+		return delay, FaultMalformed
+	}
+	return delay, FaultNone
+}
+
+// sleep 等待指定时长，ctx取消时提前返回
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
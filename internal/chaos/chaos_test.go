@@ -0,0 +1,107 @@
+package chaos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"okx-market-sentry/pkg/types"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":"0","msg":"","data":[{"instId":"BTC-USDT","last":"64250.5"}]}`)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func get(t *testing.T, config types.ChaosConfig, url string, timeout time.Duration) (*http.Response, []byte, error) {
+	t.Helper()
+	client := &http.Client{Timeout: timeout, Transport: NewTransport(nil, config)}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return resp, body, err
+}
+
+func TestNoFault(t *testing.T) {
+	server := newTestServer(t)
+	resp, body, err := get(t, types.ChaosConfig{Enabled: true}, server.URL, time.Second)
+	if err != nil || resp.StatusCode != http.StatusOK || !json.Valid(body) {
+		t.Fatalf("got status %v body %q err %v", resp, body, err)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	server := newTestServer(t)
+	start := time.Now()
+	_, _, err := get(t, types.ChaosConfig{Enabled: true, TimeoutRate: 1}, server.URL, 50*time.Millisecond)
+	var netErr interface{ Timeout() bool }
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("want timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("timeout took %s", elapsed)
+	}
+}
+
+func TestServiceError(t *testing.T) {
+	server := newTestServer(t)
+	resp, _, err := get(t, types.ChaosConfig{Enabled: true, ErrorRate: 1}, server.URL, time.Second)
+	if err != nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("want 503, got %v err %v", resp, err)
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	server := newTestServer(t)
+	resp, body, err := get(t, types.ChaosConfig{Enabled: true, MalformedRate: 1}, server.URL, time.Second)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("got %v err %v", resp, err)
+	}
+	if len(body) == 0 || json.Valid(body) {
+		t.Errorf("want truncated JSON, got %q", body)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	server := newTestServer(t)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, _, err := get(t, types.ChaosConfig{Enabled: true, Latency: 20 * time.Millisecond, Seed: int64(i + 1)}, server.URL, time.Second); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("5 requests with at most 20ms latency took %s", elapsed)
+	}
+}
+
+func TestSeedReproducible(t *testing.T) {
+	config := types.ChaosConfig{Enabled: true, TimeoutRate: 0.2, ErrorRate: 0.2, MalformedRate: 0.2, Seed: 42}
+	a, b := NewTransport(nil, config), NewTransport(nil, config)
+	faults := map[string]int{}
+	for i := 0; i < 200; i++ {
+		_, fa := a.draw()
+		_, fb := b.draw()
+		if fa != fb {
+			t.Fatalf("draw %d: %q != %q", i, fa, fb)
+		}
+		faults[fa]++
+	}
+	for _, fault := range []string{FaultNone, FaultTimeout, FaultError, FaultMalformed} {
+		if faults[fault] == 0 {
+			t.Errorf("fault %q never injected: %v", fault, faults)
+		}
+	}
+}
@@ -10,39 +10,100 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	okxcommon "github.com/nntaoli-project/goex/v2/okx/common"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.uber.org/zap"
+	"okx-market-sentry/internal/chaos"
+	"okx-market-sentry/internal/slo"
 	"okx-market-sentry/internal/storage"
+	"okx-market-sentry/pkg/logger"
+	"okx-market-sentry/pkg/tracing"
 	"okx-market-sentry/pkg/types"
 )
 
+// log 模块日志器，级别可通过 log.modules.fetcher 单独配置
+func log() *zap.Logger {
+	return logger.Named("fetcher")
+}
+
 // DataFetcher 数据获取器
 type DataFetcher struct {
 	storage    *storage.StateManager
 	interval   time.Duration
 	okxClient  *okxcommon.OKxV5
 	httpClient *http.Client // 自定义HTTP客户端
+	sloTracker *slo.Tracker
+
+	shard      Shard           // 多实例分片，未启用时为nil
+	shardKeeps map[string]bool // 不属于本实例也需存储的交易对，如相关性基准
+
+	// 运行统计
+	statsMutex     sync.RWMutex
+	lastFetchTime  time.Time // 最近一次成功获取的时间
+	lastError      string    // 最近一次失败的错误信息
+	successCount   int
+	failureCount   int
+	failureStreak  int       // 连续失败次数，成功后清零
+	lastUSDTSymbol int       // 最近一次成功获取的USDT交易对数量
+	lastSkipped    int       // 最近一次因分片跳过的USDT交易对数量
+	lastHeartbeat  time.Time // 获取循环最近一次完成的时间，无论成功失败
 }
 
-func NewDataFetcher(stateManager *storage.StateManager, networkConfig types.NetworkConfig) *DataFetcher {
+func NewDataFetcher(stateManager *storage.StateManager, networkConfig types.NetworkConfig, fetchConfig types.FetchConfig, sloTracker *slo.Tracker) *DataFetcher {
 	// 使用goex v2 OKX客户端
 	client := okxcommon.New()
 
+	// 获取间隔，未配置时每分钟获取一次
+	interval := fetchConfig.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	httpClient := NewHTTPClient(networkConfig)
+
+	// 通过反射或其他方式设置HTTP客户端（goex v2可能需要不同的方法）
+	// 暂时先创建基础客户端，后续在请求中使用自定义HTTP客户端
+
+	log().Info("✅ 初始化goex v2 OKX客户端", zap.Duration("timeout", httpClient.Timeout), zap.Duration("interval", interval))
+
+	return &DataFetcher{
+		storage:    stateManager,
+		interval:   interval,
+		okxClient:  client,
+		httpClient: httpClient, // 保存自定义HTTP客户端供后续使用
+		sloTracker: sloTracker,
+	}
+}
+
+// Shard 多实例分片，判断交易对是否由本实例获取
+type Shard interface {
+	Owns(symbol string) bool
+}
+
+// SetShard 启用分片，只存储本实例负责的交易对和keeps中的交易对，需在Start前调用
+func (f *DataFetcher) SetShard(shard Shard, keeps ...string) {
+	f.shard = shard
+	f.shardKeeps = make(map[string]bool, len(keeps))
+	for _, symbol := range keeps {
+		f.shardKeeps[symbol] = true
+	}
+}
+
+// NewHTTPClient 按网络配置创建访问OKX的HTTP客户端（超时、代理）
+func NewHTTPClient(networkConfig types.NetworkConfig) *http.Client {
 	// 设置超时时间
 	timeout := networkConfig.Timeout
 	if timeout == 0 {
 		timeout = 30 * time.Second
 	}
 
-	// 创建自定义HTTP客户端
-	httpClient := &http.Client{
-		Timeout: timeout,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: false,
-			},
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: false,
 		},
 	}
 
@@ -50,74 +111,186 @@ func NewDataFetcher(stateManager *storage.StateManager, networkConfig types.Netw
 	if networkConfig.Proxy != "" {
 		proxyURL, err := url.Parse(networkConfig.Proxy)
 		if err == nil {
-			httpClient.Transport.(*http.Transport).Proxy = http.ProxyURL(proxyURL)
-			zap.L().Info("✅ 已配置HTTP代理", zap.String("proxy", networkConfig.Proxy))
+			transport.Proxy = http.ProxyURL(proxyURL)
+			log().Info("✅ 已配置HTTP代理", zap.String("proxy", networkConfig.Proxy))
 		} else {
-			zap.L().Warn("⚠️ 代理地址格式错误", zap.Error(err))
+			log().Warn("⚠️ 代理地址格式错误", zap.Error(err))
 		}
 	}
 
-	// 通过反射或其他方式设置HTTP客户端（goex v2可能需要不同的方法）
-	// 暂时先创建基础客户端，后续在请求中使用自定义HTTP客户端
-
-	zap.L().Info("✅ 初始化goex v2 OKX客户端", zap.Duration("timeout", timeout))
+	// 创建自定义HTTP客户端
+	httpClient := &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
 
-	return &DataFetcher{
-		storage:    stateManager,
-		interval:   1 * time.Minute,
-		okxClient:  client,
-		httpClient: httpClient, // 保存自定义HTTP客户端供后续使用
+	// 故障注入，演练重试、超时和解析错误的处理
+	if chaosConfig := networkConfig.Chaos; chaosConfig.Enabled {
+		httpClient.Transport = chaos.NewTransport(transport, chaosConfig)
+		log().Warn("🧪 已开启故障注入，访问OKX的请求将随机出现延迟、超时和错误响应，请勿在生产环境使用",
+			zap.Duration("latency", chaosConfig.Latency),
+			zap.Float64("timeout_rate", chaosConfig.TimeoutRate),
+			zap.Float64("error_rate", chaosConfig.ErrorRate),
+			zap.Float64("malformed_rate", chaosConfig.MalformedRate))
 	}
+	return httpClient
 }
 
 func (f *DataFetcher) Start(ctx context.Context) {
-	zap.L().Info("🚀 数据获取器启动，开始获取OKX V5真实市场数据...")
+	log().Info("🚀 数据获取器启动，开始获取OKX V5真实市场数据...")
+
+	f.beat()
 
 	ticker := time.NewTicker(f.interval)
 	defer ticker.Stop()
 
 	// 立即执行一次
-	f.fetchAndStore()
+	f.fetchAndStore(ctx)
 
 	for {
 		select {
 		case <-ctx.Done():
-			zap.L().Info("📴 数据获取器已停止")
+			log().Info("📴 数据获取器已停止")
 			return
 		case <-ticker.C:
-			f.fetchAndStore()
+			f.fetchAndStore(ctx)
 		}
 	}
 }
 
-func (f *DataFetcher) fetchAndStore() {
-	zap.L().Info("🔄 正在使用goex v2获取OKX市场数据...",
+func (f *DataFetcher) fetchAndStore(ctx context.Context) {
+	ctx, span := tracing.Tracer().Start(ctx, "fetcher.fetch_and_store")
+	defer span.End()
+	defer f.beat()
+
+	log().Info("🔄 正在使用goex v2获取OKX市场数据...",
 		zap.String("time", time.Now().Format("15:04:05")))
 
 	// 获取所有现货交易对的ticker数据
-	tickers, err := f.getTickers()
+	tickers, err := f.getTickers(ctx)
 	if err != nil {
-		zap.L().Error("❌ 获取市场数据失败", zap.Error(err))
+		log().Error("❌ 获取市场数据失败", zap.Error(err))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "fetch failed")
+		f.recordFailure(err)
 		return
 	}
 
 	count := len(tickers)
 	usdtCount := 0
+	skipped := 0
+	// 同一批次使用相同时间戳，便于跨交易对按时间对齐
+	now := time.Now()
 
+	_, storeSpan := tracing.Tracer().Start(ctx, "storage.store")
 	for _, ticker := range tickers {
 		// 检查是否为USDT交易对并存储价格数据
 		if strings.HasSuffix(ticker.InstId, "-USDT") {
+			if f.shard != nil && !f.shard.Owns(ticker.InstId) && !f.shardKeeps[ticker.InstId] {
+				skipped++
+				continue
+			}
 			// 解析价格字符串为float64
 			if price, err := strconv.ParseFloat(ticker.Last, 64); err == nil && price > 0 {
-				f.storage.Store(ticker.InstId, price, time.Now())
+				f.storage.Store(ticker.InstId, price, now)
+				if stats, ok := parseTicker24h(ticker); ok {
+					f.storage.StoreTicker24h(ticker.InstId, stats, now)
+				}
 				usdtCount++
 			}
 		}
 	}
 
-	zap.L().Info("✅ 获取到交易对数据",
+	storeSpan.SetAttributes(attribute.Int("symbols", usdtCount))
+	storeSpan.End()
+
+	log().Info("✅ 获取到交易对数据",
 		zap.Int("total_count", count),
-		zap.Int("usdt_count", usdtCount))
+		zap.Int("usdt_count", usdtCount),
+		zap.Int("shard_skipped", skipped))
+	f.recordSuccess(now, usdtCount, skipped)
+}
+
+// recordSuccess 记录一次成功的获取
+func (f *DataFetcher) recordSuccess(fetchTime time.Time, usdtCount, skipped int) {
+	f.sloTracker.RecordFetch(true)
+
+	f.statsMutex.Lock()
+	defer f.statsMutex.Unlock()
+
+	f.lastFetchTime = fetchTime
+	f.lastUSDTSymbol = usdtCount
+	f.lastSkipped = skipped
+	f.successCount++
+	f.failureStreak = 0
+}
+
+// recordFailure 记录一次失败的获取
+func (f *DataFetcher) recordFailure(err error) {
+	f.sloTracker.RecordFetch(false)
+
+	f.statsMutex.Lock()
+	defer f.statsMutex.Unlock()
+
+	f.lastError = err.Error()
+	f.failureCount++
+	f.failureStreak++
+}
+
+// beat 记录获取循环的心跳
+func (f *DataFetcher) beat() {
+	f.statsMutex.Lock()
+	defer f.statsMutex.Unlock()
+	f.lastHeartbeat = time.Now()
+}
+
+// LastHeartbeat 获取循环最近一次心跳时间
+func (f *DataFetcher) LastHeartbeat() time.Time {
+	f.statsMutex.RLock()
+	defer f.statsMutex.RUnlock()
+	return f.lastHeartbeat
+}
+
+// HeartbeatInterval 正常情况下两次心跳的最大间隔
+func (f *DataFetcher) HeartbeatInterval() time.Duration {
+	return f.interval
+}
+
+// FailureStreak 获取连续失败次数
+func (f *DataFetcher) FailureStreak() int {
+	f.statsMutex.RLock()
+	defer f.statsMutex.RUnlock()
+	return f.failureStreak
+}
+
+// HasFetched 是否至少成功获取过一次数据
+func (f *DataFetcher) HasFetched() bool {
+	f.statsMutex.RLock()
+	defer f.statsMutex.RUnlock()
+	return f.successCount > 0
+}
+
+// GetStats 获取数据获取器的运行统计
+func (f *DataFetcher) GetStats() map[string]interface{} {
+	f.statsMutex.RLock()
+	defer f.statsMutex.RUnlock()
+
+	stats := map[string]interface{}{
+		"interval":      f.interval.String(),
+		"success_count": f.successCount,
+		"failure_count": f.failureCount,
+		"usdt_symbols":  f.lastUSDTSymbol,
+	}
+	if f.shard != nil {
+		stats["shard_skipped_symbols"] = f.lastSkipped
+	}
+	if !f.lastFetchTime.IsZero() {
+		stats["last_fetch_time"] = f.lastFetchTime
+	}
+	if f.lastError != "" {
+		stats["last_error"] = f.lastError
+	}
+	return stats
 }
 
 // Ticker 定义ticker响应结构
@@ -132,20 +305,48 @@ type Ticker struct {
 	Ts        string `json:"ts"`
 }
 
+// parseTicker24h 解析ticker中的24小时统计，开盘/最高/最低价缺失或无效时返回false
+func parseTicker24h(ticker Ticker) (types.Ticker24h, bool) {
+	var stats types.Ticker24h
+	var err error
+	if stats.Open, err = strconv.ParseFloat(ticker.Open24h, 64); err != nil || stats.Open <= 0 {
+		return stats, false
+	}
+	if stats.High, err = strconv.ParseFloat(ticker.High24h, 64); err != nil || stats.High <= 0 {
+		return stats, false
+	}
+	if stats.Low, err = strconv.ParseFloat(ticker.Low24h, 64); err != nil || stats.Low <= 0 {
+		return stats, false
+	}
+	// 成交量缺失时按0处理，不影响价格相关统计
+	stats.Volume, _ = strconv.ParseFloat(ticker.Vol24h, 64)
+	stats.QuoteVolume, _ = strconv.ParseFloat(ticker.VolCcy24h, 64)
+	return stats, true
+}
+
 // getTickers 使用自定义HTTP客户端直接获取OKX ticker数据（支持代理）
-func (f *DataFetcher) getTickers() ([]Ticker, error) {
+func (f *DataFetcher) getTickers(ctx context.Context) ([]Ticker, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "okx.get_tickers")
+	defer span.End()
+
 	// 重试机制：最多重试3次
 	var lastErr error
 	for attempt := 1; attempt <= 3; attempt++ {
 		if attempt > 1 {
-			zap.L().Info("🔄 重试获取数据", zap.Int("attempt", attempt))
+			log().Info("🔄 重试获取数据", zap.Int("attempt", attempt))
 			time.Sleep(time.Duration(attempt) * time.Second) // 指数退避
 		}
 
 		// 直接使用自定义HTTP客户端发送请求，绕过goex库的限制
 		apiURL := "https://www.okx.com/api/v5/market/tickers?instType=SPOT"
 
-		resp, err := f.httpClient.Get(apiURL)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("创建请求失败: %v", err)
+		}
+
+		span.SetAttributes(attribute.Int("attempt", attempt))
+		resp, err := f.httpClient.Do(req)
 		if err != nil {
 			lastErr = fmt.Errorf("HTTP请求失败(第%d次尝试): %v", attempt, err)
 			continue
@@ -190,7 +391,7 @@ func (f *DataFetcher) getTickers() ([]Ticker, error) {
 			}
 		}
 
-		zap.L().Info("📊 使用代理从交易对中筛选出USDT交易对",
+		log().Info("📊 使用代理从交易对中筛选出USDT交易对",
 			zap.Int("total_pairs", len(apiResp.Data)),
 			zap.Int("usdt_pairs", len(usdtTickers)))
 		return usdtTickers, nil
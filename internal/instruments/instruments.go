@@ -0,0 +1,178 @@
+package instruments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"okx-market-sentry/pkg/logger"
+)
+
+// log 模块日志器，级别可通过 log.modules.instruments 单独配置
+func log() *zap.Logger {
+	return logger.Named("instruments")
+}
+
+const (
+	defaultBaseURL = "https://www.okx.com"
+	// refreshInterval 交易对的价格精度很少变化，定期刷新以覆盖新上线的交易对
+	refreshInterval = 6 * time.Hour
+)
+
+// defaultNames 常见币种的项目名称，配置 symbol_names 可覆盖或补充
+var defaultNames = map[string]string{
+	"BTC":  "Bitcoin",
+	"ETH":  "Ethereum",
+	"SOL":  "Solana",
+	"XRP":  "XRP Ledger",
+	"BNB":  "BNB Chain",
+	"DOGE": "Dogecoin",
+	"ADA":  "Cardano",
+	"TRX":  "TRON",
+	"TON":  "Toncoin",
+	"AVAX": "Avalanche",
+	"DOT":  "Polkadot",
+	"LINK": "Chainlink",
+	"LTC":  "Litecoin",
+	"BCH":  "Bitcoin Cash",
+	"SHIB": "Shiba Inu",
+	"UNI":  "Uniswap",
+	"NEAR": "NEAR Protocol",
+	"APT":  "Aptos",
+	"SUI":  "Sui",
+	"ARB":  "Arbitrum",
+	"OP":   "Optimism",
+	"PEPE": "Pepe",
+	"OKB":  "OKB",
+	"USDC": "USD Coin",
+}
+
+// Registry 交易对的展示信息，供通知和看板使用
+type Registry struct {
+	httpClient *http.Client
+	baseURL    string
+
+	mutex     sync.RWMutex
+	names     map[string]string  // 配置的项目名称，键为交易对或基础币种（大写）
+	tickSizes map[string]float64 // 交易对的价格最小变动单位（tickSz）
+}
+
+func NewRegistry(names map[string]string, httpClient *http.Client) *Registry {
+	r := &Registry{
+		httpClient: httpClient,
+		baseURL:    defaultBaseURL,
+	}
+	r.SetNames(names)
+	return r
+}
+
+// Start 立即加载一次交易对的价格精度，之后定期刷新，直到ctx取消
+func (r *Registry) Start(ctx context.Context) {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	r.refresh(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.refresh(ctx)
+		}
+	}
+}
+
+// refresh 重新加载价格精度，失败时保留上一次的数据，价格按最短精确形式显示
+func (r *Registry) refresh(ctx context.Context) {
+	tickSizes, err := r.loadTickSizes(ctx)
+	if err != nil {
+		log().Warn("⚠️ 获取交易对价格精度失败，继续使用上一次的数据", zap.Error(err))
+		return
+	}
+
+	r.mutex.Lock()
+	r.tickSizes = tickSizes
+	r.mutex.Unlock()
+	log().Debug("📐 交易对价格精度已更新", zap.Int("instruments", len(tickSizes)))
+}
+
+// loadTickSizes 从OKX公开接口获取全部现货交易对的tickSz
+func (r *Registry) loadTickSizes(ctx context.Context) (map[string]float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.baseURL+"/api/v5/public/instruments?instType=SPOT", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP状态码错误: %d", resp.StatusCode)
+	}
+
+	var apiResp struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+		Data []struct {
+			InstID string `json:"instId"`
+			TickSz string `json:"tickSz"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("解析API响应失败: %w", err)
+	}
+	if apiResp.Code != "0" {
+		return nil, fmt.Errorf("API返回错误: %s - %s", apiResp.Code, apiResp.Msg)
+	}
+
+	tickSizes := make(map[string]float64, len(apiResp.Data))
+	for _, item := range apiResp.Data {
+		if tickSize, err := strconv.ParseFloat(item.TickSz, 64); err == nil && tickSize > 0 {
+			tickSizes[item.InstID] = tickSize
+		}
+	}
+	return tickSizes, nil
+}
+
+// TickSize 交易对的价格最小变动单位，尚未加载或未知时返回0
+func (r *Registry) TickSize(symbol string) float64 {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.tickSizes[symbol]
+}
+
+// SetNames 更新配置的项目名称，支持热加载
+// 配置键不区分大小写（viper读取后均为小写），统一转为大写匹配
+func (r *Registry) SetNames(names map[string]string) {
+	normalized := make(map[string]string, len(names))
+	for key, name := range names {
+		normalized[strings.ToUpper(key)] = name
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.names = normalized
+}
+
+// Name 交易对的项目名称，依次查找配置的交易对、配置的基础币种和内置名称，未知时返回空
+func (r *Registry) Name(symbol string) string {
+	base, _, _ := strings.Cut(symbol, "-")
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if name, ok := r.names[symbol]; ok {
+		return name
+	}
+	if name, ok := r.names[base]; ok {
+		return name
+	}
+	return defaultNames[base]
+}
@@ -0,0 +1,54 @@
+package instruments
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestName(t *testing.T) {
+	r := NewRegistry(map[string]string{"sol": "Solana Mainnet", "wif-usdt": "dogwifhat"}, nil)
+
+	tests := map[string]string{
+		"SOL-USDT":  "Solana Mainnet", // 配置覆盖内置名称
+		"WIF-USDT":  "dogwifhat",      // 按交易对配置
+		"BTC-USDT":  "Bitcoin",        // 内置名称
+		"ABCD-USDT": "",
+	}
+	for symbol, want := range tests {
+		if got := r.Name(symbol); got != want {
+			t.Errorf("Name(%q) = %q, want %q", symbol, got, want)
+		}
+	}
+}
+
+func TestRefreshTickSizes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/api/v5/public/instruments" || req.URL.Query().Get("instType") != "SPOT" {
+			t.Errorf("unexpected request: %s", req.URL)
+		}
+		fmt.Fprint(w, `{"code":"0","msg":"","data":[
+			{"instId":"BTC-USDT","tickSz":"0.1"},
+			{"instId":"PEPE-USDT","tickSz":"0.0000000001"},
+			{"instId":"BAD-USDT","tickSz":""}]}`)
+	}))
+	defer server.Close()
+
+	r := NewRegistry(nil, server.Client())
+	r.baseURL = server.URL
+	r.refresh(context.Background())
+
+	tests := map[string]float64{
+		"BTC-USDT":  0.1,
+		"PEPE-USDT": 1e-10,
+		"BAD-USDT":  0,
+		"ETH-USDT":  0,
+	}
+	for symbol, want := range tests {
+		if got := r.TickSize(symbol); got != want {
+			t.Errorf("TickSize(%q) = %v, want %v", symbol, got, want)
+		}
+	}
+}
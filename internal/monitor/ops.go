@@ -0,0 +1,196 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+
+	"go.uber.org/zap"
+	"okx-market-sentry/internal/fetcher"
+	"okx-market-sentry/internal/notifier"
+	"okx-market-sentry/internal/scheduler"
+	"okx-market-sentry/internal/storage"
+	"okx-market-sentry/pkg/logger"
+	"okx-market-sentry/pkg/types"
+)
+
+// log 模块日志器，级别可通过 log.modules.monitor 单独配置
+func log() *zap.Logger {
+	return logger.Named("monitor")
+}
+
+// Heartbeater 需要看门狗检查的核心循环
+type Heartbeater interface {
+	LastHeartbeat() time.Time
+	HeartbeatInterval() time.Duration
+}
+
+// OpsMonitor 系统自检，在自身组件异常时发送运维告警
+type OpsMonitor struct {
+	config       types.OpsAlertConfig
+	dataFetcher  *fetcher.DataFetcher
+	stateManager *storage.StateManager
+	notifier     notifier.Interface
+	loops        map[string]Heartbeater // 看门狗检查的核心循环
+	activeIssues map[string]time.Time   // 当前异常组件 -> 上次告警时间
+
+	scheduler        *scheduler.Scheduler
+	lastMissedCycles int64 // 上次自检时累计错过的分析周期数
+}
+
+func NewOpsMonitor(opsConfig types.OpsAlertConfig, dataFetcher *fetcher.DataFetcher, taskScheduler *scheduler.Scheduler, stateManager *storage.StateManager, notifyService notifier.Interface) *OpsMonitor {
+	return &OpsMonitor{
+		config:       opsConfig,
+		dataFetcher:  dataFetcher,
+		stateManager: stateManager,
+		notifier:     notifyService,
+		loops: map[string]Heartbeater{
+			"fetcher_loop":   dataFetcher,
+			"scheduler_loop": taskScheduler,
+		},
+		activeIssues: make(map[string]time.Time),
+		scheduler:    taskScheduler,
+	}
+}
+
+func (m *OpsMonitor) Start(ctx context.Context) {
+	interval := m.config.CheckInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	log().Info("🩺 系统自检已启动", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log().Info("📴 系统自检已停止")
+			return
+		case <-ticker.C:
+			m.check(ctx)
+		}
+	}
+}
+
+// check 执行一轮自检，按组件汇总异常并处理告警与恢复
+func (m *OpsMonitor) check(ctx context.Context) {
+	issues := make(map[string]string)
+
+	if m.config.FetchFailureStreak > 0 {
+		if streak := m.dataFetcher.FailureStreak(); streak >= m.config.FetchFailureStreak {
+			issues["fetcher"] = fmt.Sprintf("行情获取连续失败%d次", streak)
+		}
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	if err := m.stateManager.PingRedis(pingCtx); err != nil {
+		issues["redis"] = fmt.Sprintf("Redis不可达: %v", err)
+	}
+	cancel()
+
+	if reporter, ok := m.notifier.(notifier.HealthReporter); ok && m.config.NotifyFailureStreak > 0 {
+		if streak := reporter.FailureStreak(); streak >= m.config.NotifyFailureStreak {
+			issues["notifier"] = fmt.Sprintf("通知渠道连续投递失败%d次，已降级为控制台输出", streak)
+		}
+	}
+
+	if m.config.MemoryLimitMB > 0 {
+		var mem runtime.MemStats
+		runtime.ReadMemStats(&mem)
+		if heapMB := mem.HeapAlloc / 1024 / 1024; heapMB >= uint64(m.config.MemoryLimitMB) {
+			issues["memory"] = fmt.Sprintf("堆内存占用%dMB，超过阈值%dMB", heapMB, m.config.MemoryLimitMB)
+		}
+	}
+
+	m.checkHeartbeats(issues)
+	m.checkMissedCycles(issues)
+
+	m.process(issues)
+}
+
+// checkHeartbeats 看门狗：核心循环超过预期间隔加宽限期仍无心跳时视为卡死
+func (m *OpsMonitor) checkHeartbeats(issues map[string]string) {
+	if m.config.StallGrace <= 0 {
+		return
+	}
+
+	for name, silence := range stalledLoops(m.loops, m.config.StallGrace) {
+		issues[name] = fmt.Sprintf("核心循环已%s无进展，可能已卡死", silence.Round(time.Second))
+	}
+}
+
+// checkMissedCycles 自上次自检以来有分析时间点被跳过时告警，下一轮不再错过时发送恢复通知
+func (m *OpsMonitor) checkMissedCycles(issues map[string]string) {
+	if m.scheduler == nil {
+		return
+	}
+	missed := m.scheduler.MissedCycles()
+	if delta := missed - m.lastMissedCycles; delta > 0 {
+		issues["scheduler_missed"] = fmt.Sprintf("最近%s内错过%d个分析时间点，分析耗时过长或进程曾被挂起", m.config.CheckInterval, delta)
+	}
+	m.lastMissedCycles = missed
+}
+
+// stalledLoops 返回超过预期间隔加宽限期仍无心跳的循环及其无心跳时长，尚未启动的循环不计入
+func stalledLoops(loops map[string]Heartbeater, grace time.Duration) map[string]time.Duration {
+	stalled := make(map[string]time.Duration)
+	for name, loop := range loops {
+		last := loop.LastHeartbeat()
+		if last.IsZero() {
+			continue // 尚未启动
+		}
+		if silence := time.Since(last); silence > loop.HeartbeatInterval()+grace {
+			stalled[name] = silence
+		}
+	}
+	return stalled
+}
+
+// process 新出现或持续超过重复间隔的异常发送告警，已消失的异常发送恢复通知
+func (m *OpsMonitor) process(issues map[string]string) {
+	now := time.Now()
+
+	for component, message := range issues {
+		lastAlert, active := m.activeIssues[component]
+		if active && (m.config.RepeatInterval <= 0 || now.Sub(lastAlert) < m.config.RepeatInterval) {
+			continue
+		}
+
+		log().Warn("🛠️ 系统自检发现异常",
+			zap.String("component", component),
+			zap.String("message", message))
+		m.send(&types.OpsAlert{
+			Component: component,
+			Message:   message,
+			AlertTime: now,
+		})
+		m.activeIssues[component] = now
+	}
+
+	for component := range m.activeIssues {
+		if _, stillFailing := issues[component]; stillFailing {
+			continue
+		}
+
+		log().Info("✅ 系统组件已恢复", zap.String("component", component))
+		m.send(&types.OpsAlert{
+			Component: component,
+			Recovered: true,
+			Message:   "组件已恢复正常",
+			AlertTime: now,
+		})
+		delete(m.activeIssues, component)
+	}
+}
+
+func (m *OpsMonitor) send(alert *types.OpsAlert) {
+	if err := m.notifier.SendOpsAlert(alert); err != nil {
+		log().Error("发送运维告警失败",
+			zap.String("component", alert.Component),
+			zap.Error(err))
+	}
+}
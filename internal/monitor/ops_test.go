@@ -0,0 +1,103 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// recordingNotifier 记录发送的运维告警
+type recordingNotifier struct {
+	opsAlerts []*types.OpsAlert
+}
+
+func (n *recordingNotifier) SendAlert(*types.AlertData) error         { return nil }
+func (n *recordingNotifier) SendBatchAlerts([]*types.AlertData) error { return nil }
+func (n *recordingNotifier) SendNotice(*types.Notice) error           { return nil }
+func (n *recordingNotifier) SendOpsAlert(alert *types.OpsAlert) error {
+	n.opsAlerts = append(n.opsAlerts, alert)
+	return nil
+}
+
+func TestOpsMonitorProcess(t *testing.T) {
+	type sent struct {
+		component string
+		recovered bool
+	}
+
+	tests := []struct {
+		name           string
+		repeatInterval time.Duration
+		active         map[string]time.Duration // 已告警组件 -> 距上次告警的时间
+		issues         map[string]string
+		want           []sent
+	}{
+		{
+			name:   "新异常告警",
+			issues: map[string]string{"redis": "Redis不可达"},
+			want:   []sent{{"redis", false}},
+		},
+		{
+			name:           "重复间隔内不重复告警",
+			repeatInterval: time.Hour,
+			active:         map[string]time.Duration{"redis": 10 * time.Minute},
+			issues:         map[string]string{"redis": "Redis不可达"},
+		},
+		{
+			name:           "超过重复间隔再次告警",
+			repeatInterval: time.Hour,
+			active:         map[string]time.Duration{"redis": 2 * time.Hour},
+			issues:         map[string]string{"redis": "Redis不可达"},
+			want:           []sent{{"redis", false}},
+		},
+		{
+			name:   "重复间隔为0时只告警一次",
+			active: map[string]time.Duration{"redis": 2 * time.Hour},
+			issues: map[string]string{"redis": "Redis不可达"},
+		},
+		{
+			name:   "异常消失发送恢复通知",
+			active: map[string]time.Duration{"fetcher": time.Minute},
+			issues: map[string]string{},
+			want:   []sent{{"fetcher", true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifyService := &recordingNotifier{}
+			m := &OpsMonitor{
+				config:       types.OpsAlertConfig{RepeatInterval: tt.repeatInterval},
+				notifier:     notifyService,
+				activeIssues: make(map[string]time.Time),
+			}
+			for component, ago := range tt.active {
+				m.activeIssues[component] = time.Now().Add(-ago)
+			}
+
+			m.process(tt.issues)
+
+			if len(notifyService.opsAlerts) != len(tt.want) {
+				t.Fatalf("sent %d alerts, want %d", len(notifyService.opsAlerts), len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := notifyService.opsAlerts[i]
+				if got.Component != want.component || got.Recovered != want.recovered {
+					t.Errorf("alert %d = %s recovered=%v, want %s recovered=%v",
+						i, got.Component, got.Recovered, want.component, want.recovered)
+				}
+			}
+
+			// 处理后当前异常应全部处于活跃状态，已恢复的被移除
+			for component := range tt.issues {
+				if _, ok := m.activeIssues[component]; !ok {
+					t.Errorf("%s 应处于活跃状态", component)
+				}
+			}
+			if len(m.activeIssues) != len(tt.issues) {
+				t.Errorf("activeIssues = %v, want %d entries", m.activeIssues, len(tt.issues))
+			}
+		})
+	}
+}
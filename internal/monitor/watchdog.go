@@ -0,0 +1,61 @@
+package monitor
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"okx-market-sentry/internal/fetcher"
+	"okx-market-sentry/internal/scheduler"
+	"okx-market-sentry/pkg/systemd"
+)
+
+// SystemdWatchdog 核心循环正常时定期向 systemd 发送 WATCHDOG=1
+// 循环卡死时停止发送，由 systemd 在 WatchdogSec 超时后重启进程
+type SystemdWatchdog struct {
+	interval time.Duration // systemd配置的看门狗超时
+	grace    time.Duration // 核心循环超过预期间隔多久无心跳视为卡死，0为只上报进程存活
+	loops    map[string]Heartbeater
+}
+
+// NewSystemdWatchdog 未在 systemd 看门狗下运行时返回nil
+func NewSystemdWatchdog(dataFetcher *fetcher.DataFetcher, taskScheduler *scheduler.Scheduler, grace time.Duration) *SystemdWatchdog {
+	interval := systemd.WatchdogInterval()
+	if interval <= 0 {
+		return nil
+	}
+	return &SystemdWatchdog{
+		interval: interval,
+		grace:    grace,
+		loops: map[string]Heartbeater{
+			"fetcher_loop":   dataFetcher,
+			"scheduler_loop": taskScheduler,
+		},
+	}
+}
+
+func (w *SystemdWatchdog) Start(ctx context.Context) {
+	// 按超时的一半发送，留出调度余量
+	ticker := time.NewTicker(w.interval / 2)
+	defer ticker.Stop()
+
+	log().Info("🐶 已启用systemd看门狗", zap.Duration("watchdog_sec", w.interval))
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if stalled := stalledLoops(w.loops, w.grace); w.grace > 0 && len(stalled) > 0 {
+				for name, silence := range stalled {
+					log().Error("❌ 核心循环无进展，停止向systemd发送看门狗心跳",
+						zap.String("loop", name),
+						zap.Duration("silence", silence.Round(time.Second)))
+				}
+				continue
+			}
+			if _, err := systemd.Notify(systemd.Watchdog); err != nil {
+				log().Warn("发送systemd看门狗心跳失败", zap.Error(err))
+			}
+		}
+	}
+}
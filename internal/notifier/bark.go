@@ -0,0 +1,236 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"okx-market-sentry/pkg/types"
+)
+
+// Bark推送的中断级别，timeSensitive可突破iOS专注模式
+const (
+	BarkLevelActive        = "active"
+	BarkLevelTimeSensitive = "timeSensitive"
+	BarkLevelPassive       = "passive"
+	BarkLevelCritical      = "critical"
+)
+
+// BarkNotifier Bark通知器，向iPhone发送原生推送
+type BarkNotifier struct {
+	deliveryStats
+	console    *ConsoleNotifier // 发送失败时降级输出
+	config     types.BarkConfig
+	enabled    bool
+	dryRun     bool // 演练模式，只记录渲染后的消息不实际发送
+	httpClient *http.Client
+}
+
+// BarkRequest Bark推送请求
+type BarkRequest struct {
+	DeviceKey string `json:"device_key"`
+	Title     string `json:"title"`
+	Body      string `json:"body"`
+	Sound     string `json:"sound,omitempty"`
+	Level     string `json:"level,omitempty"`
+	Group     string `json:"group,omitempty"`
+	URL       string `json:"url,omitempty"` // 点击推送打开的链接
+}
+
+// BarkResponse Bark API响应
+type BarkResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func NewBarkNotifier(barkConfig types.BarkConfig, dryRun bool, console *ConsoleNotifier) Interface {
+	// 如果没有配置设备密钥，返回控制台通知器
+	if barkConfig.DeviceKey == "" {
+		log().Info("🔧 未配置Bark设备密钥，使用控制台输出模式")
+		return console
+	}
+
+	log().Info("✅ 已配置Bark推送服务", zap.String("server_url", barkConfig.ServerURL))
+	return &BarkNotifier{
+		console: console,
+		config:  barkConfig,
+		enabled: true,
+		dryRun:  dryRun,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (bn *BarkNotifier) SendAlert(alert *types.AlertData) error {
+	if !bn.enabled {
+		// 降级为控制台输出
+		return bn.console.SendAlert(alert)
+	}
+
+	title := fmt.Sprintf("%s OKX%s - %s", alertEmoji(alert), alertKindLabel(alert), displaySymbol(alert))
+	err := bn.push(title, plainAlertBody(alert), bn.config.Level, buildTradingURL(alert.Symbol))
+	bn.record(err)
+	if err != nil {
+		log().Error("❌ Bark发送失败，降级为控制台输出",
+			zap.String("channel", "bark"),
+			zap.String("symbol", alert.Symbol),
+			zap.Error(err))
+		// 降级为控制台输出
+		return bn.console.SendAlert(alert)
+	}
+
+	log().Info("✅ Bark通知已发送",
+		zap.String("channel", "bark"),
+		zap.String("symbol", alert.Symbol),
+		zap.Float64("change_percent", alert.ChangePercent))
+	return nil
+}
+
+func (bn *BarkNotifier) SendBatchAlerts(alerts []*types.AlertData) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	if len(alerts) == 1 {
+		return bn.SendAlert(alerts[0])
+	}
+
+	if !bn.enabled {
+		// 降级为控制台输出
+		return bn.console.SendBatchAlerts(alerts)
+	}
+
+	title := fmt.Sprintf("📊 OKX批量价格预警 - %d个币种", len(alerts))
+	err := bn.push(title, barkBatchBody(alerts), bn.config.Level, "")
+	bn.record(err)
+	if err != nil {
+		log().Error("❌ Bark批量发送失败，降级为控制台输出",
+			zap.String("channel", "bark"),
+			zap.Error(err))
+		// 降级为控制台输出
+		return bn.console.SendBatchAlerts(alerts)
+	}
+
+	log().Info("✅ Bark批量通知已发送",
+		zap.String("channel", "bark"),
+		zap.Int("alert_count", len(alerts)))
+	return nil
+}
+
+func (bn *BarkNotifier) SendOpsAlert(alert *types.OpsAlert) error {
+	if !bn.enabled {
+		return bn.console.SendOpsAlert(alert)
+	}
+
+	// 恢复通知不需要打断专注模式
+	level := bn.config.Level
+	if alert.Recovered {
+		level = BarkLevelActive
+	}
+	body := fmt.Sprintf("组件: %s\n详情: %s\n时间: %s",
+		alert.Component, alert.Message, alert.AlertTime.Format("2006-01-02 15:04:05"))
+
+	err := bn.push(opsAlertTitle(alert), body, level, "")
+	bn.record(err)
+	if err != nil {
+		log().Error("❌ Bark运维告警发送失败，降级为控制台输出",
+			zap.String("channel", "bark"),
+			zap.String("component", alert.Component),
+			zap.Error(err))
+		return bn.console.SendOpsAlert(alert)
+	}
+	return nil
+}
+
+func (bn *BarkNotifier) SendNotice(notice *types.Notice) error {
+	if !bn.enabled {
+		return bn.console.SendNotice(notice)
+	}
+
+	// 资讯通知不打断专注模式
+	err := bn.push(noticeTitle(notice), strings.Join(noticeLines(notice), "\n"), BarkLevelActive, notice.URL)
+	bn.record(err)
+	if err != nil {
+		log().Error("❌ Bark资讯通知发送失败，降级为控制台输出",
+			zap.String("channel", "bark"),
+			zap.String("title", notice.Title),
+			zap.Error(err))
+		return bn.console.SendNotice(notice)
+	}
+	return nil
+}
+
+// barkBatchBody 批量预警的推送正文，上涨在前、下跌在后，每组最多显示8个
+func barkBatchBody(alerts []*types.AlertData) string {
+	// 按上涨和下跌分组，超过逐条列出上限的预警按幅度分档计数
+	groups := groupBatch(alerts)
+
+	lines := []string{fmt.Sprintf("上涨 %d 个，下跌 %d 个", groups.upTotal, groups.downTotal)}
+	for _, group := range []struct {
+		alerts    []*types.AlertData
+		direction string
+	}{{groups.up, "上涨"}, {groups.down, "下跌"}} {
+		maxShow := groups.show(8) // 每个分组最多显示8个
+		for i, alert := range group.alerts[:min(len(group.alerts), maxShow)] {
+			lines = append(lines, strings.TrimSpace(batchLine(i+1, alert)))
+		}
+		if len(group.alerts) > maxShow {
+			lines = append(lines, fmt.Sprintf("... 还有%d个%s币种", len(group.alerts)-maxShow, group.direction))
+		}
+	}
+	if title := groups.omittedTitle(); title != "" {
+		lines = append(lines, title)
+		lines = append(lines, groups.omittedLines()...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// push 发送Bark推送
+func (bn *BarkNotifier) push(title, body, level, link string) error {
+	if bn.dryRun {
+		logDryRun("bark", title, body)
+		return nil
+	}
+
+	// 构建请求数据
+	reqData := BarkRequest{
+		DeviceKey: bn.config.DeviceKey,
+		Title:     title,
+		Body:      body,
+		Sound:     bn.config.Sound,
+		Level:     level,
+		Group:     bn.config.Group,
+		URL:       link,
+	}
+
+	// 序列化为JSON
+	jsonData, err := json.Marshal(reqData)
+	if err != nil {
+		return fmt.Errorf("序列化请求数据失败: %v", err)
+	}
+
+	// 发送HTTP请求
+	resp, err := bn.httpClient.Post(strings.TrimRight(bn.config.ServerURL, "/")+"/push", "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("HTTP请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// 解析响应
+	var barkResp BarkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&barkResp); err != nil {
+		return fmt.Errorf("解析响应失败: %v", err)
+	}
+
+	// 检查返回结果
+	if barkResp.Code != 200 {
+		return fmt.Errorf("Bark API错误 [%d]: %s", barkResp.Code, barkResp.Message)
+	}
+
+	return nil
+}
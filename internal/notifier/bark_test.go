@@ -0,0 +1,87 @@
+package notifier
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// barkServer 模拟Bark服务，记录收到的推送并返回指定的状态码
+func barkServer(t *testing.T, code int, received *[]BarkRequest) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/push" {
+			t.Errorf("请求路径 = %s, want /push", r.URL.Path)
+		}
+		var req BarkRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("请求体不是JSON: %v", err)
+		}
+		*received = append(*received, req)
+		_ = json.NewEncoder(w).Encode(BarkResponse{Code: code, Message: "failed to get device token"})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestBarkAlertPush(t *testing.T) {
+	var received []BarkRequest
+	server := barkServer(t, 200, &received)
+	bn := NewBarkNotifier(types.BarkConfig{
+		ServerURL: server.URL + "/",
+		DeviceKey: "device-key",
+		Sound:     "alarm",
+		Level:     BarkLevelTimeSensitive,
+		Group:     "OKX Sentry",
+	}, false, &ConsoleNotifier{mode: ConsoleModeLog, out: io.Discard})
+
+	if err := bn.SendAlert(testAlert("SOL-USDT", -3.67)); err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 1 {
+		t.Fatalf("收到 %d 个请求，want 1", len(received))
+	}
+	req := received[0]
+	if req.DeviceKey != "device-key" || req.Sound != "alarm" || req.Level != BarkLevelTimeSensitive || req.Group != "OKX Sentry" {
+		t.Errorf("推送参数: %+v", req)
+	}
+	if !strings.Contains(req.Title, "SOL-USDT") || !strings.Contains(req.Body, "$198.20") || !strings.Contains(req.Body, "-3.67%") {
+		t.Errorf("推送内容: %q / %q", req.Title, req.Body)
+	}
+	if !strings.Contains(req.URL, "SOLUSDT") {
+		t.Errorf("点击链接 = %q", req.URL)
+	}
+
+	// 恢复通知不打断专注模式
+	if err := bn.SendOpsAlert(&types.OpsAlert{Component: "fetcher", Message: "已恢复", Recovered: true}); err != nil {
+		t.Fatal(err)
+	}
+	if last := received[len(received)-1]; last.Level != BarkLevelActive {
+		t.Errorf("恢复通知 level = %q, want active", last.Level)
+	}
+}
+
+func TestBarkBatchAndFailure(t *testing.T) {
+	var received []BarkRequest
+	server := barkServer(t, 400, &received)
+	bn := NewBarkNotifier(types.BarkConfig{ServerURL: server.URL, DeviceKey: "bad-key", Level: BarkLevelActive},
+		false, &ConsoleNotifier{mode: ConsoleModeLog, out: io.Discard}).(*BarkNotifier)
+
+	// 失败时降级为控制台输出，计入连续失败次数
+	if err := bn.SendBatchAlerts(benchAlerts(20)); err != nil {
+		t.Fatal(err)
+	}
+	if bn.FailureStreak() != 1 {
+		t.Errorf("FailureStreak = %d, want 1", bn.FailureStreak())
+	}
+
+	body := received[0].Body
+	if !strings.HasPrefix(body, "上涨 10 个，下跌 10 个") || !strings.Contains(body, "... 还有2个上涨币种") {
+		t.Errorf("批量正文:\n%s", body)
+	}
+}
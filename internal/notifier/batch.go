@@ -0,0 +1,167 @@
+package notifier
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"sync/atomic"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// batchLimit 批量预警逐条列出的数量上限和幅度分档，由 SetBatchLimit 设置，支持热加载
+var batchLimit atomic.Pointer[types.BatchConfig]
+
+// SetBatchLimit 设置批量预警只逐条列出幅度最大的前 max_detail 个，其余按涨跌幅分档计数
+// max_detail 为0时全部交给各渠道按默认数量展示
+func SetBatchLimit(config types.BatchConfig) {
+	batchLimit.Store(&config)
+}
+
+// batchGroups 批量预警按上涨/下跌分组，各组按幅度从大到小排序，各渠道的批量模板共用
+type batchGroups struct {
+	up, down           []*types.AlertData // 逐条列出的预警
+	upTotal, downTotal int                // 包含未逐条列出预警的各组总数
+	omitted            []batchBucket      // 未逐条列出的预警按幅度分档计数，幅度从高到低，只含非空分档
+	limited            bool               // 配置了逐条列出的上限，各渠道不再按默认数量截断
+}
+
+// batchBucket 一个幅度分档内未逐条列出的预警数量
+type batchBucket struct {
+	label    string
+	up, down int
+}
+
+// groupBatch 分组批量预警；配置了上限且预警数量超过上限时，只逐条列出涨跌幅绝对值最大的前N个
+func groupBatch(alerts []*types.AlertData) batchGroups {
+	var config types.BatchConfig
+	if loaded := batchLimit.Load(); loaded != nil {
+		config = *loaded
+	}
+
+	g := batchGroups{limited: config.MaxDetail > 0}
+	detail, rest := alerts, []*types.AlertData(nil)
+	if g.limited && len(alerts) > config.MaxDetail {
+		sorted := slices.Clone(alerts)
+		slices.SortStableFunc(sorted, func(a, b *types.AlertData) int {
+			return cmp.Compare(math.Abs(b.ChangePercent), math.Abs(a.ChangePercent))
+		})
+		detail, rest = sorted[:config.MaxDetail], sorted[config.MaxDetail:]
+	}
+
+	// 分离上涨和下跌的预警
+	for _, alert := range detail {
+		if alert.ChangePercent > 0 {
+			g.up = append(g.up, alert)
+		} else {
+			g.down = append(g.down, alert)
+		}
+	}
+
+	// 按涨跌幅排序：上涨按涨幅从高到低，下跌按跌幅从高到低（绝对值）
+	sort.Slice(g.up, func(i, j int) bool {
+		return g.up[i].ChangePercent > g.up[j].ChangePercent
+	})
+	sort.Slice(g.down, func(i, j int) bool {
+		return g.down[i].ChangePercent < g.down[j].ChangePercent // 负数，越小跌幅越大
+	})
+
+	g.upTotal, g.downTotal = len(g.up), len(g.down)
+	g.omitted = bucketAlerts(rest, config.Buckets)
+	for _, bucket := range g.omitted {
+		g.upTotal += bucket.up
+		g.downTotal += bucket.down
+	}
+	return g
+}
+
+// bucketAlerts 按涨跌幅绝对值分档计数，edges 为升序的分档边界，如 [3 5 10] 分为 <3%、3–5%、5–10%、>10%
+func bucketAlerts(alerts []*types.AlertData, edges []float64) []batchBucket {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	buckets := make([]batchBucket, len(edges)+1)
+	for i := range buckets {
+		buckets[i].label = bucketLabel(edges, i)
+	}
+	for _, alert := range alerts {
+		i := bucketIndex(edges, alert.ChangePercent)
+		if alert.ChangePercent > 0 {
+			buckets[i].up++
+		} else {
+			buckets[i].down++
+		}
+	}
+
+	// 幅度高的分档在前，省略空分档
+	result := make([]batchBucket, 0, len(buckets))
+	for i := len(buckets) - 1; i >= 0; i-- {
+		if buckets[i].up+buckets[i].down > 0 {
+			result = append(result, buckets[i])
+		}
+	}
+	return result
+}
+
+// bucketIndex 涨跌幅所在的分档序号，边界值归入较高的分档
+func bucketIndex(edges []float64, changePercent float64) int {
+	change := math.Abs(changePercent)
+	return sort.Search(len(edges), func(i int) bool { return edges[i] > change })
+}
+
+// bucketLabel 第i个分档的展示，如 <3%、3–5%、>10%
+func bucketLabel(edges []float64, i int) string {
+	switch {
+	case len(edges) == 0:
+		return "其他"
+	case i == 0:
+		return "<" + formatEdge(edges[0]) + "%"
+	case i == len(edges):
+		return ">" + formatEdge(edges[i-1]) + "%"
+	default:
+		return formatEdge(edges[i-1]) + "–" + formatEdge(edges[i]) + "%"
+	}
+}
+
+// formatEdge 分档边界的展示，整数不带小数
+func formatEdge(edge float64) string {
+	return strconv.FormatFloat(edge, 'f', -1, 64)
+}
+
+// show 各组最多逐条列出的数量：配置了上限时已在分组时截断，否则使用渠道的默认数量
+func (g batchGroups) show(fallback int) int {
+	if g.limited {
+		return max(len(g.up), len(g.down))
+	}
+	return fallback
+}
+
+// omittedCount 未逐条列出的预警数量
+func (g batchGroups) omittedCount() int {
+	n := 0
+	for _, bucket := range g.omitted {
+		n += bucket.up + bucket.down
+	}
+	return n
+}
+
+// omittedTitle 未逐条列出预警的统计标题，全部列出时为空
+func (g batchGroups) omittedTitle() string {
+	if len(g.omitted) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("另有%d个币种未逐条列出，按涨跌幅统计：", g.omittedCount())
+}
+
+// omittedLines 未逐条列出的预警每个分档一行，如 “5–10%: 上涨 12 个，下跌 30 个”
+func (g batchGroups) omittedLines() []string {
+	lines := make([]string, 0, len(g.omitted))
+	for _, bucket := range g.omitted {
+		lines = append(lines, fmt.Sprintf("%s: 上涨 %d 个，下跌 %d 个", bucket.label, bucket.up, bucket.down))
+	}
+	return lines
+}
@@ -0,0 +1,102 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// setBatchLimit 测试期间设置批量预警上限，结束后恢复为不限制
+func setBatchLimit(t *testing.T, config types.BatchConfig) {
+	t.Helper()
+	SetBatchLimit(config)
+	t.Cleanup(func() { batchLimit.Store(nil) })
+}
+
+func TestGroupBatchTopN(t *testing.T) {
+	setBatchLimit(t, types.BatchConfig{MaxDetail: 10, Buckets: []float64{3, 5, 10}})
+
+	alerts := benchAlerts(100) // 涨跌幅绝对值在3%到9%之间，涨跌交替
+	groups := groupBatch(alerts)
+
+	if got := len(groups.up) + len(groups.down); got != 10 {
+		t.Fatalf("逐条列出 %d 个，want 10", got)
+	}
+	for _, alert := range append(groups.up, groups.down...) {
+		if math.Abs(alert.ChangePercent) != 9 {
+			t.Errorf("%s 涨跌幅 %v 不在幅度最大的前10个中", alert.Symbol, alert.ChangePercent)
+		}
+	}
+	if groups.upTotal+groups.downTotal != 100 || groups.omittedCount() != 90 {
+		t.Errorf("upTotal=%d downTotal=%d omitted=%d", groups.upTotal, groups.downTotal, groups.omittedCount())
+	}
+
+	// 幅度高的分档在前，边界值归入较高的分档
+	var labels []string
+	for _, bucket := range groups.omitted {
+		labels = append(labels, bucket.label)
+	}
+	if got := strings.Join(labels, ","); got != "5–10%,3–5%" {
+		t.Errorf("分档 = %s, want 5–10%%,3–5%%", got)
+	}
+}
+
+func TestGroupBatchUnlimited(t *testing.T) {
+	alerts := benchAlerts(30)
+	groups := groupBatch(alerts)
+	if len(groups.up)+len(groups.down) != 30 || len(groups.omitted) != 0 || groups.omittedTitle() != "" {
+		t.Fatalf("未配置上限时应全部交给渠道展示: up=%d down=%d omitted=%v", len(groups.up), len(groups.down), groups.omitted)
+	}
+	if groups.show(8) != 8 {
+		t.Errorf("show = %d, want 渠道默认值 8", groups.show(8))
+	}
+}
+
+func TestBucketAlertsLabels(t *testing.T) {
+	alerts := []*types.AlertData{testAlert("A-USDT", 2.5), testAlert("B-USDT", -12), testAlert("C-USDT", 10.5)}
+	buckets := bucketAlerts(alerts, []float64{3, 5, 10})
+	if len(buckets) != 2 {
+		t.Fatalf("buckets = %+v", buckets)
+	}
+	if buckets[0] != (batchBucket{label: ">10%", up: 1, down: 1}) || buckets[1] != (batchBucket{label: "<3%", up: 1}) {
+		t.Errorf("buckets = %+v", buckets)
+	}
+}
+
+func TestBatchTemplatesSummarizeOverflow(t *testing.T) {
+	setBatchLimit(t, types.BatchConfig{MaxDetail: 4, Buckets: []float64{3, 5, 10}})
+	alerts := benchAlerts(40)
+
+	var console bytes.Buffer
+	(&ConsoleNotifier{mode: ConsoleModeTable, out: &console}).printTable(alerts)
+	dingtalk := (&DingTalkNotifier{}).buildBatchMarkdownContent(alerts)
+	feishu, err := json.Marshal((&FeishuNotifier{}).buildBatchCard(alerts))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outputs := map[string]string{
+		"html":     buildBatchHTMLContent(alerts),
+		"markdown": dingtalk,
+		"bark":     barkBatchBody(alerts),
+		"console":  console.String(),
+		"feishu":   string(feishu),
+	}
+	for name, content := range outputs {
+		if !strings.Contains(content, "另有36个币种未逐条列出") || !strings.Contains(content, "5–10%") {
+			t.Errorf("%s 缺少未列出预警的分档统计:\n%s", name, content)
+		}
+		if strings.Contains(content, "还有") {
+			t.Errorf("%s 配置上限后不应再按默认数量截断:\n%s", name, content)
+		}
+	}
+
+	// 统计数量包含未逐条列出的预警
+	if !strings.Contains(outputs["bark"], "上涨 20 个，下跌 20 个") {
+		t.Errorf("bark 统计: %s", outputs["bark"])
+	}
+}
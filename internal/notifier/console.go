@@ -0,0 +1,241 @@
+package notifier
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode"
+
+	"go.uber.org/zap"
+	"golang.org/x/text/width"
+	"okx-market-sentry/pkg/types"
+)
+
+// 控制台预警框的默认内容宽度（不含边框），终端较窄时按终端宽度收窄
+const (
+	alertBoxWidth = 60
+	batchBoxWidth = 80
+	// minBoxWidth 终端窄于该宽度时预警框难以阅读，改用紧凑表格输出
+	minBoxWidth = 40
+)
+
+// boxStyle 预警框的边框字符
+type boxStyle struct {
+	topLeft, topRight, bottomLeft, bottomRight, horizontal, vertical string
+}
+
+var (
+	doubleBox = boxStyle{"╔", "╗", "╚", "╝", "═", "║"} // 价格预警
+	singleBox = boxStyle{"┌", "┐", "└", "┘", "─", "│"} // 运维告警和资讯
+	asciiBox  = boxStyle{"+", "+", "+", "+", "-", "|"} // 纯文本输出
+)
+
+// terminalColumns 标准输出所在终端的宽度，环境变量 COLUMNS 优先，非终端或无法获取时返回0
+func terminalColumns() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	if !isTerminal(os.Stdout) {
+		return 0
+	}
+	return terminalWidth(os.Stdout)
+}
+
+// boxWidth 预警框的内容宽度，终端宽度未知时使用默认宽度；终端过窄时返回false，应改用表格输出
+func (cn *ConsoleNotifier) boxWidth(preferred int) (int, bool) {
+	columns := cn.columns()
+	if columns <= 0 {
+		return preferred, true
+	}
+	boxWidth := min(preferred, columns-2) // 左右边框各占1列
+	return boxWidth, boxWidth >= minBoxWidth
+}
+
+// printBox 输出带边框的预警框，按显示宽度补齐空格，超出宽度的行自动折行
+func (cn *ConsoleNotifier) printBox(style boxStyle, preferred int, lines []string) {
+	boxWidth, _ := cn.boxWidth(preferred)
+	if cn.noEmoji {
+		style = asciiBox
+	}
+
+	var b strings.Builder
+	b.WriteString("\n" + style.topLeft + strings.Repeat(style.horizontal, boxWidth) + style.topRight + "\n")
+	for _, line := range lines {
+		for _, part := range wrapLine(cn.plain(line), boxWidth-2) {
+			b.WriteString(style.vertical + " " + part + strings.Repeat(" ", max(boxWidth-2-displayWidth(part), 0)) + " " + style.vertical + "\n")
+		}
+	}
+	b.WriteString(style.bottomLeft + strings.Repeat(style.horizontal, boxWidth) + style.bottomRight + "\n\n")
+	fmt.Fprint(cn.out, b.String())
+}
+
+// printTable 紧凑表格输出预警，每个预警一行，按涨跌幅从高到低排列
+// 可能含中文的说明放在最后一列，避免tabwriter按字符数对齐时错位
+func (cn *ConsoleNotifier) printTable(alerts []*types.AlertData) {
+	groups := groupBatch(alerts)
+	sorted := append(slices.Clone(groups.up), groups.down...)
+	slices.SortStableFunc(sorted, func(a, b *types.AlertData) int {
+		return cmp.Compare(b.ChangePercent, a.ChangePercent)
+	})
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tSYMBOL\tPRICE\tCHANGE\tALERT")
+	for _, alert := range sorted {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			alert.AlertTime.Format("01-02 15:04:05"),
+			alert.Symbol,
+			formatTickPrice(alert.CurrentPrice, alert.TickSize),
+			formatPercent(alert.ChangePercent),
+			cn.plain(tableNote(alert)))
+	}
+	_ = w.Flush()
+	// 未逐条列出的预警按幅度分档计数
+	if title := groups.omittedTitle(); title != "" {
+		fmt.Fprintln(&b, title)
+		for _, line := range groups.omittedLines() {
+			fmt.Fprintln(&b, "  "+line)
+		}
+	}
+	fmt.Fprint(cn.out, b.String())
+}
+
+// printDigestTable 紧凑输出热力图摘要：标题行加“币种 涨跌幅”文本网格，网格宽度随终端收窄
+func (cn *ConsoleNotifier) printDigestTable(digest *types.Digest) {
+	width, _ := cn.boxWidth(batchBoxWidth)
+	cn.printLine(digest.Time, digestTitle(digest), digestSummary(digest))
+	for _, line := range digestGrid(digest, width) {
+		fmt.Fprintln(cn.out, line)
+	}
+}
+
+// consoleEvent JSON模式下输出的对象，type 为 alert/batch/ops_alert/notice/digest，对应字段携带数据
+type consoleEvent struct {
+	Type     string             `json:"type"`
+	Alert    *types.AlertData   `json:"alert,omitempty"`
+	Alerts   []*types.AlertData `json:"alerts,omitempty"`
+	OpsAlert *types.OpsAlert    `json:"ops_alert,omitempty"`
+	Notice   *types.Notice      `json:"notice,omitempty"`
+	Digest   *types.Digest      `json:"digest,omitempty"`
+}
+
+// printJSON 输出一行JSON对象
+func (cn *ConsoleNotifier) printJSON(event consoleEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log().Error("序列化控制台预警失败", zap.String("type", event.Type), zap.Error(err))
+		return
+	}
+	cn.out.Write(append(data, '\n'))
+}
+
+// printLine 表格模式下运维告警和资讯的单行输出
+func (cn *ConsoleNotifier) printLine(t time.Time, title, detail string) {
+	fmt.Fprintf(cn.out, "%s  %s  %s\n", t.Format("01-02 15:04:05"), cn.plain(title), cn.plain(detail))
+}
+
+// tableNote 表格中预警的说明：预警名称、周期、项目名称和24h涨跌
+func tableNote(alert *types.AlertData) string {
+	notes := []string{alertEmoji(alert) + " " + alertKindLabel(alert) + " " + periodLabel(alert)}
+	if alert.Name != "" {
+		notes = append(notes, alert.Name)
+	}
+	if alert.Stats24h != nil {
+		notes = append(notes, fmt.Sprintf("24h %+.2f%%", percentChange(alert.Stats24h.Open, alert.CurrentPrice)))
+	}
+	return strings.Join(notes, ", ")
+}
+
+// plain 纯文本模式下去掉emoji
+func (cn *ConsoleNotifier) plain(s string) string {
+	if !cn.noEmoji {
+		return s
+	}
+	return stripEmoji(s)
+}
+
+// stripEmoji 去掉emoji及紧随其后的空格，用于写入日志文件等不便显示emoji的场景
+func stripEmoji(s string) string {
+	var b strings.Builder
+	skipSpace := false
+	for _, r := range s {
+		if isEmoji(r) {
+			skipSpace = true
+			continue
+		}
+		if skipSpace && r == ' ' {
+			skipSpace = false
+			continue
+		}
+		skipSpace = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// isEmoji 判断字符是否为emoji或组成emoji的零宽连接符、变体选择符
+func isEmoji(r rune) bool {
+	return r >= 0x1F000 && r <= 0x1FAFF || // 表情、符号和图形
+		r >= 0x2600 && r <= 0x27BF || // 杂项符号和装饰符号，如 ⚡ ⚠
+		r >= 0x2300 && r <= 0x23FF || // 杂项技术符号，如 ⏰
+		r >= 0x2B00 && r <= 0x2BFF ||
+		r == 0x200D || r >= 0xFE00 && r <= 0xFE0F
+}
+
+// displayWidth 字符串在终端中的显示宽度：中文和emoji占2列，组合字符、零宽连接符不占宽度，
+// 带emoji变体选择符（U+FE0F）的符号按2列计算
+func displayWidth(s string) int {
+	total, last := 0, 0
+	for _, r := range s {
+		w := runeWidth(r)
+		if r == 0xFE0F && last == 1 {
+			w = 1 // 变体选择符使前一个符号按emoji显示
+		}
+		total += w
+		last = w
+	}
+	return total
+}
+
+// runeWidth 单个字符的显示宽度
+func runeWidth(r rune) int {
+	switch {
+	case r == 0x200D || r >= 0xFE00 && r <= 0xFE0F || unicode.Is(unicode.Mn, r):
+		return 0
+	case r >= 0x1F000 && r <= 0x1FAFF:
+		return 2
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// wrapLine 按显示宽度折行，maxWidth不大于0时不折行
+func wrapLine(line string, maxWidth int) []string {
+	if maxWidth <= 0 || displayWidth(line) <= maxWidth {
+		return []string{line}
+	}
+
+	var parts []string
+	var current strings.Builder
+	currentWidth := 0
+	for _, r := range line {
+		w := runeWidth(r)
+		if currentWidth+w > maxWidth && currentWidth > 0 {
+			parts = append(parts, current.String())
+			current.Reset()
+			currentWidth = 0
+		}
+		current.WriteRune(r)
+		currentWidth += w
+	}
+	return append(parts, current.String())
+}
@@ -0,0 +1,136 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"okx-market-sentry/pkg/types"
+)
+
+func newTestConsole(mode string, noEmoji bool, columns int) (*ConsoleNotifier, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return &ConsoleNotifier{
+		mode:    mode,
+		noEmoji: noEmoji,
+		out:     &buf,
+		columns: func() int { return columns },
+	}, &buf
+}
+
+func testAlert(symbol string, change float64) *types.AlertData {
+	return &types.AlertData{
+		Symbol:        symbol,
+		Name:          "Solana",
+		CurrentPrice:  198.2,
+		PastPrice:     205.75,
+		ChangePercent: change,
+		AlertTime:     time.Date(2025, 1, 23, 17, 21, 35, 0, time.Local),
+		MonitorPeriod: 5 * time.Minute,
+		TickSize:      0.01,
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	tests := map[string]int{
+		"SOL-USDT":  8,
+		"交易对":       6,
+		"📈 上涨":      7,
+		"⚠️ 注意":     7, // 带变体选择符的符号按emoji宽度计算
+		"$198.20 ✓": 9,
+	}
+	for s, want := range tests {
+		if got := displayWidth(s); got != want {
+			t.Errorf("displayWidth(%q) = %d, want %d", s, got, want)
+		}
+	}
+}
+
+func TestStripEmoji(t *testing.T) {
+	if got, want := stripEmoji("📈 上涨: 2个  📉 下跌: 1个 ⚠️ 注意"), "上涨: 2个  下跌: 1个 注意"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestPrintBoxAligned(t *testing.T) {
+	for _, columns := range []int{0, 50} {
+		cn, buf := newTestConsole(ConsoleModePretty, false, columns)
+		if err := cn.SendBatchAlerts([]*types.AlertData{testAlert("SOL-USDT", -3.67), testAlert("ETH-USDT", 4.12)}); err != nil {
+			t.Fatal(err)
+		}
+
+		lines := strings.Split(strings.Trim(buf.String(), "\n"), "\n")
+		want := displayWidth(lines[0])
+		if columns > 0 && want != columns {
+			t.Errorf("columns %d: box width %d", columns, want)
+		}
+		for _, line := range lines {
+			if displayWidth(line) != want {
+				t.Errorf("columns %d: misaligned line %q (%d != %d)", columns, line, displayWidth(line), want)
+			}
+		}
+	}
+}
+
+func TestNarrowTerminalUsesTable(t *testing.T) {
+	cn, buf := newTestConsole(ConsoleModePretty, true, 30)
+	if err := cn.SendAlert(testAlert("SOL-USDT", -3.67)); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "TIME") || !strings.Contains(out, "SOL-USDT") || !strings.Contains(out, "-3.67%") {
+		t.Errorf("unexpected table output:\n%s", out)
+	}
+	if strings.Contains(out, "📉") {
+		t.Errorf("emoji not stripped:\n%s", out)
+	}
+}
+
+func TestNoEmojiBox(t *testing.T) {
+	cn, buf := newTestConsole(ConsoleModePretty, true, 0)
+	if err := cn.SendAlert(testAlert("SOL-USDT", -3.67)); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, glyph := range []string{"📉", "🚨", "💡", "╔", "║"} {
+		if strings.Contains(out, glyph) {
+			t.Errorf("output contains %q:\n%s", glyph, out)
+		}
+	}
+	if !strings.Contains(out, "| 交易对: SOL-USDT (Solana)") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestJSONMode(t *testing.T) {
+	cn, buf := newTestConsole(ConsoleModeJSON, false, 0)
+	_ = cn.SendAlert(testAlert("SOL-USDT", -3.67))
+	_ = cn.SendBatchAlerts([]*types.AlertData{testAlert("SOL-USDT", -3.67), testAlert("ETH-USDT", 4.12)})
+	_ = cn.SendOpsAlert(&types.OpsAlert{Component: "redis", Message: "连接失败"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), buf.String())
+	}
+	var events []consoleEvent
+	for _, line := range lines {
+		var event consoleEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+		events = append(events, event)
+	}
+	if events[0].Type != "alert" || events[0].Alert.Symbol != "SOL-USDT" || events[0].Alert.ChangePercent != -3.67 {
+		t.Errorf("unexpected alert event: %+v", events[0])
+	}
+	if events[1].Type != "batch" || len(events[1].Alerts) != 2 {
+		t.Errorf("unexpected batch event: %+v", events[1])
+	}
+	if events[2].Type != "ops_alert" || events[2].OpsAlert.Component != "redis" {
+		t.Errorf("unexpected ops event: %+v", events[2])
+	}
+}
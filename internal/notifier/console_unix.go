@@ -0,0 +1,18 @@
+//go:build !windows
+
+package notifier
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// terminalWidth 通过TIOCGWINSZ获取终端宽度，失败时返回0
+func terminalWidth(f *os.File) int {
+	size, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0
+	}
+	return int(size.Col)
+}
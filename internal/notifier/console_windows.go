@@ -0,0 +1,10 @@
+//go:build windows
+
+package notifier
+
+import "os"
+
+// terminalWidth Windows下不检测终端宽度，可通过环境变量 COLUMNS 指定
+func terminalWidth(*os.File) int {
+	return 0
+}
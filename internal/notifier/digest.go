@@ -0,0 +1,176 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// DigestSender 可发送涨跌幅热力图摘要的通知器，目前PushPlus、邮件和控制台支持
+type DigestSender interface {
+	SendDigest(digest *types.Digest) error
+}
+
+// ErrDigestUnsupported 通知渠道不支持热力图摘要
+var ErrDigestUnsupported = errors.New("通知渠道不支持热力图摘要，可选 pushplus/email/console")
+
+// SendDigest 通过通知器发送热力图摘要，通知器不支持时返回 ErrDigestUnsupported
+func SendDigest(n Interface, digest *types.Digest) error {
+	if sender, ok := n.(DigestSender); ok {
+		return sender.SendDigest(digest)
+	}
+	return ErrDigestUnsupported
+}
+
+// digestScale 热力图颜色分级的涨跌幅边界，幅度越大颜色越深
+var digestScale = []float64{0.5, 1, 2, 3, 5, 10}
+
+// 各分级的背景色，从浅到深，与 digestScale 分出的档位一一对应
+var (
+	digestUpColors   = []string{"#E8F5E9", "#C8E6C9", "#A5D6A7", "#66BB6A", "#43A047", "#2E7D32", "#1B5E20"}
+	digestDownColors = []string{"#FFEBEE", "#FFCDD2", "#EF9A9A", "#E57373", "#E53935", "#C62828", "#B71C1C"}
+)
+
+// digestColor 涨跌幅对应的背景色和文字颜色，上涨为绿色、下跌为红色、持平为灰色，深色背景使用白色文字
+func digestColor(changePercent float64) (background, text string) {
+	level := bucketIndex(digestScale, changePercent)
+	text = "#333333"
+	if level >= 4 {
+		text = "#FFFFFF"
+	}
+	switch {
+	case changePercent > 0:
+		return digestUpColors[level], text
+	case changePercent < 0:
+		return digestDownColors[level], text
+	default:
+		return "#EEEEEE", "#666666"
+	}
+}
+
+// digestLabel 热力图格子中显示的基础币种，如 SOL-USDT 显示为 SOL
+func digestLabel(symbol string) string {
+	base, _, _ := strings.Cut(symbol, "-")
+	return base
+}
+
+// digestTitle 热力图摘要标题
+func digestTitle(digest *types.Digest) string {
+	return fmt.Sprintf("🌡️ OKX涨跌幅热力图 - 近%s %d个币种", formatDuration(digest.Window), digest.Total)
+}
+
+// digestSummary 热力图摘要的统计说明
+func digestSummary(digest *types.Digest) string {
+	return fmt.Sprintf("近%s 上涨 %d 个，下跌 %d 个，时间: %s",
+		formatDuration(digest.Window), digest.Up, digest.Down, digest.Time.Format("2006-01-02 15:04:05"))
+}
+
+// digestOmitted 超出展示上限、未在热力图中展示的说明，全部展示时为空
+func digestOmitted(digest *types.Digest) string {
+	if omitted := digest.Total - len(digest.Cells); omitted > 0 {
+		return fmt.Sprintf("另有%d个币种波动较小，未在图中展示", omitted)
+	}
+	return ""
+}
+
+// buildDigestHTML 以按颜色分级的网格渲染热力图摘要，PushPlus和邮件共用
+// 每个交易对一个小格子，按涨跌幅从高到低排列，颜色深浅表示幅度，格子之间不留空白以便一屏看到全部交易对
+func buildDigestHTML(digest *types.Digest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `
+<div style="border: 2px solid #1890ff; border-radius: 10px; padding: 20px; margin: 10px; background-color: #f9f9f9;">
+    <h2 style="color: #1890ff; text-align: center; margin-top: 0;">🌡️ 涨跌幅热力图</h2>
+    <p style="text-align: center; margin: 5px 0; color: #666;">近%s · 📈 上涨 <span style="color: #00C851; font-weight: bold;">%d</span> · 📉 下跌 <span style="color: #FF4444; font-weight: bold;">%d</span> · 🕐 %s</p>
+    <div style="margin: 10px 0;">`,
+		formatDuration(digest.Window), digest.Up, digest.Down, digest.Time.Format("2006-01-02 15:04:05"))
+
+	for _, cell := range digest.Cells {
+		background, text := digestColor(cell.ChangePercent)
+		fmt.Fprintf(&b, `<a href="%s" target="_blank" style="display:inline-block;width:68px;margin:1px;padding:4px 0;border-radius:3px;background-color:%s;color:%s;font-size:11px;line-height:14px;text-align:center;text-decoration:none;"><b>%s</b><br>%s</a>`,
+			buildTradingURL(cell.Symbol), background, text, html.EscapeString(digestLabel(cell.Symbol)), formatPercent(cell.ChangePercent))
+	}
+	b.WriteString(`</div>`)
+
+	if omitted := digestOmitted(digest); omitted != "" {
+		fmt.Fprintf(&b, `
+    <p style="margin: 5px 0; color: #666; font-style: italic;">%s</p>`, omitted)
+	}
+
+	// 图例：上涨和下跌各一行，从幅度小到大
+	b.WriteString(`
+    <div style="font-size: 11px; color: #666;">`)
+	for _, row := range []struct {
+		label string
+		sign  float64
+	}{{"📈", 1}, {"📉", -1}} {
+		fmt.Fprintf(&b, `
+        <div style="margin: 2px 0;">%s `, row.label)
+		for i := range len(digestScale) + 1 {
+			// 取档位内的一个涨跌幅计算颜色
+			sample := 0.5 * digestScale[0]
+			if i > 0 {
+				sample = digestScale[i-1]
+			}
+			background, text := digestColor(row.sign * sample)
+			fmt.Fprintf(&b, `<span style="display:inline-block;padding:1px 4px;margin:1px;background-color:%s;color:%s;">%s</span>`,
+				background, text, html.EscapeString(bucketLabel(digestScale, i)))
+		}
+		b.WriteString(`</div>`)
+	}
+	b.WriteString(`
+    </div>
+</div>
+`)
+	return b.String()
+}
+
+// digestCellText 文本中的一个交易对，如 SOL +3.67%
+func digestCellText(cell types.DigestCell) string {
+	return digestLabel(cell.Symbol) + " " + formatPercent(cell.ChangePercent)
+}
+
+// digestGrid 控制台输出的文本网格，每行排列尽量多的交易对，不超过width列
+func digestGrid(digest *types.Digest, width int) []string {
+	const cellWidth = 16
+	perRow := max(width/cellWidth, 1)
+
+	var lines []string
+	var row strings.Builder
+	for i, cell := range digest.Cells {
+		text := digestCellText(cell)
+		row.WriteString(text)
+		if (i+1)%perRow == 0 || i == len(digest.Cells)-1 {
+			lines = append(lines, row.String())
+			row.Reset()
+			continue
+		}
+		row.WriteString(strings.Repeat(" ", max(cellWidth-displayWidth(text), 1)))
+	}
+	if omitted := digestOmitted(digest); omitted != "" {
+		lines = append(lines, omitted)
+	}
+	return lines
+}
+
+// filterDigest 只保留指定交易对的热力图摘要，统计按保留的交易对重新计算
+func filterDigest(digest *types.Digest, symbols []string) *types.Digest {
+	filtered := &types.Digest{Window: digest.Window, Time: digest.Time}
+	for _, cell := range digest.Cells {
+		if !slices.Contains(symbols, cell.Symbol) {
+			continue
+		}
+		filtered.Cells = append(filtered.Cells, cell)
+		switch {
+		case cell.ChangePercent > 0:
+			filtered.Up++
+		case cell.ChangePercent < 0:
+			filtered.Down++
+		}
+	}
+	filtered.Total = len(filtered.Cells)
+	return filtered
+}
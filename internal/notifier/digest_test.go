@@ -0,0 +1,150 @@
+package notifier
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// testDigest 按给定涨跌幅生成热力图摘要，交易对依次为 SYM0-USDT、SYM1-USDT…
+func testDigest(changes ...float64) *types.Digest {
+	digest := &types.Digest{
+		Window: time.Hour,
+		Time:   time.Date(2025, 1, 23, 18, 0, 0, 0, time.Local),
+		Total:  len(changes),
+	}
+	for i, change := range changes {
+		digest.Cells = append(digest.Cells, types.DigestCell{
+			Symbol:        fmt.Sprintf("SYM%d-USDT", i),
+			Price:         1.5,
+			ChangePercent: change,
+		})
+		switch {
+		case change > 0:
+			digest.Up++
+		case change < 0:
+			digest.Down++
+		}
+	}
+	return digest
+}
+
+func TestDigestColor(t *testing.T) {
+	tests := []struct {
+		change           float64
+		background, text string
+	}{
+		{0.3, digestUpColors[0], "#333333"},
+		{0.5, digestUpColors[1], "#333333"}, // 边界值归入较高的分级
+		{-4, digestDownColors[4], "#FFFFFF"},
+		{12, digestUpColors[6], "#FFFFFF"},
+		{-12, digestDownColors[6], "#FFFFFF"},
+		{0, "#EEEEEE", "#666666"},
+	}
+	for _, tt := range tests {
+		background, text := digestColor(tt.change)
+		if background != tt.background || text != tt.text {
+			t.Errorf("digestColor(%v) = %s/%s, want %s/%s", tt.change, background, text, tt.background, tt.text)
+		}
+	}
+}
+
+func TestBuildDigestHTML(t *testing.T) {
+	digest := testDigest(12, 2.5, 0, -0.8, -6)
+	digest.Total = 8
+	content := buildDigestHTML(digest)
+
+	for _, want := range []string{
+		">SYM0</b><br>+12.00%", ">SYM4</b><br>-6.00%", "background-color:" + digestUpColors[6],
+		"background-color:" + digestDownColors[5], buildTradingURL("SYM3-USDT"),
+		"另有3个币种波动较小", ">0.5–1%<", "&gt;10%",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("热力图缺少 %q:\n%s", want, content)
+		}
+	}
+	if got := strings.Count(content, "display:inline-block;width:68px"); got != 5 {
+		t.Errorf("格子数量 = %d, want 5", got)
+	}
+
+	// 热力图每个交易对占用的篇幅远小于批量预警的逐行表格
+	alerts := benchAlerts(10)
+	perRow := len(buildBatchHTMLContent(alerts)) / len(alerts)
+	perCell := len(buildDigestHTML(testDigest(make([]float64, 200)...))) / 200
+	if perCell*2 > perRow {
+		t.Errorf("每个格子 %d 字节，逐行表格每行 %d 字节", perCell, perRow)
+	}
+}
+
+func TestDigestGrid(t *testing.T) {
+	digest := testDigest(3.67, 1, -0.25, -5)
+	lines := digestGrid(digest, 40)
+	if len(lines) != 2 || lines[0] != "SYM0 +3.67%     SYM1 +1.00%" || lines[1] != "SYM2 -0.25%     SYM3 -5.00%" {
+		t.Errorf("grid = %q", lines)
+	}
+	if lines := digestGrid(digest, 10); len(lines) != 4 {
+		t.Errorf("过窄时每行一个，grid = %q", lines)
+	}
+}
+
+func TestConsoleDigest(t *testing.T) {
+	var out bytes.Buffer
+	cn := &ConsoleNotifier{mode: ConsoleModePretty, out: &out, columns: func() int { return 100 }}
+	if err := cn.SendDigest(testDigest(3.67, -5)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "OKX涨跌幅热力图 - 近1.0小时 2个币种") || !strings.Contains(out.String(), "SYM1 -5.00%") {
+		t.Errorf("控制台输出:\n%s", out.String())
+	}
+
+	out.Reset()
+	cn.mode = ConsoleModeJSON
+	if err := cn.SendDigest(testDigest(1)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `"type":"digest"`) || !strings.Contains(out.String(), `"symbol":"SYM0-USDT"`) {
+		t.Errorf("JSON输出: %s", out.String())
+	}
+}
+
+func TestSendDigestRouting(t *testing.T) {
+	var all, majors bytes.Buffer
+	fanout := NewFanoutNotifier([]FanoutTarget{
+		{Notifier: &ConsoleNotifier{mode: ConsoleModeTable, out: &all, columns: func() int { return 0 }}},
+		{Label: "majors", Notifier: &ConsoleNotifier{mode: ConsoleModeTable, out: &majors, columns: func() int { return 0 }},
+			Symbols: []string{"SYM1-USDT"}},
+	})
+	dn := NewDynamicNotifier(&recordingNotifier{})
+	dn.SetChannels(map[string]Interface{
+		ChannelPushPlus: fanout,
+		ChannelBark:     &recordingNotifier{},
+		ChannelEmail:    NewEmailNotifier(types.EmailConfig{Host: "smtp.example.com", Recipients: []types.EmailRecipient{{Address: "ops@example.com"}}}, true, &ConsoleNotifier{mode: ConsoleModeLog, out: io.Discard}),
+	})
+
+	if err := SendDigest(dn.Channel(ChannelPushPlus), testDigest(2, -3)); err != nil {
+		t.Fatal(err)
+	}
+	// 配置了交易对过滤的目标只包含这些交易对，统计按保留的交易对计算
+	if !strings.Contains(all.String(), "SYM0 +2.00%") || strings.Contains(majors.String(), "SYM0") ||
+		!strings.Contains(majors.String(), "上涨 0 个，下跌 1 个") {
+		t.Errorf("all:\n%s\nmajors:\n%s", all.String(), majors.String())
+	}
+
+	// 演练模式的邮件只记录渲染结果
+	if err := SendDigest(dn.Channel(ChannelEmail), testDigest(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 不支持热力图的渠道和默认通知器返回错误
+	for _, sender := range []Interface{dn.Channel(ChannelBark), dn} {
+		if err := SendDigest(sender, testDigest(2)); !errors.Is(err, ErrDigestUnsupported) {
+			t.Errorf("err = %v, want ErrDigestUnsupported", err)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// FromConfig 根据配置选择通知服务（优先级：钉钉 > PushPlus > 飞书 > 邮件 > Bark > Webhook > 控制台）
+// 演练模式下远程渠道仍会渲染消息，但只记录日志不实际推送
+func FromConfig(cfg *types.Config) Interface {
+	return Preferred(ChannelsFromConfig(cfg))
+}
+
+// Preferred 按优先级（钉钉 > PushPlus > 飞书 > 邮件 > Bark > Webhook > 控制台）从已创建的通知渠道中选择默认渠道
+func Preferred(channels map[string]Interface) Interface {
+	for _, name := range []string{ChannelDingTalk, ChannelPushPlus, ChannelFeishu, ChannelEmail, ChannelBark, ChannelWebhook} {
+		if channel, ok := channels[name]; ok {
+			return channel
+		}
+	}
+	return channels[ChannelConsole]
+}
+
+// 通知渠道名称，预警配置组通过 channel 指定；钉钉和PushPlus的额外目标可通过“渠道:标签”单独指定
+const (
+	ChannelDingTalk = "dingtalk"
+	ChannelPushPlus = "pushplus"
+	ChannelFeishu   = "feishu"
+	ChannelEmail    = "email"
+	ChannelBark     = "bark"
+	ChannelWebhook  = "webhook"
+	ChannelConsole  = "console"
+)
+
+// ChannelsFromConfig 创建所有已配置的通知渠道，供预警配置组按名称路由
+func ChannelsFromConfig(cfg *types.Config) map[string]Interface {
+	console := NewConsoleNotifier(cfg.Console)
+	channels := map[string]Interface{ChannelConsole: console}
+
+	var dingtalk []FanoutTarget
+	if cfg.DingTalk.WebhookURL != "" {
+		dingtalk = append(dingtalk, FanoutTarget{
+			Notifier: NewDingTalkNotifier(cfg.DingTalk.WebhookURL, cfg.DingTalk.Secret, cfg.DryRun, console),
+		})
+	}
+	for _, target := range cfg.DingTalk.Targets {
+		dingtalk = append(dingtalk, FanoutTarget{
+			Label:    target.Label,
+			Notifier: NewDingTalkNotifier(target.WebhookURL, target.Secret, cfg.DryRun, console),
+			Symbols:  target.Symbols,
+			Profiles: target.Profiles,
+		})
+	}
+	addTargets(channels, ChannelDingTalk, dingtalk)
+
+	var pushplus []FanoutTarget
+	if cfg.PushPlus.UserToken != "" {
+		pushplus = append(pushplus, FanoutTarget{
+			Notifier: NewPushPlusNotifier(cfg.PushPlus.UserToken, cfg.PushPlus.To, cfg.DryRun, console),
+		})
+	}
+	for _, target := range cfg.PushPlus.Targets {
+		pushplus = append(pushplus, FanoutTarget{
+			Label:    target.Label,
+			Notifier: NewPushPlusNotifier(target.UserToken, target.To, cfg.DryRun, console),
+			Symbols:  target.Symbols,
+			Profiles: target.Profiles,
+		})
+	}
+	addTargets(channels, ChannelPushPlus, pushplus)
+
+	if cfg.Feishu.WebhookURL != "" {
+		channels[ChannelFeishu] = NewFeishuNotifier(cfg.Feishu.WebhookURL, cfg.Feishu.Secret, cfg.DryRun, console)
+	}
+	if cfg.Email.Host != "" && len(cfg.Email.Recipients) > 0 {
+		channels[ChannelEmail] = NewEmailNotifier(cfg.Email, cfg.DryRun, console)
+	}
+	if cfg.Bark.DeviceKey != "" {
+		channels[ChannelBark] = NewBarkNotifier(cfg.Bark, cfg.DryRun, console)
+	}
+	if len(cfg.Webhook.URLs) > 0 {
+		channels[ChannelWebhook] = NewWebhookNotifier(cfg.Webhook, cfg.DryRun, console)
+	}
+	return channels
+}
+
+// addTargets 注册渠道的全部目标：渠道名称发送给所有目标，“渠道:标签”只发送给该目标
+// 只有一个不带过滤条件的目标时直接使用该通知器
+func addTargets(channels map[string]Interface, channel string, targets []FanoutTarget) {
+	for _, target := range targets {
+		if target.Label != "" {
+			channels[channel+":"+target.Label] = target.Notifier
+		}
+	}
+	switch {
+	case len(targets) == 0:
+	case len(targets) == 1 && len(targets[0].Symbols) == 0 && len(targets[0].Profiles) == 0:
+		channels[channel] = targets[0].Notifier
+	default:
+		channels[channel] = NewFanoutNotifier(targets)
+	}
+}
+
+// DynamicNotifier 可在运行时替换底层通知器的包装，用于配置热加载
+type DynamicNotifier struct {
+	mutex    sync.RWMutex
+	current  Interface
+	channels map[string]Interface // 按名称路由的通知渠道，未找到时使用current
+
+	inflight      sync.WaitGroup // 正在发送的通知，关闭时等待
+	inflightCount atomic.Int64
+}
+
+func NewDynamicNotifier(initial Interface) *DynamicNotifier {
+	return &DynamicNotifier{
+		current: initial,
+	}
+}
+
+// Set 替换底层通知器
+func (dn *DynamicNotifier) Set(next Interface) {
+	dn.mutex.Lock()
+	defer dn.mutex.Unlock()
+	dn.current = next
+}
+
+// SetChannels 替换按名称路由的通知渠道
+func (dn *DynamicNotifier) SetChannels(channels map[string]Interface) {
+	dn.mutex.Lock()
+	defer dn.mutex.Unlock()
+	dn.channels = channels
+}
+
+func (dn *DynamicNotifier) get() Interface {
+	dn.mutex.RLock()
+	defer dn.mutex.RUnlock()
+	return dn.current
+}
+
+func (dn *DynamicNotifier) getChannel(name string) Interface {
+	dn.mutex.RLock()
+	defer dn.mutex.RUnlock()
+	if channel, ok := dn.channels[name]; ok {
+		return channel
+	}
+	return dn.current
+}
+
+// Channel 返回发送到指定渠道的通知器，name为空或渠道未配置时使用默认通知器
+// 返回的通知器同样计入正在发送的通知，并随热加载切换到新的渠道
+func (dn *DynamicNotifier) Channel(name string) Interface {
+	if name == "" {
+		return dn
+	}
+	return &channelNotifier{parent: dn, name: name}
+}
+
+// channelNotifier 路由到指定渠道的通知器
+type channelNotifier struct {
+	parent *DynamicNotifier
+	name   string
+}
+
+func (cn *channelNotifier) SendAlert(alert *types.AlertData) error {
+	defer cn.parent.track()()
+	return cn.parent.getChannel(cn.name).SendAlert(alert)
+}
+
+func (cn *channelNotifier) SendBatchAlerts(alerts []*types.AlertData) error {
+	defer cn.parent.track()()
+	return cn.parent.getChannel(cn.name).SendBatchAlerts(alerts)
+}
+
+func (cn *channelNotifier) SendOpsAlert(alert *types.OpsAlert) error {
+	defer cn.parent.track()()
+	return cn.parent.getChannel(cn.name).SendOpsAlert(alert)
+}
+
+func (cn *channelNotifier) SendNotice(notice *types.Notice) error {
+	defer cn.parent.track()()
+	return cn.parent.getChannel(cn.name).SendNotice(notice)
+}
+
+func (cn *channelNotifier) SendDigest(digest *types.Digest) error {
+	defer cn.parent.track()()
+	return SendDigest(cn.parent.getChannel(cn.name), digest)
+}
+
+// track 记录一次正在进行的发送，返回发送结束时调用的函数
+func (dn *DynamicNotifier) track() func() {
+	dn.inflight.Add(1)
+	dn.inflightCount.Add(1)
+	return func() {
+		dn.inflightCount.Add(-1)
+		dn.inflight.Done()
+	}
+}
+
+func (dn *DynamicNotifier) SendAlert(alert *types.AlertData) error {
+	defer dn.track()()
+	return dn.get().SendAlert(alert)
+}
+
+func (dn *DynamicNotifier) SendBatchAlerts(alerts []*types.AlertData) error {
+	defer dn.track()()
+	return dn.get().SendBatchAlerts(alerts)
+}
+
+func (dn *DynamicNotifier) SendOpsAlert(alert *types.OpsAlert) error {
+	defer dn.track()()
+	return dn.get().SendOpsAlert(alert)
+}
+
+func (dn *DynamicNotifier) SendNotice(notice *types.Notice) error {
+	defer dn.track()()
+	return dn.get().SendNotice(notice)
+}
+
+// SendDigest 转发热力图摘要，底层通知器不支持时返回 ErrDigestUnsupported
+func (dn *DynamicNotifier) SendDigest(digest *types.Digest) error {
+	defer dn.track()()
+	return SendDigest(dn.get(), digest)
+}
+
+// Drain 等待正在发送的通知完成，返回超时后仍未完成的发送数量
+func (dn *DynamicNotifier) Drain(ctx context.Context) int {
+	done := make(chan struct{})
+	go func() {
+		dn.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return 0
+	case <-ctx.Done():
+		return int(dn.inflightCount.Load())
+	}
+}
+
+// FailureStreak 转发底层通知器的投递健康状况，底层不支持时返回0
+func (dn *DynamicNotifier) FailureStreak() int {
+	if reporter, ok := dn.get().(HealthReporter); ok {
+		return reporter.FailureStreak()
+	}
+	return 0
+}
@@ -0,0 +1,48 @@
+package notifier
+
+import (
+	"testing"
+
+	"okx-market-sentry/pkg/types"
+)
+
+func TestDynamicNotifierReload(t *testing.T) {
+	oldDefault, newDefault := &recordingNotifier{}, &recordingNotifier{}
+	oldBark, newBark := &recordingNotifier{}, &recordingNotifier{}
+	dn := NewDynamicNotifier(oldDefault)
+	dn.SetChannels(map[string]Interface{ChannelBark: oldBark})
+
+	// 渠道通知器在热加载前获取，热加载后同样发送到新的渠道
+	bark := dn.Channel(ChannelBark)
+	_ = dn.SendAlert(&types.AlertData{Symbol: "BTC-USDT"})
+	_ = bark.SendAlert(&types.AlertData{Symbol: "ETH-USDT"})
+
+	dn.Set(newDefault)
+	dn.SetChannels(map[string]Interface{ChannelBark: newBark})
+	_ = dn.SendAlert(&types.AlertData{Symbol: "SOL-USDT"})
+	_ = bark.SendBatchAlerts([]*types.AlertData{{Symbol: "DOGE-USDT"}})
+	// 未配置的渠道使用默认通知器
+	_ = dn.Channel(ChannelEmail).SendAlert(&types.AlertData{Symbol: "XRP-USDT"})
+
+	for _, tt := range []struct {
+		name     string
+		notifier *recordingNotifier
+		want     []string
+	}{
+		{"旧默认", oldDefault, []string{"BTC-USDT"}},
+		{"旧bark", oldBark, []string{"ETH-USDT"}},
+		{"新默认", newDefault, []string{"SOL-USDT", "XRP-USDT"}},
+		{"新bark", newBark, []string{"DOGE-USDT"}},
+	} {
+		if len(tt.notifier.symbols) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.notifier.symbols, tt.want)
+			continue
+		}
+		for i := range tt.want {
+			if tt.notifier.symbols[i] != tt.want[i] {
+				t.Errorf("%s: got %v, want %v", tt.name, tt.notifier.symbols, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package notifier
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"okx-market-sentry/pkg/types"
+)
+
+// SMTP连接的加密方式
+const (
+	EmailSecuritySTARTTLS = "starttls" // 明文连接后升级为TLS，通常为587端口
+	EmailSecurityTLS      = "tls"      // 直接建立TLS连接，通常为465端口
+	EmailSecurityNone     = "none"     // 不加密，仅适用于本机或内网中继
+)
+
+// EmailNotifier 邮件通知器，通过SMTP发送HTML邮件，正文与PushPlus相同
+// 每个收件人按自己的过滤条件接收预警，同一轮的多个预警合并为一封摘要邮件
+type EmailNotifier struct {
+	deliveryStats
+	console  *ConsoleNotifier // 发送失败时降级输出
+	config   types.EmailConfig
+	enabled  bool
+	dryRun   bool                                    // 演练模式，只记录渲染后的消息不实际发送
+	sendMail func(to []string, message []byte) error // 投递邮件，默认通过SMTP发送
+}
+
+func NewEmailNotifier(emailConfig types.EmailConfig, dryRun bool, console *ConsoleNotifier) Interface {
+	// 如果没有配置SMTP服务器或收件人，返回控制台通知器
+	if emailConfig.Host == "" || len(emailConfig.Recipients) == 0 {
+		log().Info("🔧 未配置SMTP服务器或收件人，使用控制台输出模式")
+		return console
+	}
+
+	if emailConfig.Security == EmailSecurityNone && emailConfig.Username != "" {
+		log().Warn("⚠️ 邮件通知未加密，认证信息将以明文传输（建议使用 starttls 或 tls）")
+	} else {
+		log().Info("✅ 已配置邮件通知服务",
+			zap.String("host", emailConfig.Host),
+			zap.Int("recipients", len(emailConfig.Recipients)))
+	}
+
+	en := &EmailNotifier{
+		console: console,
+		config:  emailConfig,
+		enabled: true,
+		dryRun:  dryRun,
+	}
+	en.sendMail = en.deliver
+	return en
+}
+
+func (en *EmailNotifier) SendAlert(alert *types.AlertData) error {
+	if !en.enabled {
+		// 降级为控制台输出
+		return en.console.SendAlert(alert)
+	}
+
+	sent, err := en.sendAlerts([]*types.AlertData{alert})
+	if sent == 0 && err == nil {
+		log().Debug("没有收件人订阅该预警", zap.String("channel", "email"), zap.String("symbol", alert.Symbol))
+		return nil
+	}
+	en.record(err)
+	if err != nil {
+		log().Error("❌ 邮件发送失败，降级为控制台输出",
+			zap.String("channel", "email"),
+			zap.String("symbol", alert.Symbol),
+			zap.Error(err))
+		// 降级为控制台输出
+		return en.console.SendAlert(alert)
+	}
+
+	log().Info("✅ 邮件通知已发送",
+		zap.String("channel", "email"),
+		zap.String("symbol", alert.Symbol),
+		zap.Int("recipients", sent),
+		zap.Float64("change_percent", alert.ChangePercent))
+	return nil
+}
+
+func (en *EmailNotifier) SendBatchAlerts(alerts []*types.AlertData) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	if len(alerts) == 1 {
+		return en.SendAlert(alerts[0])
+	}
+
+	if !en.enabled {
+		// 降级为控制台输出
+		return en.console.SendBatchAlerts(alerts)
+	}
+
+	sent, err := en.sendAlerts(alerts)
+	if sent == 0 && err == nil {
+		log().Debug("没有收件人订阅本轮预警", zap.String("channel", "email"), zap.Int("alert_count", len(alerts)))
+		return nil
+	}
+	en.record(err)
+	if err != nil {
+		log().Error("❌ 邮件批量发送失败，降级为控制台输出",
+			zap.String("channel", "email"),
+			zap.Error(err))
+		// 降级为控制台输出
+		return en.console.SendBatchAlerts(alerts)
+	}
+
+	log().Info("✅ 邮件批量通知已发送",
+		zap.String("channel", "email"),
+		zap.Int("recipients", sent),
+		zap.Int("alert_count", len(alerts)))
+	return nil
+}
+
+func (en *EmailNotifier) SendOpsAlert(alert *types.OpsAlert) error {
+	if !en.enabled {
+		return en.console.SendOpsAlert(alert)
+	}
+
+	err := en.send(en.allRecipients(), opsAlertTitle(alert), opsAlertHTML(alert))
+	en.record(err)
+	if err != nil {
+		log().Error("❌ 邮件运维告警发送失败，降级为控制台输出",
+			zap.String("channel", "email"),
+			zap.String("component", alert.Component),
+			zap.Error(err))
+		return en.console.SendOpsAlert(alert)
+	}
+	return nil
+}
+
+func (en *EmailNotifier) SendNotice(notice *types.Notice) error {
+	if !en.enabled {
+		return en.console.SendNotice(notice)
+	}
+
+	err := en.send(en.allRecipients(), noticeTitle(notice), noticeHTML(notice))
+	en.record(err)
+	if err != nil {
+		log().Error("❌ 邮件资讯通知发送失败，降级为控制台输出",
+			zap.String("channel", "email"),
+			zap.String("title", notice.Title),
+			zap.Error(err))
+		return en.console.SendNotice(notice)
+	}
+	return nil
+}
+
+// SendDigest 热力图摘要发送给全部收件人
+func (en *EmailNotifier) SendDigest(digest *types.Digest) error {
+	if !en.enabled {
+		return en.console.SendDigest(digest)
+	}
+
+	err := en.send(en.allRecipients(), digestTitle(digest), buildDigestHTML(digest))
+	en.record(err)
+	if err != nil {
+		log().Error("❌ 邮件热力图摘要发送失败，降级为控制台输出",
+			zap.String("channel", "email"),
+			zap.Error(err))
+		return en.console.SendDigest(digest)
+	}
+
+	log().Info("✅ 邮件热力图摘要已发送",
+		zap.String("channel", "email"),
+		zap.Int("recipients", len(en.config.Recipients)),
+		zap.Int("symbol_count", len(digest.Cells)))
+	return nil
+}
+
+// sendAlerts 按收件人过滤预警，每个收件人一封邮件：只匹配一个预警时为单个预警格式，多个时为摘要
+// 返回发送的邮件数量，部分收件人发送失败时返回合并的错误
+func (en *EmailNotifier) sendAlerts(alerts []*types.AlertData) (int, error) {
+	sent := 0
+	var problems []error
+	for _, recipient := range en.config.Recipients {
+		var matched []*types.AlertData
+		for _, alert := range alerts {
+			if recipientWants(recipient, alert) {
+				matched = append(matched, alert)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		var subject, body string
+		if len(matched) == 1 {
+			subject = fmt.Sprintf("%s OKX%s - %s", alertEmoji(matched[0]), alertKindLabel(matched[0]), displaySymbol(matched[0]))
+			body = buildHTMLContent(matched[0])
+		} else {
+			subject = fmt.Sprintf("📊 OKX批量价格预警 - %d个币种", len(matched))
+			body = buildBatchHTMLContent(matched)
+		}
+		sent++
+		if err := en.send([]string{recipient.Address}, subject, body); err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", recipient.Address, err))
+		}
+	}
+	return sent, errors.Join(problems...)
+}
+
+// recipientWants 判断预警是否符合收件人的过滤条件
+func recipientWants(recipient types.EmailRecipient, alert *types.AlertData) bool {
+	return matchesRoute(recipient.Symbols, recipient.Profiles, alert) && math.Abs(alert.ChangePercent) >= recipient.MinChange
+}
+
+// allRecipients 全部收件人地址，运维告警和资讯通知发送给所有人
+func (en *EmailNotifier) allRecipients() []string {
+	addresses := make([]string, 0, len(en.config.Recipients))
+	for _, recipient := range en.config.Recipients {
+		addresses = append(addresses, recipient.Address)
+	}
+	return addresses
+}
+
+// send 渲染并投递一封HTML邮件
+func (en *EmailNotifier) send(to []string, subject, body string) error {
+	if en.dryRun {
+		logDryRun("email", subject, body)
+		return nil
+	}
+	return en.sendMail(to, en.buildMessage(to, subject, body))
+}
+
+// buildMessage 构建MIME邮件，标题按RFC 2047编码，正文以base64传输
+func (en *EmailNotifier) buildMessage(to []string, subject, body string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", en.config.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	document := "<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"></head>\n<body>" + body + "</body>\n</html>\n"
+	encoded := base64.StdEncoding.EncodeToString([]byte(document))
+	for len(encoded) > 76 { // RFC 2045 每行不超过76个字符
+		b.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded + "\r\n")
+	return b.Bytes()
+}
+
+// deliver 通过SMTP投递邮件，按配置使用TLS/STARTTLS加密，配置了用户名时进行PLAIN认证
+func (en *EmailNotifier) deliver(to []string, message []byte) error {
+	from, err := mail.ParseAddress(en.config.From)
+	if err != nil {
+		return fmt.Errorf("无效的发件人地址: %v", err)
+	}
+
+	address := net.JoinHostPort(en.config.Host, strconv.Itoa(en.config.Port))
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	tlsConfig := &tls.Config{ServerName: en.config.Host}
+	var conn net.Conn
+	if en.config.Security == EmailSecurityTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		return fmt.Errorf("连接SMTP服务器失败: %v", err)
+	}
+	// 整个会话的超时，避免服务器无响应时阻塞通知
+	_ = conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	client, err := smtp.NewClient(conn, en.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("SMTP握手失败: %v", err)
+	}
+	defer client.Close()
+
+	if en.config.Security == EmailSecuritySTARTTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("SMTP服务器不支持STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS失败: %v", err)
+		}
+	}
+	if en.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", en.config.Username, en.config.Password, en.config.Host)); err != nil {
+			return fmt.Errorf("SMTP认证失败: %v", err)
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("MAIL FROM失败: %v", err)
+	}
+	for _, recipient := range to {
+		rcpt, err := mail.ParseAddress(recipient)
+		if err != nil {
+			return fmt.Errorf("无效的收件人地址 %q: %v", recipient, err)
+		}
+		if err := client.Rcpt(rcpt.Address); err != nil {
+			return fmt.Errorf("RCPT TO %s失败: %v", rcpt.Address, err)
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("DATA失败: %v", err)
+	}
+	if _, err := writer.Write(message); err != nil {
+		return fmt.Errorf("写入邮件失败: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("邮件投递失败: %v", err)
+	}
+	return client.Quit()
+}
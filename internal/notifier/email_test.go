@@ -0,0 +1,191 @@
+package notifier
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// sentMail 测试中记录的一封邮件
+type sentMail struct {
+	to      []string
+	subject string
+	body    string
+}
+
+// newTestEmailNotifier 创建不实际投递的邮件通知器，记录发送的邮件，fail 中的收件人投递失败
+func newTestEmailNotifier(t *testing.T, recipients []types.EmailRecipient, fail ...string) (*EmailNotifier, *[]sentMail) {
+	t.Helper()
+	var sent []sentMail
+	en := NewEmailNotifier(types.EmailConfig{
+		Host:       "smtp.example.com",
+		Port:       587,
+		Security:   EmailSecuritySTARTTLS,
+		From:       "OKX Sentry <sentry@example.com>",
+		Recipients: recipients,
+	}, false, &ConsoleNotifier{mode: ConsoleModeLog, out: io.Discard}).(*EmailNotifier)
+	en.sendMail = func(to []string, message []byte) error {
+		for _, address := range fail {
+			if to[0] == address {
+				return errors.New("550 mailbox unavailable")
+			}
+		}
+		sent = append(sent, parseTestMail(t, to, message))
+		return nil
+	}
+	return en, &sent
+}
+
+// parseTestMail 解析MIME邮件的标题和base64正文
+func parseTestMail(t *testing.T, to []string, message []byte) sentMail {
+	t.Helper()
+	msg, err := mail.ReadMessage(strings.NewReader(string(message)))
+	if err != nil {
+		t.Fatalf("邮件格式错误: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("标题解码失败: %v", err)
+	}
+	body, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, msg.Body))
+	if err != nil {
+		t.Fatalf("正文解码失败: %v", err)
+	}
+	return sentMail{to: to, subject: subject, body: string(body)}
+}
+
+func TestEmailPerRecipientDigest(t *testing.T) {
+	en, sent := newTestEmailNotifier(t, []types.EmailRecipient{
+		{Address: "all@example.com"},
+		{Address: "btc@example.com", Symbols: []string{"BTC-USDT"}},
+		{Address: "big@example.com", MinChange: 5},
+		{Address: "nobody@example.com", Profiles: []string{"alts"}},
+	})
+
+	alerts := []*types.AlertData{testAlert("BTC-USDT", 3.2), testAlert("ETH-USDT", -6.5), testAlert("SOL-USDT", 4.1)}
+	if err := en.SendBatchAlerts(alerts); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]sentMail)
+	for _, m := range *sent {
+		got[m.to[0]] = m
+	}
+	if len(got) != 3 {
+		t.Fatalf("发送给 %d 个收件人，want 3: %v", len(got), got)
+	}
+	if m := got["all@example.com"]; m.subject != "📊 OKX批量价格预警 - 3个币种" || !strings.Contains(m.body, "<table") {
+		t.Errorf("全部预警应为摘要邮件: %q", m.subject)
+	}
+	// 只匹配一个预警时使用单个预警的格式
+	if m := got["btc@example.com"]; !strings.Contains(m.subject, "BTC-USDT") || strings.Contains(m.body, "ETH-USDT") {
+		t.Errorf("btc收件人: %q", m.subject)
+	}
+	if m := got["big@example.com"]; !strings.Contains(m.subject, "ETH-USDT") || !strings.Contains(m.body, "-6.50%") {
+		t.Errorf("big收件人: %q", m.subject)
+	}
+}
+
+func TestEmailFailureFallsBack(t *testing.T) {
+	en, sent := newTestEmailNotifier(t, []types.EmailRecipient{{Address: "ok@example.com"}, {Address: "bad@example.com"}}, "bad@example.com")
+
+	// 部分收件人失败时降级为控制台输出，计入连续失败次数
+	if err := en.SendAlert(testAlert("BTC-USDT", 3.2)); err != nil {
+		t.Fatal(err)
+	}
+	if len(*sent) != 1 || en.FailureStreak() != 1 {
+		t.Errorf("sent = %d, FailureStreak = %d", len(*sent), en.FailureStreak())
+	}
+
+	// 运维告警发送给全部收件人
+	en.sendMail = func(to []string, message []byte) error {
+		*sent = append(*sent, parseTestMail(t, to, message))
+		return nil
+	}
+	if err := en.SendOpsAlert(&types.OpsAlert{Component: "fetcher", Message: "连续失败"}); err != nil {
+		t.Fatal(err)
+	}
+	if last := (*sent)[len(*sent)-1]; len(last.to) != 2 || !strings.Contains(last.body, "fetcher") {
+		t.Errorf("运维告警: %+v", last)
+	}
+	if en.FailureStreak() != 0 {
+		t.Errorf("发送成功后 FailureStreak = %d", en.FailureStreak())
+	}
+}
+
+// TestEmailDeliverSMTP 通过模拟的SMTP服务器验证完整的投递会话（不加密、不认证）
+func TestEmailDeliverSMTP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	commands := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var received []string
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+		reply("220 localhost ESMTP")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				break
+			}
+			line = strings.TrimRight(line, "\r\n")
+			received = append(received, line)
+			switch verb := strings.ToUpper(strings.Fields(line + " ")[0]); verb {
+			case "EHLO", "HELO", "MAIL", "RCPT":
+				reply("250 OK")
+			case "DATA":
+				reply("354 go ahead")
+				for {
+					data, err := reader.ReadString('\n')
+					if err != nil || data == ".\r\n" {
+						break
+					}
+				}
+				reply("250 queued")
+			case "QUIT":
+				reply("221 bye")
+				commands <- received
+				return
+			default:
+				reply("502 unknown")
+			}
+		}
+		commands <- received
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	en := &EmailNotifier{config: types.EmailConfig{
+		Host:     addr.IP.String(),
+		Port:     addr.Port,
+		Security: EmailSecurityNone,
+		From:     "OKX Sentry <sentry@example.com>",
+	}}
+	message := en.buildMessage([]string{"Ops <ops@example.com>"}, "测试", "<p>hello</p>")
+	if err := en.deliver([]string{"Ops <ops@example.com>"}, message); err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.Join(<-commands, "\n")
+	for _, want := range []string{"MAIL FROM:<sentry@example.com>", "RCPT TO:<ops@example.com>", "DATA", "QUIT"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("SMTP会话缺少 %q:\n%s", want, got)
+		}
+	}
+}
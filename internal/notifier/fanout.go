@@ -0,0 +1,111 @@
+package notifier
+
+import (
+	"errors"
+	"slices"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// FanoutTarget 多目标渠道中的一个目标，Symbols、Profiles 为空时接收全部预警
+type FanoutTarget struct {
+	Label    string
+	Notifier Interface
+	Symbols  []string
+	Profiles []string
+}
+
+// FanoutNotifier 将通知同时发送给同一渠道的多个目标（如多个钉钉群），各目标按自己的过滤条件接收预警
+// 运维告警和资讯通知发送给全部目标
+type FanoutNotifier struct {
+	targets []FanoutTarget
+}
+
+func NewFanoutNotifier(targets []FanoutTarget) *FanoutNotifier {
+	return &FanoutNotifier{targets: targets}
+}
+
+func (fn *FanoutNotifier) SendAlert(alert *types.AlertData) error {
+	var problems []error
+	for _, target := range fn.targets {
+		if !matchesRoute(target.Symbols, target.Profiles, alert) {
+			continue
+		}
+		if err := target.Notifier.SendAlert(alert); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	return errors.Join(problems...)
+}
+
+func (fn *FanoutNotifier) SendBatchAlerts(alerts []*types.AlertData) error {
+	var problems []error
+	for _, target := range fn.targets {
+		var matched []*types.AlertData
+		for _, alert := range alerts {
+			if matchesRoute(target.Symbols, target.Profiles, alert) {
+				matched = append(matched, alert)
+			}
+		}
+		if err := target.Notifier.SendBatchAlerts(matched); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	return errors.Join(problems...)
+}
+
+func (fn *FanoutNotifier) SendOpsAlert(alert *types.OpsAlert) error {
+	var problems []error
+	for _, target := range fn.targets {
+		if err := target.Notifier.SendOpsAlert(alert); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	return errors.Join(problems...)
+}
+
+func (fn *FanoutNotifier) SendNotice(notice *types.Notice) error {
+	var problems []error
+	for _, target := range fn.targets {
+		if err := target.Notifier.SendNotice(notice); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	return errors.Join(problems...)
+}
+
+// SendDigest 热力图摘要发送给全部目标，配置了交易对过滤的目标只包含这些交易对
+func (fn *FanoutNotifier) SendDigest(digest *types.Digest) error {
+	var problems []error
+	for _, target := range fn.targets {
+		targetDigest := digest
+		if len(target.Symbols) > 0 {
+			if targetDigest = filterDigest(digest, target.Symbols); len(targetDigest.Cells) == 0 {
+				continue
+			}
+		}
+		if err := SendDigest(target.Notifier, targetDigest); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	return errors.Join(problems...)
+}
+
+// FailureStreak 各目标中最长的连续失败次数
+func (fn *FanoutNotifier) FailureStreak() int {
+	streak := 0
+	for _, target := range fn.targets {
+		if reporter, ok := target.Notifier.(HealthReporter); ok {
+			streak = max(streak, reporter.FailureStreak())
+		}
+	}
+	return streak
+}
+
+// matchesRoute 判断预警是否属于指定的交易对和配置组，列表为空时不限制
+func matchesRoute(symbols, profiles []string, alert *types.AlertData) bool {
+	if len(symbols) > 0 && !slices.Contains(symbols, alert.Symbol) {
+		return false
+	}
+	return len(profiles) == 0 || slices.Contains(profiles, alert.Profile)
+}
@@ -0,0 +1,85 @@
+package notifier
+
+import (
+	"testing"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// recordingNotifier 记录收到的预警交易对
+type recordingNotifier struct {
+	symbols []string
+	notices int
+}
+
+func (n *recordingNotifier) SendAlert(alert *types.AlertData) error {
+	n.symbols = append(n.symbols, alert.Symbol)
+	return nil
+}
+
+func (n *recordingNotifier) SendBatchAlerts(alerts []*types.AlertData) error {
+	for _, alert := range alerts {
+		n.symbols = append(n.symbols, alert.Symbol)
+	}
+	return nil
+}
+
+func (n *recordingNotifier) SendOpsAlert(*types.OpsAlert) error { return nil }
+func (n *recordingNotifier) SendNotice(*types.Notice) error {
+	n.notices++
+	return nil
+}
+
+func TestFanoutRouting(t *testing.T) {
+	all, majors, alts := &recordingNotifier{}, &recordingNotifier{}, &recordingNotifier{}
+	fn := NewFanoutNotifier([]FanoutTarget{
+		{Notifier: all},
+		{Label: "majors", Notifier: majors, Symbols: []string{"BTC-USDT", "ETH-USDT"}},
+		{Label: "alts", Notifier: alts, Profiles: []string{"alts"}},
+	})
+
+	sol := testAlert("SOL-USDT", 6.1)
+	sol.Profile = "alts"
+	if err := fn.SendBatchAlerts([]*types.AlertData{testAlert("BTC-USDT", 3.2), sol}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fn.SendNotice(&types.Notice{Title: "维护公告"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(all.symbols) != 2 || len(majors.symbols) != 1 || majors.symbols[0] != "BTC-USDT" ||
+		len(alts.symbols) != 1 || alts.symbols[0] != "SOL-USDT" {
+		t.Errorf("all = %v, majors = %v, alts = %v", all.symbols, majors.symbols, alts.symbols)
+	}
+	// 资讯通知发送给全部目标
+	if all.notices != 1 || majors.notices != 1 || alts.notices != 1 {
+		t.Errorf("notices = %d/%d/%d, want 1/1/1", all.notices, majors.notices, alts.notices)
+	}
+}
+
+func TestChannelsFromConfigTargets(t *testing.T) {
+	cfg := &types.Config{DryRun: true}
+	cfg.Console.Mode = ConsoleModeLog
+	cfg.DingTalk = types.DingTalkConfig{
+		WebhookURL: "https://oapi.dingtalk.com/robot/send?access_token=main",
+		Targets: []types.DingTalkTarget{
+			{Label: "desk", WebhookURL: "https://oapi.dingtalk.com/robot/send?access_token=desk", Symbols: []string{"BTC-USDT"}},
+		},
+	}
+	cfg.PushPlus.Targets = []types.PushPlusTarget{{Label: "alice", UserToken: "token"}}
+
+	channels := ChannelsFromConfig(cfg)
+	if _, ok := channels[ChannelDingTalk].(*FanoutNotifier); !ok {
+		t.Errorf("dingtalk = %T, want *FanoutNotifier", channels[ChannelDingTalk])
+	}
+	if _, ok := channels["dingtalk:desk"].(*DingTalkNotifier); !ok {
+		t.Errorf("dingtalk:desk = %T, want *DingTalkNotifier", channels["dingtalk:desk"])
+	}
+	// 只有一个不带过滤条件的目标时直接使用该通知器
+	if _, ok := channels[ChannelPushPlus].(*PushPlusNotifier); !ok {
+		t.Errorf("pushplus = %T, want *PushPlusNotifier", channels[ChannelPushPlus])
+	}
+	if Preferred(channels) != channels[ChannelDingTalk] {
+		t.Error("默认渠道应为钉钉")
+	}
+}
@@ -0,0 +1,456 @@
+package notifier
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"okx-market-sentry/pkg/types"
+)
+
+// FeishuNotifier 飞书（Lark）群机器人通知器，以消息卡片形式发送
+type FeishuNotifier struct {
+	deliveryStats
+	console    *ConsoleNotifier // 发送失败时降级输出
+	webhookURL string
+	secret     string
+	enabled    bool
+	dryRun     bool // 演练模式，只记录渲染后的消息不实际发送
+	httpClient *http.Client
+}
+
+// FeishuMessage 飞书消息结构，配置了签名校验时需携带timestamp和sign
+type FeishuMessage struct {
+	Timestamp string      `json:"timestamp,omitempty"`
+	Sign      string      `json:"sign,omitempty"`
+	MsgType   string      `json:"msg_type"`
+	Card      *FeishuCard `json:"card"`
+}
+
+// FeishuCard 飞书消息卡片
+type FeishuCard struct {
+	Config   feishuCardConfig `json:"config"`
+	Header   feishuHeader     `json:"header"`
+	Elements []any            `json:"elements"`
+}
+
+type feishuCardConfig struct {
+	WideScreenMode bool `json:"wide_screen_mode"`
+}
+
+// feishuHeader 卡片标题，template为标题栏颜色：green/red/orange/blue等
+type feishuHeader struct {
+	Template string     `json:"template"`
+	Title    feishuText `json:"title"`
+}
+
+// feishuText 文本，tag为plain_text或lark_md
+type feishuText struct {
+	Tag     string `json:"tag"`
+	Content string `json:"content"`
+}
+
+type feishuField struct {
+	IsShort bool       `json:"is_short"`
+	Text    feishuText `json:"text"`
+}
+
+type feishuDiv struct {
+	Tag    string        `json:"tag"`
+	Text   *feishuText   `json:"text,omitempty"`
+	Fields []feishuField `json:"fields,omitempty"`
+}
+
+type feishuButton struct {
+	Tag  string     `json:"tag"`
+	Text feishuText `json:"text"`
+	URL  string     `json:"url"`
+	Type string     `json:"type"`
+}
+
+type feishuAction struct {
+	Tag     string         `json:"tag"`
+	Actions []feishuButton `json:"actions"`
+}
+
+type feishuNote struct {
+	Tag      string       `json:"tag"`
+	Elements []feishuText `json:"elements"`
+}
+
+type feishuHr struct {
+	Tag string `json:"tag"`
+}
+
+// feishuPageSize 表格每页的最大行数，超出时飞书分页展示
+const feishuPageSize = 10
+
+// feishuTable 表格组件，rows中每行以列名为键
+type feishuTable struct {
+	Tag       string              `json:"tag"`
+	PageSize  int                 `json:"page_size"`
+	RowHeight string              `json:"row_height"`
+	Columns   []feishuTableColumn `json:"columns"`
+	Rows      []map[string]string `json:"rows"`
+}
+
+type feishuTableColumn struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	DataType    string `json:"data_type"`
+}
+
+// FeishuResponse 飞书API响应
+type FeishuResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+func NewFeishuNotifier(webhookURL, secret string, dryRun bool, console *ConsoleNotifier) Interface {
+	// 如果没有配置webhook URL，返回控制台通知器
+	if webhookURL == "" {
+		log().Info("🔧 未配置飞书Webhook URL，使用控制台输出模式")
+		return console
+	}
+
+	if secret != "" {
+		log().Info("✅ 已配置飞书通知服务（含签名校验）")
+	} else {
+		log().Warn("⚠️ 飞书通知已配置，但未设置secret（建议开启签名校验）")
+	}
+
+	return &FeishuNotifier{
+		console:    console,
+		webhookURL: webhookURL,
+		secret:     secret,
+		enabled:    true,
+		dryRun:     dryRun,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (fsn *FeishuNotifier) SendAlert(alert *types.AlertData) error {
+	if !fsn.enabled {
+		// 降级为控制台输出
+		return fsn.console.SendAlert(alert)
+	}
+
+	err := fsn.sendCard(fsn.buildAlertCard(alert))
+	fsn.record(err)
+	if err != nil {
+		log().Error("❌ 飞书发送失败，降级为控制台输出",
+			zap.String("channel", "feishu"),
+			zap.String("symbol", alert.Symbol),
+			zap.Error(err))
+		// 降级为控制台输出
+		return fsn.console.SendAlert(alert)
+	}
+
+	log().Info("✅ 飞书通知已发送",
+		zap.String("channel", "feishu"),
+		zap.String("symbol", alert.Symbol),
+		zap.Float64("change_percent", alert.ChangePercent))
+	return nil
+}
+
+func (fsn *FeishuNotifier) SendBatchAlerts(alerts []*types.AlertData) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	if len(alerts) == 1 {
+		return fsn.SendAlert(alerts[0])
+	}
+
+	if !fsn.enabled {
+		// 降级为控制台输出
+		return fsn.console.SendBatchAlerts(alerts)
+	}
+
+	err := fsn.sendCard(fsn.buildBatchCard(alerts))
+	fsn.record(err)
+	if err != nil {
+		log().Error("❌ 飞书批量发送失败，降级为控制台输出",
+			zap.String("channel", "feishu"),
+			zap.Error(err))
+		// 降级为控制台输出
+		return fsn.console.SendBatchAlerts(alerts)
+	}
+
+	log().Info("✅ 飞书批量通知已发送",
+		zap.String("channel", "feishu"),
+		zap.Int("alert_count", len(alerts)))
+	return nil
+}
+
+func (fsn *FeishuNotifier) SendOpsAlert(alert *types.OpsAlert) error {
+	if !fsn.enabled {
+		return fsn.console.SendOpsAlert(alert)
+	}
+
+	template := "orange"
+	if alert.Recovered {
+		template = "green"
+	}
+	card := newFeishuCard(template, opsAlertTitle(alert),
+		feishuFieldsDiv(
+			feishuField{IsShort: true, Text: larkMarkdown("**组件**\n" + alert.Component)},
+			feishuField{IsShort: true, Text: larkMarkdown("**时间**\n" + alert.AlertTime.Format("2006-01-02 15:04:05"))},
+		),
+		feishuDiv{Tag: "div", Text: ptr(larkMarkdown("**详情**: " + alert.Message))},
+	)
+
+	err := fsn.sendCard(card)
+	fsn.record(err)
+	if err != nil {
+		log().Error("❌ 飞书运维告警发送失败，降级为控制台输出",
+			zap.String("channel", "feishu"),
+			zap.String("component", alert.Component),
+			zap.Error(err))
+		return fsn.console.SendOpsAlert(alert)
+	}
+	return nil
+}
+
+func (fsn *FeishuNotifier) SendNotice(notice *types.Notice) error {
+	if !fsn.enabled {
+		return fsn.console.SendNotice(notice)
+	}
+
+	content := ""
+	for _, line := range noticeLines(notice) {
+		content += line + "\n"
+	}
+	elements := []any{feishuDiv{Tag: "div", Text: ptr(larkMarkdown(content))}}
+	if notice.URL != "" {
+		elements = append(elements, feishuLinkButton("查看公告", notice.URL))
+	}
+
+	err := fsn.sendCard(newFeishuCard("blue", noticeTitle(notice), elements...))
+	fsn.record(err)
+	if err != nil {
+		log().Error("❌ 飞书资讯通知发送失败，降级为控制台输出",
+			zap.String("channel", "feishu"),
+			zap.String("title", notice.Title),
+			zap.Error(err))
+		return fsn.console.SendNotice(notice)
+	}
+	return nil
+}
+
+// newFeishuCard 构建宽屏消息卡片
+func newFeishuCard(template, title string, elements ...any) *FeishuCard {
+	return &FeishuCard{
+		Config:   feishuCardConfig{WideScreenMode: true},
+		Header:   feishuHeader{Template: template, Title: feishuText{Tag: "plain_text", Content: title}},
+		Elements: elements,
+	}
+}
+
+func larkMarkdown(content string) feishuText {
+	return feishuText{Tag: "lark_md", Content: content}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func feishuFieldsDiv(fields ...feishuField) feishuDiv {
+	return feishuDiv{Tag: "div", Fields: fields}
+}
+
+// feishuLinkButton 跳转链接按钮
+func feishuLinkButton(label, url string) feishuAction {
+	return feishuAction{Tag: "action", Actions: []feishuButton{{
+		Tag:  "button",
+		Text: feishuText{Tag: "plain_text", Content: label},
+		URL:  url,
+		Type: "primary",
+	}}}
+}
+
+// feishuChange 以颜色区分涨跌的涨跌幅
+func feishuChange(alert *types.AlertData) string {
+	color := "green"
+	if alert.ChangePercent < 0 {
+		color = "red"
+	}
+	return fmt.Sprintf("<font color='%s'>%s</font>", color, formatPercent(alert.ChangePercent))
+}
+
+// buildAlertCard 构建单个预警的卡片，标题栏上涨为绿色、下跌为红色
+func (fsn *FeishuNotifier) buildAlertCard(alert *types.AlertData) *FeishuCard {
+	template := "green"
+	changeText := "上涨"
+	if alert.ChangePercent < 0 {
+		template = "red"
+		changeText = "下跌"
+	}
+	title := fmt.Sprintf("%s OKX%s - %s", alertEmoji(alert), alertKindLabel(alert), displaySymbol(alert))
+	tradingURL := buildTradingURL(alert.Symbol)
+
+	// 配置组/规则指定了正文模板时按模板输出
+	if body, ok := renderBody(alert); ok {
+		return newFeishuCard(template, title,
+			feishuDiv{Tag: "div", Text: ptr(larkMarkdown(body))},
+			feishuLinkButton("查看行情", tradingURL))
+	}
+
+	elements := []any{
+		feishuFieldsDiv(
+			feishuField{IsShort: true, Text: larkMarkdown("**当前价格**\n$" + formatTickPrice(alert.CurrentPrice, alert.TickSize))},
+			feishuField{IsShort: true, Text: larkMarkdown("**" + pastPriceLabel(alert) + "**\n$" + formatTickPrice(alert.PastPrice, alert.TickSize))},
+			feishuField{IsShort: true, Text: larkMarkdown("**价格变化**\n" + feishuChange(alert))},
+			feishuField{IsShort: true, Text: larkMarkdown("**预警时间**\n" + alert.AlertTime.Format("2006-01-02 15:04:05"))},
+		),
+	}
+	if fields := alertContext(alert); len(fields) > 0 {
+		content := ""
+		for _, field := range fields {
+			content += fmt.Sprintf("**%s**: %s\n", field.label, field.value)
+		}
+		elements = append(elements, feishuDiv{Tag: "div", Text: ptr(larkMarkdown(content))})
+	}
+	elements = append(elements,
+		feishuLinkButton("查看行情", tradingURL),
+		feishuHr{Tag: "hr"},
+		feishuNote{Tag: "note", Elements: []feishuText{{Tag: "plain_text",
+			Content: fmt.Sprintf("%s 该交易对出现显著%s，请关注市场动向！", alertEmoji(alert), changeText)}}},
+	)
+	return newFeishuCard(template, title, elements...)
+}
+
+// buildBatchCard 构建批量预警的卡片，上涨和下跌各一个表格，每组最多显示8个
+// 全部上涨时标题栏为绿色、全部下跌为红色，涨跌混合为橙色
+func (fsn *FeishuNotifier) buildBatchCard(alerts []*types.AlertData) *FeishuCard {
+	// 按上涨和下跌分组，超过逐条列出上限的预警按幅度分档计数
+	groups := groupBatch(alerts)
+
+	template := "orange"
+	switch {
+	case groups.downTotal == 0:
+		template = "green"
+	case groups.upTotal == 0:
+		template = "red"
+	}
+
+	elements := []any{
+		feishuFieldsDiv(
+			feishuField{IsShort: true, Text: larkMarkdown(fmt.Sprintf("📈 **上涨币种**\n<font color='green'>%d个</font>", groups.upTotal))},
+			feishuField{IsShort: true, Text: larkMarkdown(fmt.Sprintf("📉 **下跌币种**\n<font color='red'>%d个</font>", groups.downTotal))},
+			feishuField{IsShort: true, Text: larkMarkdown("🕐 **预警时间**\n" + alerts[0].AlertTime.Format("2006-01-02 15:04:05"))},
+		),
+	}
+	maxShow := groups.show(8) // 每个分组最多显示8个
+	elements = append(elements, feishuMoverTable("📈 上涨币种", "上涨", groups.up, maxShow)...)
+	elements = append(elements, feishuMoverTable("📉 下跌币种", "下跌", groups.down, maxShow)...)
+	if title := groups.omittedTitle(); title != "" {
+		lines := append([]string{"**📊 " + title + "**"}, groups.omittedLines()...)
+		elements = append(elements, feishuDiv{Tag: "div", Text: ptr(larkMarkdown(strings.Join(lines, "\n")))})
+	}
+	elements = append(elements,
+		feishuHr{Tag: "hr"},
+		feishuNote{Tag: "note", Elements: []feishuText{{Tag: "plain_text", Content: "⚠️ 多个交易对同时出现显著波动，请密切关注市场动向！"}}},
+	)
+
+	return newFeishuCard(template, fmt.Sprintf("📊 OKX批量价格预警 - %d个币种", len(alerts)), elements...)
+}
+
+// feishuMoverTable 批量预警中一个分组的标题和表格，最多显示maxShow个，分组为空时不输出
+func feishuMoverTable(heading, direction string, alerts []*types.AlertData, maxShow int) []any {
+	if len(alerts) == 0 {
+		return nil
+	}
+	showCount := min(len(alerts), maxShow)
+
+	rows := make([]map[string]string, 0, showCount)
+	for _, alert := range alerts[:showCount] {
+		rows = append(rows, map[string]string{
+			"symbol": fmt.Sprintf("[%s](%s)", displaySymbol(alert), buildTradingURL(alert.Symbol)),
+			"price":  "$" + formatTickPrice(alert.CurrentPrice, alert.TickSize),
+			"change": feishuChange(alert),
+			"note":   batchNote(alert),
+		})
+	}
+	elements := []any{
+		feishuDiv{Tag: "div", Text: ptr(larkMarkdown("**" + heading + "**"))},
+		feishuTable{
+			Tag:       "table",
+			PageSize:  min(maxShow, feishuPageSize),
+			RowHeight: "low",
+			Columns: []feishuTableColumn{
+				{Name: "symbol", DisplayName: "交易对", DataType: "lark_md"},
+				{Name: "price", DisplayName: "当前价格", DataType: "text"},
+				{Name: "change", DisplayName: "涨跌幅", DataType: "lark_md"},
+				{Name: "note", DisplayName: "备注", DataType: "text"},
+			},
+			Rows: rows,
+		},
+	}
+	if len(alerts) > maxShow {
+		elements = append(elements, feishuNote{Tag: "note", Elements: []feishuText{{Tag: "plain_text",
+			Content: fmt.Sprintf("... 还有%d个%s币种", len(alerts)-maxShow, direction)}}})
+	}
+	return elements
+}
+
+// generateSignature 生成飞书签名：以 timestamp + "\n" + secret 为密钥对空串做HMAC-SHA256，再Base64编码
+func (fsn *FeishuNotifier) generateSignature(timestamp int64) string {
+	stringToSign := fmt.Sprintf("%d\n%s", timestamp, fsn.secret)
+	h := hmac.New(sha256.New, []byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// sendCard 发送飞书卡片消息
+func (fsn *FeishuNotifier) sendCard(card *FeishuCard) error {
+	message := &FeishuMessage{
+		MsgType: "interactive",
+		Card:    card,
+	}
+	if fsn.secret != "" {
+		timestamp := time.Now().Unix() // 秒级时间戳，与服务器时间相差超过1小时会被拒绝
+		message.Timestamp = strconv.FormatInt(timestamp, 10)
+		message.Sign = fsn.generateSignature(timestamp)
+	}
+
+	// 序列化为JSON
+	jsonData, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %v", err)
+	}
+
+	if fsn.dryRun {
+		logDryRun("feishu", card.Header.Title.Content, string(jsonData))
+		return nil
+	}
+
+	// 发送HTTP请求
+	resp, err := fsn.httpClient.Post(fsn.webhookURL, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("HTTP请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// 解析响应
+	var feishuResp FeishuResponse
+	if err := json.NewDecoder(resp.Body).Decode(&feishuResp); err != nil {
+		return fmt.Errorf("解析响应失败: %v", err)
+	}
+
+	// 检查返回结果
+	if feishuResp.Code != 0 {
+		return fmt.Errorf("飞书API错误 [%d]: %s", feishuResp.Code, feishuResp.Msg)
+	}
+
+	return nil
+}
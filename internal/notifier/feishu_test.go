@@ -0,0 +1,95 @@
+package notifier
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// feishuServer 模拟飞书机器人Webhook，记录收到的请求体并返回指定的错误码
+func feishuServer(t *testing.T, code int, received *[]map[string]any) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("请求体不是JSON: %v", err)
+		}
+		*received = append(*received, body)
+		_ = json.NewEncoder(w).Encode(FeishuResponse{Code: code, Msg: "sign match fail"})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFeishuSignedAlertCard(t *testing.T) {
+	var received []map[string]any
+	server := feishuServer(t, 0, &received)
+	fsn := NewFeishuNotifier(server.URL, "test-secret", false, &ConsoleNotifier{mode: ConsoleModeLog, out: io.Discard})
+
+	if err := fsn.SendAlert(testAlert("SOL-USDT", -3.67)); err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 1 {
+		t.Fatalf("收到 %d 个请求，want 1", len(received))
+	}
+	body := received[0]
+	if body["msg_type"] != "interactive" {
+		t.Errorf("msg_type = %v", body["msg_type"])
+	}
+
+	// 签名：以 timestamp + "\n" + secret 为密钥对空串做HMAC-SHA256
+	timestamp, _ := body["timestamp"].(string)
+	h := hmac.New(sha256.New, []byte(timestamp+"\ntest-secret"))
+	if want := base64.StdEncoding.EncodeToString(h.Sum(nil)); timestamp == "" || body["sign"] != want {
+		t.Errorf("timestamp = %q, sign = %v, want %s", timestamp, body["sign"], want)
+	}
+
+	card, _ := json.Marshal(body["card"])
+	for _, want := range []string{`"template":"red"`, "SOL-USDT (Solana)", "$198.20", "-3.67%", "bybits.io/trade/usdt/SOLUSDT"} {
+		if !strings.Contains(string(card), want) {
+			t.Errorf("卡片缺少 %q: %s", want, card)
+		}
+	}
+}
+
+func TestFeishuBatchCardTable(t *testing.T) {
+	var received []map[string]any
+	server := feishuServer(t, 0, &received)
+	fsn := NewFeishuNotifier(server.URL, "", false, &ConsoleNotifier{mode: ConsoleModeLog, out: io.Discard})
+
+	if err := fsn.SendBatchAlerts(benchAlerts(20)); err != nil {
+		t.Fatal(err)
+	}
+	body := received[0]
+	if _, ok := body["sign"]; ok {
+		t.Error("未配置secret时不应携带签名")
+	}
+	card, _ := json.Marshal(body["card"])
+	for _, want := range []string{`"template":"orange"`, `"tag":"table"`, "20个币种", "还有2个上涨币种", "还有2个下跌币种"} {
+		if !strings.Contains(string(card), want) {
+			t.Errorf("卡片缺少 %q: %s", want, card)
+		}
+	}
+}
+
+func TestFeishuErrorFallsBack(t *testing.T) {
+	var received []map[string]any
+	server := feishuServer(t, 19021, &received)
+	fsn := NewFeishuNotifier(server.URL, "wrong", false, &ConsoleNotifier{mode: ConsoleModeLog, out: io.Discard})
+
+	// 发送失败时降级为控制台输出，不返回错误，但计入连续失败次数
+	if err := fsn.SendNotice(&types.Notice{Title: "测试"}); err != nil {
+		t.Fatal(err)
+	}
+	if streak := fsn.(HealthReporter).FailureStreak(); streak != 1 {
+		t.Errorf("FailureStreak = %d, want 1", streak)
+	}
+}
@@ -7,26 +7,25 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"html"
+	"io"
+	"math"
 	"net/http"
 	"net/url"
+	"okx-market-sentry/pkg/logger"
 	"okx-market-sentry/pkg/types"
-	"sort"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
-	"unicode/utf8"
 
 	"go.uber.org/zap"
 )
 
-// safePadding 安全地计算填充空格数量，避免负数
-func safePadding(content string, totalWidth int) int {
-	// 使用utf8.RuneCountInString计算实际显示字符数，而不是字节数
-	runeCount := utf8.RuneCountInString(content)
-	padding := totalWidth - runeCount - 4 // 4是边框字符数
-	if padding < 0 {
-		padding = 0
-	}
-	return padding
+// log 模块日志器，级别可通过 log.modules.notifier 单独配置
+func log() *zap.Logger {
+	return logger.Named("notifier")
 }
 
 // formatDuration 格式化时间周期为中文描述
@@ -42,6 +41,157 @@ func formatDuration(d time.Duration) string {
 	}
 }
 
+// periodLabel 预警涨跌幅对应的时长描述，部分数据的预警附加标记
+func periodLabel(alert *types.AlertData) string {
+	if alert.Partial {
+		return formatDuration(alert.MonitorPeriod) + "(部分数据)"
+	}
+	return formatDuration(alert.MonitorPeriod)
+}
+
+// displaySymbol 通知中显示的交易对，附带项目名称，如 SOL-USDT (Solana)
+func displaySymbol(alert *types.AlertData) string {
+	if alert.Name == "" {
+		return alert.Symbol
+	}
+	return alert.Symbol + " (" + alert.Name + ")"
+}
+
+// alertKindLabel 预警名称，用于标题；配置组/规则指定了标题时使用指定的名称
+func alertKindLabel(alert *types.AlertData) string {
+	if alert.Style != nil && alert.Style.Title != "" {
+		return alert.Style.Title
+	}
+	switch alert.Kind {
+	case types.AlertKindDrawdown:
+		return "高点回撤预警"
+	case types.AlertKindBounce:
+		return "低点反弹预警"
+	case types.AlertKindAcceleration:
+		return "加速预警"
+	case types.AlertKindRule:
+		return "规则预警"
+	case types.AlertKindSubscription:
+		return "订阅预警"
+	default:
+		return "价格预警"
+	}
+}
+
+// pastPriceLabel 预警中对比价格的名称，回撤/反弹预警对比的是窗口内的极值
+func pastPriceLabel(alert *types.AlertData) string {
+	switch alert.Kind {
+	case types.AlertKindDrawdown:
+		return periodLabel(alert) + "内高点"
+	case types.AlertKindBounce:
+		return periodLabel(alert) + "内低点"
+	default:
+		return periodLabel(alert) + "前价格"
+	}
+}
+
+// alertField 预警消息中价格之外的附加信息
+type alertField struct {
+	label string
+	value string
+}
+
+// alertContext 预警的附加信息，各通知渠道按自身格式渲染
+func alertContext(alert *types.AlertData) []alertField {
+	var fields []alertField
+	if alert.Kind == types.AlertKindRule {
+		fields = append(fields, alertField{"🧩 规则", alert.Profile + "：" + strings.Join(alert.Conditions, "，")})
+	}
+	if m := alert.Momentum; m != nil {
+		trend := "🐢 动能减弱，主要波动发生在较早时段"
+		if m.Accelerating {
+			trend = "🚀 加速中，新启动的行情"
+		}
+		fields = append(fields, alertField{"⚡ 动量", fmt.Sprintf("前%s %+.2f%%，近%s %+.2f%%，%s",
+			formatDuration(m.EarlyPeriod), m.EarlyChange, formatDuration(m.RecentPeriod), m.RecentChange, trend)})
+	}
+	if stats := alert.Stats24h; stats != nil {
+		fields = append(fields,
+			alertField{"📊 24h涨跌", fmt.Sprintf("%+.2f%%（开盘 $%s）", percentChange(stats.Open, alert.CurrentPrice), formatPrice(stats.Open))},
+			alertField{"📏 距24h高/低", fmt.Sprintf("%+.2f%% / %+.2f%%", percentChange(stats.High, alert.CurrentPrice), percentChange(stats.Low, alert.CurrentPrice))},
+			alertField{"💰 24h成交额", formatVolume(stats.QuoteVolume) + " USDT"})
+	}
+	if event := alert.Event; event != nil {
+		fields = append(fields, alertField{"📅 经济事件", fmt.Sprintf("%s %s（%s），可能由该事件驱动",
+			event.Country, event.Title, event.Time.Format("01-02 15:04"))})
+	}
+	return fields
+}
+
+// percentChange 从base到price的涨跌幅（百分比）
+func percentChange(base, price float64) float64 {
+	if base == 0 {
+		return 0
+	}
+	return (price - base) / base * 100
+}
+
+// formatPrice 以最短的精确形式显示价格，不补多余的0
+func formatPrice(price float64) string {
+	return strconv.FormatFloat(price, 'f', -1, 64)
+}
+
+// formatTickPrice 按交易对的价格最小变动单位显示价格（如 0.0000012、64250.5），精度未知时按最短精确形式显示
+func formatTickPrice(price, tickSize float64) string {
+	if tickSize <= 0 {
+		return formatPrice(price)
+	}
+	decimals := 0
+	if _, fraction, ok := strings.Cut(strconv.FormatFloat(tickSize, 'f', -1, 64), "."); ok {
+		decimals = len(fraction)
+	}
+	return strconv.FormatFloat(price, 'f', decimals, 64)
+}
+
+// formatPercent 显示涨跌幅，上涨带+号，至少2位小数，小幅波动（如稳定币）增加小数位保留两位有效数字
+func formatPercent(percent float64) string {
+	decimals := 2
+	for scaled := math.Abs(percent) * 100; scaled > 0 && scaled < 10 && decimals < 6; scaled *= 10 {
+		decimals++
+	}
+	text := strconv.FormatFloat(percent, 'f', decimals, 64) + "%"
+	if percent > 0 {
+		text = "+" + text
+	}
+	return text
+}
+
+// formatVolume 以万/亿为单位显示成交额
+func formatVolume(volume float64) string {
+	switch {
+	case volume >= 1e8:
+		return fmt.Sprintf("%.2f亿", volume/1e8)
+	case volume >= 1e4:
+		return fmt.Sprintf("%.2f万", volume/1e4)
+	default:
+		return fmt.Sprintf("%.0f", volume)
+	}
+}
+
+// batchNote 批量预警中每个交易对附带的预警类型和24h涨跌，都没有时为空
+func batchNote(alert *types.AlertData) string {
+	var notes []string
+	switch alert.Kind {
+	case types.AlertKindDrawdown:
+		notes = append(notes, "高点回撤")
+	case types.AlertKindBounce:
+		notes = append(notes, "低点反弹")
+	case types.AlertKindAcceleration:
+		notes = append(notes, "加速")
+	case types.AlertKindRule:
+		notes = append(notes, "规则 "+alert.Profile)
+	}
+	if alert.Stats24h != nil {
+		notes = append(notes, fmt.Sprintf("24h %+.2f%%", percentChange(alert.Stats24h.Open, alert.CurrentPrice)))
+	}
+	return strings.Join(notes, ", ")
+}
+
 // buildTradingURL 根据交易对生成交易链接
 func buildTradingURL(symbol string) string {
 	// 将 BTC-USDT 格式转换为 BTCUSDT 格式
@@ -53,21 +203,150 @@ func buildTradingURL(symbol string) string {
 type Interface interface {
 	SendAlert(alert *types.AlertData) error
 	SendBatchAlerts(alerts []*types.AlertData) error
+	SendOpsAlert(alert *types.OpsAlert) error
+	SendNotice(notice *types.Notice) error
+}
+
+// Router 可按渠道名称路由的通知器
+type Router interface {
+	Channel(name string) Interface
+}
+
+// HealthReporter 可报告投递健康状况的通知器
+type HealthReporter interface {
+	FailureStreak() int
+}
+
+// deliveryStats 记录远程通知渠道的连续投递失败次数
+// 远程发送失败时会降级为控制台输出并返回nil，调用方感知不到失败，因此在通知器内部统计
+type deliveryStats struct {
+	mutex         sync.Mutex
+	failureStreak int
+}
+
+func (ds *deliveryStats) record(err error) {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	if err != nil {
+		ds.failureStreak++
+	} else {
+		ds.failureStreak = 0
+	}
+}
+
+// FailureStreak 获取连续投递失败次数
+func (ds *deliveryStats) FailureStreak() int {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+	return ds.failureStreak
 }
 
+// logDryRun 演练模式下记录将要发送的消息，包含渲染后的完整内容，便于核对配置和消息模板
+func logDryRun(channel, title, content string) {
+	log().Info("📝 演练模式，消息未实际发送",
+		zap.String("channel", channel),
+		zap.String("title", title),
+		zap.String("content", content))
+}
+
+// opsAlertTitle 运维告警标题
+func opsAlertTitle(alert *types.OpsAlert) string {
+	if alert.Recovered {
+		return fmt.Sprintf("✅ OKX Sentry运维恢复 - %s", alert.Component)
+	}
+	return fmt.Sprintf("🛠️ OKX Sentry运维告警 - %s", alert.Component)
+}
+
+// noticeTitle 资讯通知标题
+func noticeTitle(notice *types.Notice) string {
+	return fmt.Sprintf("📢 %s", notice.Title)
+}
+
+// noticeLines 资讯通知的正文各行（不含标题）
+func noticeLines(notice *types.Notice) []string {
+	var lines []string
+	if notice.Message != "" {
+		lines = append(lines, "详情: "+notice.Message)
+	}
+	if len(notice.Symbols) > 0 {
+		lines = append(lines, "涉及交易对: "+strings.Join(notice.Symbols, ", "))
+	}
+	lines = append(lines, "时间: "+notice.Time.Format("2006-01-02 15:04:05"))
+	return lines
+}
+
+// 控制台输出模式
+const (
+	ConsoleModeAuto   = "auto"   // 标准输出为终端时使用pretty，否则使用log
+	ConsoleModePretty = "pretty" // 带边框的可读输出，适合交互式运行，终端过窄时自动改用表格
+	ConsoleModeTable  = "table"  // 紧凑表格输出，每个预警一行
+	ConsoleModeJSON   = "json"   // 每个预警/批量预警输出一行JSON对象，便于通过管道交给jq等工具处理
+	ConsoleModeLog    = "log"    // 通过zap输出结构化日志，适合后台运行和日志采集
+)
+
 // ConsoleNotifier 控制台通知器
-type ConsoleNotifier struct{}
+type ConsoleNotifier struct {
+	mode    string     // pretty/table/json/log，auto已按标准输出解析
+	noEmoji bool       // 纯文本输出，去掉emoji并使用ASCII边框，适合重定向到文件
+	out     io.Writer  // 输出目标，默认为标准输出
+	columns func() int // 终端宽度，未知时返回0
+}
 
-func NewConsoleNotifier() *ConsoleNotifier {
-	return &ConsoleNotifier{}
+func NewConsoleNotifier(consoleConfig types.ConsoleConfig) *ConsoleNotifier {
+	mode := consoleConfig.Mode
+	if mode == "" || mode == ConsoleModeAuto {
+		mode = ConsoleModeLog
+		if isTerminal(os.Stdout) {
+			mode = ConsoleModePretty
+		}
+	}
+	return &ConsoleNotifier{
+		mode:    mode,
+		noEmoji: consoleConfig.NoEmoji,
+		out:     os.Stdout,
+		columns: terminalColumns,
+	}
+}
+
+// isTerminal 判断文件是否为终端
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
 
 func (cn *ConsoleNotifier) SendAlert(alert *types.AlertData) error {
-	// 生成漂亮的控制台输出
-	cn.printAlert(alert)
+	switch cn.mode {
+	case ConsoleModeLog:
+		cn.logAlert(alert)
+	case ConsoleModeTable:
+		cn.printTable([]*types.AlertData{alert})
+	case ConsoleModeJSON:
+		cn.printJSON(consoleEvent{Type: "alert", Alert: alert})
+	default:
+		// 生成漂亮的控制台输出
+		cn.printAlert(alert)
+	}
 	return nil
 }
 
+// logAlert 以结构化日志输出预警
+func (cn *ConsoleNotifier) logAlert(alert *types.AlertData) {
+	log().Warn("🚨 价格预警触发",
+		zap.String("channel", "console"),
+		zap.String("symbol", alert.Symbol),
+		zap.String("name", alert.Name),
+		zap.Float64("current_price", alert.CurrentPrice),
+		zap.Float64("past_price", alert.PastPrice),
+		zap.Float64("change_percent", alert.ChangePercent),
+		zap.Duration("monitor_period", alert.MonitorPeriod),
+		zap.Bool("partial", alert.Partial),
+		zap.String("kind", alert.Kind),
+		zap.Any("event", alert.Event),
+		zap.Any("stats_24h", alert.Stats24h),
+		zap.Time("alert_time", alert.AlertTime))
+}
+
 func (cn *ConsoleNotifier) SendBatchAlerts(alerts []*types.AlertData) error {
 	if len(alerts) == 0 {
 		return nil
@@ -77,148 +356,250 @@ func (cn *ConsoleNotifier) SendBatchAlerts(alerts []*types.AlertData) error {
 		return cn.SendAlert(alerts[0])
 	}
 
-	// 批量预警的控制台输出
-	cn.printBatchAlerts(alerts)
+	switch cn.mode {
+	case ConsoleModeLog:
+		for _, alert := range alerts {
+			cn.logAlert(alert)
+		}
+		log().Warn("🚨 批量价格预警触发",
+			zap.String("channel", "console"),
+			zap.Int("alert_count", len(alerts)))
+	case ConsoleModeTable:
+		cn.printTable(alerts)
+	case ConsoleModeJSON:
+		cn.printJSON(consoleEvent{Type: "batch", Alerts: alerts})
+	default:
+		// 批量预警的控制台输出
+		cn.printBatchAlerts(alerts)
+	}
+	return nil
+}
+
+func (cn *ConsoleNotifier) SendOpsAlert(alert *types.OpsAlert) error {
+	switch cn.mode {
+	case ConsoleModeLog:
+		fields := []zap.Field{
+			zap.String("channel", "console"),
+			zap.String("component", alert.Component),
+			zap.String("message", alert.Message),
+			zap.Time("alert_time", alert.AlertTime),
+		}
+		if alert.Recovered {
+			log().Info(opsAlertTitle(alert), fields...)
+		} else {
+			log().Warn(opsAlertTitle(alert), fields...)
+		}
+	case ConsoleModeTable:
+		cn.printLine(alert.AlertTime, opsAlertTitle(alert), alert.Component+": "+alert.Message)
+	case ConsoleModeJSON:
+		cn.printJSON(consoleEvent{Type: "ops_alert", OpsAlert: alert})
+	default:
+		cn.printBox(singleBox, alertBoxWidth, []string{
+			opsAlertTitle(alert),
+			"组件: " + alert.Component,
+			"详情: " + alert.Message,
+			"时间: " + alert.AlertTime.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return nil
+}
+
+func (cn *ConsoleNotifier) SendNotice(notice *types.Notice) error {
+	lines := noticeLines(notice)
+	if notice.URL != "" {
+		lines = append(lines, notice.URL)
+	}
+
+	switch cn.mode {
+	case ConsoleModeLog:
+		log().Info(noticeTitle(notice),
+			zap.String("channel", "console"),
+			zap.String("source", notice.Source),
+			zap.String("category", notice.Category),
+			zap.String("message", notice.Message),
+			zap.Strings("symbols", notice.Symbols),
+			zap.String("url", notice.URL),
+			zap.Time("time", notice.Time))
+	case ConsoleModeTable:
+		cn.printLine(notice.Time, noticeTitle(notice), strings.Join(lines, " | "))
+	case ConsoleModeJSON:
+		cn.printJSON(consoleEvent{Type: "notice", Notice: notice})
+	default:
+		cn.printBox(singleBox, alertBoxWidth, append([]string{noticeTitle(notice)}, lines...))
+	}
+	return nil
+}
+
+func (cn *ConsoleNotifier) SendDigest(digest *types.Digest) error {
+	switch cn.mode {
+	case ConsoleModeLog:
+		cells := make([]string, 0, len(digest.Cells))
+		for _, cell := range digest.Cells {
+			cells = append(cells, digestCellText(cell))
+		}
+		log().Info(digestTitle(digest),
+			zap.String("channel", "console"),
+			zap.Duration("window", digest.Window),
+			zap.Int("total", digest.Total),
+			zap.Int("up", digest.Up),
+			zap.Int("down", digest.Down),
+			zap.Strings("cells", cells))
+	case ConsoleModeTable:
+		cn.printDigestTable(digest)
+	case ConsoleModeJSON:
+		cn.printJSON(consoleEvent{Type: "digest", Digest: digest})
+	default:
+		boxWidth, ok := cn.boxWidth(batchBoxWidth)
+		if !ok {
+			cn.printDigestTable(digest)
+			break
+		}
+		lines := append([]string{digestTitle(digest), digestSummary(digest)}, digestGrid(digest, boxWidth-2)...)
+		cn.printBox(singleBox, batchBoxWidth, lines)
+	}
 	return nil
 }
 
 func (cn *ConsoleNotifier) printAlert(alert *types.AlertData) {
+	if _, ok := cn.boxWidth(alertBoxWidth); !ok {
+		cn.printTable([]*types.AlertData{alert})
+		return
+	}
+
 	// 创建一个漂亮的预警框
-	border := "╔" + strings.Repeat("═", 60) + "╗"
-	bottomBorder := "╚" + strings.Repeat("═", 60) + "╝"
+	lines := []string{fmt.Sprintf("%s 🚨 %s触发！", alertEmoji(alert), alertKindLabel(alert)), ""}
 
-	// 获取变化方向的箭头
-	arrow := "📈"
-	if alert.ChangePercent < 0 {
-		arrow = "📉"
+	// 配置组/规则指定了正文模板时按模板输出
+	if body, ok := renderBody(alert); ok {
+		lines = append(lines, strings.Split(strings.TrimRight(body, "\n"), "\n")...)
+		cn.printBox(doubleBox, alertBoxWidth, lines)
+		return
 	}
 
-	fmt.Println()
-	fmt.Println(border)
-	fmt.Printf("║ %s 🚨 价格预警触发！%s ║\n", arrow, strings.Repeat(" ", 34))
-	fmt.Println("║" + strings.Repeat(" ", 60) + "║")
-	fmt.Printf("║ 交易对: %-47s ║\n", alert.Symbol)
-	fmt.Printf("║ 当前价格: $%-43.6f ║\n", alert.CurrentPrice)
-	fmt.Printf("║ %s前价格: $%-39.6f ║\n", formatDuration(alert.MonitorPeriod), alert.PastPrice)
+	lines = append(lines,
+		"交易对: "+displaySymbol(alert),
+		"当前价格: $"+formatTickPrice(alert.CurrentPrice, alert.TickSize),
+		pastPriceLabel(alert)+": $"+formatTickPrice(alert.PastPrice, alert.TickSize))
 
-	// 根据涨跌幅显示不同颜色的提示
-	changeStr := fmt.Sprintf("%.2f%%", alert.ChangePercent)
+	// 根据涨跌幅显示不同的提示
 	if alert.ChangePercent > 0 {
-		fmt.Printf("║ 涨幅: +%-48s ║\n", changeStr)
+		lines = append(lines, "涨幅: "+formatPercent(alert.ChangePercent))
 	} else {
-		fmt.Printf("║ 跌幅: %-49s ║\n", changeStr)
+		lines = append(lines, "跌幅: "+formatPercent(alert.ChangePercent))
 	}
 
-	fmt.Printf("║ 预警时间: %-44s ║\n", alert.AlertTime.Format("2006-01-02 15:04:05"))
-	fmt.Println("║" + strings.Repeat(" ", 60) + "║")
+	lines = append(lines, "预警时间: "+alert.AlertTime.Format("2006-01-02 15:04:05"))
+	for _, field := range alertContext(alert) {
+		lines = append(lines, field.label+": "+field.value)
+	}
+	lines = append(lines, "")
 
 	// 添加提示信息
 	if alert.ChangePercent > 0 {
-		fmt.Printf("║ 💡 该交易对出现显著上涨，请关注市场动向！%-14s ║\n", "")
+		lines = append(lines, "💡 该交易对出现显著上涨，请关注市场动向！")
 	} else {
-		fmt.Printf("║ 💡 该交易对出现显著下跌，请关注风险控制！%-14s ║\n", "")
+		lines = append(lines, "💡 该交易对出现显著下跌，请关注风险控制！")
 	}
 
-	fmt.Println(bottomBorder)
-	fmt.Println()
+	cn.printBox(doubleBox, alertBoxWidth, lines)
 }
 
 func (cn *ConsoleNotifier) printBatchAlerts(alerts []*types.AlertData) {
-	// 分离上涨和下跌的预警
-	var upAlerts []*types.AlertData
-	var downAlerts []*types.AlertData
-
-	for _, alert := range alerts {
-		if alert.ChangePercent > 0 {
-			upAlerts = append(upAlerts, alert)
-		} else {
-			downAlerts = append(downAlerts, alert)
-		}
+	if _, ok := cn.boxWidth(batchBoxWidth); !ok {
+		cn.printTable(alerts)
+		return
 	}
 
-	// 按涨跌幅排序：上涨按涨幅从高到低，下跌按跌幅从高到低（绝对值）
-	sort.Slice(upAlerts, func(i, j int) bool {
-		return upAlerts[i].ChangePercent > upAlerts[j].ChangePercent
-	})
-	sort.Slice(downAlerts, func(i, j int) bool {
-		return downAlerts[i].ChangePercent < downAlerts[j].ChangePercent // 负数，越小跌幅越大
-	})
-
-	// 创建批量预警的漂亮输出
-	border := "╔" + strings.Repeat("═", 80) + "╗"
-	bottomBorder := "╚" + strings.Repeat("═", 80) + "╝"
-
-	fmt.Println()
-	fmt.Println(border)
-
-	// 标题行
-	title := fmt.Sprintf("🚨 批量价格预警触发！- %d个币种", len(alerts))
-	padding := safePadding(title, 80)
-	fmt.Printf("║ %s%s ║\n", title, strings.Repeat(" ", padding))
+	// 按上涨和下跌分组，超过逐条列出上限的预警按幅度分档计数
+	groups := groupBatch(alerts)
+	upAlerts, downAlerts := groups.up, groups.down
 
-	// 统计信息
-	statsStr := fmt.Sprintf("📈 上涨: %d个  📉 下跌: %d个", len(upAlerts), len(downAlerts))
-	padding = safePadding(statsStr, 80)
-	fmt.Printf("║ %s%s ║\n", statsStr, strings.Repeat(" ", padding))
-	fmt.Println("║" + strings.Repeat(" ", 80) + "║")
+	// 标题行和统计信息
+	lines := []string{
+		fmt.Sprintf("🚨 批量价格预警触发！- %d个币种", len(alerts)),
+		fmt.Sprintf("📈 上涨: %d个  📉 下跌: %d个", groups.upTotal, groups.downTotal),
+		"",
+	}
 
 	// 显示上涨币种
 	if len(upAlerts) > 0 {
-		sectionTitle := "📈 上涨币种 (按涨幅排序):"
-		padding = safePadding(sectionTitle, 80)
-		fmt.Printf("║ %s%s ║\n", sectionTitle, strings.Repeat(" ", padding))
-
+		lines = append(lines, "📈 上涨币种 (按涨幅排序):")
 		for i, alert := range upAlerts {
-			changeStr := fmt.Sprintf("+%.2f%%", alert.ChangePercent)
-			content := fmt.Sprintf("  %d. 📈 %s: $%.6f (%s)",
-				i+1, alert.Symbol, alert.CurrentPrice, changeStr)
-
-			// 使用安全的填充计算
-			padding := safePadding(content, 80)
-			fmt.Printf("║ %s%s ║\n", content, strings.Repeat(" ", padding))
+			lines = append(lines, batchLine(i+1, alert))
 		}
-		fmt.Println("║" + strings.Repeat(" ", 80) + "║")
+		lines = append(lines, "")
 	}
 
 	// 显示下跌币种
 	if len(downAlerts) > 0 {
-		sectionTitle := "📉 下跌币种 (按跌幅排序):"
-		padding = safePadding(sectionTitle, 80)
-		fmt.Printf("║ %s%s ║\n", sectionTitle, strings.Repeat(" ", padding))
-
+		lines = append(lines, "📉 下跌币种 (按跌幅排序):")
 		for i, alert := range downAlerts {
-			changeStr := fmt.Sprintf("%.2f%%", alert.ChangePercent)
-			content := fmt.Sprintf("  %d. 📉 %s: $%.6f (%s)",
-				i+1, alert.Symbol, alert.CurrentPrice, changeStr)
+			lines = append(lines, batchLine(i+1, alert))
+		}
+		lines = append(lines, "")
+	}
 
-			// 使用安全的填充计算
-			padding := safePadding(content, 80)
-			fmt.Printf("║ %s%s ║\n", content, strings.Repeat(" ", padding))
+	// 未逐条列出的预警按幅度分档计数
+	if title := groups.omittedTitle(); title != "" {
+		lines = append(lines, "📊 "+title)
+		for _, line := range groups.omittedLines() {
+			lines = append(lines, "  "+line)
 		}
-		fmt.Println("║" + strings.Repeat(" ", 80) + "║")
+		lines = append(lines, "")
 	}
 
-	// 预警时间
-	timeStr := fmt.Sprintf("预警时间: %s", alerts[0].AlertTime.Format("2006-01-02 15:04:05"))
-	padding = safePadding(timeStr, 80)
-	fmt.Printf("║ %s%s ║\n", timeStr, strings.Repeat(" ", padding))
+	// 预警时间和提示信息
+	lines = append(lines,
+		"预警时间: "+alerts[0].AlertTime.Format("2006-01-02 15:04:05"),
+		"",
+		"💡 多个交易对同时出现显著波动，请密切关注市场动向！")
+
+	cn.printBox(doubleBox, batchBoxWidth, lines)
+}
 
-	fmt.Println("║" + strings.Repeat(" ", 80) + "║")
+// batchLine 批量预警框中单个交易对的一行
+func batchLine(index int, alert *types.AlertData) string {
+	changeStr := formatPercent(alert.ChangePercent)
+	if note := batchNote(alert); note != "" {
+		changeStr += ", " + note
+	}
+	return fmt.Sprintf("  %d. %s %s: $%s (%s)",
+		index, alertEmoji(alert), displaySymbol(alert), formatTickPrice(alert.CurrentPrice, alert.TickSize), changeStr)
+}
 
-	// 提示信息
-	msg := "💡 多个交易对同时出现显著波动，请密切关注市场动向！"
-	padding = safePadding(msg, 80)
-	fmt.Printf("║ %s%s ║\n", msg, strings.Repeat(" ", padding))
+// plainAlertBody 单个预警的纯文本正文，每项一行，用于不支持富文本的渠道
+func plainAlertBody(alert *types.AlertData) string {
+	// 配置组/规则指定了正文模板时按模板输出
+	if body, ok := renderBody(alert); ok {
+		return body
+	}
 
-	fmt.Println(bottomBorder)
-	fmt.Println()
+	lines := []string{
+		"当前价格: $" + formatTickPrice(alert.CurrentPrice, alert.TickSize),
+		pastPriceLabel(alert) + ": $" + formatTickPrice(alert.PastPrice, alert.TickSize),
+		"价格变化: " + formatPercent(alert.ChangePercent),
+		"预警时间: " + alert.AlertTime.Format("2006-01-02 15:04:05"),
+	}
+	for _, field := range alertContext(alert) {
+		lines = append(lines, field.label+": "+field.value)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PlainText 预警的纯文本格式（标题和正文），供Telegram等其他模块直接推送
+func PlainText(alert *types.AlertData) string {
+	title := fmt.Sprintf("%s OKX%s - %s", alertEmoji(alert), alertKindLabel(alert), displaySymbol(alert))
+	return title + "\n\n" + plainAlertBody(alert)
 }
 
 // PushPlusNotifier PushPlus通知器
 type PushPlusNotifier struct {
+	deliveryStats
+	console    *ConsoleNotifier // 发送失败时降级输出
 	userToken  string
 	to         string // 好友令牌，多人用逗号分隔
 	enabled    bool
+	dryRun     bool // 演练模式，只记录渲染后的消息不实际发送
 	httpClient *http.Client
 }
 
@@ -236,23 +617,25 @@ type PushPlusResponse struct {
 	Data string `json:"data"`
 }
 
-func NewPushPlusNotifier(userToken, to string) Interface {
+func NewPushPlusNotifier(userToken, to string, dryRun bool, console *ConsoleNotifier) Interface {
 	// 如果没有配置user token，返回控制台通知器
 	if userToken == "" {
-		fmt.Println("🔧 未配置PushPlus User Token，使用控制台输出模式")
-		return NewConsoleNotifier()
+		log().Info("🔧 未配置PushPlus User Token，使用控制台输出模式")
+		return console
 	}
 
 	if to != "" {
-		fmt.Printf("✅ 已配置PushPlus通知服务（包含好友推送: %s）\n", to)
+		log().Info("✅ 已配置PushPlus通知服务（包含好友推送）", zap.Int("friends", len(strings.Split(to, ","))))
 	} else {
-		fmt.Println("✅ 已配置PushPlus通知服务")
+		log().Info("✅ 已配置PushPlus通知服务")
 	}
 
 	return &PushPlusNotifier{
+		console:   console,
 		userToken: userToken,
 		to:        to,
 		enabled:   true,
+		dryRun:    dryRun,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
@@ -262,24 +645,29 @@ func NewPushPlusNotifier(userToken, to string) Interface {
 func (ppn *PushPlusNotifier) SendAlert(alert *types.AlertData) error {
 	if !ppn.enabled {
 		// 降级为控制台输出
-		console := NewConsoleNotifier()
-		return console.SendAlert(alert)
+		return ppn.console.SendAlert(alert)
 	}
 
 	// 构建PushPlus消息内容
-	title := fmt.Sprintf("📈 OKX价格预警 - %s", alert.Symbol)
-	content := ppn.buildHTMLContent(alert)
+	title := fmt.Sprintf("%s OKX%s - %s", alertEmoji(alert), alertKindLabel(alert), displaySymbol(alert))
+	content := buildHTMLContent(alert)
 
 	// 发送PushPlus通知
 	err := ppn.sendPushPlusMessage(title, content)
+	ppn.record(err)
 	if err != nil {
-		fmt.Printf("❌ PushPlus发送失败: %v，降级为控制台输出\n", err)
+		log().Error("❌ PushPlus发送失败，降级为控制台输出",
+			zap.String("channel", "pushplus"),
+			zap.String("symbol", alert.Symbol),
+			zap.Error(err))
 		// 降级为控制台输出
-		console := NewConsoleNotifier()
-		return console.SendAlert(alert)
+		return ppn.console.SendAlert(alert)
 	}
 
-	fmt.Printf("✅ PushPlus通知已发送: %s 变化 %+.2f%%\n", alert.Symbol, alert.ChangePercent)
+	log().Info("✅ PushPlus通知已发送",
+		zap.String("channel", "pushplus"),
+		zap.String("symbol", alert.Symbol),
+		zap.Float64("change_percent", alert.ChangePercent))
 	return nil
 }
 
@@ -294,69 +682,184 @@ func (ppn *PushPlusNotifier) SendBatchAlerts(alerts []*types.AlertData) error {
 
 	if !ppn.enabled {
 		// 降级为控制台输出
-		console := NewConsoleNotifier()
-		return console.SendBatchAlerts(alerts)
+		return ppn.console.SendBatchAlerts(alerts)
 	}
 
 	// 构建批量预警消息
 	title := fmt.Sprintf("📊 OKX批量价格预警 - %d个币种", len(alerts))
-	content := ppn.buildBatchHTMLContent(alerts)
+	content := buildBatchHTMLContent(alerts)
 
 	// 发送PushPlus通知
 	err := ppn.sendPushPlusMessage(title, content)
+	ppn.record(err)
 	if err != nil {
-		fmt.Printf("❌ PushPlus批量发送失败: %v，降级为控制台输出\n", err)
+		log().Error("❌ PushPlus批量发送失败，降级为控制台输出",
+			zap.String("channel", "pushplus"),
+			zap.Error(err))
 		// 降级为控制台输出
-		console := NewConsoleNotifier()
-		return console.SendBatchAlerts(alerts)
+		return ppn.console.SendBatchAlerts(alerts)
 	}
 
-	fmt.Printf("✅ PushPlus批量通知已发送: %d个币种预警\n", len(alerts))
+	log().Info("✅ PushPlus批量通知已发送",
+		zap.String("channel", "pushplus"),
+		zap.Int("alert_count", len(alerts)))
 	return nil
 }
 
-func (ppn *PushPlusNotifier) buildHTMLContent(alert *types.AlertData) string {
+func (ppn *PushPlusNotifier) SendOpsAlert(alert *types.OpsAlert) error {
+	if !ppn.enabled {
+		return ppn.console.SendOpsAlert(alert)
+	}
+
+	err := ppn.sendPushPlusMessage(opsAlertTitle(alert), opsAlertHTML(alert))
+	ppn.record(err)
+	if err != nil {
+		log().Error("❌ PushPlus运维告警发送失败，降级为控制台输出",
+			zap.String("channel", "pushplus"),
+			zap.String("component", alert.Component),
+			zap.Error(err))
+		return ppn.console.SendOpsAlert(alert)
+	}
+	return nil
+}
+
+func (ppn *PushPlusNotifier) SendNotice(notice *types.Notice) error {
+	if !ppn.enabled {
+		return ppn.console.SendNotice(notice)
+	}
+
+	err := ppn.sendPushPlusMessage(noticeTitle(notice), noticeHTML(notice))
+	ppn.record(err)
+	if err != nil {
+		log().Error("❌ PushPlus资讯通知发送失败，降级为控制台输出",
+			zap.String("channel", "pushplus"),
+			zap.String("title", notice.Title),
+			zap.Error(err))
+		return ppn.console.SendNotice(notice)
+	}
+	return nil
+}
+
+func (ppn *PushPlusNotifier) SendDigest(digest *types.Digest) error {
+	if !ppn.enabled {
+		return ppn.console.SendDigest(digest)
+	}
+
+	err := ppn.sendPushPlusMessage(digestTitle(digest), buildDigestHTML(digest))
+	ppn.record(err)
+	if err != nil {
+		log().Error("❌ PushPlus热力图摘要发送失败，降级为控制台输出",
+			zap.String("channel", "pushplus"),
+			zap.Error(err))
+		return ppn.console.SendDigest(digest)
+	}
+
+	log().Info("✅ PushPlus热力图摘要已发送",
+		zap.String("channel", "pushplus"),
+		zap.Int("symbol_count", len(digest.Cells)))
+	return nil
+}
+
+// opsAlertHTML 以HTML渲染运维告警，PushPlus和邮件共用
+func opsAlertHTML(alert *types.OpsAlert) string {
+	color := "#FF8800"
+	if alert.Recovered {
+		color = "#00C851"
+	}
+	return fmt.Sprintf(`
+<div style="border: 2px dashed %s; border-radius: 10px; padding: 20px; margin: 10px; background-color: #f4f4f4;">
+    <h3 style="color: %s; margin-top: 0;">%s</h3>
+    <p><strong>组件:</strong> %s</p>
+    <p><strong>详情:</strong> %s</p>
+    <p><strong>时间:</strong> <span style="color: #666;">%s</span></p>
+</div>
+`, color, color, opsAlertTitle(alert), alert.Component, alert.Message,
+		alert.AlertTime.Format("2006-01-02 15:04:05"))
+}
+
+// noticeHTML 以HTML渲染资讯通知，PushPlus和邮件共用
+func noticeHTML(notice *types.Notice) string {
+	var body strings.Builder
+	for _, line := range noticeLines(notice) {
+		fmt.Fprintf(&body, "    <p>%s</p>\n", line)
+	}
+	if notice.URL != "" {
+		fmt.Fprintf(&body, "    <p><a href=\"%s\" target=\"_blank\">查看公告 🔗</a></p>\n", notice.URL)
+	}
+	return fmt.Sprintf(`
+<div style="border: 2px solid #1890ff; border-radius: 10px; padding: 20px; margin: 10px; background-color: #f9f9f9;">
+    <h3 style="color: #1890ff; margin-top: 0;">%s</h3>
+%s</div>
+`, noticeTitle(notice), body.String())
+}
+
+// contextHTML 以HTML段落渲染预警的附加信息
+func contextHTML(alert *types.AlertData) string {
+	var b strings.Builder
+	for _, field := range alertContext(alert) {
+		fmt.Fprintf(&b, "        <p><strong>%s:</strong> <span style=\"color: #666;\">%s</span></p>\n", field.label, field.value)
+	}
+	return b.String()
+}
+
+// buildHTMLContent 以HTML渲染单个预警，PushPlus和邮件共用
+func buildHTMLContent(alert *types.AlertData) string {
 	// 获取变化方向和颜色
-	arrow := "📈"
+	arrow := alertEmoji(alert)
 	color := "#00C851" // 绿色表示上涨
 	changeText := "上涨"
 	if alert.ChangePercent < 0 {
-		arrow = "📉"
 		color = "#FF4444" // 红色表示下跌
 		changeText = "下跌"
 	}
 
+	// 配置组/规则指定了正文模板时按模板输出，保留换行
+	if body, ok := renderBody(alert); ok {
+		return fmt.Sprintf(`
+<div style="border: 2px solid %s; border-radius: 10px; padding: 20px; margin: 10px; background-color: #f9f9f9;">
+    <h2 style="color: %s; text-align: center; margin-top: 0;">%s %s触发</h2>
+    <div style="background-color: white; padding: 15px; border-radius: 8px; margin: 10px 0; white-space: pre-wrap;">%s</div>
+</div>
+`, color, color, arrow, html.EscapeString(alertKindLabel(alert)), html.EscapeString(body))
+	}
+
 	// 构建HTML格式的消息内容
 	tradingURL := buildTradingURL(alert.Symbol)
 	content := fmt.Sprintf(`
 <div style="border: 2px solid %s; border-radius: 10px; padding: 20px; margin: 10px; background-color: #f9f9f9;">
-    <h2 style="color: %s; text-align: center; margin-top: 0;">%s 价格预警触发</h2>
+    <h2 style="color: %s; text-align: center; margin-top: 0;">%s %s触发</h2>
     
     <div style="background-color: white; padding: 15px; border-radius: 8px; margin: 10px 0;">
         <p><strong>交易对:</strong> <a href="%s" style="font-size: 18px; color: #1890ff; text-decoration: none;" target="_blank">%s 🔗</a></p>
-        <p><strong>当前价格:</strong> <span style="font-size: 16px; color: #333;">$%.6f</span></p>
-        <p><strong>%s前价格:</strong> <span style="font-size: 16px; color: #333;">$%.6f</span></p>
-        <p><strong>价格变化:</strong> <span style="font-size: 18px; font-weight: bold; color: %s;">%+.2f%%</span></p>
+        <p><strong>当前价格:</strong> <span style="font-size: 16px; color: #333;">$%s</span></p>
+        <p><strong>%s:</strong> <span style="font-size: 16px; color: #333;">$%s</span></p>
+        <p><strong>价格变化:</strong> <span style="font-size: 18px; font-weight: bold; color: %s;">%s</span></p>
         <p><strong>预警时间:</strong> <span style="color: #666;">%s</span></p>
-    </div>
+%s    </div>
     
     <div style="background-color: %s; color: white; padding: 10px; border-radius: 8px; text-align: center; margin-top: 15px;">
         <strong>💡 该交易对出现显著%s，请关注市场动向！</strong>
     </div>
 </div>
 `,
-		color, color, arrow,
-		tradingURL, alert.Symbol,
-		alert.CurrentPrice,
-		formatDuration(alert.MonitorPeriod), alert.PastPrice,
-		color, alert.ChangePercent,
+		color, color, arrow, alertKindLabel(alert),
+		tradingURL, displaySymbol(alert),
+		formatTickPrice(alert.CurrentPrice, alert.TickSize),
+		pastPriceLabel(alert), formatTickPrice(alert.PastPrice, alert.TickSize),
+		color, formatPercent(alert.ChangePercent),
 		alert.AlertTime.Format("2006-01-02 15:04:05"),
+		contextHTML(alert),
 		color, changeText)
 
 	return content
 }
 
 func (ppn *PushPlusNotifier) sendPushPlusMessage(title, content string) error {
+	if ppn.dryRun {
+		logDryRun("pushplus", title, content)
+		return nil
+	}
+
 	// 构建请求数据
 	reqData := PushPlusRequest{
 		Token:    ppn.userToken,
@@ -397,30 +900,24 @@ func (ppn *PushPlusNotifier) sendPushPlusMessage(title, content string) error {
 	return nil
 }
 
-func (ppn *PushPlusNotifier) buildBatchHTMLContent(alerts []*types.AlertData) string {
-	if len(alerts) == 0 {
+// batchNoteHTML 批量预警表格中涨跌幅下方的预警类型和24h涨跌
+func batchNoteHTML(alert *types.AlertData) string {
+	note := batchNote(alert)
+	if note == "" {
 		return ""
 	}
+	return `<br><span style="font-size: 12px; color: #999; font-weight: normal;">` + note + `</span>`
+}
 
-	// 分离上涨和下跌的预警
-	var upAlerts []*types.AlertData
-	var downAlerts []*types.AlertData
-
-	for _, alert := range alerts {
-		if alert.ChangePercent > 0 {
-			upAlerts = append(upAlerts, alert)
-		} else {
-			downAlerts = append(downAlerts, alert)
-		}
+// buildBatchHTMLContent 以HTML渲染批量预警，上涨和下跌各一个表格，PushPlus和邮件共用
+func buildBatchHTMLContent(alerts []*types.AlertData) string {
+	if len(alerts) == 0 {
+		return ""
 	}
 
-	// 按涨跌幅排序：上涨按涨幅从高到低，下跌按跌幅从高到低（绝对值）
-	sort.Slice(upAlerts, func(i, j int) bool {
-		return upAlerts[i].ChangePercent > upAlerts[j].ChangePercent
-	})
-	sort.Slice(downAlerts, func(i, j int) bool {
-		return downAlerts[i].ChangePercent < downAlerts[j].ChangePercent // 负数，越小跌幅越大
-	})
+	// 按上涨和下跌分组，超过逐条列出上限的预警按幅度分档计数
+	groups := groupBatch(alerts)
+	upAlerts, downAlerts := groups.up, groups.down
 
 	// 构建HTML格式的批量消息内容
 	content := fmt.Sprintf(`
@@ -433,7 +930,7 @@ func (ppn *PushPlusNotifier) buildBatchHTMLContent(alerts []*types.AlertData) st
         <p style="margin: 5px 0;">📉 下跌币种: <span style="color: #FF4444; font-weight: bold;">%d个</span></p>
         <p style="margin: 5px 0;">🕐 预警时间: <span style="color: #666;">%s</span></p>
     </div>`,
-		len(upAlerts), len(downAlerts), alerts[0].AlertTime.Format("2006-01-02 15:04:05"))
+		groups.upTotal, groups.downTotal, alerts[0].AlertTime.Format("2006-01-02 15:04:05"))
 
 	// 显示上涨币种
 	if len(upAlerts) > 0 {
@@ -447,7 +944,7 @@ func (ppn *PushPlusNotifier) buildBatchHTMLContent(alerts []*types.AlertData) st
                 <th style="padding: 8px; text-align: right; border-bottom: 1px solid #ddd;">涨幅</th>
             </tr>`
 
-		maxShow := 10 // 每个分组最多显示10个
+		maxShow := groups.show(10) // 每个分组最多显示10个
 		showCount := len(upAlerts)
 		if showCount > maxShow {
 			showCount = maxShow
@@ -459,10 +956,10 @@ func (ppn *PushPlusNotifier) buildBatchHTMLContent(alerts []*types.AlertData) st
 			content += fmt.Sprintf(`
             <tr>
                 <td style="padding: 8px; border-bottom: 1px solid #eee;">📈 <a href="%s" style="color: #00C851; text-decoration: none;" target="_blank">%s 🔗</a></td>
-                <td style="padding: 8px; text-align: right; border-bottom: 1px solid #eee;">$%.6f</td>
-                <td style="padding: 8px; text-align: right; border-bottom: 1px solid #eee; color: #00C851; font-weight: bold;">+%.2f%%</td>
+                <td style="padding: 8px; text-align: right; border-bottom: 1px solid #eee;">$%s</td>
+                <td style="padding: 8px; text-align: right; border-bottom: 1px solid #eee; color: #00C851; font-weight: bold;">%s%s</td>
             </tr>`,
-				tradingURL, alert.Symbol, alert.CurrentPrice, alert.ChangePercent)
+				tradingURL, displaySymbol(alert), formatTickPrice(alert.CurrentPrice, alert.TickSize), formatPercent(alert.ChangePercent), batchNoteHTML(alert))
 		}
 
 		if len(upAlerts) > maxShow {
@@ -489,7 +986,7 @@ func (ppn *PushPlusNotifier) buildBatchHTMLContent(alerts []*types.AlertData) st
                 <th style="padding: 8px; text-align: right; border-bottom: 1px solid #ddd;">跌幅</th>
             </tr>`
 
-		maxShow := 10 // 每个分组最多显示10个
+		maxShow := groups.show(10) // 每个分组最多显示10个
 		showCount := len(downAlerts)
 		if showCount > maxShow {
 			showCount = maxShow
@@ -501,10 +998,10 @@ func (ppn *PushPlusNotifier) buildBatchHTMLContent(alerts []*types.AlertData) st
 			content += fmt.Sprintf(`
             <tr>
                 <td style="padding: 8px; border-bottom: 1px solid #eee;">📉 <a href="%s" style="color: #FF4444; text-decoration: none;" target="_blank">%s 🔗</a></td>
-                <td style="padding: 8px; text-align: right; border-bottom: 1px solid #eee;">$%.6f</td>
-                <td style="padding: 8px; text-align: right; border-bottom: 1px solid #eee; color: #FF4444; font-weight: bold;">%.2f%%</td>
+                <td style="padding: 8px; text-align: right; border-bottom: 1px solid #eee;">$%s</td>
+                <td style="padding: 8px; text-align: right; border-bottom: 1px solid #eee; color: #FF4444; font-weight: bold;">%s%s</td>
             </tr>`,
-				tradingURL, alert.Symbol, alert.CurrentPrice, alert.ChangePercent)
+				tradingURL, displaySymbol(alert), formatTickPrice(alert.CurrentPrice, alert.TickSize), formatPercent(alert.ChangePercent), batchNoteHTML(alert))
 		}
 
 		if len(downAlerts) > maxShow {
@@ -519,6 +1016,30 @@ func (ppn *PushPlusNotifier) buildBatchHTMLContent(alerts []*types.AlertData) st
     </div>`
 	}
 
+	// 未逐条列出的预警按幅度分档计数
+	if title := groups.omittedTitle(); title != "" {
+		content += fmt.Sprintf(`
+    <div style="background-color: white; padding: 15px; border-radius: 8px; margin: 10px 0;">
+        <h3 style="color: #666; margin-top: 0;">📊 %s</h3>
+        <table style="width: 100%%; border-collapse: collapse;">
+            <tr style="background-color: #F5F5F5;">
+                <th style="padding: 8px; text-align: left; border-bottom: 1px solid #ddd;">涨跌幅</th>
+                <th style="padding: 8px; text-align: right; border-bottom: 1px solid #ddd;">上涨</th>
+                <th style="padding: 8px; text-align: right; border-bottom: 1px solid #ddd;">下跌</th>
+            </tr>`, title)
+		for _, bucket := range groups.omitted {
+			content += fmt.Sprintf(`
+            <tr>
+                <td style="padding: 8px; border-bottom: 1px solid #eee;">%s</td>
+                <td style="padding: 8px; text-align: right; border-bottom: 1px solid #eee; color: #00C851;">%d个</td>
+                <td style="padding: 8px; text-align: right; border-bottom: 1px solid #eee; color: #FF4444;">%d个</td>
+            </tr>`, html.EscapeString(bucket.label), bucket.up, bucket.down)
+		}
+		content += `
+        </table>
+    </div>`
+	}
+
 	content += `
     <div style="background-color: #FF6B6B; color: white; padding: 15px; border-radius: 8px; text-align: center; margin-top: 15px;">
         <strong>⚠️ 多个交易对同时出现显著波动，请密切关注市场动向！</strong>
@@ -530,9 +1051,12 @@ func (ppn *PushPlusNotifier) buildBatchHTMLContent(alerts []*types.AlertData) st
 
 // DingTalkNotifier 钉钉通知器
 type DingTalkNotifier struct {
+	deliveryStats
+	console    *ConsoleNotifier // 发送失败时降级输出
 	webhookURL string
 	secret     string
 	enabled    bool
+	dryRun     bool // 演练模式，只记录渲染后的消息不实际发送
 	httpClient *http.Client
 }
 
@@ -558,23 +1082,25 @@ type DingTalkResponse struct {
 	ErrMsg  string `json:"errmsg"`
 }
 
-func NewDingTalkNotifier(webhookURL, secret string) Interface {
+func NewDingTalkNotifier(webhookURL, secret string, dryRun bool, console *ConsoleNotifier) Interface {
 	// 如果没有配置webhook URL，返回控制台通知器
 	if webhookURL == "" {
-		zap.L().Info("🔧 未配置钉钉Webhook URL，使用控制台输出模式")
-		return NewConsoleNotifier()
+		log().Info("🔧 未配置钉钉Webhook URL，使用控制台输出模式")
+		return console
 	}
 
 	if secret != "" {
-		zap.L().Info("✅ 已配置钉钉通知服务（含加签验证）")
+		log().Info("✅ 已配置钉钉通知服务（含加签验证）")
 	} else {
-		zap.L().Warn("⚠️ 钉钉通知已配置，但未设置secret（建议配置加签验证）")
+		log().Warn("⚠️ 钉钉通知已配置，但未设置secret（建议配置加签验证）")
 	}
 
 	return &DingTalkNotifier{
+		console:    console,
 		webhookURL: webhookURL,
 		secret:     secret,
 		enabled:    true,
+		dryRun:     dryRun,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
@@ -584,24 +1110,27 @@ func NewDingTalkNotifier(webhookURL, secret string) Interface {
 func (dtn *DingTalkNotifier) SendAlert(alert *types.AlertData) error {
 	if !dtn.enabled {
 		// 降级为控制台输出
-		console := NewConsoleNotifier()
-		return console.SendAlert(alert)
+		return dtn.console.SendAlert(alert)
 	}
 
 	// 构建钉钉消息内容
-	title := fmt.Sprintf("📈 OKX价格预警 - %s", alert.Symbol)
+	title := fmt.Sprintf("%s OKX%s - %s", alertEmoji(alert), alertKindLabel(alert), displaySymbol(alert))
 	content := dtn.buildMarkdownContent(alert)
 
 	// 发送钉钉通知
 	err := dtn.sendDingTalkMessage(title, content)
+	dtn.record(err)
 	if err != nil {
-		fmt.Printf("❌ 钉钉发送失败: %v，降级为控制台输出\n", err)
+		log().Error("❌ 钉钉发送失败，降级为控制台输出",
+			zap.String("channel", "dingtalk"),
+			zap.String("symbol", alert.Symbol),
+			zap.Error(err))
 		// 降级为控制台输出
-		console := NewConsoleNotifier()
-		return console.SendAlert(alert)
+		return dtn.console.SendAlert(alert)
 	}
 
-	zap.L().Info("✅ 钉钉通知已发送",
+	log().Info("✅ 钉钉通知已发送",
+		zap.String("channel", "dingtalk"),
 		zap.String("symbol", alert.Symbol),
 		zap.Float64("change_percent", alert.ChangePercent))
 
@@ -619,8 +1148,7 @@ func (dtn *DingTalkNotifier) SendBatchAlerts(alerts []*types.AlertData) error {
 
 	if !dtn.enabled {
 		// 降级为控制台输出
-		console := NewConsoleNotifier()
-		return console.SendBatchAlerts(alerts)
+		return dtn.console.SendBatchAlerts(alerts)
 	}
 
 	// 构建批量预警消息
@@ -629,14 +1157,70 @@ func (dtn *DingTalkNotifier) SendBatchAlerts(alerts []*types.AlertData) error {
 
 	// 发送钉钉通知
 	err := dtn.sendDingTalkMessage(title, content)
+	dtn.record(err)
 	if err != nil {
-		zap.L().Error("❌ 钉钉批量发送失败，降级为控制台输出", zap.Error(err))
+		log().Error("❌ 钉钉批量发送失败，降级为控制台输出",
+			zap.String("channel", "dingtalk"),
+			zap.Error(err))
 		// 降级为控制台输出
-		console := NewConsoleNotifier()
-		return console.SendBatchAlerts(alerts)
+		return dtn.console.SendBatchAlerts(alerts)
+	}
+
+	log().Info("✅ 钉钉批量通知已发送",
+		zap.String("channel", "dingtalk"),
+		zap.Int("alert_count", len(alerts)))
+	return nil
+}
+
+func (dtn *DingTalkNotifier) SendOpsAlert(alert *types.OpsAlert) error {
+	if !dtn.enabled {
+		return dtn.console.SendOpsAlert(alert)
+	}
+
+	content := fmt.Sprintf(`### %s
+
+**组件**: %s  
+**详情**: %s  
+**时间**: %s`,
+		opsAlertTitle(alert),
+		alert.Component,
+		alert.Message,
+		alert.AlertTime.Format("2006-01-02 15:04:05"))
+
+	err := dtn.sendDingTalkMessage(opsAlertTitle(alert), content)
+	dtn.record(err)
+	if err != nil {
+		log().Error("❌ 钉钉运维告警发送失败，降级为控制台输出",
+			zap.String("channel", "dingtalk"),
+			zap.String("component", alert.Component),
+			zap.Error(err))
+		return dtn.console.SendOpsAlert(alert)
+	}
+	return nil
+}
+
+func (dtn *DingTalkNotifier) SendNotice(notice *types.Notice) error {
+	if !dtn.enabled {
+		return dtn.console.SendNotice(notice)
+	}
+
+	content := "### " + noticeTitle(notice) + "\n\n"
+	for _, line := range noticeLines(notice) {
+		content += line + "  \n"
+	}
+	if notice.URL != "" {
+		content += fmt.Sprintf("\n[查看公告](%s)", notice.URL)
 	}
 
-	zap.L().Info("✅ 钉钉批量通知已发送", zap.Int("alert_count", len(alerts)))
+	err := dtn.sendDingTalkMessage(noticeTitle(notice), content)
+	dtn.record(err)
+	if err != nil {
+		log().Error("❌ 钉钉资讯通知发送失败，降级为控制台输出",
+			zap.String("channel", "dingtalk"),
+			zap.String("title", notice.Title),
+			zap.Error(err))
+		return dtn.console.SendNotice(notice)
+	}
 	return nil
 }
 
@@ -683,60 +1267,66 @@ func (dtn *DingTalkNotifier) buildSignedURL() (string, error) {
 
 // buildMarkdownContent 构建单个预警的Markdown内容
 func (dtn *DingTalkNotifier) buildMarkdownContent(alert *types.AlertData) string {
-	arrow := "📈"
+	arrow := alertEmoji(alert)
 	color := "green"
 	changeText := "上涨"
 
 	if alert.ChangePercent < 0 {
-		arrow = "📉"
 		color = "red"
 		changeText = "下跌"
 	}
 
+	// 配置组/规则指定了正文模板时按模板输出
+	if body, ok := renderBody(alert); ok {
+		return fmt.Sprintf("## %s %s触发\n\n%s", arrow, alertKindLabel(alert), body)
+	}
+
 	// 生成交易链接
 	tradingURL := buildTradingURL(alert.Symbol)
 
-	content := fmt.Sprintf(`## %s 价格预警触发
+	content := fmt.Sprintf(`## %s %s触发
 
 **交易对**: [%s](%s)  
-**当前价格**: $%.6f  
-**%s前价格**: $%.6f  
-**价格变化**: <font color="%s">%+.2f%%</font>  
+**当前价格**: $%s  
+**%s**: $%s  
+**价格变化**: <font color="%s">%s</font>  
 **预警时间**: %s  
-
+%s
 > %s 该交易对出现显著%s，请关注市场动向！`,
-		arrow,
-		alert.Symbol, tradingURL,
-		alert.CurrentPrice,
-		formatDuration(alert.MonitorPeriod), alert.PastPrice,
-		color, alert.ChangePercent,
+		arrow, alertKindLabel(alert),
+		displaySymbol(alert), tradingURL,
+		formatTickPrice(alert.CurrentPrice, alert.TickSize),
+		pastPriceLabel(alert), formatTickPrice(alert.PastPrice, alert.TickSize),
+		color, formatPercent(alert.ChangePercent),
 		alert.AlertTime.Format("2006-01-02 15:04:05"),
+		contextMarkdown(alert),
 		arrow, changeText)
 
 	return content
 }
 
-// buildBatchMarkdownContent 构建批量预警的Markdown内容
-func (dtn *DingTalkNotifier) buildBatchMarkdownContent(alerts []*types.AlertData) string {
-	// 分离上涨和下跌的预警
-	var upAlerts []*types.AlertData
-	var downAlerts []*types.AlertData
+// contextMarkdown 以Markdown渲染预警的附加信息
+func contextMarkdown(alert *types.AlertData) string {
+	var b strings.Builder
+	for _, field := range alertContext(alert) {
+		fmt.Fprintf(&b, "**%s**: %s  \n", field.label, field.value)
+	}
+	return b.String()
+}
 
-	for _, alert := range alerts {
-		if alert.ChangePercent > 0 {
-			upAlerts = append(upAlerts, alert)
-		} else {
-			downAlerts = append(downAlerts, alert)
-		}
+// batchNoteMarkdown 批量预警列表中附带的预警类型和24h涨跌
+func batchNoteMarkdown(alert *types.AlertData) string {
+	if note := batchNote(alert); note != "" {
+		return " · " + note
 	}
+	return ""
+}
 
-	// 按涨跌幅排序：上涨按涨幅从高到低，下跌按跌幅从高到低（绝对值）
-	sort.Slice(upAlerts, func(i, j int) bool {
-		return upAlerts[i].ChangePercent > upAlerts[j].ChangePercent
-	})
-	sort.Slice(downAlerts, func(i, j int) bool {
-		return downAlerts[i].ChangePercent < downAlerts[j].ChangePercent // 负数，越小跌幅越大
-	})
+// buildBatchMarkdownContent 构建批量预警的Markdown内容
+func (dtn *DingTalkNotifier) buildBatchMarkdownContent(alerts []*types.AlertData) string {
+	// 按上涨和下跌分组，超过逐条列出上限的预警按幅度分档计数
+	groups := groupBatch(alerts)
+	upAlerts, downAlerts := groups.up, groups.down
 
 	content := fmt.Sprintf(`## 🚨 批量价格预警触发
 
@@ -746,12 +1336,12 @@ func (dtn *DingTalkNotifier) buildBatchMarkdownContent(alerts []*types.AlertData
 🕐 预警时间: %s  
 
 **详细列表**:  
-`, len(upAlerts), len(downAlerts), alerts[0].AlertTime.Format("2006-01-02 15:04:05"))
+`, groups.upTotal, groups.downTotal, alerts[0].AlertTime.Format("2006-01-02 15:04:05"))
 
 	// 显示上涨部分
 	if len(upAlerts) > 0 {
 		content += "**📈 上涨币种**:\n"
-		maxShow := 8 // 每个分组最多显示8个
+		maxShow := groups.show(8) // 每个分组最多显示8个
 		showCount := len(upAlerts)
 		if showCount > maxShow {
 			showCount = maxShow
@@ -760,8 +1350,8 @@ func (dtn *DingTalkNotifier) buildBatchMarkdownContent(alerts []*types.AlertData
 		for i := 0; i < showCount; i++ {
 			alert := upAlerts[i]
 			tradingURL := buildTradingURL(alert.Symbol)
-			content += fmt.Sprintf("- 📈 **[%s](%s)**: $%.6f (<font color=\"green\">+%.2f%%</font>)\n",
-				alert.Symbol, tradingURL, alert.CurrentPrice, alert.ChangePercent)
+			content += fmt.Sprintf("- 📈 **[%s](%s)**: $%s (<font color=\"green\">%s</font>)%s\n",
+				displaySymbol(alert), tradingURL, formatTickPrice(alert.CurrentPrice, alert.TickSize), formatPercent(alert.ChangePercent), batchNoteMarkdown(alert))
 		}
 
 		if len(upAlerts) > maxShow {
@@ -773,7 +1363,7 @@ func (dtn *DingTalkNotifier) buildBatchMarkdownContent(alerts []*types.AlertData
 	// 显示下跌部分
 	if len(downAlerts) > 0 {
 		content += "**📉 下跌币种**:\n"
-		maxShow := 8 // 每个分组最多显示8个
+		maxShow := groups.show(8) // 每个分组最多显示8个
 		showCount := len(downAlerts)
 		if showCount > maxShow {
 			showCount = maxShow
@@ -782,8 +1372,8 @@ func (dtn *DingTalkNotifier) buildBatchMarkdownContent(alerts []*types.AlertData
 		for i := 0; i < showCount; i++ {
 			alert := downAlerts[i]
 			tradingURL := buildTradingURL(alert.Symbol)
-			content += fmt.Sprintf("- 📉 **[%s](%s)**: $%.6f (<font color=\"red\">%.2f%%</font>)\n",
-				alert.Symbol, tradingURL, alert.CurrentPrice, alert.ChangePercent)
+			content += fmt.Sprintf("- 📉 **[%s](%s)**: $%s (<font color=\"red\">%s</font>)%s\n",
+				displaySymbol(alert), tradingURL, formatTickPrice(alert.CurrentPrice, alert.TickSize), formatPercent(alert.ChangePercent), batchNoteMarkdown(alert))
 		}
 
 		if len(downAlerts) > maxShow {
@@ -791,6 +1381,14 @@ func (dtn *DingTalkNotifier) buildBatchMarkdownContent(alerts []*types.AlertData
 		}
 	}
 
+	// 未逐条列出的预警按幅度分档计数
+	if title := groups.omittedTitle(); title != "" {
+		content += "\n**📊 " + title + "**\n"
+		for _, line := range groups.omittedLines() {
+			content += "- " + line + "\n"
+		}
+	}
+
 	content += "\n> ⚠️ 多个交易对同时出现显著波动，请密切关注市场动向！"
 
 	return content
@@ -798,6 +1396,11 @@ func (dtn *DingTalkNotifier) buildBatchMarkdownContent(alerts []*types.AlertData
 
 // sendDingTalkMessage 发送钉钉消息
 func (dtn *DingTalkNotifier) sendDingTalkMessage(title, content string) error {
+	if dtn.dryRun {
+		logDryRun("dingtalk", title, content)
+		return nil
+	}
+
 	// 构建带签名的URL
 	signedURL, err := dtn.buildSignedURL()
 	if err != nil {
@@ -0,0 +1,87 @@
+package notifier
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"okx-market-sentry/pkg/types"
+)
+
+func TestFormatTickPrice(t *testing.T) {
+	tests := []struct {
+		price, tickSize float64
+		want            string
+	}{
+		{64250.5, 0.1, "64250.5"},
+		{64250, 0.1, "64250.0"},
+		{0.0000012, 0.0000001, "0.0000012"},
+		{1.23456789, 0.0001, "1.2346"},
+		{12, 1, "12"},
+		{0.985, 0, "0.985"}, // 精度未知
+	}
+	for _, tt := range tests {
+		if got := formatTickPrice(tt.price, tt.tickSize); got != tt.want {
+			t.Errorf("formatTickPrice(%v, %v) = %q, want %q", tt.price, tt.tickSize, got, tt.want)
+		}
+	}
+}
+
+func TestFormatPercent(t *testing.T) {
+	tests := map[float64]string{
+		3.456:    "+3.46%",
+		-12.3:    "-12.30%",
+		0.05:     "+0.050%",
+		-0.0012:  "-0.0012%",
+		0:        "0.00%",
+		0.000001: "+0.000001%",
+	}
+	for percent, want := range tests {
+		if got := formatPercent(percent); got != want {
+			t.Errorf("formatPercent(%v) = %q, want %q", percent, got, want)
+		}
+	}
+}
+
+// benchAlerts 生成n个涨跌交替的预警，带24h统计
+func benchAlerts(n int) []*types.AlertData {
+	alerts := make([]*types.AlertData, n)
+	for i := range alerts {
+		change := 3 + float64(i%7)
+		if i%2 == 1 {
+			change = -change
+		}
+		alerts[i] = testAlert(fmt.Sprintf("SYM%d-USDT", i), change)
+		alerts[i].Stats24h = &types.Ticker24h{Open: 190, High: 210, Low: 185, Volume: 1e6, QuoteVolume: 2e8}
+	}
+	return alerts
+}
+
+func BenchmarkBuildBatchHTMLContent(b *testing.B) {
+	alerts := benchAlerts(30)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buildBatchHTMLContent(alerts)
+	}
+}
+
+func BenchmarkBuildBatchMarkdownContent(b *testing.B) {
+	dtn := &DingTalkNotifier{}
+	alerts := benchAlerts(30)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dtn.buildBatchMarkdownContent(alerts)
+	}
+}
+
+func BenchmarkConsoleBatch(b *testing.B) {
+	cn := &ConsoleNotifier{mode: ConsoleModePretty, out: io.Discard, columns: func() int { return 0 }}
+	alerts := benchAlerts(30)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = cn.SendBatchAlerts(alerts)
+	}
+}
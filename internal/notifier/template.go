@@ -0,0 +1,73 @@
+package notifier
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"text/template"
+
+	"go.uber.org/zap"
+	"okx-market-sentry/pkg/types"
+)
+
+// templateFuncs 预警正文模板可用的函数
+var templateFuncs = template.FuncMap{
+	"pct":      formatPercent,
+	"price":    formatPrice,
+	"duration": formatDuration,
+	"link":     buildTradingURL,
+}
+
+// ParseTemplate 解析预警正文模板（text/template），模板数据为 *types.AlertData
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs).Parse(text)
+}
+
+// ValidateTemplates 校验 alert.templates 中的全部模板能否解析
+func ValidateTemplates(templates map[string]string) error {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []error
+	for _, name := range names {
+		if _, err := ParseTemplate(name, templates[name]); err != nil {
+			problems = append(problems, fmt.Errorf("alert.templates.%s: %w", name, err))
+		}
+	}
+	return errors.Join(problems...)
+}
+
+// renderBody 按配置组/规则指定的模板渲染预警正文，未指定模板或渲染失败时返回false，使用渠道默认格式
+func renderBody(alert *types.AlertData) (string, bool) {
+	if alert.Style == nil || alert.Style.Body == "" {
+		return "", false
+	}
+
+	tmpl, err := ParseTemplate(alert.Profile, alert.Style.Body)
+	if err == nil {
+		var buf bytes.Buffer
+		if err = tmpl.Execute(&buf, alert); err == nil {
+			return buf.String(), true
+		}
+	}
+	log().Warn("⚠️ 预警正文模板渲染失败，使用默认格式",
+		zap.String("profile", alert.Profile),
+		zap.String("symbol", alert.Symbol),
+		zap.Error(err))
+	return "", false
+}
+
+// alertEmoji 标题前的表情，配置组/规则未指定时按涨跌显示
+func alertEmoji(alert *types.AlertData) string {
+	if alert.Style != nil && alert.Style.Emoji != "" {
+		return alert.Style.Emoji
+	}
+	if alert.ChangePercent < 0 {
+		return "📉"
+	}
+	return "📈"
+}
@@ -0,0 +1,37 @@
+package notifier
+
+import (
+	"testing"
+	"time"
+
+	"okx-market-sentry/pkg/types"
+)
+
+func TestRenderBody(t *testing.T) {
+	alert := &types.AlertData{
+		Symbol:        "USDC-USDT",
+		CurrentPrice:  0.985,
+		ChangePercent: -1.5,
+		MonitorPeriod: 15 * time.Minute,
+		Profile:       "stables",
+		Style:         &types.AlertStyle{Title: "脱锚预警", Emoji: "🪙", Body: "{{.Symbol}} {{duration .MonitorPeriod}}内 {{pct .ChangePercent}}，现价 ${{price .CurrentPrice}}"},
+	}
+
+	body, ok := renderBody(alert)
+	if want := "USDC-USDT 15分钟内 -1.50%，现价 $0.985"; !ok || body != want {
+		t.Errorf("got %q, want %q", body, want)
+	}
+	if alertKindLabel(alert) != "脱锚预警" || alertEmoji(alert) != "🪙" {
+		t.Errorf("got label %q emoji %q", alertKindLabel(alert), alertEmoji(alert))
+	}
+
+	// 渲染失败时使用默认格式
+	alert.Style.Body = "{{.Missing}}"
+	if _, ok := renderBody(alert); ok {
+		t.Error("引用不存在的字段应渲染失败")
+	}
+
+	if err := ValidateTemplates(map[string]string{"ok": "{{pct .ChangePercent}}", "bad": "{{unknown .Symbol}}"}); err == nil {
+		t.Error("未定义的函数应校验失败")
+	}
+}
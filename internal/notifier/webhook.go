@@ -0,0 +1,220 @@
+package notifier
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// 通用Webhook的事件类型，通过 X-Sentry-Event 请求头区分请求体的结构
+const (
+	WebhookEventAlert  = "alert"  // 单个预警，请求体为 types.AlertData
+	WebhookEventBatch  = "batch"  // 批量预警，请求体为 types.AlertData 数组
+	WebhookEventOps    = "ops"    // 运维告警，请求体为 types.OpsAlert
+	WebhookEventNotice = "notice" // 资讯通知，请求体为 types.Notice
+)
+
+// DefaultWebhookSignatureHeader 默认的签名请求头，值为 sha256=<请求体的HMAC-SHA256十六进制>
+const DefaultWebhookSignatureHeader = "X-Sentry-Signature"
+
+// WebhookNotifier 通用Webhook通知器，将预警以JSON POST到用户自定义的地址，便于接入自己的系统
+type WebhookNotifier struct {
+	deliveryStats
+	console    *ConsoleNotifier // 发送失败时降级输出
+	config     types.WebhookConfig
+	enabled    bool
+	dryRun     bool // 演练模式，只记录请求体不实际发送
+	httpClient *http.Client
+}
+
+func NewWebhookNotifier(webhookConfig types.WebhookConfig, dryRun bool, console *ConsoleNotifier) Interface {
+	// 如果没有配置地址，返回控制台通知器
+	if len(webhookConfig.URLs) == 0 {
+		log().Info("🔧 未配置Webhook地址，使用控制台输出模式")
+		return console
+	}
+	if webhookConfig.SignatureHeader == "" {
+		webhookConfig.SignatureHeader = DefaultWebhookSignatureHeader
+	}
+
+	log().Info("✅ 已配置通用Webhook",
+		zap.Int("url_count", len(webhookConfig.URLs)),
+		zap.Bool("signed", webhookConfig.Secret != ""))
+	return &WebhookNotifier{
+		console: console,
+		config:  webhookConfig,
+		enabled: true,
+		dryRun:  dryRun,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (wn *WebhookNotifier) SendAlert(alert *types.AlertData) error {
+	if !wn.enabled {
+		// 降级为控制台输出
+		return wn.console.SendAlert(alert)
+	}
+
+	err := wn.post(WebhookEventAlert, alert)
+	wn.record(err)
+	if err != nil {
+		log().Error("❌ Webhook发送失败，降级为控制台输出",
+			zap.String("channel", "webhook"),
+			zap.String("symbol", alert.Symbol),
+			zap.Error(err))
+		// 降级为控制台输出
+		return wn.console.SendAlert(alert)
+	}
+
+	log().Info("✅ Webhook通知已发送",
+		zap.String("channel", "webhook"),
+		zap.String("symbol", alert.Symbol),
+		zap.Float64("change_percent", alert.ChangePercent))
+	return nil
+}
+
+func (wn *WebhookNotifier) SendBatchAlerts(alerts []*types.AlertData) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	if !wn.enabled {
+		// 降级为控制台输出
+		return wn.console.SendBatchAlerts(alerts)
+	}
+
+	// 单个预警也按数组发送，接收方按事件类型解析即可
+	err := wn.post(WebhookEventBatch, alerts)
+	wn.record(err)
+	if err != nil {
+		log().Error("❌ Webhook批量发送失败，降级为控制台输出",
+			zap.String("channel", "webhook"),
+			zap.Error(err))
+		// 降级为控制台输出
+		return wn.console.SendBatchAlerts(alerts)
+	}
+
+	log().Info("✅ Webhook批量通知已发送",
+		zap.String("channel", "webhook"),
+		zap.Int("alert_count", len(alerts)))
+	return nil
+}
+
+func (wn *WebhookNotifier) SendOpsAlert(alert *types.OpsAlert) error {
+	if !wn.enabled {
+		return wn.console.SendOpsAlert(alert)
+	}
+
+	err := wn.post(WebhookEventOps, alert)
+	wn.record(err)
+	if err != nil {
+		log().Error("❌ Webhook运维告警发送失败，降级为控制台输出",
+			zap.String("channel", "webhook"),
+			zap.String("component", alert.Component),
+			zap.Error(err))
+		return wn.console.SendOpsAlert(alert)
+	}
+	return nil
+}
+
+func (wn *WebhookNotifier) SendNotice(notice *types.Notice) error {
+	if !wn.enabled {
+		return wn.console.SendNotice(notice)
+	}
+
+	err := wn.post(WebhookEventNotice, notice)
+	wn.record(err)
+	if err != nil {
+		log().Error("❌ Webhook资讯通知发送失败，降级为控制台输出",
+			zap.String("channel", "webhook"),
+			zap.String("title", notice.Title),
+			zap.Error(err))
+		return wn.console.SendNotice(notice)
+	}
+	return nil
+}
+
+// post 将数据序列化为JSON后发送到全部地址，只要有一个地址成功即视为送达
+// 全部地址失败时返回错误，由调用方降级并计入失败；部分地址失败时只记录日志
+func (wn *WebhookNotifier) post(event string, payload any) error {
+	// 序列化为JSON
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("序列化请求数据失败: %v", err)
+	}
+
+	if wn.dryRun {
+		logDryRun("webhook", event, string(body))
+		return nil
+	}
+
+	signature := ""
+	if wn.config.Secret != "" {
+		signature = "sha256=" + WebhookSignature(wn.config.Secret, body)
+	}
+
+	var problems []error
+	for _, url := range wn.config.URLs {
+		if err := wn.postTo(url, event, signature, body); err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", url, err))
+		}
+	}
+	if len(problems) == len(wn.config.URLs) {
+		return errors.Join(problems...)
+	}
+	for _, problem := range problems {
+		log().Warn("⚠️ 部分Webhook地址发送失败",
+			zap.String("channel", "webhook"),
+			zap.String("event", event),
+			zap.Int("delivered", len(wn.config.URLs)-len(problems)),
+			zap.Error(problem))
+	}
+	return nil
+}
+
+// postTo 向单个地址发送请求，非2xx状态码视为失败
+func (wn *WebhookNotifier) postTo(url, event, signature string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "okx-market-sentry")
+	req.Header.Set("X-Sentry-Event", event)
+	if signature != "" {
+		req.Header.Set(wn.config.SignatureHeader, signature)
+	}
+
+	// 发送HTTP请求
+	resp, err := wn.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP状态码 %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// WebhookSignature 请求体的HMAC-SHA256签名（十六进制），接收方用相同的密钥计算后比较即可校验来源
+func WebhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
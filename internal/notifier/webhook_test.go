@@ -0,0 +1,132 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// webhookRequest 模拟接收方收到的请求
+type webhookRequest struct {
+	event     string
+	signature string
+	body      []byte
+}
+
+// webhookServer 模拟接收方，记录收到的请求并返回指定的状态码
+func webhookServer(t *testing.T, status int, received *[]webhookRequest) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type = %q", r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		*received = append(*received, webhookRequest{
+			event:     r.Header.Get("X-Sentry-Event"),
+			signature: r.Header.Get(DefaultWebhookSignatureHeader),
+			body:      body,
+		})
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWebhookSignedPayload(t *testing.T) {
+	var received []webhookRequest
+	first := webhookServer(t, http.StatusOK, &received)
+	second := webhookServer(t, http.StatusNoContent, &received)
+	wn := NewWebhookNotifier(types.WebhookConfig{
+		URLs:   []string{first.URL, second.URL},
+		Secret: "s3cret",
+	}, false, &ConsoleNotifier{mode: ConsoleModeLog, out: io.Discard})
+
+	if err := wn.SendAlert(testAlert("SOL-USDT", -3.67)); err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 2 {
+		t.Fatalf("收到 %d 个请求，want 2", len(received))
+	}
+	for _, req := range received {
+		if req.event != WebhookEventAlert {
+			t.Errorf("event = %q, want %q", req.event, WebhookEventAlert)
+		}
+		if want := "sha256=" + WebhookSignature("s3cret", req.body); req.signature != want {
+			t.Errorf("signature = %q, want %q", req.signature, want)
+		}
+		var alert types.AlertData
+		if err := json.Unmarshal(req.body, &alert); err != nil {
+			t.Fatalf("请求体不是AlertData: %v", err)
+		}
+		if alert.Symbol != "SOL-USDT" || alert.ChangePercent != -3.67 {
+			t.Errorf("alert = %+v", alert)
+		}
+	}
+
+	received = nil
+	if err := wn.SendBatchAlerts(benchAlerts(3)); err != nil {
+		t.Fatal(err)
+	}
+	var alerts []types.AlertData
+	if err := json.Unmarshal(received[0].body, &alerts); err != nil {
+		t.Fatalf("批量请求体不是AlertData数组: %v", err)
+	}
+	if received[0].event != WebhookEventBatch || len(alerts) != 3 {
+		t.Errorf("event = %q, alert_count = %d", received[0].event, len(alerts))
+	}
+}
+
+func TestWebhookUnsignedAndFailure(t *testing.T) {
+	var received []webhookRequest
+	server := webhookServer(t, http.StatusInternalServerError, &received)
+	wn := NewWebhookNotifier(types.WebhookConfig{
+		URLs:            []string{server.URL},
+		SignatureHeader: DefaultWebhookSignatureHeader,
+	}, false, &ConsoleNotifier{mode: ConsoleModeLog, out: io.Discard}).(*WebhookNotifier)
+
+	// 失败时降级为控制台输出，计入连续失败次数
+	if err := wn.SendOpsAlert(&types.OpsAlert{Component: "fetcher", Message: "连续失败"}); err != nil {
+		t.Fatal(err)
+	}
+	if wn.FailureStreak() != 1 {
+		t.Errorf("FailureStreak = %d, want 1", wn.FailureStreak())
+	}
+	if len(received) != 1 || received[0].event != WebhookEventOps {
+		t.Fatalf("received = %+v", received)
+	}
+	if received[0].signature != "" {
+		t.Errorf("未配置密钥时不应签名，signature = %q", received[0].signature)
+	}
+}
+
+func TestWebhookPartialFailure(t *testing.T) {
+	var received []webhookRequest
+	down := webhookServer(t, http.StatusBadGateway, &received)
+	up := webhookServer(t, http.StatusOK, &received)
+	var fallback bytes.Buffer
+	console := &ConsoleNotifier{mode: ConsoleModeTable, out: &fallback, columns: func() int { return 0 }}
+
+	// 部分地址成功时视为送达，不降级也不计入失败
+	wn := NewWebhookNotifier(types.WebhookConfig{URLs: []string{down.URL, up.URL}}, false, console).(*WebhookNotifier)
+	if err := wn.SendAlert(testAlert("SOL-USDT", 3.67)); err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 2 || fallback.Len() != 0 || wn.FailureStreak() != 0 {
+		t.Errorf("received = %d, fallback = %q, FailureStreak = %d", len(received), fallback.String(), wn.FailureStreak())
+	}
+
+	// 全部地址失败时降级为控制台输出
+	wn = NewWebhookNotifier(types.WebhookConfig{URLs: []string{down.URL, down.URL}}, false, console).(*WebhookNotifier)
+	if err := wn.SendAlert(testAlert("SOL-USDT", 3.67)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(fallback.String(), "SOL") || wn.FailureStreak() != 1 {
+		t.Errorf("fallback = %q, FailureStreak = %d", fallback.String(), wn.FailureStreak())
+	}
+}
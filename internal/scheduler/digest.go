@@ -0,0 +1,65 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"okx-market-sentry/internal/notifier"
+	"okx-market-sentry/pkg/types"
+)
+
+// DigestSource 生成涨跌幅热力图摘要，由分析引擎实现
+type DigestSource interface {
+	Digest(window time.Duration, limit int) *types.Digest
+}
+
+// DigestJob 按 digest.schedule 定期发送涨跌幅热力图摘要
+type DigestJob struct {
+	config types.DigestConfig
+	source DigestSource
+	sender notifier.Interface
+}
+
+func NewDigestJob(config types.DigestConfig, source DigestSource, sender notifier.Interface) *DigestJob {
+	return &DigestJob{
+		config: config,
+		source: source,
+		sender: sender,
+	}
+}
+
+// Start 按cron表达式发送摘要，直到ctx取消
+func (j *DigestJob) Start(ctx context.Context) {
+	c := cron.New(cron.WithLocation(time.Local), cron.WithChain(cron.Recover(cronLogger{})))
+	if _, err := c.AddFunc(j.config.Schedule, j.send); err != nil {
+		log().Error("❌ 热力图摘要cron表达式无效，不发送摘要", zap.String("spec", j.config.Schedule), zap.Error(err))
+		return
+	}
+	log().Info("🌡️ 热力图摘要已启用",
+		zap.String("spec", j.config.Schedule),
+		zap.Duration("window", j.config.Window),
+		zap.String("channel", j.config.Channel))
+	c.Start()
+
+	<-ctx.Done()
+	<-c.Stop().Done()
+}
+
+// send 生成并发送一次摘要，还没有交易对积累满一个统计周期时跳过
+func (j *DigestJob) send() {
+	digest := j.source.Digest(j.config.Window, j.config.MaxSymbols)
+	if len(digest.Cells) == 0 {
+		log().Info("热力图摘要暂无完整统计周期的数据，跳过本次发送", zap.Duration("window", j.config.Window))
+		return
+	}
+
+	if err := notifier.SendDigest(j.sender, digest); err != nil {
+		log().Error("❌ 热力图摘要发送失败", zap.String("channel", j.config.Channel), zap.Error(err))
+		return
+	}
+	log().Debug("热力图摘要已发送",
+		zap.Int("total", digest.Total),
+		zap.Int("shown", len(digest.Cells)))
+}
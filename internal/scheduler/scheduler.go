@@ -2,138 +2,319 @@ package scheduler
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
 	"okx-market-sentry/internal/analyzer"
 	"okx-market-sentry/internal/fetcher"
 	"okx-market-sentry/internal/storage"
+	"okx-market-sentry/pkg/logger"
+	"okx-market-sentry/pkg/types"
 )
 
+// log 模块日志器，级别可通过 log.modules.scheduler 单独配置
+func log() *zap.Logger {
+	return logger.Named("scheduler")
+}
+
 // Scheduler 调度器
 type Scheduler struct {
-	dataFetcher     *fetcher.DataFetcher
-	analysisEngine  *analyzer.AnalysisEngine
-	stateManager    *storage.StateManager
-	fetchInterval   time.Duration
-	analyzeInterval time.Duration
-	monitorPeriod   time.Duration // 监控周期
+	dataFetcher    *fetcher.DataFetcher
+	analysisEngine *analyzer.AnalysisEngine
+	stateManager   *storage.StateManager
+	monitorPeriod  time.Duration // 监控周期
+	analysisSpec   string        // 分析任务的cron表达式
+	schedule       cron.Schedule
+	runOnStart     bool // 启动后立即按已有数据分析一轮
+
+	heartbeatMutex sync.RWMutex
+	lastHeartbeat  time.Time // 分析循环最近一次完成的时间
+
+	running       atomic.Bool  // 是否有分析正在执行
+	lastScheduled time.Time    // 上一轮实际执行的计划时间点，仅在分析goroutine中访问
+	missedCycles  atomic.Int64 // 累计错过的分析时间点
 }
 
-func NewScheduler(dataFetcher *fetcher.DataFetcher, analysisEngine *analyzer.AnalysisEngine, stateManager *storage.StateManager, monitorPeriod time.Duration) *Scheduler {
+const (
+	startLagWarning = 5 * time.Second // 实际开始时间晚于计划时间点超过该值时告警
+	maxMissedScan   = 10000           // 统计错过的时间点时最多遍历的数量
+
+	cycleBudgetPercent = 80 // 分析耗时（从计划时间点算起）超过分析间隔的该百分比时告警
+)
+
+// ErrAnalysisRunning 已有分析正在执行，手动触发被拒绝
+var ErrAnalysisRunning = errors.New("analysis already running")
+
+// NewScheduler 分析时间点见 AnalysisSchedule
+func NewScheduler(dataFetcher *fetcher.DataFetcher, analysisEngine *analyzer.AnalysisEngine, stateManager *storage.StateManager, monitorPeriod time.Duration, scheduleConfig types.ScheduleConfig) *Scheduler {
+	analysisSpec, schedule := AnalysisSchedule(monitorPeriod, scheduleConfig)
 	return &Scheduler{
-		dataFetcher:     dataFetcher,
-		analysisEngine:  analysisEngine,
-		stateManager:    stateManager,
-		fetchInterval:   1 * time.Minute, // 每分钟获取数据
-		analyzeInterval: 1 * time.Minute, // 每分钟分析一次
-		monitorPeriod:   monitorPeriod,   // 监控周期
+		dataFetcher:    dataFetcher,
+		analysisEngine: analysisEngine,
+		stateManager:   stateManager,
+		monitorPeriod:  monitorPeriod,
+		analysisSpec:   analysisSpec,
+		schedule:       schedule,
+		runOnStart:     scheduleConfig.RunOnStart,
 	}
 }
 
+// AnalysisSchedule 分析时间点优先按 schedule.analysis 的cron表达式，其次按 schedule.analysis_interval 固定间隔，
+// 都未配置时按监控周期对齐到K线时间，如5m对应 */5 * * * *，返回表达式说明和调度
+func AnalysisSchedule(monitorPeriod time.Duration, scheduleConfig types.ScheduleConfig) (string, cron.Schedule) {
+	analysisSpec := scheduleConfig.Analysis
+	if analysisSpec == "" && scheduleConfig.AnalysisInterval > 0 {
+		return "@every " + scheduleConfig.AnalysisInterval.String(), intervalSchedule{interval: scheduleConfig.AnalysisInterval}
+	}
+	if analysisSpec == "" {
+		analysisSpec = KlineSpec(monitorPeriod)
+	}
+	// 表达式已在配置校验时检查，这里解析失败时退回按监控周期对齐
+	schedule, err := cron.ParseStandard(analysisSpec)
+	if err != nil {
+		log().Error("❌ 分析任务cron表达式无效，按监控周期对齐", zap.String("spec", analysisSpec), zap.Error(err))
+		analysisSpec = KlineSpec(monitorPeriod)
+		schedule, _ = cron.ParseStandard(analysisSpec)
+	}
+	return analysisSpec, schedule
+}
+
+// intervalSchedule 按固定间隔执行，时间点对齐到间隔的整数倍（如30s对应每分钟的00秒和30秒）
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+func (s intervalSchedule) Next(t time.Time) time.Time {
+	return t.Truncate(s.interval).Add(s.interval)
+}
+
+// KlineSpec 返回对齐到监控周期K线收盘时间的cron表达式，监控周期需整除60分钟
+func KlineSpec(monitorPeriod time.Duration) string {
+	minutes := int(monitorPeriod / time.Minute)
+	if minutes <= 1 {
+		return "* * * * *"
+	}
+	if minutes >= 60 {
+		return "0 * * * *"
+	}
+	return fmt.Sprintf("*/%d * * * *", minutes)
+}
+
 func (s *Scheduler) Start(ctx context.Context) {
-	zap.L().Info("🚀 调度器启动中...")
+	log().Info("🚀 调度器启动中...", zap.String("analysis_spec", s.analysisSpec))
+	s.beat()
 
 	// 启动数据获取器
 	go s.dataFetcher.Start(ctx)
 
-	// 计算下一个K线对齐的时间点
-	nextKlineTime := s.calculateNextKlineTime()
-	waitDuration := time.Until(nextKlineTime)
+	// 下次执行时间由cron按表达式从计划时间点推算，不受分析耗时影响，不会累积漂移
+	c := cron.New(
+		cron.WithLocation(time.Local),
+		cron.WithChain(cron.Recover(cronLogger{})),
+	)
+	var entryID cron.EntryID
+	entryID = c.Schedule(s.schedule, cron.FuncJob(func() {
+		entry := c.Entry(entryID)
+		s.runScheduled(ctx, entry.Prev)
+		log().Info("⏰ 下次分析时间",
+			zap.String("next_time", c.Entry(entryID).Next.Format("15:04:05")))
+	}))
+	c.Start()
+
+	next := c.Entry(entryID).Next
+	if s.runOnStart {
+		go s.runStartup(ctx, next)
+	} else {
+		log().Info("⏳ 等待第一个分析时间点",
+			zap.String("next_time", next.Format("15:04:05")))
+	}
 
-	zap.L().Info("⏳ 等待同步到下一个K线时间点",
-		zap.String("next_time", nextKlineTime.Format("15:04:05")),
-		zap.Duration("wait_duration", waitDuration))
+	<-ctx.Done()
+	<-c.Stop().Done()
+	log().Info("📴 调度器已停止")
+}
+
+// runStartup 首次获取行情完成后立即分析一轮，数据不足完整监控周期的交易对按已有数据计算
+// 第一个分析时间点先到达时不再单独执行
+func (s *Scheduler) runStartup(ctx context.Context, next time.Time) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for !s.dataFetcher.HasFetched() {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !time.Now().Before(next) {
+				return
+			}
+		}
+	}
 
-	select {
-	case <-ctx.Done():
+	if !s.running.CompareAndSwap(false, true) {
 		return
-	case <-time.After(waitDuration):
-		zap.L().Info("✅ 已同步到K线时间，开始价格分析和预警监控",
-			zap.String("sync_time", time.Now().Format("15:04:05")))
 	}
+	defer s.running.Store(false)
 
-	// 创建对齐到K线时间的定时器
-	s.startKlineAlignedAnalysis(ctx)
+	log().Info("⚡ 启动后立即执行一轮分析，之后对齐到分析时间点",
+		zap.String("next_time", next.Format("15:04:05")))
+	s.runAnalysis(ctx, true)
 }
 
-func (s *Scheduler) runAnalysis() {
-	zap.L().Info("--- 价格分析任务开始 ---",
+// Trigger 在定时计划之外立即执行一次分析，symbols为空时分析全部交易对，返回触发的预警
+// 已有分析正在执行时返回ErrAnalysisRunning；不影响后续分析时间点
+func (s *Scheduler) Trigger(ctx context.Context, symbols []string) ([]*types.AlertData, error) {
+	if !s.running.CompareAndSwap(false, true) {
+		return nil, ErrAnalysisRunning
+	}
+	defer s.running.Store(false)
+
+	log().Info("👆 手动触发分析", zap.Strings("symbols", symbols))
+	alerts := s.analysisEngine.AnalyzeSymbols(ctx, symbols)
+	if len(symbols) == 0 {
+		s.beat()
+	}
+	return alerts, nil
+}
+
+// runScheduled 执行计划时间点的分析
+// 上一轮尚未结束时跳过本轮；两次执行之间被跳过的时间点（分析超时、进程挂起、系统休眠）计入错过的周期
+func (s *Scheduler) runScheduled(ctx context.Context, scheduled time.Time) {
+	if !s.running.CompareAndSwap(false, true) {
+		log().Warn("⏭️ 上一轮分析尚未结束，跳过本轮", zap.Time("scheduled", scheduled))
+		return
+	}
+	defer s.running.Store(false)
+
+	if missed := s.countMissed(s.lastScheduled, scheduled); missed > 0 {
+		s.missedCycles.Add(int64(missed))
+		log().Warn("⚠️ 错过了分析时间点，已重新对齐到当前时间点",
+			zap.Int("missed", missed),
+			zap.Time("last_scheduled", s.lastScheduled),
+			zap.Time("scheduled", scheduled))
+	}
+	if lag := time.Since(scheduled); lag > startLagWarning {
+		log().Warn("⚠️ 分析开始时间晚于计划时间点",
+			zap.Time("scheduled", scheduled),
+			zap.Duration("lag", lag))
+	}
+	s.lastScheduled = scheduled
+
+	s.runAnalysis(ctx, false)
+
+	// 耗时接近分析间隔时，继续增长会导致跳过时间点
+	interval := s.schedule.Next(scheduled).Sub(scheduled)
+	if elapsed := time.Since(scheduled); elapsed > interval*cycleBudgetPercent/100 {
+		log().Warn("⚠️ 分析耗时接近分析间隔，可调大 alert.workers 或分析间隔",
+			zap.Duration("elapsed", elapsed),
+			zap.Duration("interval", interval))
+	}
+}
+
+// countMissed 统计两个计划时间点之间被跳过的时间点数量
+func (s *Scheduler) countMissed(last, scheduled time.Time) int {
+	if last.IsZero() {
+		return 0
+	}
+	missed := 0
+	for t := s.schedule.Next(last); t.Before(scheduled) && missed < maxMissedScan; t = s.schedule.Next(t) {
+		missed++
+	}
+	return missed
+}
+
+// MissedCycles 启动以来累计错过的分析时间点数量
+func (s *Scheduler) MissedCycles() int64 {
+	return s.missedCycles.Load()
+}
+
+// runAnalysis 执行一轮分析，partial为true时允许按不足完整监控周期的数据计算
+func (s *Scheduler) runAnalysis(ctx context.Context, partial bool) {
+	log().Info("--- 价格分析任务开始 ---",
 		zap.String("time", time.Now().Format("15:04:05")))
 
 	// 显示存储状态
 	stats := s.stateManager.GetRedisStats()
 	if stats["redis_enabled"].(bool) {
 		if redisKeys, ok := stats["redis_keys"]; ok {
-			zap.L().Info("📊 存储状态",
+			log().Info("📊 存储状态",
 				zap.Int("memory_symbols", stats["memory_symbols"].(int)),
 				zap.Int("redis_keys", redisKeys.(int)))
 		} else {
-			zap.L().Info("📊 存储状态",
+			log().Info("📊 存储状态",
 				zap.Int("memory_symbols", stats["memory_symbols"].(int)),
 				zap.String("redis_status", "已连接但获取key数失败"))
 		}
 	} else {
-		zap.L().Info("📊 存储状态",
+		log().Info("📊 存储状态",
 			zap.Int("memory_symbols", stats["memory_symbols"].(int)),
 			zap.String("redis_status", "未启用"))
 	}
 
-	s.analysisEngine.AnalyzeAll()
-	zap.L().Info("--- 分析任务完成 ---")
+	s.logWarmupStatus()
+
+	if partial {
+		s.analysisEngine.AnalyzePartial(ctx)
+	} else {
+		s.analysisEngine.AnalyzeAll(ctx)
+	}
+	s.beat()
+	log().Info("--- 分析任务完成 ---")
+}
+
+// beat 记录分析循环的心跳
+func (s *Scheduler) beat() {
+	s.heartbeatMutex.Lock()
+	defer s.heartbeatMutex.Unlock()
+	s.lastHeartbeat = time.Now()
 }
 
-// calculateNextKlineTime 计算下一个K线对齐的时间点
-func (s *Scheduler) calculateNextKlineTime() time.Time {
-	now := time.Now()
+// LastHeartbeat 分析循环最近一次心跳时间
+func (s *Scheduler) LastHeartbeat() time.Time {
+	s.heartbeatMutex.RLock()
+	defer s.heartbeatMutex.RUnlock()
+	return s.lastHeartbeat
+}
 
-	// 获取监控周期的分钟数
-	periodMinutes := int(s.monitorPeriod.Minutes())
+// HeartbeatInterval 正常情况下两次心跳的最大间隔，即上次心跳之后下一个分析时间点的间隔
+func (s *Scheduler) HeartbeatInterval() time.Duration {
+	last := s.LastHeartbeat()
+	return s.schedule.Next(last).Sub(last)
+}
 
-	// 计算当前小时内的分钟数，向上取整到下一个周期倍数
-	currentMinute := now.Minute()
-	nextAlignedMinute := ((currentMinute / periodMinutes) + 1) * periodMinutes
+// logWarmupStatus 输出仍在预热中的交易对数量，便于判断新交易对何时开始参与分析
+func (s *Scheduler) logWarmupStatus() {
+	warming := 0
+	var maxRemaining time.Duration
+	for _, status := range s.analysisEngine.GetWarmupStatus() {
+		progress, ok := status.Indicators["price_change"]
+		if !ok || progress.Ready {
+			continue
+		}
+		warming++
+		if remaining := progress.Required - status.Covered; remaining > maxRemaining {
+			maxRemaining = remaining
+		}
+	}
 
-	// 如果超过60分钟，进入下一小时
-	if nextAlignedMinute >= 60 {
-		// 进入下一小时的对齐时间点
-		nextHour := now.Hour() + 1
-		nextAlignedMinute = 0
-		return time.Date(now.Year(), now.Month(), now.Day(), nextHour, nextAlignedMinute, 0, 0, now.Location())
+	if warming > 0 {
+		log().Info("🔥 交易对数据预热中",
+			zap.Int("warming_symbols", warming),
+			zap.Duration("max_remaining", maxRemaining))
 	}
+}
+
+// cronLogger 将cron的日志转到模块日志器
+type cronLogger struct{}
 
-	// 同一小时内的对齐时间点
-	return time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), nextAlignedMinute, 0, 0, now.Location())
+func (cronLogger) Info(msg string, keysAndValues ...interface{}) {
+	log().Debug("cron", zap.String("msg", msg), zap.Any("details", keysAndValues))
 }
 
-// startKlineAlignedAnalysis 启动对齐到K线时间的分析任务
-func (s *Scheduler) startKlineAlignedAnalysis(ctx context.Context) {
-	for {
-		select {
-		case <-ctx.Done():
-			zap.L().Info("📴 调度器已停止")
-			return
-		default:
-			// 运行分析
-			s.runAnalysis()
-
-			// 计算下一次分析时间（下一个K线时间点）
-			nextAnalysisTime := s.calculateNextKlineTime()
-			waitDuration := time.Until(nextAnalysisTime)
-
-			zap.L().Info("⏰ 下次分析时间",
-				zap.String("next_time", nextAnalysisTime.Format("15:04:05")),
-				zap.Duration("wait_duration", waitDuration))
-
-			// 等待到下一个K线时间点
-			select {
-			case <-ctx.Done():
-				zap.L().Info("📴 调度器已停止")
-				return
-			case <-time.After(waitDuration):
-				// 继续下一轮分析
-				continue
-			}
-		}
-	}
+func (cronLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	log().Error("❌ 定时任务异常", zap.String("cron", msg), zap.Any("details", keysAndValues), zap.Error(err))
 }
@@ -0,0 +1,174 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"okx-market-sentry/internal/analyzer"
+	"okx-market-sentry/internal/notifier"
+	"okx-market-sentry/internal/slo"
+	"okx-market-sentry/internal/storage"
+	"okx-market-sentry/pkg/types"
+)
+
+func TestKlineSpec(t *testing.T) {
+	tests := []struct {
+		period time.Duration
+		want   string
+	}{
+		{time.Minute, "* * * * *"},
+		{5 * time.Minute, "*/5 * * * *"},
+		{15 * time.Minute, "*/15 * * * *"},
+		{time.Hour, "0 * * * *"},
+	}
+	for _, tt := range tests {
+		if got := KlineSpec(tt.period); got != tt.want {
+			t.Errorf("KlineSpec(%s) = %q, want %q", tt.period, got, tt.want)
+		}
+	}
+}
+
+func TestHeartbeatIntervalFollowsSchedule(t *testing.T) {
+	schedule, err := cron.ParseStandard("*/15 * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Scheduler{schedule: schedule}
+
+	s.lastHeartbeat = time.Date(2024, 1, 1, 10, 5, 0, 0, time.Local)
+	if got := s.HeartbeatInterval(); got != 10*time.Minute {
+		t.Errorf("10:05 -> 10:15: got %s", got)
+	}
+	s.lastHeartbeat = time.Date(2024, 1, 1, 10, 15, 0, 0, time.Local)
+	if got := s.HeartbeatInterval(); got != 15*time.Minute {
+		t.Errorf("10:15 -> 10:30: got %s", got)
+	}
+}
+
+func TestCountMissed(t *testing.T) {
+	schedule, err := cron.ParseStandard("*/5 * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Scheduler{schedule: schedule}
+	at := func(minute int) time.Time { return time.Date(2024, 1, 1, 10, minute, 0, 0, time.Local) }
+
+	tests := []struct {
+		name      string
+		last      time.Time
+		scheduled time.Time
+		want      int
+	}{
+		{"首次执行", time.Time{}, at(5), 0},
+		{"相邻时间点", at(5), at(10), 0},
+		{"跳过两个时间点", at(5), at(20), 2},
+	}
+	for _, tt := range tests {
+		if got := s.countMissed(tt.last, tt.scheduled); got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalScheduleAligned(t *testing.T) {
+	schedule := intervalSchedule{interval: 30 * time.Second}
+	start := time.Date(2024, 1, 1, 10, 0, 12, 0, time.UTC)
+
+	want := []time.Time{
+		time.Date(2024, 1, 1, 10, 0, 30, 0, time.UTC),
+		time.Date(2024, 1, 1, 10, 1, 0, 0, time.UTC),
+	}
+	next := start
+	for _, w := range want {
+		next = schedule.Next(next)
+		if !next.Equal(w) {
+			t.Fatalf("got %s, want %s", next, w)
+		}
+	}
+}
+
+// fixedDigest 返回固定摘要的数据源
+type fixedDigest struct {
+	digest *types.Digest
+	window time.Duration
+	limit  int
+}
+
+func (f *fixedDigest) Digest(window time.Duration, limit int) *types.Digest {
+	f.window, f.limit = window, limit
+	return f.digest
+}
+
+// discardNotifier 丢弃所有通知
+type discardNotifier struct{ notifier.Interface }
+
+func (discardNotifier) SendAlert(*types.AlertData) error         { return nil }
+func (discardNotifier) SendBatchAlerts([]*types.AlertData) error { return nil }
+
+func TestTrigger(t *testing.T) {
+	stateManager := storage.NewStateManager(types.RedisConfig{}, time.Hour, time.Hour)
+	now := time.Now()
+	for symbol, change := range map[string]float64{"BTC-USDT": 2, "ETH-USDT": -3} {
+		stateManager.Store(symbol, 100, now.Add(-5*time.Minute))
+		stateManager.Store(symbol, 100*(1+change/100), now)
+	}
+	engine := analyzer.NewAnalysisEngine(stateManager, discardNotifier{},
+		types.AlertConfig{Threshold: 1, MonitorPeriod: 5 * time.Minute}, slo.NewTracker())
+	s := &Scheduler{analysisEngine: engine, stateManager: stateManager}
+
+	// 只分析指定交易对，不计入分析循环的心跳
+	alerts, err := s.Trigger(context.Background(), []string{"ETH-USDT"})
+	if err != nil || len(alerts) != 1 || alerts[0].Symbol != "ETH-USDT" {
+		t.Fatalf("Trigger(ETH-USDT) = %v, %v", alerts, err)
+	}
+	if !s.LastHeartbeat().IsZero() {
+		t.Error("分析部分交易对不应更新心跳")
+	}
+
+	// 分析全部交易对，ETH-USDT处于冷却期
+	alerts, err = s.Trigger(context.Background(), nil)
+	if err != nil || len(alerts) != 1 || alerts[0].Symbol != "BTC-USDT" {
+		t.Fatalf("Trigger(全部) = %v, %v", alerts, err)
+	}
+	if s.LastHeartbeat().IsZero() {
+		t.Error("分析全部交易对应更新心跳")
+	}
+
+	// 已有分析正在执行时拒绝
+	s.running.Store(true)
+	if _, err := s.Trigger(context.Background(), nil); !errors.Is(err, ErrAnalysisRunning) {
+		t.Errorf("err = %v, want ErrAnalysisRunning", err)
+	}
+}
+
+// digestRecorder 记录收到的热力图摘要
+type digestRecorder struct {
+	notifier.Interface
+	digests []*types.Digest
+}
+
+func (r *digestRecorder) SendDigest(digest *types.Digest) error {
+	r.digests = append(r.digests, digest)
+	return nil
+}
+
+func TestDigestJobSend(t *testing.T) {
+	source := &fixedDigest{digest: &types.Digest{}}
+	recorder := &digestRecorder{}
+	job := NewDigestJob(types.DigestConfig{Window: 4 * time.Hour, MaxSymbols: 100}, source, recorder)
+
+	// 暂无数据时跳过
+	job.send()
+	if len(recorder.digests) != 0 || source.window != 4*time.Hour || source.limit != 100 {
+		t.Fatalf("digests = %d, window = %s, limit = %d", len(recorder.digests), source.window, source.limit)
+	}
+
+	source.digest = &types.Digest{Total: 1, Cells: []types.DigestCell{{Symbol: "SOL-USDT", ChangePercent: 3.67}}}
+	job.send()
+	if len(recorder.digests) != 1 || recorder.digests[0] != source.digest {
+		t.Errorf("digests = %v", recorder.digests)
+	}
+}
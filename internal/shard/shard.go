@@ -0,0 +1,194 @@
+package shard
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+	"okx-market-sentry/pkg/logger"
+	"okx-market-sentry/pkg/types"
+)
+
+// log 模块日志器，级别可通过 log.modules.shard 单独配置
+func log() *zap.Logger {
+	return logger.Named("shard")
+}
+
+// 分片模式，shard.mode 中配置
+const (
+	ModeStatic = "static" // 按实例序号和实例总数取模分配
+	ModeRedis  = "redis"  // 实例在Redis中登记，按一致性哈希分配
+)
+
+// membersKey Redis中登记实例的有序集合，分数为最近一次心跳的Unix时间
+const membersKey = "okx:shard:members"
+
+// hash 交易对和虚拟节点的哈希值，各实例计算结果一致
+func hash(key string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return h.Sum32()
+}
+
+// Static 按实例序号分片：交易对哈希值对实例总数取模等于本实例序号时由本实例负责
+// 实例数固定时分配最均匀，增减实例需同时修改所有实例的配置
+type Static struct {
+	index int
+	count int
+}
+
+func NewStatic(index, count int) *Static {
+	log().Info("🧩 已启用静态分片", zap.Int("index", index), zap.Int("count", count))
+	return &Static{index: index, count: count}
+}
+
+func (s *Static) Owns(symbol string) bool {
+	return int(hash(symbol)%uint32(s.count)) == s.index
+}
+
+// IndexFromHostname 从主机名末尾的序号推断实例序号，如 StatefulSet 的 okx-sentry-2 为 2
+func IndexFromHostname() (int, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return 0, err
+	}
+	suffix := hostname[strings.LastIndex(hostname, "-")+1:]
+	index, err := strconv.Atoi(suffix)
+	if err != nil {
+		return 0, fmt.Errorf("主机名 %q 不以序号结尾", hostname)
+	}
+	return index, nil
+}
+
+// Ring 一致性哈希分片：各实例定期在Redis中登记心跳，按存活实例构建哈希环
+// 实例加入或下线时只有环上相邻区间的交易对改变归属，新接手的交易对需重新积累监控窗口数据
+type Ring struct {
+	client    *redis.Client
+	id        string
+	heartbeat time.Duration
+	vnodes    int
+
+	mutex   sync.RWMutex
+	points  []uint32          // 哈希环上的虚拟节点，升序
+	owners  map[uint32]string // 虚拟节点 -> 实例标识
+	members []string          // 当前存活的实例，升序
+}
+
+// NewRing 登记本实例并读取当前成员，Redis暂不可用时先负责全部交易对，恢复后按成员重新分配
+func NewRing(client *redis.Client, config types.ShardConfig) *Ring {
+	id := config.InstanceID
+	if id == "" {
+		hostname, _ := os.Hostname()
+		id = fmt.Sprintf("%s:%d", hostname, os.Getpid())
+	}
+	r := &Ring{
+		client:    client,
+		id:        id,
+		heartbeat: config.Heartbeat,
+		vnodes:    config.VirtualNodes,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.refresh(ctx); err != nil {
+		log().Warn("⚠️ 登记分片成员失败，暂时负责全部交易对", zap.String("instance_id", id), zap.Error(err))
+	}
+	return r
+}
+
+// Start 按心跳间隔续期登记并刷新成员，ctx取消时注销本实例，其他实例在下一次刷新时接手
+func (r *Ring) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			leaveCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			if err := r.client.ZRem(leaveCtx, membersKey, r.id).Err(); err != nil {
+				log().Warn("注销分片成员失败", zap.Error(err))
+			}
+			cancel()
+			log().Info("📴 已退出分片", zap.String("instance_id", r.id))
+			return
+		case <-ticker.C:
+			if err := r.refresh(ctx); err != nil {
+				log().Warn("⚠️ 刷新分片成员失败，沿用上次的分配", zap.Error(err))
+			}
+		}
+	}
+}
+
+// refresh 续期本实例的心跳，清理超过3个心跳间隔未更新的实例，成员变化时重建哈希环
+func (r *Ring) refresh(ctx context.Context) error {
+	now := time.Now()
+	pipe := r.client.TxPipeline()
+	pipe.ZAdd(ctx, membersKey, &redis.Z{Score: float64(now.Unix()), Member: r.id})
+	pipe.ZRemRangeByScore(ctx, membersKey, "-inf", strconv.FormatInt(now.Add(-3*r.heartbeat).Unix(), 10))
+	members := pipe.ZRange(ctx, membersKey, 0, -1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	r.setMembers(members.Val())
+	return nil
+}
+
+// setMembers 成员变化时重建哈希环
+func (r *Ring) setMembers(members []string) {
+	sort.Strings(members)
+	r.mutex.RLock()
+	unchanged := slices.Equal(members, r.members)
+	r.mutex.RUnlock()
+	if unchanged {
+		return
+	}
+
+	points := make([]uint32, 0, len(members)*r.vnodes)
+	owners := make(map[uint32]string, len(members)*r.vnodes)
+	for _, member := range members {
+		for i := 0; i < r.vnodes; i++ {
+			point := hash(member + "#" + strconv.Itoa(i))
+			points = append(points, point)
+			owners[point] = member
+		}
+	}
+	slices.Sort(points)
+
+	r.mutex.Lock()
+	r.points, r.owners, r.members = points, owners, members
+	r.mutex.Unlock()
+	log().Info("🧩 分片成员已更新", zap.String("instance_id", r.id), zap.Strings("members", members))
+}
+
+func (r *Ring) Owns(symbol string) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if len(r.points) == 0 {
+		return true
+	}
+
+	// 顺时针找到第一个不小于交易对哈希值的虚拟节点，超过末尾时回到环首
+	h := hash(symbol)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[r.points[i]] == r.id
+}
+
+// Members 当前存活的实例
+func (r *Ring) Members() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return slices.Clone(r.members)
+}
@@ -0,0 +1,100 @@
+package shard
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func testSymbols(n int) []string {
+	symbols := make([]string, n)
+	for i := range symbols {
+		symbols[i] = fmt.Sprintf("COIN%d-USDT", i)
+	}
+	return symbols
+}
+
+// testRing 不连接Redis，直接设置成员
+func testRing(id string, members ...string) *Ring {
+	r := &Ring{id: id, vnodes: 128}
+	r.setMembers(members)
+	return r
+}
+
+func TestStaticOwnsEachSymbolOnce(t *testing.T) {
+	const count = 3
+	shards := make([]*Static, count)
+	for i := range shards {
+		shards[i] = NewStatic(i, count)
+	}
+
+	owned := make([]int, count)
+	for _, symbol := range testSymbols(900) {
+		owners := 0
+		for i, s := range shards {
+			if s.Owns(symbol) {
+				owners++
+				owned[i]++
+			}
+		}
+		if owners != 1 {
+			t.Fatalf("%s 属于 %d 个分片，期望恰好1个", symbol, owners)
+		}
+	}
+	for i, n := range owned {
+		if n < 200 {
+			t.Errorf("分片 %d 只分到 %d 个交易对，分配不均", i, n)
+		}
+	}
+}
+
+func TestRingOwnsEachSymbolOnce(t *testing.T) {
+	members := []string{"a", "b", "c"}
+	rings := make([]*Ring, len(members))
+	for i, id := range members {
+		// 各实例读取到的成员顺序不同，分配结果也应一致
+		rotated := append(slices.Clone(members[i:]), members[:i]...)
+		rings[i] = testRing(id, rotated...)
+	}
+
+	owned := make([]int, len(rings))
+	for _, symbol := range testSymbols(900) {
+		owners := 0
+		for i, r := range rings {
+			if r.Owns(symbol) {
+				owners++
+				owned[i]++
+			}
+		}
+		if owners != 1 {
+			t.Fatalf("%s 属于 %d 个实例，期望恰好1个", symbol, owners)
+		}
+	}
+	for i, n := range owned {
+		if n < 150 {
+			t.Errorf("实例 %s 只分到 %d 个交易对，分配不均", members[i], n)
+		}
+	}
+}
+
+func TestRingMemberLeaveKeepsOtherAssignments(t *testing.T) {
+	before := testRing("a", "a", "b", "c")
+	after := testRing("a", "a", "b")
+
+	for _, symbol := range testSymbols(500) {
+		// 实例c下线后，原本属于a的交易对仍属于a
+		if before.Owns(symbol) && !after.Owns(symbol) {
+			t.Fatalf("%s 在其他实例下线后不再属于a", symbol)
+		}
+	}
+}
+
+func TestRingWithoutMembersOwnsAll(t *testing.T) {
+	r := &Ring{id: "a", vnodes: 128}
+	if !r.Owns("BTC-USDT") {
+		t.Fatal("未读取到成员时应负责全部交易对")
+	}
+	if members := r.Members(); len(members) != 0 {
+		t.Fatalf("members = %v, 期望为空", members)
+	}
+}
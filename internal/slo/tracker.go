@@ -0,0 +1,176 @@
+package slo
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"okx-market-sentry/pkg/logger"
+	"okx-market-sentry/pkg/types"
+)
+
+// log 模块日志器，级别可通过 log.modules.slo 单独配置
+func log() *zap.Logger {
+	return logger.Named("slo")
+}
+
+const (
+	retentionDays    = 7     // 保留的天数
+	maxSamplesPerDay = 10000 // 每天最多保留的延迟样本数
+	dayLayout        = "2006-01-02"
+)
+
+// dayStats 单日统计
+type dayStats struct {
+	latencies      []time.Duration
+	fetchAttempts  int
+	fetchSuccesses int
+}
+
+// Tracker 跟踪通知时效（行情时间→通知成功）和行情源可用率
+type Tracker struct {
+	days  map[string]*dayStats
+	mutex sync.Mutex
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{
+		days: make(map[string]*dayStats),
+	}
+}
+
+// RecordNotifyLatency 记录一次通知成功的端到端延迟
+func (t *Tracker) RecordNotifyLatency(latency time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	day := t.today()
+	if len(day.latencies) < maxSamplesPerDay {
+		day.latencies = append(day.latencies, latency)
+	}
+}
+
+// RecordFetch 记录一次行情获取结果
+func (t *Tracker) RecordFetch(success bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	day := t.today()
+	day.fetchAttempts++
+	if success {
+		day.fetchSuccesses++
+	}
+}
+
+// GetReport 获取按日期倒序的每日SLO统计
+func (t *Tracker) GetReport() []types.DailySLO {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	dates := make([]string, 0, len(t.days))
+	for date := range t.days {
+		dates = append(dates, date)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(dates)))
+
+	report := make([]types.DailySLO, 0, len(dates))
+	for _, date := range dates {
+		report = append(report, buildDailySLO(date, t.days[date]))
+	}
+	return report
+}
+
+// Start 按cron表达式输出前一天的SLO报告，如 1 0 * * * 表示每天00:01
+func (t *Tracker) Start(ctx context.Context, reportSpec string) {
+	c := cron.New(cron.WithLocation(time.Local))
+	if _, err := c.AddFunc(reportSpec, func() {
+		t.logDailyReport(time.Now().AddDate(0, 0, -1).Format(dayLayout))
+	}); err != nil {
+		log().Error("❌ SLO报告cron表达式无效，不输出每日报告", zap.String("spec", reportSpec), zap.Error(err))
+		return
+	}
+	c.Start()
+
+	<-ctx.Done()
+	<-c.Stop().Done()
+}
+
+func (t *Tracker) logDailyReport(date string) {
+	t.mutex.Lock()
+	day, ok := t.days[date]
+	var slo types.DailySLO
+	if ok {
+		slo = buildDailySLO(date, day)
+	}
+	t.mutex.Unlock()
+
+	if !ok {
+		return
+	}
+
+	log().Info("📅 每日SLO报告",
+		zap.String("date", slo.Date),
+		zap.Int("notify_count", slo.NotifyCount),
+		zap.Duration("notify_p50", slo.NotifyP50),
+		zap.Duration("notify_p90", slo.NotifyP90),
+		zap.Duration("notify_p99", slo.NotifyP99),
+		zap.Float64("feed_uptime_percent", slo.FeedUptimePercent))
+}
+
+// today 获取当天的统计，并清理过期数据，调用方需持有锁
+func (t *Tracker) today() *dayStats {
+	date := time.Now().Format(dayLayout)
+	day, ok := t.days[date]
+	if !ok {
+		day = &dayStats{}
+		t.days[date] = day
+
+		cutoff := time.Now().AddDate(0, 0, -retentionDays).Format(dayLayout)
+		for d := range t.days {
+			if d < cutoff {
+				delete(t.days, d)
+			}
+		}
+	}
+	return day
+}
+
+func buildDailySLO(date string, day *dayStats) types.DailySLO {
+	slo := types.DailySLO{
+		Date:           date,
+		NotifyCount:    len(day.latencies),
+		FetchAttempts:  day.fetchAttempts,
+		FetchSuccesses: day.fetchSuccesses,
+	}
+
+	if len(day.latencies) > 0 {
+		sorted := make([]time.Duration, len(day.latencies))
+		copy(sorted, day.latencies)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		slo.NotifyP50 = percentile(sorted, 0.50)
+		slo.NotifyP90 = percentile(sorted, 0.90)
+		slo.NotifyP99 = percentile(sorted, 0.99)
+		slo.NotifyMax = sorted[len(sorted)-1]
+	}
+
+	if day.fetchAttempts > 0 {
+		slo.FeedUptimePercent = float64(day.fetchSuccesses) / float64(day.fetchAttempts) * 100
+	}
+	return slo
+}
+
+// percentile 最近秩法计算分位数，sorted须已升序
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	idx := int(float64(len(sorted))*p+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
@@ -0,0 +1,50 @@
+package slo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	tests := []struct {
+		name   string
+		values []time.Duration
+		p      float64
+		want   time.Duration
+	}{
+		{"p50", sorted, 0.50, 50 * time.Millisecond},
+		{"p90", sorted, 0.90, 90 * time.Millisecond},
+		{"p99", sorted, 0.99, 99 * time.Millisecond},
+		{"p100", sorted, 1, 100 * time.Millisecond},
+		{"p0取最小值", sorted, 0, 1 * time.Millisecond},
+		{"单个样本", []time.Duration{time.Second}, 0.99, time.Second},
+		{"两个样本p50", []time.Duration{time.Second, 3 * time.Second}, 0.50, time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentile(tt.values, tt.p); got != tt.want {
+				t.Errorf("percentile(%v) = %v, want %v", tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildDailySLO(t *testing.T) {
+	slo := buildDailySLO("2026-01-01", &dayStats{
+		latencies:      []time.Duration{3 * time.Second, time.Second, 2 * time.Second},
+		fetchAttempts:  4,
+		fetchSuccesses: 3,
+	})
+
+	if slo.NotifyCount != 3 || slo.NotifyP50 != 2*time.Second || slo.NotifyMax != 3*time.Second {
+		t.Errorf("通知延迟统计错误: %+v", slo)
+	}
+	if slo.FeedUptimePercent != 75 {
+		t.Errorf("FeedUptimePercent = %v, want 75", slo.FeedUptimePercent)
+	}
+}
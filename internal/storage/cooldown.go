@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+// cooldownKeyPrefix 预警冷却在Redis中的key前缀，完整key为 okx:cooldown:配置组:交易对，值为预警时间的Unix毫秒
+const cooldownKeyPrefix = "okx:cooldown:"
+
+func cooldownKey(name, symbol string) string {
+	return cooldownKeyPrefix + name + ":" + symbol
+}
+
+// parseCooldownKey 从key中解析配置组和交易对，交易对不含冒号，配置组名称可以包含
+func parseCooldownKey(key string) (name, symbol string, ok bool) {
+	rest, ok := strings.CutPrefix(key, cooldownKeyPrefix)
+	if !ok {
+		return "", "", false
+	}
+	i := strings.LastIndex(rest, ":")
+	if i <= 0 || i == len(rest)-1 {
+		return "", "", false
+	}
+	return rest[:i], rest[i+1:], true
+}
+
+// ClaimCooldown 在Redis中占用配置组对交易对的预警冷却，key在冷却期后自动过期
+// 冷却期内其他实例或重启后的本实例无法再次占用，此时返回false和占用时记录的预警时间
+// 未启用Redis或Redis出错时返回true，宁可重复预警也不漏发
+func (sm *StateManager) ClaimCooldown(name, symbol string, at time.Time, cooldown time.Duration) (bool, time.Time) {
+	if !sm.useRedis || cooldown <= 0 {
+		return true, time.Time{}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	key := cooldownKey(name, symbol)
+	claimed, err := sm.redisClient.SetNX(ctx, key, at.UnixMilli(), cooldown).Result()
+	if err != nil {
+		log().Warn("占用预警冷却失败，按本地冷却判断",
+			zap.String("profile", name),
+			zap.String("symbol", symbol),
+			zap.Error(err))
+		return true, time.Time{}
+	}
+	if claimed {
+		return true, time.Time{}
+	}
+
+	// 已被占用，读取占用时的预警时间，key恰好过期或无法解析时按当前时间计算
+	last := at
+	if value, err := sm.redisClient.Get(ctx, key).Int64(); err == nil {
+		last = time.UnixMilli(value)
+	}
+	return false, last
+}
+
+// PeekCooldown 查询Redis中配置组对交易对的预警冷却是否仍在生效，只读取不占用
+// 生效时返回占用时记录的预警时间，供状态查询和分析时提前跳过；未启用Redis、key已过期或Redis出错时返回false
+func (sm *StateManager) PeekCooldown(name, symbol string) (bool, time.Time) {
+	if !sm.useRedis {
+		return false, time.Time{}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	value, err := sm.redisClient.Get(ctx, cooldownKey(name, symbol)).Int64()
+	if err != nil {
+		if err != redis.Nil {
+			log().Warn("查询预警冷却失败，按本地冷却判断",
+				zap.String("profile", name),
+				zap.String("symbol", symbol),
+				zap.Error(err))
+		}
+		return false, time.Time{}
+	}
+	return true, time.UnixMilli(value)
+}
+
+// ReleaseCooldown 释放本实例在at时刻占用的预警冷却，用于预警发送失败后让其他实例或下次重启可以重新发送
+// 只删除值仍为at的key，不会误删冷却期已过后其他实例重新占用的冷却
+func (sm *StateManager) ReleaseCooldown(name, symbol string, at time.Time) {
+	if !sm.useRedis {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	key := cooldownKey(name, symbol)
+	value, err := sm.redisClient.Get(ctx, key).Int64()
+	if err == nil && value == at.UnixMilli() {
+		err = sm.redisClient.Del(ctx, key).Err()
+	}
+	if err != nil && err != redis.Nil {
+		log().Warn("释放预警冷却失败",
+			zap.String("profile", name),
+			zap.String("symbol", symbol),
+			zap.Error(err))
+	}
+}
+
+// LoadCooldowns 读取Redis中尚未过期的预警冷却（配置组 -> 交易对 -> 预警时间），启动时恢复预警历史
+func (sm *StateManager) LoadCooldowns() map[string]map[string]time.Time {
+	history := make(map[string]map[string]time.Time)
+	if !sm.useRedis {
+		return history
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var keys []string
+	iter := sm.redisClient.Scan(ctx, 0, cooldownKeyPrefix+"*", 1000).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		log().Warn("读取预警冷却失败", zap.Error(err))
+		return history
+	}
+	if len(keys) == 0 {
+		return history
+	}
+
+	values, err := sm.redisClient.MGet(ctx, keys...).Result()
+	if err != nil && err != redis.Nil {
+		log().Warn("读取预警冷却失败", zap.Error(err))
+		return history
+	}
+
+	restored := 0
+	for i, key := range keys {
+		name, symbol, ok := parseCooldownKey(key)
+		value, isString := values[i].(string)
+		if !ok || !isString {
+			continue
+		}
+		millis, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		if history[name] == nil {
+			history[name] = make(map[string]time.Time)
+		}
+		history[name][symbol] = time.UnixMilli(millis)
+		restored++
+	}
+	log().Info("♻️ 已从Redis恢复预警冷却", zap.Int("count", restored))
+	return history
+}
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"okx-market-sentry/internal/storage/redistest"
+	"okx-market-sentry/pkg/types"
+)
+
+func TestClaimCooldown(t *testing.T) {
+	server := redistest.NewServer(t)
+	first, second := newRedisStateManager(t, server), newRedisStateManager(t, server)
+	at := time.Now()
+
+	if claimed, _ := first.ClaimCooldown("majors", "BTC-USDT", at, time.Minute); !claimed {
+		t.Fatal("首次占用失败")
+	}
+	claimed, last := second.ClaimCooldown("majors", "BTC-USDT", at.Add(time.Second), time.Minute)
+	if claimed || last.UnixMilli() != at.UnixMilli() {
+		t.Fatalf("重复占用: claimed=%v last=%v, want false %v", claimed, last, at)
+	}
+	// 各配置组的冷却互不影响
+	if claimed, _ := second.ClaimCooldown("alts", "BTC-USDT", at, time.Minute); !claimed {
+		t.Error("其他配置组占用失败")
+	}
+
+	// 多个实例同时占用，只有一个成功
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	wins := 0
+	for i := 0; i < 8; i++ {
+		sm := first
+		if i%2 == 1 {
+			sm = second
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if claimed, _ := sm.ClaimCooldown("majors", "ETH-USDT", at, time.Minute); claimed {
+				mutex.Lock()
+				wins++
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if wins != 1 {
+		t.Errorf("%d次占用成功, want 1", wins)
+	}
+
+	// 查询不占用
+	if active, last := second.PeekCooldown("majors", "BTC-USDT"); !active || last.UnixMilli() != at.UnixMilli() {
+		t.Errorf("PeekCooldown = %v %v, want true %v", active, last, at)
+	}
+	if active, _ := second.PeekCooldown("majors", "SOL-USDT"); active || server.Exists(cooldownKey("majors", "SOL-USDT")) {
+		t.Error("查询未占用的冷却时不应写入")
+	}
+
+	// 冷却期后key过期，可以再次占用
+	server.FastForward(time.Minute + time.Millisecond)
+	if active, _ := second.PeekCooldown("majors", "BTC-USDT"); active {
+		t.Error("冷却期后仍处于冷却中")
+	}
+	if claimed, _ := second.ClaimCooldown("majors", "BTC-USDT", at.Add(time.Minute), time.Minute); !claimed {
+		t.Error("冷却期后占用失败")
+	}
+}
+
+func TestReleaseCooldown(t *testing.T) {
+	server := redistest.NewServer(t)
+	sm := newRedisStateManager(t, server)
+	at := time.Now()
+	key := cooldownKey("majors", "BTC-USDT")
+
+	sm.ClaimCooldown("majors", "BTC-USDT", at, time.Minute)
+	// 只释放自己在at时刻占用的冷却
+	sm.ReleaseCooldown("majors", "BTC-USDT", at.Add(-time.Minute))
+	if !server.Exists(key) {
+		t.Fatal("释放了其他时间占用的冷却")
+	}
+	sm.ReleaseCooldown("majors", "BTC-USDT", at)
+	if server.Exists(key) {
+		t.Fatal("释放后冷却仍存在")
+	}
+	if claimed, _ := sm.ClaimCooldown("majors", "BTC-USDT", at, time.Minute); !claimed {
+		t.Error("释放后占用失败")
+	}
+}
+
+func TestLoadCooldowns(t *testing.T) {
+	server := redistest.NewServer(t)
+	at := time.Now().Add(-30 * time.Second)
+	millis := strconv.FormatInt(at.UnixMilli(), 10)
+	server.Set(cooldownKey("majors", "BTC-USDT"), millis, time.Minute)
+	server.Set(cooldownKey("rule:pump", "ETH-USDT"), millis, time.Minute) // 配置组名称可以包含冒号
+	server.Set(cooldownKey("majors", "SOL-USDT"), millis, time.Second)    // 恢复前过期
+	server.Set(cooldownKey("majors", "DOGE-USDT"), "invalid", time.Minute)
+	server.Set("okx:cooldown:invalid", millis, time.Minute)
+	server.FastForward(2 * time.Second)
+
+	history := newRedisStateManager(t, server).LoadCooldowns()
+	if len(history) != 2 || len(history["majors"]) != 1 || len(history["rule:pump"]) != 1 {
+		t.Fatalf("history = %v", history)
+	}
+	if got := history["majors"]["BTC-USDT"]; got.UnixMilli() != at.UnixMilli() {
+		t.Errorf("majors/BTC-USDT = %v, want %v", got, at)
+	}
+	if _, ok := history["rule:pump"]["ETH-USDT"]; !ok {
+		t.Errorf("rule:pump 未恢复: %v", history)
+	}
+
+	// 未启用Redis时没有共享冷却
+	memory := NewStateManager(types.RedisConfig{}, time.Hour, time.Hour)
+	if len(memory.LoadCooldowns()) != 0 {
+		t.Error("未启用Redis时恢复了冷却")
+	}
+	if claimed, _ := memory.ClaimCooldown("majors", "BTC-USDT", at, time.Minute); !claimed {
+		t.Error("未启用Redis时应总是可以占用")
+	}
+}
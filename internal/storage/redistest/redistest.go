@@ -0,0 +1,627 @@
+// Package redistest 提供测试用的内存Redis服务端，只实现本项目用到的命令子集
+// 与 net/http/httptest 类似，在本地端口上监听，业务代码通过真实的go-redis客户端访问
+package redistest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// entry Redis中的一个key，按类型只使用其中一个字段
+type entry struct {
+	str      *string
+	zset     map[string]float64
+	list     []string
+	expireAt time.Time // 零值表示不过期
+}
+
+// Server 内存Redis服务端，支持字符串、有序集合、列表的常用命令以及MULTI/EXEC
+type Server struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	data     map[string]*entry
+	offset   time.Duration // FastForward累计的时间偏移，用于模拟key过期
+	commands map[string]int
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+}
+
+// NewServer 启动服务端，测试结束时自动关闭
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("启动测试Redis失败: %v", err)
+	}
+	s := &Server{
+		listener: listener,
+		data:     make(map[string]*entry),
+		commands: make(map[string]int),
+		conns:    make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Addr 监听地址，可直接作为 redis.url 配置
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close 关闭监听和所有连接
+func (s *Server) Close() {
+	s.listener.Close()
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// FastForward 将服务端时钟前移d，到期的key随之失效
+func (s *Server) FastForward(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offset += d
+}
+
+// Set 直接写入字符串值，ttl大于0时设置过期时间，用于模拟其他实例写入的数据
+func (s *Server) Set(key, value string, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := &entry{str: &value}
+	if ttl > 0 {
+		e.expireAt = s.now().Add(ttl)
+	}
+	s.data[key] = e
+}
+
+// Get 读取字符串值，key不存在或已过期时ok为false
+func (s *Server) Get(key string) (value string, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.lookup(key)
+	if e == nil || e.str == nil {
+		return "", false
+	}
+	return *e.str, true
+}
+
+// Exists key是否存在且未过期
+func (s *Server) Exists(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lookup(key) != nil
+}
+
+// CommandCount 服务端收到的某个命令（不区分大小写）的次数
+func (s *Server) CommandCount(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.commands[strings.ToUpper(name)]
+}
+
+func (s *Server) now() time.Time {
+	return time.Now().Add(s.offset)
+}
+
+// lookup 读取key，顺带删除已过期的key，调用方需持有锁
+func (s *Server) lookup(key string) *entry {
+	e := s.data[key]
+	if e == nil {
+		return nil
+	}
+	if !e.expireAt.IsZero() && !s.now().Before(e.expireAt) {
+		delete(s.data, key)
+		return nil
+	}
+	return e
+}
+
+// sortedKeys 所有未过期的key，按字典序排列，SCAN按此顺序分页
+func (s *Server) sortedKeys() []string {
+	keys := make([]string, 0, len(s.data))
+	for key := range s.data {
+		if s.lookup(key) != nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	reader := bufio.NewReader(conn)
+	writer := bufio.NewWriter(conn)
+	var queued [][]string // MULTI之后排队的命令，为nil表示不在事务中
+	inMulti := false
+
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		name := strings.ToUpper(args[0])
+		switch {
+		case name == "MULTI":
+			inMulti, queued = true, nil
+			writer.WriteString("+OK\r\n")
+		case name == "DISCARD":
+			inMulti, queued = false, nil
+			writer.WriteString("+OK\r\n")
+		case name == "EXEC":
+			fmt.Fprintf(writer, "*%d\r\n", len(queued))
+			for _, cmd := range queued {
+				writer.WriteString(s.execute(cmd))
+			}
+			inMulti, queued = false, nil
+		case inMulti:
+			queued = append(queued, args)
+			writer.WriteString("+QUEUED\r\n")
+		default:
+			writer.WriteString(s.execute(args))
+		}
+
+		// 管道中的命令全部读完后再统一写回
+		if reader.Buffered() == 0 {
+			if err := writer.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readCommand 读取一条RESP数组形式的命令
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return strings.Fields(line), nil // inline命令
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		header, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(header, "$") {
+			return nil, errors.New("期望bulk string")
+		}
+		size, err := strconv.Atoi(header[1:])
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args = append(args, string(buf[:size]))
+	}
+	return args, nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// ---------- RESP回复 ----------
+
+func simple(s string) string     { return "+" + s + "\r\n" }
+func errorReply(s string) string { return "-" + s + "\r\n" }
+func integer(n int) string       { return ":" + strconv.Itoa(n) + "\r\n" }
+func nilBulk() string            { return "$-1\r\n" }
+
+func bulk(s string) string {
+	return "$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n"
+}
+
+func array(items []string) string {
+	var b strings.Builder
+	b.WriteString("*" + strconv.Itoa(len(items)) + "\r\n")
+	for _, item := range items {
+		b.WriteString(bulk(item))
+	}
+	return b.String()
+}
+
+const (
+	errSyntax    = "ERR syntax error"
+	errWrongType = "WRONGTYPE Operation against a key holding the wrong kind of value"
+	errNotInt    = "ERR value is not an integer or out of range"
+	errNotFloat  = "ERR min or max is not a float"
+)
+
+// execute 执行一条命令并返回RESP编码的回复
+func (s *Server) execute(args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := strings.ToUpper(args[0])
+	s.commands[name]++
+	args = args[1:]
+
+	switch name {
+	case "PING":
+		return simple("PONG")
+	case "GET":
+		if len(args) != 1 {
+			return errorReply(errSyntax)
+		}
+		e := s.lookup(args[0])
+		if e == nil {
+			return nilBulk()
+		}
+		if e.str == nil {
+			return errorReply(errWrongType)
+		}
+		return bulk(*e.str)
+	case "SET":
+		return s.set(args)
+	case "DEL":
+		deleted := 0
+		for _, key := range args {
+			if s.lookup(key) != nil {
+				delete(s.data, key)
+				deleted++
+			}
+		}
+		return integer(deleted)
+	case "EXPIRE", "PEXPIRE":
+		if len(args) != 2 {
+			return errorReply(errSyntax)
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil {
+			return errorReply(errNotInt)
+		}
+		e := s.lookup(args[0])
+		if e == nil {
+			return integer(0)
+		}
+		unit := time.Second
+		if name == "PEXPIRE" {
+			unit = time.Millisecond
+		}
+		e.expireAt = s.now().Add(time.Duration(n) * unit)
+		return integer(1)
+	case "TTL", "PTTL":
+		if len(args) != 1 {
+			return errorReply(errSyntax)
+		}
+		e := s.lookup(args[0])
+		switch {
+		case e == nil:
+			return integer(-2)
+		case e.expireAt.IsZero():
+			return integer(-1)
+		}
+		remaining := e.expireAt.Sub(s.now())
+		if name == "TTL" {
+			return integer(int((remaining + time.Second - 1) / time.Second))
+		}
+		return integer(int((remaining + time.Millisecond - 1) / time.Millisecond))
+	case "MGET":
+		var b strings.Builder
+		b.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+		for _, key := range args {
+			if e := s.lookup(key); e != nil && e.str != nil {
+				b.WriteString(bulk(*e.str))
+			} else {
+				b.WriteString(nilBulk())
+			}
+		}
+		return b.String()
+	case "KEYS":
+		if len(args) != 1 {
+			return errorReply(errSyntax)
+		}
+		return array(matchKeys(s.sortedKeys(), args[0]))
+	case "DBSIZE":
+		return integer(len(s.sortedKeys()))
+	case "SCAN":
+		return s.scan(args)
+	case "ZADD":
+		return s.zadd(args)
+	case "ZRANGEBYSCORE":
+		return s.zrangeByScore(args, false)
+	case "ZREMRANGEBYSCORE":
+		return s.zrangeByScore(args, true)
+	case "RPUSH":
+		if len(args) < 2 {
+			return errorReply(errSyntax)
+		}
+		e, reply := s.listEntry(args[0])
+		if e == nil {
+			return reply
+		}
+		e.list = append(e.list, args[1:]...)
+		return integer(len(e.list))
+	case "LRANGE", "LTRIM":
+		if len(args) != 3 {
+			return errorReply(errSyntax)
+		}
+		start, err1 := strconv.Atoi(args[1])
+		stop, err2 := strconv.Atoi(args[2])
+		if err1 != nil || err2 != nil {
+			return errorReply(errNotInt)
+		}
+		e := s.lookup(args[0])
+		if e == nil {
+			if name == "LTRIM" {
+				return simple("OK")
+			}
+			return array(nil)
+		}
+		if e.list == nil {
+			return errorReply(errWrongType)
+		}
+		lo, hi := listRange(len(e.list), start, stop)
+		if name == "LTRIM" {
+			e.list = append([]string{}, e.list[lo:hi]...)
+			return simple("OK")
+		}
+		return array(e.list[lo:hi])
+	default:
+		return errorReply("ERR unknown command '" + strings.ToLower(name) + "'")
+	}
+}
+
+// set SET key value [EX seconds|PX milliseconds] [NX|XX]
+func (s *Server) set(args []string) string {
+	if len(args) < 2 {
+		return errorReply(errSyntax)
+	}
+	key, value := args[0], args[1]
+	var ttl time.Duration
+	var nx, xx bool
+	for i := 2; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "NX":
+			nx = true
+		case "XX":
+			xx = true
+		case "EX", "PX":
+			if i+1 >= len(args) {
+				return errorReply(errSyntax)
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n <= 0 {
+				return errorReply(errNotInt)
+			}
+			unit := time.Second
+			if strings.ToUpper(args[i]) == "PX" {
+				unit = time.Millisecond
+			}
+			ttl = time.Duration(n) * unit
+			i++
+		case "KEEPTTL":
+		default:
+			return errorReply(errSyntax)
+		}
+	}
+
+	exists := s.lookup(key) != nil
+	if (nx && exists) || (xx && !exists) {
+		return nilBulk()
+	}
+	e := &entry{str: &value}
+	if ttl > 0 {
+		e.expireAt = s.now().Add(ttl)
+	}
+	s.data[key] = e
+	return simple("OK")
+}
+
+// scan SCAN cursor [MATCH pattern] [COUNT count]，游标为按字典序排列的key的下标
+func (s *Server) scan(args []string) string {
+	if len(args) < 1 {
+		return errorReply(errSyntax)
+	}
+	cursor, err := strconv.Atoi(args[0])
+	if err != nil {
+		return errorReply("ERR invalid cursor")
+	}
+	pattern, count := "*", 10
+	for i := 1; i+1 < len(args); i += 2 {
+		switch strings.ToUpper(args[i]) {
+		case "MATCH":
+			pattern = args[i+1]
+		case "COUNT":
+			if count, err = strconv.Atoi(args[i+1]); err != nil || count <= 0 {
+				return errorReply(errSyntax)
+			}
+		default:
+			return errorReply(errSyntax)
+		}
+	}
+
+	keys := s.sortedKeys()
+	if cursor > len(keys) {
+		cursor = len(keys)
+	}
+	end := min(cursor+count, len(keys))
+	next := end
+	if end == len(keys) {
+		next = 0
+	}
+	return "*2\r\n" + bulk(strconv.Itoa(next)) + array(matchKeys(keys[cursor:end], pattern))
+}
+
+func matchKeys(keys []string, pattern string) []string {
+	var matched []string
+	for _, key := range keys {
+		if ok, _ := path.Match(pattern, key); ok {
+			matched = append(matched, key)
+		}
+	}
+	return matched
+}
+
+// zadd ZADD key score member [score member ...]
+func (s *Server) zadd(args []string) string {
+	if len(args) < 3 || len(args)%2 != 1 {
+		return errorReply(errSyntax)
+	}
+	e := s.lookup(args[0])
+	if e == nil {
+		e = &entry{zset: make(map[string]float64)}
+		s.data[args[0]] = e
+	} else if e.zset == nil {
+		return errorReply(errWrongType)
+	}
+
+	added := 0
+	for i := 1; i < len(args); i += 2 {
+		score, err := strconv.ParseFloat(args[i], 64)
+		if err != nil {
+			return errorReply("ERR value is not a valid float")
+		}
+		if _, ok := e.zset[args[i+1]]; !ok {
+			added++
+		}
+		e.zset[args[i+1]] = score
+	}
+	return integer(added)
+}
+
+// zrangeByScore ZRANGEBYSCORE / ZREMRANGEBYSCORE key min max
+func (s *Server) zrangeByScore(args []string, remove bool) string {
+	if len(args) != 3 {
+		return errorReply(errSyntax)
+	}
+	minScore, minExclusive, err1 := parseScore(args[1])
+	maxScore, maxExclusive, err2 := parseScore(args[2])
+	if err1 != nil || err2 != nil {
+		return errorReply(errNotFloat)
+	}
+
+	e := s.lookup(args[0])
+	if e == nil {
+		if remove {
+			return integer(0)
+		}
+		return array(nil)
+	}
+	if e.zset == nil {
+		return errorReply(errWrongType)
+	}
+
+	var members []string
+	for member, score := range e.zset {
+		if score < minScore || (minExclusive && score == minScore) ||
+			score > maxScore || (maxExclusive && score == maxScore) {
+			continue
+		}
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		a, b := e.zset[members[i]], e.zset[members[j]]
+		if a != b {
+			return a < b
+		}
+		return members[i] < members[j]
+	})
+
+	if remove {
+		for _, member := range members {
+			delete(e.zset, member)
+		}
+		if len(e.zset) == 0 {
+			delete(s.data, args[0])
+		}
+		return integer(len(members))
+	}
+	return array(members)
+}
+
+func parseScore(s string) (score float64, exclusive bool, err error) {
+	if rest, ok := strings.CutPrefix(s, "("); ok {
+		s, exclusive = rest, true
+	}
+	score, err = strconv.ParseFloat(s, 64) // 可解析 +inf / -inf
+	return score, exclusive, err
+}
+
+// listEntry 获取或创建列表，key已存在且不是列表时返回错误回复
+func (s *Server) listEntry(key string) (*entry, string) {
+	e := s.lookup(key)
+	if e == nil {
+		e = &entry{list: []string{}}
+		s.data[key] = e
+		return e, ""
+	}
+	if e.list == nil {
+		return nil, errorReply(errWrongType)
+	}
+	return e, ""
+}
+
+// listRange 将LRANGE/LTRIM的下标（支持负数）转换为切片范围
+func listRange(length, start, stop int) (int, int) {
+	if start < 0 {
+		start += length
+	}
+	if stop < 0 {
+		stop += length
+	}
+	start = max(start, 0)
+	stop = min(stop, length-1)
+	if start > stop {
+		return 0, 0
+	}
+	return start, stop + 1
+}
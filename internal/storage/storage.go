@@ -5,14 +5,22 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 	"go.uber.org/zap"
+	"okx-market-sentry/pkg/logger"
 	"okx-market-sentry/pkg/types"
 )
 
+// log 模块日志器，级别可通过 log.modules.storage 单独配置
+func log() *zap.Logger {
+	return logger.Named("storage")
+}
+
 // CircularQueue 循环队列实现滑动窗口
 type CircularQueue struct {
 	data   []types.PriceDataPoint
@@ -34,8 +42,8 @@ func (cq *CircularQueue) Add(point types.PriceDataPoint) {
 	// 添加新数据点
 	cq.data = append(cq.data, point)
 
-	// 清理超过maxAge的旧数据
-	cutoff := time.Now().Add(-cq.maxAge)
+	// 清理比新数据点早maxAge以上的旧数据，以数据点时间为准，回放历史行情时同样适用
+	cutoff := point.Timestamp.Add(-cq.maxAge)
 	newStart := 0
 	for i, p := range cq.data {
 		if p.Timestamp.After(cutoff) {
@@ -68,7 +76,7 @@ func (cq *CircularQueue) GetLatest() *types.PriceDataPoint {
 	return &cq.data[len(cq.data)-1]
 }
 
-func (cq *CircularQueue) FindPriceAroundTime(targetTime time.Time) *types.PriceDataPoint {
+func (cq *CircularQueue) FindPriceAroundTime(targetTime time.Time, tolerance time.Duration) *types.PriceDataPoint {
 	cq.mutex.RLock()
 	defer cq.mutex.RUnlock()
 
@@ -91,14 +99,28 @@ func (cq *CircularQueue) FindPriceAroundTime(targetTime time.Time) *types.PriceD
 		}
 	}
 
-	// 如果最接近的数据点与目标时间相差超过2分钟，认为数据不足
-	if minDiff > 2*time.Minute {
+	// 如果最接近的数据点与目标时间相差超过容差，认为数据不足
+	if minDiff > tolerance {
 		return nil
 	}
 
 	return closest
 }
 
+// GetSince 返回指定时间之后的数据点副本（按时间升序）
+func (cq *CircularQueue) GetSince(since time.Time) []types.PriceDataPoint {
+	cq.mutex.RLock()
+	defer cq.mutex.RUnlock()
+
+	result := make([]types.PriceDataPoint, 0, len(cq.data))
+	for _, p := range cq.data {
+		if !p.Timestamp.Before(since) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
 func (cq *CircularQueue) Length() int {
 	cq.mutex.RLock()
 	defer cq.mutex.RUnlock()
@@ -108,16 +130,30 @@ func (cq *CircularQueue) Length() int {
 // StateManager 状态管理器
 type StateManager struct {
 	priceHistory map[string]*CircularQueue
+	tickers24h   map[string]types.Ticker24h // 最近一次获取的24小时统计，仅保存在内存
+	volumes24h   map[string]*CircularQueue  // 24小时成交额的历史（Price字段为成交额），用于估算窗口成交额
 	mutex        sync.RWMutex
-	windowSize   time.Duration
+	retention    time.Duration    // 内存中保留的数据时长，不小于最长的监控周期
+	now          func() time.Time // 当前时间，回放历史行情时替换为回放时钟
 	redisClient  *redis.Client
 	useRedis     bool
+
+	pendingWrites sync.WaitGroup // 尚未完成的异步Redis写入，关闭时等待
+	pendingCount  atomic.Int64
 }
 
-func NewStateManager(redisConfig types.RedisConfig, monitorPeriod time.Duration) *StateManager {
+func NewStateManager(redisConfig types.RedisConfig, monitorPeriod, retention time.Duration) *StateManager {
+	// 保留时长至少覆盖一个监控周期
+	if retention < monitorPeriod {
+		retention = monitorPeriod
+	}
+
 	sm := &StateManager{
 		priceHistory: make(map[string]*CircularQueue),
-		windowSize:   monitorPeriod, // 使用配置的监控周期
+		tickers24h:   make(map[string]types.Ticker24h),
+		volumes24h:   make(map[string]*CircularQueue),
+		retention:    retention,
+		now:          time.Now,
 	}
 
 	// 尝试连接Redis
@@ -134,27 +170,33 @@ func NewStateManager(redisConfig types.RedisConfig, monitorPeriod time.Duration)
 
 		_, err := sm.redisClient.Ping(ctx).Result()
 		if err != nil {
-			zap.L().Warn("⚠️  Redis连接失败，使用纯内存模式", zap.Error(err))
+			log().Warn("⚠️  Redis连接失败，使用纯内存模式", zap.Error(err))
 			sm.useRedis = false
 		} else {
-			zap.L().Info("✅ Redis连接成功")
+			log().Info("✅ Redis连接成功")
 			sm.useRedis = true
+			sm.restoreFromRedis()
 		}
 	} else {
-		zap.L().Info("🔧 未配置Redis，使用纯内存模式")
+		log().Info("🔧 未配置Redis，使用纯内存模式")
 		sm.useRedis = false
 	}
 
 	return sm
 }
 
+// SetClock 设置查询价格窗口时使用的当前时间，用于回放历史行情，需在写入数据前调用
+func (sm *StateManager) SetClock(now func() time.Time) {
+	sm.now = now
+}
+
 func (sm *StateManager) Store(symbol string, price float64, timestamp time.Time) {
 	sm.mutex.Lock()
 	defer sm.mutex.Unlock()
 
 	// 获取或创建队列
 	if sm.priceHistory[symbol] == nil {
-		sm.priceHistory[symbol] = NewCircularQueue(sm.windowSize)
+		sm.priceHistory[symbol] = NewCircularQueue(sm.retention)
 	}
 
 	// 添加新数据点
@@ -166,10 +208,87 @@ func (sm *StateManager) Store(symbol string, price float64, timestamp time.Time)
 
 	// 异步备份到Redis
 	if sm.useRedis {
-		go sm.backupToRedis(symbol, dataPoint)
+		sm.goWrite(func() { sm.backupToRedis(symbol, dataPoint) })
+	}
+}
+
+// StoreTicker24h 保存交易对最近一次获取的24小时统计，并记录24小时成交额的历史
+func (sm *StateManager) StoreTicker24h(symbol string, stats types.Ticker24h, timestamp time.Time) {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+	sm.tickers24h[symbol] = stats
+
+	if sm.volumes24h[symbol] == nil {
+		sm.volumes24h[symbol] = NewCircularQueue(sm.retention)
+	}
+	sm.volumes24h[symbol].Add(types.PriceDataPoint{Price: stats.QuoteVolume, Timestamp: timestamp})
+}
+
+// GetWindowVolume 估算交易对最近window内的成交额（USDT）
+// 行情接口只提供滚动24小时成交额，窗口成交额 ≈ 当前值 - window前的值 + 期间滚出24小时范围的部分（按平均水平估算）
+func (sm *StateManager) GetWindowVolume(symbol string, window time.Duration) (float64, bool) {
+	sm.mutex.RLock()
+	queue := sm.volumes24h[symbol]
+	sm.mutex.RUnlock()
+	if queue == nil {
+		return 0, false
+	}
+
+	latest := queue.GetLatest()
+	past := queue.FindPriceAroundTime(sm.now().Add(-window), min(2*time.Minute, window/2))
+	if latest == nil || past == nil {
+		return 0, false
+	}
+	rolledOff := past.Price * float64(window) / float64(24*time.Hour)
+	return max(latest.Price-past.Price+rolledOff, 0), true
+}
+
+// GetTicker24h 获取交易对最近一次的24小时统计，没有时返回nil
+func (sm *StateManager) GetTicker24h(symbol string) *types.Ticker24h {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+	stats, ok := sm.tickers24h[symbol]
+	if !ok {
+		return nil
+	}
+	return &stats
+}
+
+// goWrite 异步执行Redis写入并计入待完成数量，关闭时由Flush等待
+func (sm *StateManager) goWrite(write func()) {
+	sm.pendingWrites.Add(1)
+	sm.pendingCount.Add(1)
+	go func() {
+		defer sm.pendingWrites.Done()
+		defer sm.pendingCount.Add(-1)
+		write()
+	}()
+}
+
+// Flush 等待尚未完成的异步Redis写入，返回超时后仍未完成的写入数量
+func (sm *StateManager) Flush(ctx context.Context) int {
+	done := make(chan struct{})
+	go func() {
+		sm.pendingWrites.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return 0
+	case <-ctx.Done():
+		return int(sm.pendingCount.Load())
 	}
 }
 
+// Close 关闭Redis连接，之后不再备份数据
+func (sm *StateManager) Close() error {
+	if sm.redisClient == nil {
+		return nil
+	}
+	return sm.redisClient.Close()
+}
+
 // backupToRedis 备份数据到Redis
 func (sm *StateManager) backupToRedis(symbol string, point types.PriceDataPoint) {
 	if !sm.useRedis {
@@ -182,7 +301,7 @@ func (sm *StateManager) backupToRedis(symbol string, point types.PriceDataPoint)
 	key := fmt.Sprintf("okx:price:%s", symbol)
 	value, err := json.Marshal(point)
 	if err != nil {
-		zap.L().Error("序列化价格数据失败", zap.Error(err))
+		log().Error("序列化价格数据失败", zap.Error(err))
 		return
 	}
 
@@ -193,21 +312,93 @@ func (sm *StateManager) backupToRedis(symbol string, point types.PriceDataPoint)
 	}).Err()
 
 	if err != nil {
-		zap.L().Error("Redis存储失败",
+		log().Error("Redis存储失败",
 			zap.String("symbol", symbol),
 			zap.Error(err))
 		return
 	}
 
-	// 设置过期时间，只保留10分钟数据
-	sm.redisClient.Expire(ctx, key, 10*time.Minute)
+	// 设置过期时间，只保留redisRetention内的数据
+	retention := sm.redisRetention()
+	sm.redisClient.Expire(ctx, key, retention)
 
-	// 清理旧数据，只保留最近10分钟
-	cutoff := float64(time.Now().Add(-10 * time.Minute).Unix())
+	// 清理旧数据
+	cutoff := float64(time.Now().Add(-retention).Unix())
 	sm.redisClient.ZRemRangeByScore(ctx, key, "0", fmt.Sprintf("%.0f", cutoff))
 }
 
-func (sm *StateManager) GetPriceData(symbol string) (*types.PriceDataPoint, *types.PriceDataPoint) {
+// redisRetention Redis中备份数据的保留时长，至少10分钟且覆盖内存保留时长
+func (sm *StateManager) redisRetention() time.Duration {
+	if sm.retention > 10*time.Minute {
+		return sm.retention
+	}
+	return 10 * time.Minute
+}
+
+// restoreFromRedis 启动时从Redis恢复价格历史，避免重启后重新积累监控窗口数据
+func (sm *StateManager) restoreFromRedis() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	keys, err := sm.scanKeys(ctx, "okx:price:*")
+	if err != nil {
+		log().Warn("⚠️ 获取Redis备份数据失败，跳过恢复", zap.Error(err))
+		return
+	}
+
+	cutoff := time.Now().Add(-sm.retention)
+	minScore := fmt.Sprintf("%d", cutoff.Unix())
+	restoredPoints := 0
+
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	for _, key := range keys {
+		symbol := strings.TrimPrefix(key, "okx:price:")
+		members, err := sm.redisClient.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+			Min: minScore,
+			Max: "+inf",
+		}).Result()
+		if err != nil {
+			log().Warn("恢复交易对数据失败", zap.String("symbol", symbol), zap.Error(err))
+			continue
+		}
+
+		for _, member := range members {
+			var point types.PriceDataPoint
+			if err := json.Unmarshal([]byte(member), &point); err != nil {
+				continue
+			}
+			if sm.priceHistory[symbol] == nil {
+				sm.priceHistory[symbol] = NewCircularQueue(sm.retention)
+			}
+			sm.priceHistory[symbol].Add(point)
+			restoredPoints++
+		}
+	}
+
+	if restoredPoints > 0 {
+		log().Info("♻️ 已从Redis恢复价格历史",
+			zap.Int("symbols", len(sm.priceHistory)),
+			zap.Int("points", restoredPoints))
+	}
+}
+
+// scanCount SCAN每批建议返回的key数量
+const scanCount = 500
+
+// scanKeys 用SCAN分批遍历匹配pattern的key，避免KEYS在key较多时阻塞Redis
+func (sm *StateManager) scanKeys(ctx context.Context, pattern string) ([]string, error) {
+	var keys []string
+	iter := sm.redisClient.Scan(ctx, 0, pattern, scanCount).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	return keys, iter.Err()
+}
+
+// GetPriceData 获取最新价格和一个监控周期之前的价格，数据不足时past为nil
+func (sm *StateManager) GetPriceData(symbol string, window time.Duration) (*types.PriceDataPoint, *types.PriceDataPoint) {
 	sm.mutex.RLock()
 	defer sm.mutex.RUnlock()
 
@@ -222,12 +413,55 @@ func (sm *StateManager) GetPriceData(symbol string) (*types.PriceDataPoint, *typ
 		return nil, nil
 	}
 
-	// 获取5分钟前的价格
-	past := queue.FindPriceAroundTime(time.Now().Add(-sm.windowSize))
+	// 获取监控周期之前的价格
+	// 容差最多2分钟，且不超过监控周期的一半，避免秒级周期用到窗口内过近的数据
+	past := queue.FindPriceAroundTime(sm.now().Add(-window), min(2*time.Minute, window/2))
 
 	return current, past
 }
 
+// GetOldestPriceData 获取交易对最早的价格数据，用于启动时按已有数据分析
+func (sm *StateManager) GetOldestPriceData(symbol string) *types.PriceDataPoint {
+	sm.mutex.RLock()
+	defer sm.mutex.RUnlock()
+
+	queue := sm.priceHistory[symbol]
+	if queue == nil {
+		return nil
+	}
+	return queue.GetOldest()
+}
+
+// GetPriceSeries 获取交易对在回看周期内的价格序列
+func (sm *StateManager) GetPriceSeries(symbol string, lookback time.Duration) []types.PriceDataPoint {
+	sm.mutex.RLock()
+	queue := sm.priceHistory[symbol]
+	sm.mutex.RUnlock()
+
+	if queue == nil {
+		return nil
+	}
+	return queue.GetSince(sm.now().Add(-lookback))
+}
+
+// GetCoverage 获取交易对已收集的数据点数和覆盖的时间跨度
+func (sm *StateManager) GetCoverage(symbol string) (int, time.Duration) {
+	sm.mutex.RLock()
+	queue := sm.priceHistory[symbol]
+	sm.mutex.RUnlock()
+
+	if queue == nil {
+		return 0, 0
+	}
+
+	oldest := queue.GetOldest()
+	latest := queue.GetLatest()
+	if oldest == nil || latest == nil {
+		return 0, 0
+	}
+	return queue.Length(), latest.Timestamp.Sub(oldest.Timestamp)
+}
+
 func (sm *StateManager) GetAllSymbols() []string {
 	sm.mutex.RLock()
 	defer sm.mutex.RUnlock()
@@ -239,11 +473,95 @@ func (sm *StateManager) GetAllSymbols() []string {
 	return symbols
 }
 
+// cycleMetricsKey 分析指标历史在Redis中的key
+const cycleMetricsKey = "okx:metrics:cycles"
+
+// SaveCycleMetricsAsync 异步保存分析指标，关闭时由Flush等待写入完成
+func (sm *StateManager) SaveCycleMetricsAsync(metrics types.CycleMetrics, maxLen int) {
+	if !sm.useRedis {
+		return
+	}
+	sm.goWrite(func() { sm.SaveCycleMetrics(metrics, maxLen) })
+}
+
+// SaveCycleMetrics 将单轮分析指标追加到Redis，最多保留maxLen条
+func (sm *StateManager) SaveCycleMetrics(metrics types.CycleMetrics, maxLen int) {
+	if !sm.useRedis {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	value, err := json.Marshal(metrics)
+	if err != nil {
+		log().Error("序列化分析指标失败", zap.Error(err))
+		return
+	}
+
+	pipe := sm.redisClient.TxPipeline()
+	pipe.RPush(ctx, cycleMetricsKey, value)
+	pipe.LTrim(ctx, cycleMetricsKey, int64(-maxLen), -1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log().Warn("保存分析指标到Redis失败", zap.Error(err))
+	}
+}
+
+// LoadCycleMetrics 从Redis读取最近的分析指标（按时间升序）
+func (sm *StateManager) LoadCycleMetrics(limit int) []types.CycleMetrics {
+	if !sm.useRedis {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	values, err := sm.redisClient.LRange(ctx, cycleMetricsKey, int64(-limit), -1).Result()
+	if err != nil {
+		log().Warn("读取分析指标历史失败", zap.Error(err))
+		return nil
+	}
+
+	result := make([]types.CycleMetrics, 0, len(values))
+	for _, v := range values {
+		var m types.CycleMetrics
+		if err := json.Unmarshal([]byte(v), &m); err == nil {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// RedisEnabled 是否启用了Redis备份
+func (sm *StateManager) RedisEnabled() bool {
+	return sm.useRedis
+}
+
+// Redis 已连接的Redis客户端，未启用Redis时返回nil，供需要持久化的其他模块共用连接
+func (sm *StateManager) Redis() *redis.Client {
+	if !sm.useRedis {
+		return nil
+	}
+	return sm.redisClient
+}
+
+// PingRedis 检查Redis连接，未启用Redis时返回nil
+func (sm *StateManager) PingRedis(ctx context.Context) error {
+	if !sm.useRedis {
+		return nil
+	}
+	return sm.redisClient.Ping(ctx).Err()
+}
+
 // GetRedisStats 获取Redis统计信息
 func (sm *StateManager) GetRedisStats() map[string]interface{} {
+	sm.mutex.RLock()
+	memorySymbols := len(sm.priceHistory)
+	sm.mutex.RUnlock()
+
 	stats := map[string]interface{}{
 		"redis_enabled":  sm.useRedis,
-		"memory_symbols": len(sm.priceHistory),
+		"memory_symbols": memorySymbols,
 	}
 
 	if sm.useRedis {
@@ -251,7 +569,7 @@ func (sm *StateManager) GetRedisStats() map[string]interface{} {
 		defer cancel()
 
 		// 获取Redis中的key数量
-		keys, err := sm.redisClient.Keys(ctx, "okx:price:*").Result()
+		keys, err := sm.scanKeys(ctx, "okx:price:*")
 		if err == nil {
 			stats["redis_keys"] = len(keys)
 		} else {
@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"okx-market-sentry/internal/storage/redistest"
+	"okx-market-sentry/pkg/types"
+)
+
+// 基准测试的数据规模：200个交易对，15秒获取一次，保留1小时（每个交易对240个数据点）
+const (
+	benchSymbols  = 200
+	benchInterval = 15 * time.Second
+	benchPoints   = 240
+)
+
+// newBenchStateManager 创建已填满数据的纯内存存储
+func newBenchStateManager(b *testing.B) (*StateManager, []string) {
+	b.Helper()
+	sm := NewStateManager(types.RedisConfig{}, time.Hour, time.Hour)
+	symbols := make([]string, benchSymbols)
+	start := time.Now().Add(-benchInterval * benchPoints)
+	for i := range symbols {
+		symbols[i] = fmt.Sprintf("SYM%d-USDT", i)
+		for j := 0; j < benchPoints; j++ {
+			sm.Store(symbols[i], 100+float64(j%10), start.Add(time.Duration(j+1)*benchInterval))
+		}
+	}
+	return sm, symbols
+}
+
+func BenchmarkStore(b *testing.B) {
+	sm, symbols := newBenchStateManager(b)
+	now := time.Now()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sm.Store(symbols[i%len(symbols)], 100, now)
+	}
+}
+
+func BenchmarkFindPriceAroundTime(b *testing.B) {
+	queue := NewCircularQueue(time.Hour)
+	now := time.Now()
+	for j := 0; j < benchPoints; j++ {
+		queue.Add(types.PriceDataPoint{Price: 100, Timestamp: now.Add(-time.Duration(benchPoints-j) * benchInterval)})
+	}
+	target := now.Add(-5 * time.Minute)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if queue.FindPriceAroundTime(target, time.Minute) == nil {
+			b.Fatal("price not found")
+		}
+	}
+}
+
+func BenchmarkGetPriceData(b *testing.B) {
+	sm, symbols := newBenchStateManager(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sm.GetPriceData(symbols[i%len(symbols)], 5*time.Minute)
+	}
+}
+
+// newRedisStateManager 创建连接测试Redis的存储
+func newRedisStateManager(t *testing.T, server *redistest.Server) *StateManager {
+	t.Helper()
+	sm := NewStateManager(types.RedisConfig{URL: server.Addr()}, time.Hour, time.Hour)
+	if !sm.RedisEnabled() {
+		t.Fatal("连接测试Redis失败")
+	}
+	t.Cleanup(func() { sm.Close() })
+	return sm
+}
+
+func TestRestoreFromRedis(t *testing.T) {
+	server := redistest.NewServer(t)
+
+	// 交易对数量超过一批SCAN的数量，覆盖分批遍历
+	symbols := scanCount + 20
+	start := time.Now().Add(-10 * time.Minute)
+	writer := newRedisStateManager(t, server)
+	for i := 0; i < symbols; i++ {
+		writer.Store(fmt.Sprintf("SYM%d-USDT", i), 100, start)
+		writer.Store(fmt.Sprintf("SYM%d-USDT", i), 101, start.Add(time.Minute))
+	}
+	if pending := writer.Flush(context.Background()); pending != 0 {
+		t.Fatalf("仍有%d个写入未完成", pending)
+	}
+
+	restored := newRedisStateManager(t, server)
+	if got := len(restored.GetAllSymbols()); got != symbols {
+		t.Fatalf("恢复了%d个交易对, want %d", got, symbols)
+	}
+	oldest := restored.GetOldestPriceData("SYM7-USDT")
+	latest, _ := restored.GetPriceData("SYM7-USDT", time.Minute)
+	if oldest == nil || oldest.Price != 100 || latest == nil || latest.Price != 101 {
+		t.Fatalf("SYM7-USDT 恢复的数据 oldest=%v latest=%v", oldest, latest)
+	}
+	if n := server.CommandCount("KEYS"); n != 0 {
+		t.Errorf("恢复时使用了%d次KEYS，应使用SCAN", n)
+	}
+	if n := server.CommandCount("SCAN"); n < 2 {
+		t.Errorf("SCAN调用%d次，应分批遍历", n)
+	}
+}
+
+func TestWindowVolume(t *testing.T) {
+	sm := NewStateManager(types.RedisConfig{}, time.Hour, time.Hour)
+	now := time.Now()
+	sm.SetClock(func() time.Time { return now })
+
+	if stats := sm.GetTicker24h("BTC-USDT"); stats != nil {
+		t.Fatalf("未保存时 GetTicker24h = %+v", stats)
+	}
+	if _, ok := sm.GetWindowVolume("BTC-USDT", 5*time.Minute); ok {
+		t.Fatal("未保存时不应估算成交额")
+	}
+
+	sm.StoreTicker24h("BTC-USDT", types.Ticker24h{QuoteVolume: 1_000_000}, now.Add(-5*time.Minute))
+	if _, ok := sm.GetWindowVolume("BTC-USDT", 5*time.Minute); ok {
+		t.Fatal("只有一个数据点时不应估算成交额")
+	}
+	sm.StoreTicker24h("BTC-USDT", types.Ticker24h{High: 105, QuoteVolume: 1_100_000}, now)
+	if stats := sm.GetTicker24h("BTC-USDT"); stats == nil || stats.High != 105 {
+		t.Errorf("GetTicker24h = %+v, 期望最近一次的统计", stats)
+	}
+
+	// 窗口成交额 = 增量 + 5分钟内滚出24小时范围的部分
+	want := 100_000 + 1_000_000*5.0/(24*60)
+	if got, ok := sm.GetWindowVolume("BTC-USDT", 5*time.Minute); !ok || math.Abs(got-want) > 1e-6 {
+		t.Errorf("GetWindowVolume = %v, %v, want %v", got, ok, want)
+	}
+	// 窗口前后没有足够接近的数据点
+	if _, ok := sm.GetWindowVolume("BTC-USDT", 30*time.Minute); ok {
+		t.Error("30分钟前没有数据，不应估算成交额")
+	}
+
+	// 24小时成交额大幅回落时不返回负数
+	sm.StoreTicker24h("ETH-USDT", types.Ticker24h{QuoteVolume: 1_000_000}, now.Add(-5*time.Minute))
+	sm.StoreTicker24h("ETH-USDT", types.Ticker24h{QuoteVolume: 500_000}, now)
+	if got, ok := sm.GetWindowVolume("ETH-USDT", 5*time.Minute); !ok || got != 0 {
+		t.Errorf("GetWindowVolume = %v, %v, want 0", got, ok)
+	}
+}
+
+func TestCooldownKey(t *testing.T) {
+	name, symbol, ok := parseCooldownKey(cooldownKey("rule:breakout", "BTC-USDT"))
+	if !ok || name != "rule:breakout" || symbol != "BTC-USDT" {
+		t.Fatalf("parseCooldownKey = %q, %q, %v", name, symbol, ok)
+	}
+	for _, key := range []string{"okx:price:BTC-USDT", "okx:cooldown:BTC-USDT", "okx:cooldown:alts:"} {
+		if _, _, ok := parseCooldownKey(key); ok {
+			t.Errorf("parseCooldownKey(%q) 应失败", key)
+		}
+	}
+}
+
+func TestClaimCooldownWithoutRedis(t *testing.T) {
+	sm := NewStateManager(types.RedisConfig{}, time.Hour, time.Hour)
+	// 未启用Redis时由分析引擎的本地冷却判断
+	for i := 0; i < 2; i++ {
+		if claimed, _ := sm.ClaimCooldown("default", "BTC-USDT", time.Now(), time.Hour); !claimed {
+			t.Fatal("未启用Redis时应总是返回true")
+		}
+	}
+	if history := sm.LoadCooldowns(); len(history) != 0 {
+		t.Fatalf("LoadCooldowns = %v, 期望为空", history)
+	}
+}
@@ -0,0 +1,73 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// benchKlines 生成n根正弦走势的K线
+func benchKlines(n int) []types.Kline {
+	klines := make([]types.Kline, n)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range klines {
+		price := 100 + 10*math.Sin(float64(i)/20)
+		klines[i] = types.Kline{
+			OpenTime: start.Add(time.Duration(i) * time.Minute),
+			Open:     price - 0.5,
+			High:     price + 1,
+			Low:      price - 1,
+			Close:    price,
+			Volume:   1000 + float64(i%50),
+		}
+	}
+	return klines
+}
+
+func benchPrices(n int, phase float64) []float64 {
+	prices := make([]float64, n)
+	for i := range prices {
+		prices[i] = 100 + 10*math.Sin(float64(i)/20+phase)
+	}
+	return prices
+}
+
+func BenchmarkOBV(b *testing.B) {
+	klines := benchKlines(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		OBV(klines)
+	}
+}
+
+func BenchmarkCMF(b *testing.B) {
+	klines := benchKlines(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		CMF(klines, 20)
+	}
+}
+
+func BenchmarkHeikinAshi(b *testing.B) {
+	klines := benchKlines(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		HeikinAshi(klines)
+	}
+}
+
+// BenchmarkCorrelationBeta 每个交易对每轮分析计算一次相对基准的相关性和Beta
+func BenchmarkCorrelationBeta(b *testing.B) {
+	asset, benchmark := Returns(benchPrices(240, 0)), Returns(benchPrices(240, 0.3))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Correlation(asset, benchmark)
+		Beta(asset, benchmark)
+	}
+}
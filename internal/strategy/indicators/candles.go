@@ -0,0 +1,112 @@
+package indicators
+
+import (
+	"math"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// 形态识别参数
+const (
+	dojiBodyRatio      = 0.1 // 实体不超过振幅的10%视为十字星
+	hammerShadowRatio  = 2.0 // 下影线至少为实体的2倍
+	hammerUpperMaxRate = 0.3 // 上影线不超过振幅的30%
+)
+
+// PatternFlags K线形态确认标记，均为可选的辅助确认信号
+type PatternFlags struct {
+	BullishEngulfing bool `json:"bullish_engulfing"`
+	BearishEngulfing bool `json:"bearish_engulfing"`
+	Doji             bool `json:"doji"`
+	Hammer           bool `json:"hammer"`
+	HeikinAshiBull   bool `json:"heikin_ashi_bull"` // 最新平均K线为阳线
+	HeikinAshiBear   bool `json:"heikin_ashi_bear"` // 最新平均K线为阴线
+}
+
+// HeikinAshi 将普通K线转换为平均K线（Heikin-Ashi）
+func HeikinAshi(klines []types.Kline) []types.Kline {
+	result := make([]types.Kline, len(klines))
+	for i, k := range klines {
+		haClose := (k.Open + k.High + k.Low + k.Close) / 4
+
+		var haOpen float64
+		if i == 0 {
+			haOpen = (k.Open + k.Close) / 2
+		} else {
+			haOpen = (result[i-1].Open + result[i-1].Close) / 2
+		}
+
+		result[i] = types.Kline{
+			OpenTime: k.OpenTime,
+			Open:     haOpen,
+			High:     math.Max(k.High, math.Max(haOpen, haClose)),
+			Low:      math.Min(k.Low, math.Min(haOpen, haClose)),
+			Close:    haClose,
+			Volume:   k.Volume,
+		}
+	}
+	return result
+}
+
+// IsDoji 判断是否为十字星
+func IsDoji(k types.Kline) bool {
+	hl := k.High - k.Low
+	if hl == 0 {
+		return true
+	}
+	return math.Abs(k.Close-k.Open) <= hl*dojiBodyRatio
+}
+
+// IsHammer 判断是否为锤子线（长下影、小实体、短上影）
+func IsHammer(k types.Kline) bool {
+	hl := k.High - k.Low
+	body := math.Abs(k.Close - k.Open)
+	if hl == 0 || body == 0 {
+		return false
+	}
+
+	lowerShadow := math.Min(k.Open, k.Close) - k.Low
+	upperShadow := k.High - math.Max(k.Open, k.Close)
+	return lowerShadow >= body*hammerShadowRatio && upperShadow <= hl*hammerUpperMaxRate
+}
+
+// IsBullishEngulfing 判断看涨吞没：前阴后阳，且后者实体完全覆盖前者实体
+func IsBullishEngulfing(prev, curr types.Kline) bool {
+	return prev.Close < prev.Open &&
+		curr.Close > curr.Open &&
+		curr.Open <= prev.Close &&
+		curr.Close >= prev.Open
+}
+
+// IsBearishEngulfing 判断看跌吞没：前阳后阴，且后者实体完全覆盖前者实体
+func IsBearishEngulfing(prev, curr types.Kline) bool {
+	return prev.Close > prev.Open &&
+		curr.Close < curr.Open &&
+		curr.Open >= prev.Close &&
+		curr.Close <= prev.Open
+}
+
+// DetectPatterns 识别最新一根K线的形态
+func DetectPatterns(klines []types.Kline) PatternFlags {
+	var flags PatternFlags
+	if len(klines) == 0 {
+		return flags
+	}
+
+	last := klines[len(klines)-1]
+	flags.Doji = IsDoji(last)
+	flags.Hammer = IsHammer(last)
+
+	if len(klines) >= 2 {
+		prev := klines[len(klines)-2]
+		flags.BullishEngulfing = IsBullishEngulfing(prev, last)
+		flags.BearishEngulfing = IsBearishEngulfing(prev, last)
+	}
+
+	ha := HeikinAshi(klines)
+	haLast := ha[len(ha)-1]
+	flags.HeikinAshiBull = haLast.Close > haLast.Open
+	flags.HeikinAshiBear = haLast.Close < haLast.Open
+
+	return flags
+}
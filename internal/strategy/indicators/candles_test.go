@@ -0,0 +1,107 @@
+package indicators
+
+import (
+	"testing"
+
+	"okx-market-sentry/pkg/types"
+)
+
+func ohlc(open, high, low, close float64) types.Kline {
+	return types.Kline{Open: open, High: high, Low: low, Close: close}
+}
+
+func TestIsDoji(t *testing.T) {
+	tests := []struct {
+		name  string
+		kline types.Kline
+		want  bool
+	}{
+		{"一字线", ohlc(10, 10, 10, 10), true},
+		{"实体为振幅5%", ohlc(10, 11, 9, 10.1), true},
+		{"实体恰为振幅10%", ohlc(10, 11, 9, 10.2), true},
+		{"实体为振幅50%", ohlc(10, 11, 9, 11), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsDoji(tt.kline); got != tt.want {
+				t.Errorf("IsDoji() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsHammer(t *testing.T) {
+	tests := []struct {
+		name  string
+		kline types.Kline
+		want  bool
+	}{
+		{"长下影短上影", ohlc(10, 10.6, 9, 10.5), true},
+		{"阴线锤子", ohlc(10.5, 10.6, 9, 10), true},
+		{"下影线不足实体2倍", ohlc(10, 10.6, 9.8, 10.5), false},
+		{"上影线过长", ohlc(10, 11.5, 9, 10.5), false},
+		{"无实体", ohlc(10, 10.5, 9, 10), false},
+		{"一字线", ohlc(10, 10, 10, 10), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsHammer(tt.kline); got != tt.want {
+				t.Errorf("IsHammer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngulfing(t *testing.T) {
+	tests := []struct {
+		name        string
+		prev, curr  types.Kline
+		wantBullish bool
+		wantBearish bool
+	}{
+		{"看涨吞没", ohlc(10, 10.1, 8.9, 9), ohlc(8.9, 10.3, 8.8, 10.2), true, false},
+		{"看涨未完全覆盖", ohlc(10, 10.1, 8.9, 9), ohlc(9.1, 10.3, 9, 10.2), false, false},
+		{"看跌吞没", ohlc(9, 10.1, 8.9, 10), ohlc(10.1, 10.2, 8.7, 8.8), false, true},
+		{"看跌未完全覆盖", ohlc(9, 10.1, 8.9, 10), ohlc(10.1, 10.2, 9.1, 9.2), false, false},
+		{"同向K线", ohlc(9, 10.1, 8.9, 10), ohlc(9.8, 11, 9.7, 10.9), false, false},
+		{"实体相等", ohlc(10, 10.1, 8.9, 9), ohlc(9, 10.1, 8.9, 10), true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsBullishEngulfing(tt.prev, tt.curr); got != tt.wantBullish {
+				t.Errorf("IsBullishEngulfing() = %v, want %v", got, tt.wantBullish)
+			}
+			if got := IsBearishEngulfing(tt.prev, tt.curr); got != tt.wantBearish {
+				t.Errorf("IsBearishEngulfing() = %v, want %v", got, tt.wantBearish)
+			}
+		})
+	}
+}
+
+func TestDetectPatterns(t *testing.T) {
+	tests := []struct {
+		name   string
+		klines []types.Kline
+		want   PatternFlags
+	}{
+		{"无数据", nil, PatternFlags{}},
+		{"单根十字星", []types.Kline{ohlc(10, 11, 9, 10)}, PatternFlags{Doji: true}},
+		{
+			"看涨吞没且平均K线为阳线",
+			[]types.Kline{ohlc(10, 10.1, 8.9, 9), ohlc(8.9, 10.3, 8.8, 10.2)},
+			PatternFlags{BullishEngulfing: true, HeikinAshiBull: true},
+		},
+		{
+			"锤子线且平均K线为阴线",
+			[]types.Kline{ohlc(12, 12.1, 10.9, 11), ohlc(10, 10.6, 9, 10.5)},
+			PatternFlags{Hammer: true, HeikinAshiBear: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectPatterns(tt.klines); got != tt.want {
+				t.Errorf("DetectPatterns() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
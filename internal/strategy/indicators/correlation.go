@@ -0,0 +1,75 @@
+package indicators
+
+import "math"
+
+// Returns 将价格序列转换为简单收益率序列，长度比输入少1
+func Returns(prices []float64) []float64 {
+	if len(prices) < 2 {
+		return nil
+	}
+
+	result := make([]float64, 0, len(prices)-1)
+	for i := 1; i < len(prices); i++ {
+		if prices[i-1] == 0 {
+			result = append(result, 0)
+			continue
+		}
+		result = append(result, (prices[i]-prices[i-1])/prices[i-1])
+	}
+	return result
+}
+
+// Correlation 计算两个等长序列的皮尔逊相关系数，数据不足或方差为0时返回NaN
+func Correlation(x, y []float64) float64 {
+	n := len(x)
+	if n != len(y) || n < 2 {
+		return math.NaN()
+	}
+
+	meanX, meanY := mean(x), mean(y)
+	var cov, varX, varY float64
+	for i := 0; i < n; i++ {
+		dx := x[i] - meanX
+		dy := y[i] - meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+
+	if varX == 0 || varY == 0 {
+		return math.NaN()
+	}
+	return cov / math.Sqrt(varX*varY)
+}
+
+// Beta 计算资产相对基准的Beta系数（cov(asset, benchmark) / var(benchmark)）
+func Beta(asset, benchmark []float64) float64 {
+	n := len(asset)
+	if n != len(benchmark) || n < 2 {
+		return math.NaN()
+	}
+
+	meanA, meanB := mean(asset), mean(benchmark)
+	var cov, varB float64
+	for i := 0; i < n; i++ {
+		db := benchmark[i] - meanB
+		cov += (asset[i] - meanA) * db
+		varB += db * db
+	}
+
+	if varB == 0 {
+		return math.NaN()
+	}
+	return cov / varB
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
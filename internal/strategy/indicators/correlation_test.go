@@ -0,0 +1,50 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCorrelationBetaEdgeCases(t *testing.T) {
+	tests := []struct {
+		name     string
+		x, y     []float64
+		wantCorr float64
+		wantBeta float64 // Beta(x, y)，y为基准
+	}{
+		{"长度不一致", []float64{1, 2, 3}, []float64{1, 2}, math.NaN(), math.NaN()},
+		{"样本不足", []float64{1}, []float64{2}, math.NaN(), math.NaN()},
+		{"空序列", nil, nil, math.NaN(), math.NaN()},
+		{"资产方差为0", []float64{1, 1, 1}, []float64{1, 2, 3}, math.NaN(), 0},
+		{"基准方差为0", []float64{1, 2, 3}, []float64{5, 5, 5}, math.NaN(), math.NaN()},
+		{"完全正相关", []float64{2, 4, 6, 8}, []float64{1, 2, 3, 4}, 1, 2},
+		{"完全负相关", []float64{-1, -2, -3}, []float64{1, 2, 3}, -1, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Correlation(tt.x, tt.y); !approxEqual(got, tt.wantCorr) {
+				t.Errorf("Correlation() = %v, want %v", got, tt.wantCorr)
+			}
+			if got := Beta(tt.x, tt.y); !approxEqual(got, tt.wantBeta) {
+				t.Errorf("Beta() = %v, want %v", got, tt.wantBeta)
+			}
+		})
+	}
+}
+
+func TestReturns(t *testing.T) {
+	if got := Returns([]float64{100}); got != nil {
+		t.Errorf("单个价格应返回nil, got %v", got)
+	}
+
+	got := Returns([]float64{100, 110, 0, 5})
+	want := []float64{0.1, -1, 0} // 前值为0时收益率记为0
+	if len(got) != len(want) {
+		t.Fatalf("长度 got %d, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !approxEqual(got[i], want[i]) {
+			t.Errorf("第%d个收益率: got %v, want %v", i, got[i], want[i])
+		}
+	}
+}
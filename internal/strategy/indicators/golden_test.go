@@ -0,0 +1,165 @@
+package indicators
+
+import (
+	"encoding/csv"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// goldenTolerance 与参考实现对比的相对误差容忍度
+const goldenTolerance = 1e-9
+
+// goldenCalculators 黄金文件中每一列期望值对应的计算器，新增指标时在此注册
+var goldenCalculators = map[string]func([]types.Kline) []float64{
+	"obv":    OBV,
+	"cmf_20": func(k []types.Kline) []float64 { return CMF(k, 20) },
+	"ha_open": func(k []types.Kline) []float64 {
+		return klineField(HeikinAshi(k), func(x types.Kline) float64 { return x.Open })
+	},
+	"ha_high": func(k []types.Kline) []float64 {
+		return klineField(HeikinAshi(k), func(x types.Kline) float64 { return x.High })
+	},
+	"ha_low": func(k []types.Kline) []float64 {
+		return klineField(HeikinAshi(k), func(x types.Kline) float64 { return x.Low })
+	},
+	"ha_close": func(k []types.Kline) []float64 {
+		return klineField(HeikinAshi(k), func(x types.Kline) float64 { return x.Close })
+	},
+}
+
+func TestGoldenOHLCV(t *testing.T) {
+	header, rows := readGoldenCSV(t, "golden_ohlcv.csv")
+	klines, expected := parseGoldenOHLCV(t, header, rows)
+
+	for column, values := range expected {
+		calc, ok := goldenCalculators[column]
+		if !ok {
+			t.Errorf("黄金文件列 %s 没有注册计算器", column)
+			continue
+		}
+
+		t.Run(column, func(t *testing.T) {
+			actual := calc(klines)
+			if len(actual) != len(values) {
+				t.Fatalf("结果长度不一致: got %d, want %d", len(actual), len(values))
+			}
+			for i := range values {
+				if !approxEqual(actual[i], values[i]) {
+					t.Errorf("第%d行: got %v, want %v", i, actual[i], values[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGoldenCorrelation(t *testing.T) {
+	_, rows := readGoldenCSV(t, "golden_correlation.csv")
+	if len(rows) < 3 {
+		t.Fatalf("黄金文件数据不足")
+	}
+
+	// 第一行为期望值，第二行为价格序列表头
+	wantCorr := parseFloat(t, rows[0][0])
+	wantBeta := parseFloat(t, rows[0][1])
+
+	var asset, benchmark []float64
+	for _, row := range rows[2:] {
+		asset = append(asset, parseFloat(t, row[0]))
+		benchmark = append(benchmark, parseFloat(t, row[1]))
+	}
+
+	assetReturns := Returns(asset)
+	benchReturns := Returns(benchmark)
+
+	if got := Correlation(assetReturns, benchReturns); !approxEqual(got, wantCorr) {
+		t.Errorf("Correlation: got %v, want %v", got, wantCorr)
+	}
+	if got := Beta(assetReturns, benchReturns); !approxEqual(got, wantBeta) {
+		t.Errorf("Beta: got %v, want %v", got, wantBeta)
+	}
+}
+
+// readGoldenCSV 读取testdata下的CSV文件，返回表头和数据行
+func readGoldenCSV(t *testing.T, name string) ([]string, [][]string) {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("打开黄金文件失败: %v", err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("解析黄金文件失败: %v", err)
+	}
+	if len(records) < 2 {
+		t.Fatalf("黄金文件 %s 为空", name)
+	}
+	return records[0], records[1:]
+}
+
+// parseGoldenOHLCV 将前6列解析为K线，其余列作为期望值
+func parseGoldenOHLCV(t *testing.T, header []string, rows [][]string) ([]types.Kline, map[string][]float64) {
+	t.Helper()
+
+	const ohlcvColumns = 6
+	klines := make([]types.Kline, 0, len(rows))
+	expected := make(map[string][]float64)
+
+	for _, row := range rows {
+		ts, err := strconv.ParseInt(row[0], 10, 64)
+		if err != nil {
+			t.Fatalf("解析时间戳失败: %v", err)
+		}
+		klines = append(klines, types.Kline{
+			OpenTime: time.UnixMilli(ts),
+			Open:     parseFloat(t, row[1]),
+			High:     parseFloat(t, row[2]),
+			Low:      parseFloat(t, row[3]),
+			Close:    parseFloat(t, row[4]),
+			Volume:   parseFloat(t, row[5]),
+		})
+
+		for i := ohlcvColumns; i < len(header); i++ {
+			expected[header[i]] = append(expected[header[i]], parseFloat(t, row[i]))
+		}
+	}
+	return klines, expected
+}
+
+func parseFloat(t *testing.T, s string) float64 {
+	t.Helper()
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		t.Fatalf("解析数值 %q 失败: %v", s, err)
+	}
+	return v
+}
+
+func klineField(klines []types.Kline, field func(types.Kline) float64) []float64 {
+	result := make([]float64, len(klines))
+	for i, k := range klines {
+		result[i] = field(k)
+	}
+	return result
+}
+
+// approxEqual 比较两个浮点数，NaN与NaN视为相等
+func approxEqual(a, b float64) bool {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.IsNaN(a) && math.IsNaN(b)
+	}
+	diff := math.Abs(a - b)
+	scale := math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+	return diff <= goldenTolerance*scale
+}
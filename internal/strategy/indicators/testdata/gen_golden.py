@@ -0,0 +1,252 @@
+#!/usr/bin/env python3
+"""生成指标黄金文件（golden file）测试数据。
+
+期望值取自参考库的输出，而不是按Go实现的公式重新推导，避免两边共享同一个公式错误：
+  - OBV:          TA-Lib talib.OBV
+  - CMF(20):      TA-Lib talib.AD 的逐根增量按20根滚动求和，再除以同窗口成交量之和
+  - Heikin-Ashi:  pandas-ta ta.ha
+  - 相关系数/Beta: pandas Series.corr / Series.cov / Series.var
+
+一字线（high == low，生成器中 i % 53 == 7 的行）：
+  资金流量乘数 ((close-low) - (high-close)) / (high-low) 分母为0。TA-Lib AD 对这类K线
+  记0资金流量，Go 的 CMF 与之一致（moneyFlowMultiplier 返回0）。pandas-ta 的 cmf 直接除以
+  (high-low)，不同版本会得到 NaN 或依赖 epsilon 的结果，并污染之后20根的滚动窗口，
+  因此 CMF 以 TA-Lib AD 为准，pandas-ta cmf 只在不含一字线的窗口上交叉核对。
+
+依赖: pip install TA-Lib pandas pandas-ta
+未安装参考库时退回到逐行转写自上述库源码的纯Python实现（TA-Lib ta_OBV.c / ta_AD.c、
+pandas-ta candles/ha.py、pandas 的样本协方差），与参考库只在浮点求和顺序上有差异。
+
+用法:
+  python3 gen_golden.py          在 testdata 目录下重新生成 golden_*.csv
+  python3 gen_golden.py --check  重新计算并与已提交的 golden_*.csv 比对，不一致时返回1
+"""
+import math
+import os
+import random
+import statistics
+import sys
+
+try:
+    import numpy as np
+    import pandas as pd
+    import pandas_ta as ta
+    import talib
+
+    HAVE_REFERENCE = True
+except ImportError:
+    HAVE_REFERENCE = False
+
+ROWS = 120
+CMF_LENGTH = 20
+COLUMNS = ["ts", "open", "high", "low", "close", "volume",
+           "obv", "cmf_20", "ha_open", "ha_high", "ha_low", "ha_close"]
+
+
+def generate_ohlcv(rows):
+    random.seed(42)
+    price = 100.0
+    data = []
+    for i in range(rows):
+        o = price
+        c = max(0.01, o * (1 + random.gauss(0, 0.01)))
+        h = max(o, c) * (1 + abs(random.gauss(0, 0.004)))
+        l = min(o, c) * (1 - abs(random.gauss(0, 0.004)))
+        v = round(random.uniform(100, 5000), 4)
+        # 偶尔插入平盘和一字线，覆盖边界情况
+        if i % 37 == 5:
+            c = o
+        if i % 53 == 7:
+            h = l = o = c
+        data.append((1700000000000 + i * 60000, o, h, l, c, v))
+        price = c
+    return data
+
+
+def generate_pair(rows):
+    random.seed(7)
+    bench, asset = [60000.0], [150.0]
+    for _ in range(rows - 1):
+        b = random.gauss(0, 0.004)
+        bench.append(bench[-1] * (1 + b))
+        asset.append(asset[-1] * (1 + 1.4 * b + random.gauss(0, 0.003)))
+    return asset, bench
+
+
+# ---------- 参考库 ----------
+
+def reference_ohlcv(data):
+    df = pd.DataFrame(data, columns=COLUMNS[:6])
+    high, low = df["high"].to_numpy(np.float64), df["low"].to_numpy(np.float64)
+    close, volume = df["close"].to_numpy(np.float64), df["volume"].to_numpy(np.float64)
+
+    obv = talib.OBV(close, volume)
+    ad = pd.Series(talib.AD(high, low, close, volume))
+    flow = ad.diff()
+    flow.iloc[0] = ad.iloc[0]
+    cmf = flow.rolling(CMF_LENGTH).sum() / df["volume"].rolling(CMF_LENGTH).sum()
+
+    # 不含一字线的窗口与 pandas-ta cmf 交叉核对
+    pta_cmf = ta.cmf(df["high"], df["low"], df["close"], df["volume"], length=CMF_LENGTH)
+    flat = (df["high"] == df["low"]).astype(int).rolling(CMF_LENGTH, min_periods=1).sum()
+    for i in range(len(df)):
+        if flat.iloc[i] == 0 and not math.isnan(cmf.iloc[i]):
+            assert math.isclose(cmf.iloc[i], pta_cmf.iloc[i], rel_tol=1e-9, abs_tol=1e-12), i
+
+    ha = ta.ha(df["open"], df["high"], df["low"], df["close"])
+    return {
+        "obv": list(obv),
+        "cmf_20": list(cmf),
+        "ha_open": list(ha["HA_open"]),
+        "ha_high": list(ha["HA_high"]),
+        "ha_low": list(ha["HA_low"]),
+        "ha_close": list(ha["HA_close"]),
+    }
+
+
+def reference_correlation(asset, bench):
+    asset_returns = pd.Series(asset).pct_change().dropna()
+    bench_returns = pd.Series(bench).pct_change().dropna()
+    corr = asset_returns.corr(bench_returns)
+    beta = asset_returns.cov(bench_returns) / bench_returns.var()
+    return float(corr), float(beta)
+
+
+# ---------- 纯Python转写 ----------
+
+def transcribed_ohlcv(data):
+    # ta_OBV.c: 以第一根的成交量为起点，收盘上涨加、下跌减、持平不变
+    obv = []
+    prev_obv, prev_close = 0.0, None
+    for _, _, _, _, c, v in data:
+        if prev_close is None:
+            prev_obv = v
+        elif c > prev_close:
+            prev_obv += v
+        elif c < prev_close:
+            prev_obv -= v
+        obv.append(prev_obv)
+        prev_close = c
+
+    # ta_AD.c: high == low 时本根资金流量记0
+    flow = []
+    for _, _, h, l, c, v in data:
+        hl = h - l
+        flow.append(((c - l) - (h - c)) / hl * v if hl > 0 else 0.0)
+    cmf = []
+    for i in range(len(data)):
+        if i < CMF_LENGTH - 1:
+            cmf.append(math.nan)
+            continue
+        window = range(i - CMF_LENGTH + 1, i + 1)
+        volume = math.fsum(data[j][5] for j in window)
+        cmf.append(math.fsum(flow[j] for j in window) / volume if volume else math.nan)
+
+    # pandas-ta candles/ha.py
+    ha_open, ha_high, ha_low, ha_close = [], [], [], []
+    for i, (_, o, h, l, c, _) in enumerate(data):
+        hc = 0.25 * (o + h + l + c)
+        ho = 0.5 * (o + c) if i == 0 else 0.5 * (ha_open[i - 1] + ha_close[i - 1])
+        ha_open.append(ho)
+        ha_close.append(hc)
+        ha_high.append(max(h, ho, hc))
+        ha_low.append(min(l, ho, hc))
+
+    return {
+        "obv": obv,
+        "cmf_20": cmf,
+        "ha_open": ha_open,
+        "ha_high": ha_high,
+        "ha_low": ha_low,
+        "ha_close": ha_close,
+    }
+
+
+def transcribed_correlation(asset, bench):
+    def pct_change(prices):
+        return [(prices[i] - prices[i - 1]) / prices[i - 1] for i in range(1, len(prices))]
+
+    asset_returns, bench_returns = pct_change(asset), pct_change(bench)
+    corr = statistics.correlation(asset_returns, bench_returns)
+    beta = statistics.covariance(asset_returns, bench_returns) / statistics.variance(bench_returns)
+    return corr, beta
+
+
+# ---------- 输出 ----------
+
+def fmt(x):
+    return "NaN" if math.isnan(x) else repr(float(x))
+
+
+def render_ohlcv():
+    data = generate_ohlcv(ROWS)
+    values = reference_ohlcv(data) if HAVE_REFERENCE else transcribed_ohlcv(data)
+
+    lines = [",".join(COLUMNS)]
+    for i, row in enumerate(data):
+        fields = [str(row[0])] + [fmt(x) for x in row[1:]]
+        fields += [fmt(values[column][i]) for column in COLUMNS[6:]]
+        lines.append(",".join(fields))
+    return "\n".join(lines) + "\n"
+
+
+def render_correlation():
+    asset, bench = generate_pair(ROWS)
+    if HAVE_REFERENCE:
+        corr, beta = reference_correlation(asset, bench)
+    else:
+        corr, beta = transcribed_correlation(asset, bench)
+
+    # 首行为期望值，其后为价格序列
+    lines = ["correlation,beta", "%r,%r" % (corr, beta), "asset,benchmark"]
+    lines += ["%r,%r" % (a, b) for a, b in zip(asset, bench)]
+    return "\n".join(lines) + "\n"
+
+
+def same_values(expected, actual, tolerance=1e-12):
+    """逐字段比较两份CSV，数值允许tolerance的相对误差（参考库与转写实现的求和顺序不同）"""
+    expected_lines, actual_lines = expected.splitlines(), actual.splitlines()
+    if len(expected_lines) != len(actual_lines):
+        return False
+    for want_line, got_line in zip(expected_lines, actual_lines):
+        want_fields, got_fields = want_line.split(","), got_line.split(",")
+        if len(want_fields) != len(got_fields):
+            return False
+        for want, got in zip(want_fields, got_fields):
+            if want == got:
+                continue
+            try:
+                w, g = float(want), float(got)
+            except ValueError:
+                return False
+            if not math.isclose(w, g, rel_tol=tolerance, abs_tol=tolerance):
+                return False
+    return True
+
+
+def main():
+    if not HAVE_REFERENCE:
+        print("未安装 TA-Lib/pandas-ta，使用转写实现生成", file=sys.stderr)
+
+    here = os.path.dirname(os.path.abspath(__file__))
+    outputs = {
+        "golden_ohlcv.csv": render_ohlcv(),
+        "golden_correlation.csv": render_correlation(),
+    }
+
+    if "--check" in sys.argv[1:]:
+        ok = True
+        for name, content in outputs.items():
+            with open(os.path.join(here, name)) as f:
+                if not same_values(f.read(), content):
+                    print("%s 与重新生成的结果不一致" % name, file=sys.stderr)
+                    ok = False
+        sys.exit(0 if ok else 1)
+
+    for name, content in outputs.items():
+        with open(os.path.join(here, name), "w") as f:
+            f.write(content)
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,99 @@
+package indicators
+
+import (
+	"math"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// OBV 计算能量潮指标（On-Balance Volume），返回与输入等长的序列
+func OBV(klines []types.Kline) []float64 {
+	result := make([]float64, len(klines))
+	if len(klines) == 0 {
+		return result
+	}
+
+	// 与TA-Lib/pandas-ta保持一致，以第一根K线的成交量作为起点
+	result[0] = klines[0].Volume
+	for i := 1; i < len(klines); i++ {
+		switch {
+		case klines[i].Close > klines[i-1].Close:
+			result[i] = result[i-1] + klines[i].Volume
+		case klines[i].Close < klines[i-1].Close:
+			result[i] = result[i-1] - klines[i].Volume
+		default:
+			result[i] = result[i-1]
+		}
+	}
+	return result
+}
+
+// CMF 计算蔡金资金流量（Chaikin Money Flow），数据不足period的位置为NaN
+func CMF(klines []types.Kline, period int) []float64 {
+	result := make([]float64, len(klines))
+	for i := range result {
+		result[i] = math.NaN()
+	}
+	if period <= 0 || len(klines) < period {
+		return result
+	}
+
+	// 逐根计算资金流量（Money Flow Volume）
+	mfv := make([]float64, len(klines))
+	for i, k := range klines {
+		mfv[i] = moneyFlowMultiplier(k) * k.Volume
+	}
+
+	// 滑动窗口累加，避免重复求和
+	var sumMFV, sumVolume float64
+	for i := range klines {
+		sumMFV += mfv[i]
+		sumVolume += klines[i].Volume
+		if i >= period {
+			sumMFV -= mfv[i-period]
+			sumVolume -= klines[i-period].Volume
+		}
+		if i >= period-1 {
+			if sumVolume == 0 {
+				result[i] = 0
+			} else {
+				result[i] = sumMFV / sumVolume
+			}
+		}
+	}
+	return result
+}
+
+// moneyFlowMultiplier 资金流量乘数，范围[-1, 1]，收盘越靠近最高价越接近1
+func moneyFlowMultiplier(k types.Kline) float64 {
+	hl := k.High - k.Low
+	if hl == 0 {
+		// 一字线没有方向信息，与TA-Lib AD一致记0资金流量，成交量仍计入CMF分母
+		// pandas-ta cmf 在此处直接除以0，黄金文件以TA-Lib为准，见 testdata/gen_golden.py
+		return 0
+	}
+	return ((k.Close - k.Low) - (k.High - k.Close)) / hl
+}
+
+// VolumeFlowConfirms 判断量能流向是否确认突破方向
+// bullish为true时要求CMF为正且OBV在period内上升，反之要求CMF为负且OBV下降
+func VolumeFlowConfirms(klines []types.Kline, period int, bullish bool) bool {
+	if period <= 0 || len(klines) <= period {
+		return false // 数据不足，无法确认
+	}
+
+	cmf := CMF(klines, period)
+	obv := OBV(klines)
+
+	last := len(klines) - 1
+	cmfValue := cmf[last]
+	obvDelta := obv[last] - obv[last-period]
+
+	if math.IsNaN(cmfValue) {
+		return false
+	}
+	if bullish {
+		return cmfValue > 0 && obvDelta > 0
+	}
+	return cmfValue < 0 && obvDelta < 0
+}
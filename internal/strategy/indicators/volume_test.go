@@ -0,0 +1,63 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// trendKlines 生成收盘价单边变化的K线，step>0时收于最高价，step<0时收于最低价
+func trendKlines(n int, step float64) []types.Kline {
+	klines := make([]types.Kline, n)
+	price := 100.0
+	for i := range klines {
+		next := price + step
+		klines[i] = types.Kline{
+			Open:   price,
+			High:   max(price, next),
+			Low:    min(price, next),
+			Close:  next,
+			Volume: 1000,
+		}
+		price = next
+	}
+	return klines
+}
+
+func TestVolumeFlowConfirms(t *testing.T) {
+	tests := []struct {
+		name    string
+		klines  []types.Kline
+		period  int
+		bullish bool
+		want    bool
+	}{
+		{"上涨确认看涨", trendKlines(6, 1), 3, true, true},
+		{"上涨不确认看跌", trendKlines(6, 1), 3, false, false},
+		{"下跌确认看跌", trendKlines(6, -1), 3, false, true},
+		{"下跌不确认看涨", trendKlines(6, -1), 3, true, false},
+		{"数据恰好等于周期", trendKlines(3, 1), 3, true, false},
+		{"周期非法", trendKlines(6, 1), 0, true, false},
+		{"平盘", trendKlines(6, 0), 3, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VolumeFlowConfirms(tt.klines, tt.period, tt.bullish); got != tt.want {
+				t.Errorf("VolumeFlowConfirms() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCMFFlatBar(t *testing.T) {
+	klines := []types.Kline{
+		{Open: 100, High: 101, Low: 100, Close: 101, Volume: 1000},
+		{Open: 101, High: 101, Low: 101, Close: 101, Volume: 1000}, // 一字线
+	}
+	// 一字线资金流量为0，但成交量计入分母
+	got := CMF(klines, 2)
+	if !math.IsNaN(got[0]) || got[1] != 0.5 {
+		t.Errorf("CMF() = %v, want [NaN 0.5]", got)
+	}
+}
@@ -0,0 +1,122 @@
+package subscription
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+	"okx-market-sentry/pkg/types"
+)
+
+// redisKey 订阅保存在Redis哈希中，字段为订阅ID，值为订阅的JSON
+const redisKey = "okx:subscriptions"
+
+// Store 订阅的持久化存储
+type Store interface {
+	List(ctx context.Context) ([]*types.Subscription, error)
+	Save(ctx context.Context, sub *types.Subscription) error
+	Delete(ctx context.Context, id string) (bool, error)
+}
+
+// NewStore 启用Redis时订阅保存在Redis中，跨重启保留并在多个实例间共享，否则保存在内存中
+func NewStore(client *redis.Client) Store {
+	if client == nil {
+		log().Warn("⚠️ 未启用Redis，用户订阅只保存在内存中，重启后丢失")
+		return NewMemoryStore()
+	}
+	return &RedisStore{client: client}
+}
+
+// RedisStore 基于Redis哈希的订阅存储
+type RedisStore struct {
+	client *redis.Client
+}
+
+func (rs *RedisStore) List(ctx context.Context) ([]*types.Subscription, error) {
+	values, err := rs.client.HGetAll(ctx, redisKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("读取订阅失败: %v", err)
+	}
+
+	subs := make([]*types.Subscription, 0, len(values))
+	for id, value := range values {
+		var sub types.Subscription
+		if err := json.Unmarshal([]byte(value), &sub); err != nil {
+			log().Warn("忽略无法解析的订阅", zap.String("id", id), zap.Error(err))
+			continue
+		}
+		subs = append(subs, &sub)
+	}
+	sortSubscriptions(subs)
+	return subs, nil
+}
+
+func (rs *RedisStore) Save(ctx context.Context, sub *types.Subscription) error {
+	value, err := json.Marshal(sub)
+	if err != nil {
+		return fmt.Errorf("序列化订阅失败: %v", err)
+	}
+	if err := rs.client.HSet(ctx, redisKey, sub.ID, value).Err(); err != nil {
+		return fmt.Errorf("保存订阅失败: %v", err)
+	}
+	return nil
+}
+
+func (rs *RedisStore) Delete(ctx context.Context, id string) (bool, error) {
+	n, err := rs.client.HDel(ctx, redisKey, id).Result()
+	if err != nil {
+		return false, fmt.Errorf("删除订阅失败: %v", err)
+	}
+	return n > 0, nil
+}
+
+// MemoryStore 内存中的订阅存储，未启用Redis时使用
+type MemoryStore struct {
+	mutex sync.RWMutex
+	subs  map[string]types.Subscription
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{subs: make(map[string]types.Subscription)}
+}
+
+func (ms *MemoryStore) List(ctx context.Context) ([]*types.Subscription, error) {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	subs := make([]*types.Subscription, 0, len(ms.subs))
+	for _, sub := range ms.subs {
+		subs = append(subs, &sub)
+	}
+	sortSubscriptions(subs)
+	return subs, nil
+}
+
+func (ms *MemoryStore) Save(ctx context.Context, sub *types.Subscription) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+	ms.subs[sub.ID] = *sub
+	return nil
+}
+
+func (ms *MemoryStore) Delete(ctx context.Context, id string) (bool, error) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+	_, ok := ms.subs[id]
+	delete(ms.subs, id)
+	return ok, nil
+}
+
+// sortSubscriptions 按用户、交易对排序，列表输出稳定
+func sortSubscriptions(subs []*types.Subscription) {
+	sort.Slice(subs, func(i, j int) bool {
+		if subs[i].UserID != subs[j].UserID {
+			return subs[i].UserID < subs[j].UserID
+		}
+		return subs[i].Symbol < subs[j].Symbol
+	})
+}
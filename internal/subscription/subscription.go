@@ -0,0 +1,286 @@
+package subscription
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"okx-market-sentry/internal/notifier"
+	"okx-market-sentry/pkg/logger"
+	"okx-market-sentry/pkg/types"
+)
+
+// log 模块日志器，级别可通过 log.modules.subscription 单独配置
+func log() *zap.Logger {
+	return logger.Named("subscription")
+}
+
+var (
+	ErrUnknownSymbol = errors.New("未监控该交易对")
+	ErrThreshold     = errors.New("阈值低于允许的最小值")
+	ErrPeriod        = errors.New("无效的监控周期")
+	ErrLimit         = errors.New("订阅数量已达上限")
+	ErrNotFound      = errors.New("订阅不存在")
+)
+
+// PriceSource 价格窗口数据，由 storage.StateManager 提供
+type PriceSource interface {
+	GetPriceData(symbol string, window time.Duration) (*types.PriceDataPoint, *types.PriceDataPoint)
+}
+
+// Sender 向用户推送消息，userID为Telegram chat_id
+type Sender interface {
+	SendMessage(ctx context.Context, userID, text string) error
+}
+
+// Manager 管理用户订阅，每轮检查各订阅的窗口涨跌幅，达到阈值时只推送给订阅的用户
+type Manager struct {
+	config types.SubscriptionConfig
+	store  Store
+	prices PriceSource
+	sender Sender
+	dryRun bool // 演练模式，预警只记录日志不推送
+
+	mutex     sync.Mutex
+	lastAlert map[string]time.Time // 订阅ID -> 最近一次预警时间，冷却期为订阅的监控周期
+}
+
+func NewManager(config types.SubscriptionConfig, store Store, prices PriceSource, sender Sender, dryRun bool) *Manager {
+	return &Manager{
+		config:    config,
+		store:     store,
+		prices:    prices,
+		sender:    sender,
+		dryRun:    dryRun,
+		lastAlert: make(map[string]time.Time),
+	}
+}
+
+// Start 按间隔检查订阅，直到ctx取消，间隔通常与行情获取间隔相同
+func (m *Manager) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	log().Info("🔔 用户订阅已启动", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			log().Info("📴 用户订阅已停止")
+			return
+		case <-ticker.C:
+			m.Check(ctx)
+		}
+	}
+}
+
+// Add 为用户订阅交易对，同一用户重复订阅同一交易对时更新阈值和周期
+// symbol 只写基础币种时按USDT交易对处理，period为0时使用默认周期
+func (m *Manager) Add(ctx context.Context, userID, symbol string, threshold float64, period time.Duration) (*types.Subscription, error) {
+	if period == 0 {
+		period = m.config.DefaultPeriod
+	}
+	if period < time.Minute || period > m.config.MaxPeriod {
+		return nil, fmt.Errorf("%w: %s 需在1m到%s之间", ErrPeriod, period, m.config.MaxPeriod)
+	}
+	if threshold < m.config.MinThreshold {
+		return nil, fmt.Errorf("%w: %.2f%% < %.2f%%", ErrThreshold, threshold, m.config.MinThreshold)
+	}
+	symbol, ok := m.resolveSymbol(symbol)
+	if !ok {
+		return nil, ErrUnknownSymbol
+	}
+
+	subs, err := m.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sub := &types.Subscription{UserID: userID, Symbol: symbol, CreatedAt: time.Now()}
+	for _, existing := range subs {
+		if existing.Symbol == symbol {
+			sub = existing
+			break
+		}
+	}
+	if sub.ID == "" {
+		if len(subs) >= m.config.MaxPerUser {
+			return nil, fmt.Errorf("%w: 最多 %d 个", ErrLimit, m.config.MaxPerUser)
+		}
+		sub.ID = newID()
+	}
+	sub.Threshold = threshold
+	sub.Period = period
+
+	if err := m.store.Save(ctx, sub); err != nil {
+		return nil, err
+	}
+	log().Info("➕ 用户订阅已保存",
+		zap.String("user_id", userID),
+		zap.String("symbol", symbol),
+		zap.Float64("threshold", threshold),
+		zap.Duration("period", period))
+	return sub, nil
+}
+
+// List 用户的全部订阅，userID为空时返回所有用户的订阅
+func (m *Manager) List(ctx context.Context, userID string) ([]*types.Subscription, error) {
+	subs, err := m.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		return subs, nil
+	}
+	result := make([]*types.Subscription, 0, len(subs))
+	for _, sub := range subs {
+		if sub.UserID == userID {
+			result = append(result, sub)
+		}
+	}
+	return result, nil
+}
+
+// Delete 按ID删除订阅
+func (m *Manager) Delete(ctx context.Context, id string) error {
+	ok, err := m.store.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotFound
+	}
+	m.mutex.Lock()
+	delete(m.lastAlert, id)
+	m.mutex.Unlock()
+	log().Info("➖ 用户订阅已删除", zap.String("id", id))
+	return nil
+}
+
+// RemoveSymbol 删除用户对交易对的订阅，symbol为空时删除该用户的全部订阅，返回删除的数量
+func (m *Manager) RemoveSymbol(ctx context.Context, userID, symbol string) (int, error) {
+	subs, err := m.List(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if symbol != "" {
+		symbol = strings.ToUpper(symbol)
+	}
+
+	removed := 0
+	for _, sub := range subs {
+		if symbol != "" && sub.Symbol != symbol && sub.Symbol != symbol+"-USDT" {
+			continue
+		}
+		if err := m.Delete(ctx, sub.ID); err != nil && !errors.Is(err, ErrNotFound) {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// Check 检查全部订阅，按用户合并本轮触发的预警后推送
+func (m *Manager) Check(ctx context.Context) {
+	subs, err := m.store.List(ctx)
+	if err != nil {
+		log().Warn("⚠️ 读取用户订阅失败，跳过本轮检查", zap.Error(err))
+		return
+	}
+
+	now := time.Now()
+	byUser := make(map[string][]*types.AlertData)
+	var users []string
+	for _, sub := range subs {
+		alert := m.evaluate(sub, now)
+		if alert == nil {
+			continue
+		}
+		if _, ok := byUser[sub.UserID]; !ok {
+			users = append(users, sub.UserID)
+		}
+		byUser[sub.UserID] = append(byUser[sub.UserID], alert)
+	}
+
+	for _, userID := range users {
+		alerts := byUser[userID]
+		texts := make([]string, 0, len(alerts))
+		for _, alert := range alerts {
+			texts = append(texts, notifier.PlainText(alert))
+		}
+		text := strings.Join(texts, "\n\n")
+
+		if m.dryRun {
+			log().Info("📝 [演练] 用户订阅预警", zap.String("user_id", userID), zap.String("content", text))
+			continue
+		}
+		if err := m.sender.SendMessage(ctx, userID, text); err != nil {
+			log().Error("❌ 用户订阅预警发送失败",
+				zap.String("user_id", userID),
+				zap.Int("alert_count", len(alerts)),
+				zap.Error(err))
+			continue
+		}
+		log().Info("✅ 用户订阅预警已发送", zap.String("user_id", userID), zap.Int("alert_count", len(alerts)))
+	}
+}
+
+// evaluate 订阅的窗口涨跌幅达到阈值且不在冷却期时返回预警，并开始冷却
+func (m *Manager) evaluate(sub *types.Subscription, now time.Time) *types.AlertData {
+	current, past := m.prices.GetPriceData(sub.Symbol, sub.Period)
+	if current == nil || past == nil || past.Price == 0 {
+		return nil
+	}
+	change := (current.Price - past.Price) / past.Price * 100
+	if math.Abs(change) < sub.Threshold {
+		return nil
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if last, ok := m.lastAlert[sub.ID]; ok && now.Sub(last) < sub.Period {
+		return nil
+	}
+	m.lastAlert[sub.ID] = now
+
+	return &types.AlertData{
+		Symbol:        sub.Symbol,
+		CurrentPrice:  current.Price,
+		PastPrice:     past.Price,
+		ChangePercent: change,
+		AlertTime:     now,
+		MonitorPeriod: sub.Period,
+		PriceTime:     current.Timestamp,
+		Profile:       sub.ID,
+		Kind:          types.AlertKindSubscription,
+	}
+}
+
+// resolveSymbol 将用户输入转为监控中的交易对，只输入基础币种时按USDT交易对查找
+func (m *Manager) resolveSymbol(input string) (string, bool) {
+	symbol := strings.ToUpper(strings.TrimSpace(input))
+	if current, _ := m.prices.GetPriceData(symbol, 0); current != nil {
+		return symbol, true
+	}
+	if !strings.Contains(symbol, "-") {
+		if current, _ := m.prices.GetPriceData(symbol+"-USDT", 0); current != nil {
+			return symbol + "-USDT", true
+		}
+	}
+	return "", false
+}
+
+// newID 生成随机的订阅ID
+func newID() string {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,136 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"okx-market-sentry/internal/storage"
+	"okx-market-sentry/pkg/types"
+)
+
+// recordingSender 记录推送给各用户的消息
+type recordingSender struct {
+	messages map[string][]string
+}
+
+func (s *recordingSender) SendMessage(ctx context.Context, userID, text string) error {
+	s.messages[userID] = append(s.messages[userID], text)
+	return nil
+}
+
+// newTestManager BTC 5分钟上涨2%、ETH下跌4%
+func newTestManager(t *testing.T) (*Manager, *recordingSender) {
+	t.Helper()
+	stateManager := storage.NewStateManager(types.RedisConfig{}, time.Hour, time.Hour)
+	now := time.Now()
+	for symbol, change := range map[string]float64{"BTC-USDT": 2, "ETH-USDT": -4} {
+		stateManager.Store(symbol, 100, now.Add(-5*time.Minute))
+		stateManager.Store(symbol, 100*(1+change/100), now)
+	}
+	sender := &recordingSender{messages: make(map[string][]string)}
+	manager := NewManager(types.SubscriptionConfig{
+		MaxPerUser:    2,
+		MinThreshold:  1,
+		DefaultPeriod: 5 * time.Minute,
+		MaxPeriod:     time.Hour,
+	}, NewMemoryStore(), stateManager, sender, false)
+	return manager, sender
+}
+
+func TestManagerAdd(t *testing.T) {
+	manager, _ := newTestManager(t)
+	ctx := context.Background()
+
+	sub, err := manager.Add(ctx, "1001", "btc", 3, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.Symbol != "BTC-USDT" || sub.Period != 5*time.Minute {
+		t.Errorf("订阅 = %+v", sub)
+	}
+	// 重复订阅同一交易对时更新阈值
+	if again, err := manager.Add(ctx, "1001", "BTC-USDT", 1.5, 0); err != nil || again.ID != sub.ID || again.Threshold != 1.5 {
+		t.Errorf("重复订阅 = %+v, %v", again, err)
+	}
+
+	for _, tc := range []struct {
+		symbol    string
+		threshold float64
+		period    time.Duration
+		want      error
+	}{
+		{"DOGE", 3, 0, ErrUnknownSymbol},
+		{"ETH", 0.5, 0, ErrThreshold},
+		{"ETH", 3, 2 * time.Hour, ErrPeriod},
+	} {
+		if _, err := manager.Add(ctx, "1001", tc.symbol, tc.threshold, tc.period); !errors.Is(err, tc.want) {
+			t.Errorf("Add(%s, %v, %s) = %v, want %v", tc.symbol, tc.threshold, tc.period, err, tc.want)
+		}
+	}
+
+	if _, err := manager.Add(ctx, "1001", "ETH", 3, 0); err != nil {
+		t.Fatal(err)
+	}
+	manager.prices.(*storage.StateManager).Store("SOL-USDT", 100, time.Now())
+	if _, err := manager.Add(ctx, "1001", "SOL", 3, 0); !errors.Is(err, ErrLimit) {
+		t.Errorf("超过上限 = %v, want ErrLimit", err)
+	}
+}
+
+func TestManagerCheck(t *testing.T) {
+	manager, sender := newTestManager(t)
+	ctx := context.Background()
+	for _, sub := range []struct {
+		user, symbol string
+		threshold    float64
+	}{
+		{"1001", "BTC", 1.5}, // 触发
+		{"1001", "ETH", 3},   // 触发
+		{"2002", "BTC", 5},   // 未达到阈值
+	} {
+		if _, err := manager.Add(ctx, sub.user, sub.symbol, sub.threshold, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manager.Check(ctx)
+	if len(sender.messages["1001"]) != 1 || len(sender.messages["2002"]) != 0 {
+		t.Fatalf("messages = %v", sender.messages)
+	}
+	text := sender.messages["1001"][0]
+	if !strings.Contains(text, "订阅预警 - BTC-USDT") || !strings.Contains(text, "ETH-USDT") || !strings.Contains(text, "-4.00%") {
+		t.Errorf("推送内容:\n%s", text)
+	}
+
+	// 冷却期内不重复推送
+	manager.Check(ctx)
+	if len(sender.messages["1001"]) != 1 {
+		t.Errorf("冷却期内推送了 %d 次", len(sender.messages["1001"]))
+	}
+}
+
+func TestHandleCommand(t *testing.T) {
+	manager, _ := newTestManager(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"/list", "暂无订阅"},
+		{"/sub@okx_sentry_bot BTC 2% 15m", "已订阅 BTC-USDT：15m0s内涨跌幅达到 2.00%"},
+		{"/sub DOGE 2", "未监控交易对 DOGE"},
+		{"/sub BTC", "用法"},
+		{"/list", "BTC-USDT  2.00% / 15m0s"},
+		{"/unsub all", "已取消 1 个订阅"},
+		{"/price BTC", "未知命令 /price"},
+	}
+	for _, tt := range tests {
+		if got := handleCommand(ctx, manager, "1001", tt.text); !strings.Contains(got, tt.want) {
+			t.Errorf("%s = %q, want 包含 %q", tt.text, got, tt.want)
+		}
+	}
+}
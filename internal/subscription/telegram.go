@@ -0,0 +1,244 @@
+package subscription
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"okx-market-sentry/pkg/types"
+)
+
+const (
+	defaultTelegramAPI = "https://api.telegram.org"
+	pollTimeout        = 30 * time.Second // getUpdates长轮询的等待时长
+	retryDelay         = 5 * time.Second  // 拉取命令失败后的重试间隔
+)
+
+// telegramHelp 机器人支持的命令
+const telegramHelp = `可用命令：
+/sub BTC 3 [15m] - 订阅交易对，窗口涨跌幅达到3%时推送，可指定监控周期
+/unsub BTC - 取消订阅，/unsub all 取消全部
+/list - 查看我的订阅
+/help - 帮助`
+
+// Bot Telegram机器人：长轮询接收订阅命令，并向用户推送订阅预警
+type Bot struct {
+	token      string
+	apiURL     string
+	httpClient *http.Client
+	offset     int64 // 下一次拉取的update_id，仅在轮询goroutine中访问
+}
+
+// telegramResponse Bot API的通用响应
+type telegramResponse struct {
+	OK          bool            `json:"ok"`
+	Description string          `json:"description"`
+	Result      json.RawMessage `json:"result"`
+}
+
+// telegramUpdate 只解析用到的字段
+type telegramUpdate struct {
+	UpdateID int64 `json:"update_id"`
+	Message  *struct {
+		Text string `json:"text"`
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+		From struct {
+			Username string `json:"username"`
+		} `json:"from"`
+	} `json:"message"`
+}
+
+// NewBot 创建Telegram机器人，proxy为空时使用环境变量中的代理
+func NewBot(config types.TelegramBotConfig, proxy string) *Bot {
+	apiURL := config.APIURL
+	if apiURL == "" {
+		apiURL = defaultTelegramAPI
+	}
+
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if proxy != "" {
+		if proxyURL, err := url.Parse(proxy); err == nil {
+			transport.Proxy = http.ProxyURL(proxyURL)
+		}
+	}
+	return &Bot{
+		token:  config.BotToken,
+		apiURL: strings.TrimRight(apiURL, "/"),
+		httpClient: &http.Client{
+			Timeout:   pollTimeout + 10*time.Second,
+			Transport: transport,
+		},
+	}
+}
+
+// Start 长轮询接收命令并交给manager处理，直到ctx取消
+func (b *Bot) Start(ctx context.Context, manager *Manager) {
+	log().Info("🤖 Telegram订阅机器人已启动")
+	for {
+		updates, err := b.getUpdates(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				log().Info("📴 Telegram订阅机器人已停止")
+				return
+			}
+			log().Warn("⚠️ 拉取Telegram消息失败", zap.Error(err))
+			select {
+			case <-ctx.Done():
+				log().Info("📴 Telegram订阅机器人已停止")
+				return
+			case <-time.After(retryDelay):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			b.offset = update.UpdateID + 1
+			if update.Message == nil || !strings.HasPrefix(update.Message.Text, "/") {
+				continue
+			}
+			chatID := strconv.FormatInt(update.Message.Chat.ID, 10)
+			log().Info("💬 收到Telegram订阅命令",
+				zap.String("user_id", chatID),
+				zap.String("username", update.Message.From.Username),
+				zap.String("text", update.Message.Text))
+			reply := handleCommand(ctx, manager, chatID, update.Message.Text)
+			if err := b.SendMessage(ctx, chatID, reply); err != nil {
+				log().Warn("⚠️ 回复Telegram命令失败", zap.String("user_id", chatID), zap.Error(err))
+			}
+		}
+	}
+}
+
+// handleCommand 执行订阅命令，返回回复内容
+func handleCommand(ctx context.Context, manager *Manager, userID, text string) string {
+	fields := strings.Fields(text)
+	// 群聊中的命令带有机器人用户名，如 /sub@okx_sentry_bot
+	command, _, _ := strings.Cut(fields[0], "@")
+	args := fields[1:]
+
+	switch command {
+	case "/start", "/help":
+		return telegramHelp
+	case "/sub":
+		if len(args) < 2 {
+			return "用法：/sub BTC 3 [15m]"
+		}
+		threshold, err := strconv.ParseFloat(strings.TrimSuffix(args[1], "%"), 64)
+		if err != nil || threshold <= 0 {
+			return fmt.Sprintf("无效的阈值 %s", args[1])
+		}
+		var period time.Duration
+		if len(args) > 2 {
+			if period, err = time.ParseDuration(args[2]); err != nil {
+				return fmt.Sprintf("无效的监控周期 %s，如 5m、1h", args[2])
+			}
+		}
+		sub, err := manager.Add(ctx, userID, args[0], threshold, period)
+		if err != nil {
+			if errors.Is(err, ErrUnknownSymbol) {
+				return fmt.Sprintf("未监控交易对 %s", args[0])
+			}
+			return "订阅失败：" + err.Error()
+		}
+		return fmt.Sprintf("✅ 已订阅 %s：%s内涨跌幅达到 %.2f%% 时推送", sub.Symbol, sub.Period, sub.Threshold)
+	case "/unsub":
+		if len(args) == 0 {
+			return "用法：/unsub BTC 或 /unsub all"
+		}
+		symbol := args[0]
+		if strings.EqualFold(symbol, "all") {
+			symbol = ""
+		}
+		removed, err := manager.RemoveSymbol(ctx, userID, symbol)
+		if err != nil {
+			return "取消订阅失败：" + err.Error()
+		}
+		if removed == 0 {
+			return "没有匹配的订阅"
+		}
+		return fmt.Sprintf("🗑 已取消 %d 个订阅", removed)
+	case "/list":
+		subs, err := manager.List(ctx, userID)
+		if err != nil {
+			return "读取订阅失败：" + err.Error()
+		}
+		if len(subs) == 0 {
+			return "暂无订阅，发送 /sub BTC 3 订阅"
+		}
+		lines := []string{fmt.Sprintf("我的订阅（%d个）：", len(subs))}
+		for _, sub := range subs {
+			lines = append(lines, fmt.Sprintf("• %s  %.2f%% / %s", sub.Symbol, sub.Threshold, sub.Period))
+		}
+		return strings.Join(lines, "\n")
+	default:
+		return "未知命令 " + command + "\n\n" + telegramHelp
+	}
+}
+
+// getUpdates 长轮询拉取新消息
+func (b *Bot) getUpdates(ctx context.Context) ([]telegramUpdate, error) {
+	var updates []telegramUpdate
+	err := b.call(ctx, "getUpdates", map[string]any{
+		"offset":          b.offset,
+		"timeout":         int(pollTimeout.Seconds()),
+		"allowed_updates": []string{"message"},
+	}, &updates)
+	return updates, err
+}
+
+// SendMessage 向用户发送纯文本消息
+func (b *Bot) SendMessage(ctx context.Context, userID, text string) error {
+	return b.call(ctx, "sendMessage", map[string]any{
+		"chat_id":                  userID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	}, nil)
+}
+
+// call 调用Bot API方法，result非nil时解析返回结果
+func (b *Bot) call(ctx context.Context, method string, params map[string]any, result any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("序列化请求数据失败: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.apiURL+"/bot"+b.token+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		// 错误信息中的URL包含令牌，不直接输出
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("HTTP请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var tgResp telegramResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tgResp); err != nil {
+		return fmt.Errorf("解析响应失败: %v", err)
+	}
+	if !tgResp.OK {
+		return fmt.Errorf("Telegram API错误 [%d]: %s", resp.StatusCode, tgResp.Description)
+	}
+	if result != nil {
+		if err := json.Unmarshal(tgResp.Result, result); err != nil {
+			return fmt.Errorf("解析响应失败: %v", err)
+		}
+	}
+	return nil
+}
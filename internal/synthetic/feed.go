@@ -0,0 +1,86 @@
+package synthetic
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Config 合成行情参数
+type Config struct {
+	Symbols     int     // 交易对数量，命名为 SYN0-USDT、SYN1-USDT…
+	StartPrice  float64 // 初始价格
+	Volatility  float64 // 每步随机游走的标准差（百分比）
+	PumpEvery   int     // 每隔多少步向一个交易对注入一次异动，0为不注入
+	PumpSize    float64 // 注入异动的幅度（百分比），依次为拉升和砸盘
+	WarmupSteps int     // 前若干步不注入异动，等待分析窗口的数据收集完整
+	Seed        int64   // 随机种子，相同种子生成相同的行情
+}
+
+// Event 注入的异动
+type Event struct {
+	Symbol string
+	Step   int
+	Time   time.Time
+	Change float64 // 涨跌幅（百分比）
+}
+
+// Feed 合成行情：各交易对独立随机游走，按间隔轮流向交易对注入一步完成的拉升/砸盘，
+// 用于浸泡测试和离线演示，注入的异动可与触发的预警逐一核对
+type Feed struct {
+	config  Config
+	rng     *rand.Rand
+	symbols []string
+	prices  []float64
+	step    int
+	next    int // 下一个注入异动的交易对
+	events  []Event
+}
+
+func NewFeed(config Config) *Feed {
+	f := &Feed{
+		config:  config,
+		rng:     rand.New(rand.NewSource(config.Seed)),
+		symbols: make([]string, config.Symbols),
+		prices:  make([]float64, config.Symbols),
+	}
+	for i := range f.symbols {
+		f.symbols[i] = fmt.Sprintf("SYN%d-USDT", i)
+		f.prices[i] = config.StartPrice
+	}
+	return f
+}
+
+// Symbols 全部交易对
+func (f *Feed) Symbols() []string {
+	return f.symbols
+}
+
+// Next 推进一步并返回各交易对的最新价格，到达注入间隔时对下一个交易对注入异动
+func (f *Feed) Next(now time.Time) map[string]float64 {
+	f.step++
+	for i := range f.prices {
+		f.prices[i] *= 1 + f.rng.NormFloat64()*f.config.Volatility/100
+	}
+
+	if f.config.PumpEvery > 0 && len(f.symbols) > 0 && f.step > f.config.WarmupSteps && f.step%f.config.PumpEvery == 0 {
+		change := f.config.PumpSize
+		if len(f.events)%2 == 1 {
+			change = -change
+		}
+		f.prices[f.next] *= 1 + change/100
+		f.events = append(f.events, Event{Symbol: f.symbols[f.next], Step: f.step, Time: now, Change: change})
+		f.next = (f.next + 1) % len(f.symbols)
+	}
+
+	prices := make(map[string]float64, len(f.symbols))
+	for i, symbol := range f.symbols {
+		prices[symbol] = f.prices[i]
+	}
+	return prices
+}
+
+// Events 已注入的异动
+func (f *Feed) Events() []Event {
+	return f.events
+}
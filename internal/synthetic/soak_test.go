@@ -0,0 +1,159 @@
+package synthetic
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"okx-market-sentry/internal/analyzer"
+	"okx-market-sentry/internal/slo"
+	"okx-market-sentry/internal/storage"
+	"okx-market-sentry/pkg/types"
+)
+
+// countingNotifier 按交易对统计收到的预警
+type countingNotifier struct {
+	mutex  sync.Mutex
+	counts map[string]int
+	total  int
+}
+
+func (n *countingNotifier) SendAlert(alert *types.AlertData) error {
+	return n.SendBatchAlerts([]*types.AlertData{alert})
+}
+
+func (n *countingNotifier) SendBatchAlerts(alerts []*types.AlertData) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for _, alert := range alerts {
+		n.counts[alert.Symbol]++
+		n.total++
+	}
+	return nil
+}
+
+func (n *countingNotifier) SendOpsAlert(*types.OpsAlert) error { return nil }
+func (n *countingNotifier) SendNotice(*types.Notice) error     { return nil }
+
+func TestFeedDeterministic(t *testing.T) {
+	config := Config{Symbols: 3, StartPrice: 100, Volatility: 0.1, PumpEvery: 2, PumpSize: 5, Seed: 7}
+	a, b := NewFeed(config), NewFeed(config)
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		pa, pb := a.Next(now), b.Next(now)
+		for symbol, price := range pa {
+			if pb[symbol] != price {
+				t.Fatalf("step %d %s: %v != %v", i, symbol, price, pb[symbol])
+			}
+		}
+	}
+
+	events := a.Events()
+	if len(events) != 5 {
+		t.Fatalf("got %d events, want 5", len(events))
+	}
+	if events[0].Symbol != "SYN0-USDT" || events[0].Change != 5 || events[1].Symbol != "SYN1-USDT" || events[1].Change != -5 {
+		t.Errorf("unexpected events: %+v", events[:2])
+	}
+}
+
+// TestSoak 用合成行情长时间驱动存储和分析引擎，时间尺度按比例缩小（20ms一步，400ms监控周期），
+// 检查注入的每次异动恰好触发一次预警、没有协程泄漏、内存不随运行时长增长
+// 默认运行3秒，浸泡测试通过 SOAK_DURATION=2h make soak 指定时长
+func TestSoak(t *testing.T) {
+	if testing.Short() {
+		t.Skip("浸泡测试在 -short 下跳过")
+	}
+	duration := 3 * time.Second
+	if value := os.Getenv("SOAK_DURATION"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			t.Fatalf("SOAK_DURATION: %v", err)
+		}
+		duration = d
+	}
+
+	const (
+		tick   = 20 * time.Millisecond
+		period = 400 * time.Millisecond
+	)
+	baseGoroutines := runtime.NumGoroutine()
+
+	// 每个交易对每 50*5 步（5秒）注入一次异动，远大于监控周期，相邻异动互不影响
+	feed := NewFeed(Config{
+		Symbols:     50,
+		StartPrice:  100,
+		Volatility:  0.005,
+		PumpEvery:   5,
+		PumpSize:    5,
+		WarmupSteps: int(2 * period / tick),
+		Seed:        1,
+	})
+	stateManager := storage.NewStateManager(types.RedisConfig{}, period, period)
+	counter := &countingNotifier{counts: make(map[string]int)}
+	engine := analyzer.NewAnalysisEngine(stateManager, counter,
+		types.AlertConfig{Threshold: 3, MonitorPeriod: period, Workers: 4}, slo.NewTracker())
+
+	ctx := context.Background()
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	deadline := time.Now().Add(duration)
+	var baselineHeap uint64
+	for step := 0; time.Now().Before(deadline); step++ {
+		<-ticker.C
+		now := time.Now()
+		for symbol, price := range feed.Next(now) {
+			stateManager.Store(symbol, price, now)
+		}
+		engine.AnalyzeAll(ctx)
+
+		// 预热完成后记录内存基线
+		if baselineHeap == 0 && time.Until(deadline) < duration*2/3 {
+			baselineHeap = heapAlloc()
+		}
+	}
+	// 最后一步注入的异动在同一轮分析中已触发，无需等待
+
+	events := feed.Events()
+	if len(events) == 0 {
+		t.Fatal("没有注入异动")
+	}
+	want := make(map[string]int)
+	for _, event := range events {
+		want[event.Symbol]++
+	}
+	counter.mutex.Lock()
+	if counter.total != len(events) {
+		t.Errorf("注入 %d 次异动，触发 %d 个预警", len(events), counter.total)
+	}
+	for symbol, count := range want {
+		if counter.counts[symbol] != count {
+			t.Errorf("%s: 注入 %d 次异动，触发 %d 个预警", symbol, count, counter.counts[symbol])
+		}
+	}
+	counter.mutex.Unlock()
+
+	if endHeap := heapAlloc(); endHeap > baselineHeap*3/2+4<<20 {
+		t.Errorf("内存持续增长: 基线 %d 字节，结束时 %d 字节", baselineHeap, endHeap)
+	}
+
+	// 分析结束后不应残留协程
+	for wait := 0; runtime.NumGoroutine() > baseGoroutines && wait < 100; wait++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > baseGoroutines {
+		t.Errorf("协程泄漏: 开始 %d 个，结束 %d 个", baseGoroutines, n)
+	}
+	t.Logf("运行 %s，注入 %d 次异动，触发 %d 个预警", duration, len(events), counter.total)
+}
+
+// heapAlloc GC后的堆内存占用
+func heapAlloc() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
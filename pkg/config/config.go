@@ -1,15 +1,25 @@
 package config
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/spf13/viper"
 	"okx-market-sentry/pkg/types"
 )
 
+// Options 命令行传入的配置选项，优先级高于配置文件
+type Options struct {
+	ConfigFile string // 指定配置文件路径，为空时按默认位置查找
+	LogLevel   string // 覆盖配置文件中的日志级别
+	DryRun     bool   // 开启演练模式
+	NoEmoji    bool   // 控制台纯文本输出
+}
+
 // Load 加载配置
-func Load() (*types.Config, error) {
+func Load(opts Options) (*types.Config, error) {
 	viper.SetConfigType("yaml")
 	viper.AddConfigPath("./configs")
 	viper.AddConfigPath(".")
@@ -20,6 +30,28 @@ func Load() (*types.Config, error) {
 	// 读取环境变量
 	viper.AutomaticEnv()
 
+	// 命令行参数优先级最高，热加载时同样保持覆盖
+	if opts.LogLevel != "" {
+		viper.Set("log.level", opts.LogLevel)
+	}
+	if opts.DryRun {
+		viper.Set("dry_run", true)
+	}
+	if opts.NoEmoji {
+		viper.Set("console.no_emoji", true)
+	}
+
+	if opts.ConfigFile != "" {
+		viper.SetConfigFile(opts.ConfigFile)
+		if err := viper.ReadInConfig(); err != nil {
+			return nil, err
+		}
+		if err := applyRemote(); err != nil {
+			return nil, err
+		}
+		return decode()
+	}
+
 	// 优先尝试读取本地配置文件
 	viper.SetConfigName("config.local")
 	if err := viper.ReadInConfig(); err != nil {
@@ -33,22 +65,66 @@ func Load() (*types.Config, error) {
 		}
 	}
 
+	if err := applyRemote(); err != nil {
+		return nil, err
+	}
+
+	return decode()
+}
+
+// applyRemote 配置了远程配置中心时，拉取远程配置覆盖本地配置
+func applyRemote() error {
+	rc, err := remoteSettings()
+	if err != nil || rc == nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := rc.mergeRemote(ctx); err != nil {
+		return fmt.Errorf("加载远程配置失败(%s %s): %w", rc.config.Provider, rc.config.Key, err)
+	}
+	return nil
+}
+
+// decode 将viper中的配置解析为结构体并校验
+func decode() (*types.Config, error) {
 	var config types.Config
 	if err := viper.Unmarshal(&config); err != nil {
 		return nil, err
 	}
 
+	if err := resolveSecrets(&config); err != nil {
+		return nil, fmt.Errorf("解析密钥失败:\n%w", err)
+	}
+
+	if err := Validate(&config); err != nil {
+		return nil, fmt.Errorf("配置校验失败:\n%w", err)
+	}
+
 	return &config, nil
 }
 
 func setDefaults() {
 	viper.SetDefault("log_level", "info") // 兼容保留
+	viper.SetDefault("dry_run", false)
+	viper.SetDefault("timezone", "")
 	viper.SetDefault("log.level", "info")
 	viper.SetDefault("log.file_path", "logs")
 	viper.SetDefault("log.max_size", 200)
 	viper.SetDefault("log.max_age", 30)
 	viper.SetDefault("log.max_backups", 7)
 	viper.SetDefault("log.compress", false)
+	viper.SetDefault("log.console_output", "stdout")
+	viper.SetDefault("log.syslog.enabled", false)
+	viper.SetDefault("log.syslog.tag", "okx-sentry")
+	viper.SetDefault("log.journald.enabled", false)
+	viper.SetDefault("log.journald.identifier", "okx-sentry")
+	viper.SetDefault("log.loki.enabled", false)
+	viper.SetDefault("log.loki.url", "http://localhost:3100/loki/api/v1/push")
+	viper.SetDefault("log.otlp.enabled", false)
+	viper.SetDefault("log.otlp.endpoint", "http://localhost:4318/v1/logs")
+	viper.SetDefault("log.otlp.service_name", "okx-market-sentry")
 	viper.SetDefault("redis.url", "localhost:6379")
 	viper.SetDefault("redis.password", "")
 	viper.SetDefault("redis.db", 0)
@@ -56,9 +132,82 @@ func setDefaults() {
 	viper.SetDefault("dingtalk.secret", "")
 	viper.SetDefault("pushplus.user_token", "")
 	viper.SetDefault("pushplus.to", "")
+	viper.SetDefault("feishu.webhook_url", "")
+	viper.SetDefault("feishu.secret", "")
+	viper.SetDefault("email.port", 587)
+	viper.SetDefault("email.security", "starttls")
+	viper.SetDefault("bark.server_url", "https://api.day.app")
+	viper.SetDefault("bark.level", "timeSensitive")
+	viper.SetDefault("bark.group", "OKX Sentry")
+	viper.SetDefault("webhook.signature_header", "X-Sentry-Signature")
+	viper.SetDefault("console.mode", "auto")
+	viper.SetDefault("console.no_emoji", false)
 	viper.SetDefault("alert.threshold", 3.0)
 	viper.SetDefault("alert.monitor_period", 5*time.Minute)
+	viper.SetDefault("alert.benchmark", "BTC-USDT")
+	viper.SetDefault("alert.correlation_lookback", time.Hour)
+	viper.SetDefault("alert.workers", 8)
+	viper.SetDefault("alert.batch.max_detail", 0)
+	viper.SetDefault("alert.batch.buckets", []float64{3, 5, 10})
+	viper.SetDefault("alert.trailing", 0)
+	viper.SetDefault("alert.acceleration", 0)
+	viper.SetDefault("alert.decision_log.enabled", false)
+	viper.SetDefault("alert.decision_log.file_path", "logs/decisions.log")
+	viper.SetDefault("alert.decision_log.near_ratio", 0.8)
 	viper.SetDefault("fetch.interval", time.Minute)
 	viper.SetDefault("network.proxy", "")
 	viper.SetDefault("network.timeout", 30*time.Second)
+	viper.SetDefault("network.chaos.enabled", false)
+	viper.SetDefault("server.enabled", false)
+	viper.SetDefault("server.port", 8080)
+	viper.SetDefault("server.auth_token", "")
+	viper.SetDefault("server.grpc_port", 0)
+	viper.SetDefault("server.dingtalk_callback.enabled", false)
+	viper.SetDefault("server.dingtalk_callback.admin_only", true)
+	viper.SetDefault("tracing.enabled", false)
+	viper.SetDefault("tracing.endpoint", "localhost:4318")
+	viper.SetDefault("tracing.insecure", true)
+	viper.SetDefault("tracing.service_name", "okx-market-sentry")
+	viper.SetDefault("tracing.sample_ratio", 1.0)
+	viper.SetDefault("ops_alert.enabled", true)
+	viper.SetDefault("ops_alert.check_interval", time.Minute)
+	viper.SetDefault("ops_alert.fetch_failure_streak", 3)
+	viper.SetDefault("ops_alert.notify_failure_streak", 3)
+	viper.SetDefault("ops_alert.memory_limit_mb", 512)
+	viper.SetDefault("ops_alert.repeat_interval", time.Hour)
+	viper.SetDefault("ops_alert.stall_grace", 2*time.Minute)
+	viper.SetDefault("remote.provider", "")
+	viper.SetDefault("remote.poll_interval", 30*time.Second)
+	viper.SetDefault("schedule.analysis", "")
+	viper.SetDefault("schedule.slo_report", "1 0 * * *")
+	viper.SetDefault("schedule.run_on_start", false)
+	viper.SetDefault("schedule.analysis_interval", 0)
+	viper.SetDefault("announcement.enabled", false)
+	viper.SetDefault("announcement.poll_interval", 5*time.Minute)
+	viper.SetDefault("announcement.types", []string{"maintenance", "new_listing", "delisting"})
+	viper.SetDefault("announcement.channel", "")
+	viper.SetDefault("calendar.enabled", false)
+	viper.SetDefault("calendar.refresh_interval", time.Hour)
+	viper.SetDefault("calendar.window", 15*time.Minute)
+	viper.SetDefault("calendar.min_impact", "high")
+	viper.SetDefault("calendar.threshold_multiplier", 1.0)
+	viper.SetDefault("shard.mode", "")
+	viper.SetDefault("shard.count", 1)
+	viper.SetDefault("shard.heartbeat", 10*time.Second)
+	viper.SetDefault("shard.virtual_nodes", 128)
+	viper.SetDefault("digest.enabled", false)
+	viper.SetDefault("digest.schedule", "0 * * * *")
+	viper.SetDefault("digest.window", time.Hour)
+	viper.SetDefault("digest.channel", "pushplus")
+	viper.SetDefault("digest.max_symbols", 0)
+	viper.SetDefault("subscription.enabled", false)
+	viper.SetDefault("subscription.max_per_user", 20)
+	viper.SetDefault("subscription.min_threshold", 1.0)
+	viper.SetDefault("subscription.default_period", 5*time.Minute)
+	viper.SetDefault("subscription.max_period", time.Hour)
+	viper.SetDefault("subscription.telegram.api_url", "https://api.telegram.org")
+	viper.SetDefault("shutdown.intake_timeout", 20*time.Second)
+	viper.SetDefault("shutdown.persist_timeout", 5*time.Second)
+	viper.SetDefault("shutdown.notify_timeout", 10*time.Second)
+	viper.SetDefault("shutdown.close_timeout", 5*time.Second)
 }
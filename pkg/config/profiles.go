@@ -0,0 +1,38 @@
+package config
+
+import (
+	"slices"
+	"time"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// DefaultProfileName 未配置预警配置组时，由 alert.threshold/monitor_period 生成的配置组名称
+const DefaultProfileName = "default"
+
+// AlertProfiles 返回生效的预警配置组，未配置 alert.profiles 时返回覆盖全部交易对的默认配置组
+func AlertProfiles(alert types.AlertConfig) []types.AlertProfile {
+	if len(alert.Profiles) > 0 {
+		return alert.Profiles
+	}
+	return []types.AlertProfile{{
+		Name:          DefaultProfileName,
+		Threshold:     alert.Threshold,
+		Trailing:      alert.Trailing,
+		Acceleration:  alert.Acceleration,
+		MonitorPeriod: alert.MonitorPeriod,
+	}}
+}
+
+// MonitorPeriodRange 返回各配置组和组合条件规则中最短和最长的监控周期
+// 最短周期决定分析频率，最长周期决定需要保留的历史数据
+func MonitorPeriodRange(alert types.AlertConfig) (shortest, longest time.Duration) {
+	periods := make([]time.Duration, 0, len(alert.Profiles)+len(alert.Rules)+1)
+	for _, profile := range AlertProfiles(alert) {
+		periods = append(periods, profile.MonitorPeriod)
+	}
+	for _, rule := range alert.Rules {
+		periods = append(periods, rule.Period)
+	}
+	return slices.Min(periods), slices.Max(periods)
+}
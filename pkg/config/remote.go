@@ -0,0 +1,144 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"okx-market-sentry/pkg/types"
+)
+
+// remoteClient 远程配置中心客户端，按key读取整份YAML配置，覆盖本地配置文件中的同名项
+type remoteClient struct {
+	config     types.RemoteConfig
+	httpClient *http.Client
+}
+
+func newRemoteClient(remoteConfig types.RemoteConfig) *remoteClient {
+	return &remoteClient{
+		config:     remoteConfig,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// remoteSettings 从viper中读取远程配置中心的连接信息，未配置时返回nil
+func remoteSettings() (*remoteClient, error) {
+	remoteConfig := types.RemoteConfig{
+		Provider:     viper.GetString("remote.provider"),
+		Endpoint:     viper.GetString("remote.endpoint"),
+		Key:          viper.GetString("remote.key"),
+		Token:        viper.GetString("remote.token"),
+		PollInterval: viper.GetDuration("remote.poll_interval"),
+	}
+	if remoteConfig.Provider == "" {
+		return nil, nil
+	}
+
+	var problems []error
+	expandEnv(reflect.ValueOf(&remoteConfig).Elem(), &problems)
+	if remoteConfig.Endpoint == "" || remoteConfig.Key == "" {
+		problems = append(problems, fmt.Errorf("remote: 启用远程配置时endpoint和key不能为空"))
+	}
+	if err := errors.Join(problems...); err != nil {
+		return nil, err
+	}
+	return newRemoteClient(remoteConfig), nil
+}
+
+// fetch 获取远程配置内容
+func (rc *remoteClient) fetch(ctx context.Context) ([]byte, error) {
+	switch rc.config.Provider {
+	case "consul":
+		return rc.fetchConsul(ctx)
+	case "etcd":
+		return rc.fetchEtcd(ctx)
+	default:
+		return nil, fmt.Errorf("不支持的远程配置中心: %s，可选 consul/etcd", rc.config.Provider)
+	}
+}
+
+// fetchConsul 通过Consul KV HTTP API读取
+func (rc *remoteClient) fetchConsul(ctx context.Context) ([]byte, error) {
+	apiURL := strings.TrimRight(rc.config.Endpoint, "/") + "/v1/kv/" + url.PathEscape(strings.TrimLeft(rc.config.Key, "/")) + "?raw"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if rc.config.Token != "" {
+		req.Header.Set("X-Consul-Token", rc.config.Token)
+	}
+	return rc.do(req)
+}
+
+// fetchEtcd 通过etcd v3 gRPC网关的JSON接口读取
+func (rc *remoteClient) fetchEtcd(ctx context.Context) ([]byte, error) {
+	body, _ := json.Marshal(map[string]string{
+		"key": base64.StdEncoding.EncodeToString([]byte(rc.config.Key)),
+	})
+	apiURL := strings.TrimRight(rc.config.Endpoint, "/") + "/v3/kv/range"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if rc.config.Token != "" {
+		req.Header.Set("Authorization", rc.config.Token)
+	}
+
+	data, err := rc.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var rangeResp struct {
+		Kvs []struct {
+			Value string `json:"value"`
+		} `json:"kvs"`
+	}
+	if err := json.Unmarshal(data, &rangeResp); err != nil {
+		return nil, fmt.Errorf("解析etcd响应失败: %v", err)
+	}
+	if len(rangeResp.Kvs) == 0 {
+		return nil, fmt.Errorf("etcd中不存在key: %s", rc.config.Key)
+	}
+	return base64.StdEncoding.DecodeString(rangeResp.Kvs[0].Value)
+}
+
+func (rc *remoteClient) do(req *http.Request) ([]byte, error) {
+	resp, err := rc.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求远程配置中心失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取远程配置失败: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("远程配置中心返回状态码 %d", resp.StatusCode)
+	}
+	return data, nil
+}
+
+// mergeRemote 拉取远程配置并合并到viper，远程中出现的配置项覆盖本地配置文件
+func (rc *remoteClient) mergeRemote(ctx context.Context) ([]byte, error) {
+	data, err := rc.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := viper.MergeConfig(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("解析远程配置失败: %v", err)
+	}
+	return data, nil
+}
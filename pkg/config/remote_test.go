@@ -0,0 +1,98 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"okx-market-sentry/pkg/types"
+)
+
+func TestRemoteFetchConsul(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/kv/okx-sentry/config.yaml" || !r.URL.Query().Has("raw") {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("X-Consul-Token") != "acl" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte("alert:\n  threshold: 5\n"))
+	}))
+	defer server.Close()
+
+	rc := newRemoteClient(types.RemoteConfig{Provider: "consul", Endpoint: server.URL + "/", Key: "/okx-sentry/config.yaml", Token: "acl"})
+	data, err := rc.fetch(context.Background())
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if string(data) != "alert:\n  threshold: 5\n" {
+		t.Errorf("got %q", data)
+	}
+
+	rc.config.Token = "wrong"
+	if _, err := rc.fetch(context.Background()); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("err = %v, want status 403", err)
+	}
+}
+
+func TestRemoteFetchEtcd(t *testing.T) {
+	const key = "okx-sentry/config.yaml"
+	const value = "alert:\n  threshold: 5\n"
+
+	tests := []struct {
+		name     string
+		response string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "读取成功",
+			response: `{"kvs":[{"key":"` + base64.StdEncoding.EncodeToString([]byte(key)) + `","value":"` + base64.StdEncoding.EncodeToString([]byte(value)) + `"}]}`,
+			want:     value,
+		},
+		{name: "key不存在", response: `{"header":{}}`, wantErr: "不存在key"},
+		{name: "响应不是JSON", response: `not json`, wantErr: "解析etcd响应失败"},
+		{name: "value不是base64", response: `{"kvs":[{"value":"@@@"}]}`, wantErr: "illegal base64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					Key string `json:"key"`
+				}
+				if r.Method != http.MethodPost || r.URL.Path != "/v3/kv/range" {
+					http.NotFound(w, r)
+					return
+				}
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Key != base64.StdEncoding.EncodeToString([]byte(key)) {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			rc := newRemoteClient(types.RemoteConfig{Provider: "etcd", Endpoint: server.URL, Key: key})
+			data, err := rc.fetch(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want contains %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("fetch: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("got %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// envPattern 仅匹配 ${VAR} 形式，避免误伤密码中出现的 $ 字符
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// fileSecret 从文件读取的密钥：file 非空时读取文件内容写入 target
+type fileSecret struct {
+	key    string
+	file   string
+	target *string
+}
+
+// resolveSecrets 展开配置值中的环境变量引用，并读取 *_file 指向的密钥文件（如 docker secrets）
+func resolveSecrets(cfg *types.Config) error {
+	var problems []error
+
+	expandEnv(reflect.ValueOf(cfg).Elem(), &problems)
+
+	fileSecrets := []fileSecret{
+		{"dingtalk.webhook_url_file", cfg.DingTalk.WebhookURLFile, &cfg.DingTalk.WebhookURL},
+		{"dingtalk.secret_file", cfg.DingTalk.SecretFile, &cfg.DingTalk.Secret},
+		{"pushplus.user_token_file", cfg.PushPlus.UserTokenFile, &cfg.PushPlus.UserToken},
+		{"feishu.webhook_url_file", cfg.Feishu.WebhookURLFile, &cfg.Feishu.WebhookURL},
+		{"feishu.secret_file", cfg.Feishu.SecretFile, &cfg.Feishu.Secret},
+		{"email.password_file", cfg.Email.PasswordFile, &cfg.Email.Password},
+		{"bark.device_key_file", cfg.Bark.DeviceKeyFile, &cfg.Bark.DeviceKey},
+		{"webhook.secret_file", cfg.Webhook.SecretFile, &cfg.Webhook.Secret},
+		{"redis.password_file", cfg.Redis.PasswordFile, &cfg.Redis.Password},
+		{"server.auth_token_file", cfg.Server.AuthTokenFile, &cfg.Server.AuthToken},
+		{"subscription.telegram.bot_token_file", cfg.Subscription.Telegram.BotTokenFile, &cfg.Subscription.Telegram.BotToken},
+		{"server.dingtalk_callback.app_secret_file", cfg.Server.DingTalkCallback.AppSecretFile, &cfg.Server.DingTalkCallback.AppSecret},
+	}
+	for i := range cfg.DingTalk.Targets {
+		target := &cfg.DingTalk.Targets[i]
+		fileSecrets = append(fileSecrets,
+			fileSecret{fmt.Sprintf("dingtalk.targets[%d].webhook_url_file", i), target.WebhookURLFile, &target.WebhookURL},
+			fileSecret{fmt.Sprintf("dingtalk.targets[%d].secret_file", i), target.SecretFile, &target.Secret})
+	}
+	for i := range cfg.PushPlus.Targets {
+		target := &cfg.PushPlus.Targets[i]
+		fileSecrets = append(fileSecrets,
+			fileSecret{fmt.Sprintf("pushplus.targets[%d].user_token_file", i), target.UserTokenFile, &target.UserToken})
+	}
+	for _, secret := range fileSecrets {
+		if secret.file == "" {
+			continue
+		}
+		content, err := os.ReadFile(secret.file)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: 读取密钥文件失败: %v", secret.key, err))
+			continue
+		}
+		*secret.target = strings.TrimSpace(string(content))
+	}
+
+	return errors.Join(problems...)
+}
+
+// expandEnv 递归展开结构体中所有字符串字段的环境变量引用
+func expandEnv(v reflect.Value, problems *[]error) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			expandEnv(v.Field(i), problems)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			expandEnv(v.Index(i), problems)
+		}
+	case reflect.String:
+		if !v.CanSet() || !strings.Contains(v.String(), "${") {
+			return
+		}
+		expanded := envPattern.ReplaceAllStringFunc(v.String(), func(ref string) string {
+			name := envPattern.FindStringSubmatch(ref)[1]
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				*problems = append(*problems, fmt.Errorf("环境变量 %s 未设置", name))
+			}
+			return value
+		})
+		v.SetString(expanded)
+	}
+}
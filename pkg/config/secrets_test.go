@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"okx-market-sentry/pkg/types"
+)
+
+func TestResolveSecrets(t *testing.T) {
+	t.Setenv("SENTRY_TEST_SECRET", "from-env")
+
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(secretFile, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		cfg     types.Config
+		get     func(cfg *types.Config) string // 读取被解析的字段
+		want    string
+		wantErr string
+	}{
+		{
+			name: "展开环境变量",
+			cfg:  types.Config{DingTalk: types.DingTalkConfig{Secret: "${SENTRY_TEST_SECRET}"}},
+			get:  func(cfg *types.Config) string { return cfg.DingTalk.Secret },
+			want: "from-env",
+		},
+		{
+			name:    "环境变量未设置",
+			cfg:     types.Config{DingTalk: types.DingTalkConfig{Secret: "${SENTRY_TEST_UNSET}"}},
+			wantErr: "SENTRY_TEST_UNSET",
+		},
+		{
+			name: "文件优先于配置值",
+			cfg:  types.Config{Redis: types.RedisConfig{Password: "inline", PasswordFile: secretFile}},
+			get:  func(cfg *types.Config) string { return cfg.Redis.Password },
+			want: "from-file",
+		},
+		{
+			name:    "文件不存在",
+			cfg:     types.Config{Server: types.ServerConfig{AuthTokenFile: filepath.Join(t.TempDir(), "missing")}},
+			wantErr: "server.auth_token_file",
+		},
+		{
+			name: "单独的$保持原样",
+			cfg:  types.Config{PushPlus: types.PushPlusConfig{UserToken: "pa$$word$HOME"}},
+			get:  func(cfg *types.Config) string { return cfg.PushPlus.UserToken },
+			want: "pa$$word$HOME",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			err := resolveSecrets(&cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want contains %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if got := tt.get(&cfg); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
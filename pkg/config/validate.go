@@ -0,0 +1,503 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap/zapcore"
+	"okx-market-sentry/pkg/types"
+)
+
+// Validate 校验配置中不可能成立的设置，一次性返回全部问题
+func Validate(cfg *types.Config) error {
+	var problems []error
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(cfg.Log.Level)); err != nil {
+		add("log.level: 无效的日志级别 %q，可选 debug/info/warn/error", cfg.Log.Level)
+	}
+	switch cfg.Log.ConsoleOutput {
+	case "stdout", "stderr":
+	default:
+		add("log.console_output: 无效的输出 %q，可选 stdout/stderr", cfg.Log.ConsoleOutput)
+	}
+
+	if cfg.Log.Loki.Enabled && !isHTTPURL(cfg.Log.Loki.URL) {
+		add("log.loki.url: 不是有效的http(s)地址")
+	}
+	if cfg.Log.OTLP.Enabled && !isHTTPURL(cfg.Log.OTLP.Endpoint) {
+		add("log.otlp.endpoint: 不是有效的http(s)地址")
+	}
+
+	if cfg.Timezone != "" {
+		if _, err := time.LoadLocation(cfg.Timezone); err != nil {
+			add("timezone: 无效的时区 %q，示例 Asia/Shanghai、UTC", cfg.Timezone)
+		}
+	}
+
+	// 预警：监控周期 ≥ 分析间隔 ≥ 获取间隔
+	fetchInterval := cfg.Fetch.Interval
+	if fetchInterval <= 0 {
+		fetchInterval = time.Minute
+	} else if fetchInterval < time.Second {
+		add("fetch.interval: %s 不能小于1s", fetchInterval)
+	}
+	analysisInterval := cfg.Schedule.AnalysisInterval
+	// checkPeriod 校验配置组/规则的窗口时长，未配置分析间隔时按K线时间对齐分析，需为整分钟且整除60分钟
+	checkPeriod := func(key string, period time.Duration) {
+		if period < fetchInterval {
+			add("%s: %s 短于数据获取间隔 %s，窗口内没有可对比的历史价格", key, period, fetchInterval)
+		} else if analysisInterval > 0 {
+			if period < analysisInterval {
+				add("%s: %s 短于分析间隔 schedule.analysis_interval %s", key, period, analysisInterval)
+			}
+		} else if period%time.Minute != 0 {
+			add("%s: %s 必须为整分钟，分析按K线时间对齐执行", key, period)
+		} else if minutes := int(period / time.Minute); cfg.Schedule.Analysis == "" && 60%minutes != 0 {
+			add("%s: %s 必须能整除60分钟（如 1m、5m、15m、1h），分析在整点内按周期对齐执行", key, period)
+		}
+	}
+	names := make(map[string]bool)
+	for i, profile := range AlertProfiles(cfg.Alert) {
+		prefix := "alert."
+		if len(cfg.Alert.Profiles) > 0 {
+			prefix = fmt.Sprintf("alert.profiles[%d].", i)
+			if profile.Name == "" {
+				add("%sname: 不能为空", prefix)
+			} else if names[profile.Name] {
+				add("%sname: 配置组名称 %q 重复", prefix, profile.Name)
+			}
+			names[profile.Name] = true
+		}
+
+		if profile.Threshold <= 0 {
+			add("%sthreshold: 必须大于0，当前为 %v", prefix, profile.Threshold)
+		}
+		if profile.Trailing < 0 {
+			add("%strailing: 不能为负数，当前为 %v", prefix, profile.Trailing)
+		}
+		if profile.Acceleration != 0 && profile.Acceleration <= 1 {
+			add("%sacceleration: 必须大于1（近段速度是前段的倍数），0为关闭，当前为 %v", prefix, profile.Acceleration)
+		}
+		checkPeriod(prefix+"monitor_period", profile.MonitorPeriod)
+		validateChannel(cfg, prefix+"channel", profile.Channel, add)
+		validateTemplate(cfg, prefix+"template", profile.Template, add)
+	}
+	// 组合条件规则，名称与配置组共用（冷却状态、暂停、通知分组均按名称区分）
+	for i, rule := range cfg.Alert.Rules {
+		prefix := fmt.Sprintf("alert.rules[%d].", i)
+		if rule.Name == "" {
+			add("%sname: 不能为空", prefix)
+		} else if names[rule.Name] || (len(cfg.Alert.Profiles) == 0 && rule.Name == DefaultProfileName) {
+			add("%sname: 名称 %q 与其他配置组或规则重复", prefix, rule.Name)
+		}
+		names[rule.Name] = true
+
+		checkPeriod(prefix+"period", rule.Period)
+		if rule.Cooldown < 0 {
+			add("%scooldown: 不能为负数", prefix)
+		}
+		if len(rule.Conditions) == 0 {
+			add("%sconditions: 至少需要一个条件", prefix)
+		}
+		for j, condition := range rule.Conditions {
+			switch condition.Metric {
+			case "change", "abs_change", "change_24h", "volume_ratio", "vwap_distance", "volume_24h":
+			default:
+				add("%sconditions[%d].metric: 无效的指标 %q，可选 change/abs_change/change_24h/volume_ratio/vwap_distance/volume_24h", prefix, j, condition.Metric)
+			}
+			switch condition.Op {
+			case ">", ">=", "<", "<=":
+			default:
+				add("%sconditions[%d].op: 无效的比较符 %q，可选 >、>=、<、<=", prefix, j, condition.Op)
+			}
+		}
+		validateChannel(cfg, prefix+"channel", rule.Channel, add)
+		validateTemplate(cfg, prefix+"template", rule.Template, add)
+	}
+	// benchmark 为空时不计算相关性
+	if cfg.Alert.Benchmark != "" && cfg.Alert.CorrelationLookback <= 0 {
+		add("alert.correlation_lookback: 必须大于0，当前为 %s", cfg.Alert.CorrelationLookback)
+	}
+	if cfg.Alert.Workers <= 0 {
+		add("alert.workers: 必须大于0，当前为 %d", cfg.Alert.Workers)
+	}
+	if cfg.Alert.Batch.MaxDetail < 0 {
+		add("alert.batch.max_detail: 不能为负数，当前为 %d", cfg.Alert.Batch.MaxDetail)
+	}
+	for i, edge := range cfg.Alert.Batch.Buckets {
+		if edge <= 0 || (i > 0 && edge <= cfg.Alert.Batch.Buckets[i-1]) {
+			add("alert.batch.buckets: 分档边界需为递增的正数，当前为 %v", cfg.Alert.Batch.Buckets)
+			break
+		}
+	}
+	if cfg.Alert.DecisionLog.Enabled {
+		if cfg.Alert.DecisionLog.FilePath == "" {
+			add("alert.decision_log.file_path: 启用决策日志时不能为空")
+		}
+		if cfg.Alert.DecisionLog.NearRatio <= 0 || cfg.Alert.DecisionLog.NearRatio > 1 {
+			add("alert.decision_log.near_ratio: 必须在 (0, 1] 之间，当前为 %v", cfg.Alert.DecisionLog.NearRatio)
+		}
+	}
+
+	// 通知
+	if cfg.DingTalk.WebhookURL != "" && !isHTTPURL(cfg.DingTalk.WebhookURL) {
+		add("dingtalk.webhook_url: 不是有效的http(s)地址")
+	}
+	dingTalkLabels := make(map[string]bool)
+	for i, target := range cfg.DingTalk.Targets {
+		prefix := fmt.Sprintf("dingtalk.targets[%d].", i)
+		validateTargetLabel(prefix, target.Label, dingTalkLabels, add)
+		if !isHTTPURL(target.WebhookURL) {
+			add("%swebhook_url: 不是有效的http(s)地址", prefix)
+		}
+	}
+	pushPlusLabels := make(map[string]bool)
+	for i, target := range cfg.PushPlus.Targets {
+		prefix := fmt.Sprintf("pushplus.targets[%d].", i)
+		validateTargetLabel(prefix, target.Label, pushPlusLabels, add)
+		if target.UserToken == "" {
+			add("%suser_token: 不能为空", prefix)
+		}
+	}
+	if cfg.Feishu.WebhookURL != "" && !isHTTPURL(cfg.Feishu.WebhookURL) {
+		add("feishu.webhook_url: 不是有效的http(s)地址")
+	}
+	if cfg.Email.Host != "" {
+		validateEmail(cfg.Email, add)
+	}
+	if cfg.Bark.DeviceKey != "" {
+		if !isHTTPURL(cfg.Bark.ServerURL) {
+			add("bark.server_url: 不是有效的http(s)地址")
+		}
+		switch cfg.Bark.Level {
+		case "active", "timeSensitive", "passive", "critical":
+		default:
+			add("bark.level: 无效的中断级别 %q，可选 active/timeSensitive/passive/critical", cfg.Bark.Level)
+		}
+	}
+	for i, url := range cfg.Webhook.URLs {
+		if !isHTTPURL(url) {
+			add("webhook.urls[%d]: 不是有效的http(s)地址", i)
+		}
+	}
+	if len(cfg.Webhook.URLs) > 0 && cfg.Webhook.SignatureHeader == "" {
+		add("webhook.signature_header: 不能为空")
+	}
+
+	switch cfg.Console.Mode {
+	case "auto", "pretty", "table", "json", "log":
+	default:
+		add("console.mode: 无效的输出模式 %q，可选 auto/pretty/table/json/log", cfg.Console.Mode)
+	}
+
+	// 网络
+	if cfg.Network.Proxy != "" {
+		if u, err := url.Parse(cfg.Network.Proxy); err != nil || u.Scheme == "" || u.Host == "" {
+			add("network.proxy: 无效的代理地址 %q，示例 http://127.0.0.1:7890", cfg.Network.Proxy)
+		}
+	}
+	if cfg.Network.Timeout < 0 {
+		add("network.timeout: 不能为负数")
+	}
+	if chaos := cfg.Network.Chaos; chaos.Enabled {
+		if chaos.Latency < 0 {
+			add("network.chaos.latency: 不能为负数")
+		}
+		rates := map[string]float64{"timeout_rate": chaos.TimeoutRate, "error_rate": chaos.ErrorRate, "malformed_rate": chaos.MalformedRate}
+		for _, key := range []string{"timeout_rate", "error_rate", "malformed_rate"} {
+			if rates[key] < 0 || rates[key] > 1 {
+				add("network.chaos.%s: 必须在0到1之间", key)
+			}
+		}
+		if chaos.TimeoutRate+chaos.ErrorRate+chaos.MalformedRate > 1 {
+			add("network.chaos: timeout_rate、error_rate、malformed_rate 之和不能超过1")
+		}
+	}
+
+	// HTTP API
+	if cfg.Server.Enabled && (cfg.Server.Port <= 0 || cfg.Server.Port > 65535) {
+		add("server.port: 端口 %d 超出范围 1-65535", cfg.Server.Port)
+	}
+	if cfg.Server.Enabled && (cfg.Server.GRPCPort < 0 || cfg.Server.GRPCPort > 65535) {
+		add("server.grpc_port: 端口 %d 超出范围 0-65535", cfg.Server.GRPCPort)
+	} else if cfg.Server.Enabled && cfg.Server.GRPCPort == cfg.Server.Port {
+		add("server.grpc_port: 不能与 server.port 相同")
+	}
+	if cfg.Server.Enabled && cfg.Server.DingTalkCallback.Enabled && cfg.Server.DingTalkCallback.AppSecret == "" {
+		add("server.dingtalk_callback.app_secret: 启用钉钉回调需配置AppSecret以校验请求签名")
+	}
+
+	// 链路追踪
+	if cfg.Tracing.Enabled {
+		if cfg.Tracing.Endpoint == "" {
+			add("tracing.endpoint: 启用链路追踪时不能为空")
+		}
+		if cfg.Tracing.SampleRatio < 0 || cfg.Tracing.SampleRatio > 1 {
+			add("tracing.sample_ratio: 必须在 [0, 1] 之间，当前为 %v", cfg.Tracing.SampleRatio)
+		}
+	}
+
+	// 系统自检
+	if cfg.OpsAlert.Enabled {
+		if cfg.OpsAlert.CheckInterval <= 0 {
+			add("ops_alert.check_interval: 必须大于0")
+		}
+		if cfg.OpsAlert.FetchFailureStreak < 0 || cfg.OpsAlert.NotifyFailureStreak < 0 || cfg.OpsAlert.MemoryLimitMB < 0 {
+			add("ops_alert: fetch_failure_streak/notify_failure_streak/memory_limit_mb 不能为负数")
+		}
+		if cfg.OpsAlert.RepeatInterval < 0 || cfg.OpsAlert.StallGrace < 0 {
+			add("ops_alert: repeat_interval/stall_grace 不能为负数")
+		}
+	}
+
+	// OKX公告
+	if cfg.Announcement.Enabled {
+		if cfg.Announcement.PollInterval < time.Minute {
+			add("announcement.poll_interval: 不能小于1m，当前为 %s", cfg.Announcement.PollInterval)
+		}
+		if len(cfg.Announcement.Types) == 0 {
+			add("announcement.types: 启用时至少需要一种公告分类")
+		}
+		for _, t := range cfg.Announcement.Types {
+			switch t {
+			case "maintenance", "new_listing", "delisting":
+			default:
+				add("announcement.types: 无效的公告分类 %q，可选 maintenance/new_listing/delisting", t)
+			}
+		}
+		validateChannel(cfg, "announcement.channel", cfg.Announcement.Channel, add)
+	}
+
+	// 经济日历
+	if cfg.Calendar.Enabled {
+		if cfg.Calendar.Source == "" {
+			add("calendar.source: 启用经济日历时不能为空")
+		}
+		if cfg.Calendar.RefreshInterval < time.Minute {
+			add("calendar.refresh_interval: 不能小于1m，当前为 %s", cfg.Calendar.RefreshInterval)
+		}
+		if cfg.Calendar.Window <= 0 {
+			add("calendar.window: 必须大于0")
+		}
+		switch cfg.Calendar.MinImpact {
+		case "high", "medium", "low":
+		default:
+			add("calendar.min_impact: 无效的影响程度 %q，可选 high/medium/low", cfg.Calendar.MinImpact)
+		}
+		if cfg.Calendar.ThresholdMultiplier < 1 {
+			add("calendar.threshold_multiplier: 不能小于1，当前为 %v", cfg.Calendar.ThresholdMultiplier)
+		}
+	}
+
+	// 多实例分片
+	switch cfg.Shard.Mode {
+	case "":
+	case "static":
+		if cfg.Shard.Count < 1 {
+			add("shard.count: 必须大于0，当前为 %d", cfg.Shard.Count)
+		}
+		if cfg.Shard.Index < -1 || cfg.Shard.Index >= cfg.Shard.Count {
+			add("shard.index: %d 需在0到count-1之间，或为-1取主机名末尾的序号", cfg.Shard.Index)
+		}
+	case "redis":
+		if cfg.Redis.URL == "" {
+			add("shard.mode: redis分片需配置 redis.url")
+		}
+		if cfg.Shard.Heartbeat < time.Second {
+			add("shard.heartbeat: 不能小于1s，当前为 %s", cfg.Shard.Heartbeat)
+		}
+		if cfg.Shard.VirtualNodes <= 0 {
+			add("shard.virtual_nodes: 必须大于0，当前为 %d", cfg.Shard.VirtualNodes)
+		}
+	default:
+		add("shard.mode: 无效的分片模式 %q，可选 static/redis，为空时不分片", cfg.Shard.Mode)
+	}
+
+	// 涨跌幅热力图摘要
+	if cfg.Digest.Enabled {
+		if _, err := cron.ParseStandard(cfg.Digest.Schedule); err != nil {
+			add("digest.schedule: 无效的cron表达式 %q: %v", cfg.Digest.Schedule, err)
+		}
+		if cfg.Digest.Window < time.Minute {
+			add("digest.window: 不能小于1m，当前为 %s", cfg.Digest.Window)
+		}
+		if cfg.Digest.MaxSymbols < 0 {
+			add("digest.max_symbols: 不能为负数，当前为 %d", cfg.Digest.MaxSymbols)
+		}
+		// 热力图为HTML网格，只有PushPlus和邮件能完整展示，控制台输出文本网格
+		switch name, _, _ := strings.Cut(cfg.Digest.Channel, ":"); name {
+		case "pushplus", "email", "console":
+			validateChannel(cfg, "digest.channel", cfg.Digest.Channel, add)
+		default:
+			add("digest.channel: 热力图摘要不支持渠道 %q，可选 pushplus/email/console 或“pushplus:标签”", cfg.Digest.Channel)
+		}
+	}
+
+	// 用户订阅
+	if cfg.Subscription.Enabled {
+		if cfg.Subscription.Telegram.BotToken == "" {
+			add("subscription.telegram.bot_token: 启用用户订阅时不能为空，订阅预警通过Telegram机器人推送")
+		}
+		if !isHTTPURL(cfg.Subscription.Telegram.APIURL) {
+			add("subscription.telegram.api_url: 不是有效的http(s)地址")
+		}
+		if cfg.Subscription.MaxPerUser <= 0 {
+			add("subscription.max_per_user: 必须大于0，当前为 %d", cfg.Subscription.MaxPerUser)
+		}
+		if cfg.Subscription.MinThreshold <= 0 {
+			add("subscription.min_threshold: 必须大于0，当前为 %v", cfg.Subscription.MinThreshold)
+		}
+		if cfg.Subscription.MaxPeriod < time.Minute {
+			add("subscription.max_period: 不能小于1m，当前为 %s", cfg.Subscription.MaxPeriod)
+		}
+		if cfg.Subscription.DefaultPeriod < time.Minute || cfg.Subscription.DefaultPeriod > cfg.Subscription.MaxPeriod {
+			add("subscription.default_period: %s 需在1m到max_period之间", cfg.Subscription.DefaultPeriod)
+		}
+		if cfg.Shard.Mode != "" {
+			add("subscription.enabled: 用户订阅需要全部交易对的行情，不能与 shard.mode 同时启用")
+		}
+	}
+
+	// 定时任务
+	if cfg.Schedule.Analysis != "" {
+		if _, err := cron.ParseStandard(cfg.Schedule.Analysis); err != nil {
+			add("schedule.analysis: 无效的cron表达式 %q: %v", cfg.Schedule.Analysis, err)
+		}
+	}
+	if analysisInterval != 0 {
+		switch {
+		case cfg.Schedule.Analysis != "":
+			add("schedule.analysis_interval: 不能与 schedule.analysis 同时配置")
+		case analysisInterval < fetchInterval:
+			add("schedule.analysis_interval: %s 短于数据获取间隔 fetch.interval %s，两次分析之间没有新数据", analysisInterval, fetchInterval)
+		case analysisInterval%time.Second != 0:
+			add("schedule.analysis_interval: %s 必须为整秒", analysisInterval)
+		}
+	}
+	if _, err := cron.ParseStandard(cfg.Schedule.SLOReport); err != nil {
+		add("schedule.slo_report: 无效的cron表达式 %q: %v", cfg.Schedule.SLOReport, err)
+	}
+
+	// 优雅关闭
+	if cfg.Shutdown.IntakeTimeout <= 0 || cfg.Shutdown.PersistTimeout <= 0 ||
+		cfg.Shutdown.NotifyTimeout <= 0 || cfg.Shutdown.CloseTimeout <= 0 {
+		add("shutdown: intake_timeout/persist_timeout/notify_timeout/close_timeout 必须大于0")
+	}
+
+	return errors.Join(problems...)
+}
+
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// validateTemplate 校验引用的正文模板存在，模板名称不区分大小写
+func validateTemplate(cfg *types.Config, key, name string, add func(format string, args ...interface{})) {
+	if name == "" {
+		return
+	}
+	if _, ok := cfg.Alert.Templates[strings.ToLower(name)]; !ok {
+		add("%s: 模板 %q 未在 alert.templates 中定义", key, name)
+	}
+}
+
+// validateEmail 校验SMTP服务器、发件人和收件人
+func validateEmail(email types.EmailConfig, add func(format string, args ...interface{})) {
+	if email.Port <= 0 || email.Port > 65535 {
+		add("email.port: 无效的端口 %d", email.Port)
+	}
+	switch email.Security {
+	case "starttls", "tls", "none":
+	default:
+		add("email.security: 无效的加密方式 %q，可选 starttls/tls/none", email.Security)
+	}
+	if _, err := mail.ParseAddress(email.From); err != nil {
+		add("email.from: 无效的发件人地址 %q", email.From)
+	}
+	if len(email.Recipients) == 0 {
+		add("email.recipients: 至少需要一个收件人")
+	}
+	for i, recipient := range email.Recipients {
+		if _, err := mail.ParseAddress(recipient.Address); err != nil {
+			add("email.recipients[%d].address: 无效的邮件地址 %q", i, recipient.Address)
+		}
+		if recipient.MinChange < 0 {
+			add("email.recipients[%d].min_change: 不能为负数", i)
+		}
+	}
+}
+
+// validateTargetLabel 校验多目标渠道的标签，标签用于“渠道:标签”路由，需非空且在渠道内唯一
+func validateTargetLabel(prefix, label string, seen map[string]bool, add func(format string, args ...interface{})) {
+	switch {
+	case label == "":
+		add("%slabel: 不能为空", prefix)
+	case strings.ContainsAny(label, ": "):
+		add("%slabel: %q 不能包含冒号或空格", prefix, label)
+	case seen[label]:
+		add("%slabel: 重复的标签 %q", prefix, label)
+	}
+	seen[label] = true
+}
+
+// validateChannel 校验按名称指定的通知渠道存在且已配置，为空时使用默认渠道
+// 钉钉和PushPlus可用“渠道:标签”指定 targets 中的某个目标
+func validateChannel(cfg *types.Config, key, channel string, add func(format string, args ...interface{})) {
+	if name, label, ok := strings.Cut(channel, ":"); ok {
+		var found bool
+		switch name {
+		case "dingtalk":
+			found = slices.ContainsFunc(cfg.DingTalk.Targets, func(t types.DingTalkTarget) bool { return t.Label == label })
+		case "pushplus":
+			found = slices.ContainsFunc(cfg.PushPlus.Targets, func(t types.PushPlusTarget) bool { return t.Label == label })
+		default:
+			add("%s: 渠道 %q 不支持指定目标，仅 dingtalk/pushplus 可使用“渠道:标签”", key, name)
+			return
+		}
+		if !found {
+			add("%s: %s.targets 中没有标签为 %q 的目标", key, name, label)
+		}
+		return
+	}
+
+	switch channel {
+	case "", "console":
+	case "dingtalk":
+		if cfg.DingTalk.WebhookURL == "" && len(cfg.DingTalk.Targets) == 0 {
+			add("%s: 使用钉钉通知需配置 dingtalk.webhook_url 或 dingtalk.targets", key)
+		}
+	case "pushplus":
+		if cfg.PushPlus.UserToken == "" && len(cfg.PushPlus.Targets) == 0 {
+			add("%s: 使用PushPlus通知需配置 pushplus.user_token 或 pushplus.targets", key)
+		}
+	case "feishu":
+		if cfg.Feishu.WebhookURL == "" {
+			add("%s: 使用飞书通知需配置 feishu.webhook_url", key)
+		}
+	case "email":
+		if cfg.Email.Host == "" || len(cfg.Email.Recipients) == 0 {
+			add("%s: 使用邮件通知需配置 email.host 和 email.recipients", key)
+		}
+	case "bark":
+		if cfg.Bark.DeviceKey == "" {
+			add("%s: 使用Bark通知需配置 bark.device_key", key)
+		}
+	case "webhook":
+		if len(cfg.Webhook.URLs) == 0 {
+			add("%s: 使用Webhook通知需配置 webhook.urls", key)
+		}
+	default:
+		add("%s: 无效的通知渠道 %q，可选 dingtalk/pushplus/feishu/email/bark/webhook/console", key, channel)
+	}
+}
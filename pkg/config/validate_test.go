@@ -0,0 +1,245 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"okx-market-sentry/pkg/types"
+)
+
+// validConfig 一份能通过校验的最小配置
+func validConfig() types.Config {
+	return types.Config{
+		Log:     types.LogConfig{Level: "info", ConsoleOutput: "stdout"},
+		Console: types.ConsoleConfig{Mode: "auto"},
+		Alert: types.AlertConfig{
+			Threshold:           3,
+			MonitorPeriod:       5 * time.Minute,
+			Benchmark:           "BTC-USDT",
+			CorrelationLookback: time.Hour,
+			Workers:             8,
+		},
+		Fetch:    types.FetchConfig{Interval: time.Minute},
+		Schedule: types.ScheduleConfig{SLOReport: "1 0 * * *"},
+		Shutdown: types.ShutdownConfig{
+			IntakeTimeout:  20 * time.Second,
+			PersistTimeout: 5 * time.Second,
+			NotifyTimeout:  10 * time.Second,
+			CloseTimeout:   5 * time.Second,
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *types.Config)
+		want   []string // 错误信息中应包含的配置项，为空表示校验通过
+	}{
+		{"默认配置", func(cfg *types.Config) {}, nil},
+		{"关闭相关性", func(cfg *types.Config) { cfg.Alert.Benchmark = ""; cfg.Alert.CorrelationLookback = 0 }, nil},
+		{"无效日志级别", func(cfg *types.Config) { cfg.Log.Level = "verbose" }, []string{"log.level"}},
+		{"监控周期短于获取间隔", func(cfg *types.Config) { cfg.Fetch.Interval = 10 * time.Minute }, []string{"alert.monitor_period"}},
+		{"监控周期不能整除60分钟", func(cfg *types.Config) { cfg.Alert.MonitorPeriod = 2 * time.Hour }, []string{"alert.monitor_period"}},
+		{"关闭阶段超时为0", func(cfg *types.Config) { cfg.Shutdown.PersistTimeout = 0 }, []string{"shutdown"}},
+		{"故障注入比例超出范围", func(cfg *types.Config) {
+			cfg.Network.Chaos = types.ChaosConfig{Enabled: true, Latency: -time.Second, ErrorRate: 1.5}
+		}, []string{"network.chaos.latency", "network.chaos.error_rate", "network.chaos:"}},
+		{"自定义分析时间", func(cfg *types.Config) {
+			cfg.Schedule.Analysis = "*/7 * * * *"
+			cfg.Alert.MonitorPeriod = 7 * time.Minute
+		}, nil},
+		{"无效cron表达式", func(cfg *types.Config) { cfg.Schedule.SLOReport = "every day" }, []string{"schedule.slo_report"}},
+		{"秒级获取和分析", func(cfg *types.Config) {
+			cfg.Fetch.Interval = 15 * time.Second
+			cfg.Schedule.AnalysisInterval = 30 * time.Second
+			cfg.Alert.MonitorPeriod = 90 * time.Second
+		}, nil},
+		{"无效公告分类", func(cfg *types.Config) {
+			cfg.Announcement = types.AnnouncementConfig{Enabled: true, PollInterval: 5 * time.Minute, Types: []string{"listing"}}
+		}, []string{"announcement.types"}},
+		{"组合规则无效", func(cfg *types.Config) {
+			cfg.Alert.Rules = []types.AlertRule{{Name: "default", Period: 5 * time.Minute, Conditions: []types.RuleCondition{{Metric: "rsi", Op: "=", Value: 1}}}}
+		}, []string{"alert.rules[0].name", "alert.rules[0].conditions[0].metric", "alert.rules[0].conditions[0].op"}},
+		{"引用未定义的模板", func(cfg *types.Config) {
+			cfg.Alert.Profiles = []types.AlertProfile{{Name: "stables", Threshold: 1, MonitorPeriod: 5 * time.Minute, Template: "Depeg"}}
+		}, []string{"alert.profiles[0].template"}},
+		{"经济日历缺少来源", func(cfg *types.Config) {
+			cfg.Calendar = types.CalendarConfig{Enabled: true, RefreshInterval: time.Hour, Window: 15 * time.Minute, MinImpact: "high", ThresholdMultiplier: 1}
+		}, []string{"calendar.source"}},
+		{"分析间隔短于获取间隔", func(cfg *types.Config) { cfg.Schedule.AnalysisInterval = 30 * time.Second }, []string{"schedule.analysis_interval"}},
+		{"监控周期短于分析间隔", func(cfg *types.Config) { cfg.Schedule.AnalysisInterval = 10 * time.Minute }, []string{"alert.monitor_period"}},
+		{"分析间隔与cron互斥", func(cfg *types.Config) {
+			cfg.Schedule.Analysis = "*/5 * * * *"
+			cfg.Schedule.AnalysisInterval = time.Minute
+		}, []string{"schedule.analysis_interval"}},
+		{
+			"配置组",
+			func(cfg *types.Config) {
+				cfg.Alert.Profiles = []types.AlertProfile{
+					{Name: "majors", Symbols: []string{"BTC-USDT"}, Threshold: 1, MonitorPeriod: 5 * time.Minute},
+					{Name: "alts", Threshold: 5, MonitorPeriod: 15 * time.Minute, Channel: "console"},
+				}
+			},
+			nil,
+		},
+		{
+			"配置组问题",
+			func(cfg *types.Config) {
+				cfg.Alert.Profiles = []types.AlertProfile{
+					{Name: "majors", Threshold: 1, MonitorPeriod: 5 * time.Minute, Channel: "dingtalk"},
+					{Name: "majors", Threshold: 0, MonitorPeriod: 5 * time.Minute},
+				}
+			},
+			[]string{"alert.profiles[0].channel", "alert.profiles[1].name", "alert.profiles[1].threshold"},
+		},
+		{
+			"飞书渠道",
+			func(cfg *types.Config) {
+				cfg.Feishu.WebhookURL = "feishu.example.com/hook"
+				cfg.Alert.Profiles = []types.AlertProfile{
+					{Name: "majors", Threshold: 1, MonitorPeriod: 5 * time.Minute, Channel: "feishu"},
+					{Name: "alts", Threshold: 5, MonitorPeriod: 5 * time.Minute, Channel: "lark"},
+				}
+			},
+			[]string{"feishu.webhook_url", "alert.profiles[1].channel"},
+		},
+		{
+			"邮件通知",
+			func(cfg *types.Config) {
+				cfg.Email = types.EmailConfig{Host: "smtp.example.com", Port: 587, Security: "starttls"}
+				cfg.Email.From = "OKX Sentry <sentry@example.com>"
+				cfg.Email.Recipients = []types.EmailRecipient{{Address: "ops@example.com", Symbols: []string{"BTC-USDT"}}}
+				cfg.Alert.Profiles = []types.AlertProfile{
+					{Name: "majors", Threshold: 1, MonitorPeriod: 5 * time.Minute, Channel: "email"},
+				}
+			},
+			nil,
+		},
+		{
+			"邮件配置问题",
+			func(cfg *types.Config) {
+				cfg.Email = types.EmailConfig{Host: "smtp.example.com", Port: 465, Security: "ssl"}
+				cfg.Email.Recipients = []types.EmailRecipient{{Address: "not-an-address", MinChange: -1}}
+			},
+			[]string{"email.security", "email.from", "email.recipients[0].address", "email.recipients[0].min_change"},
+		},
+		{"钉钉回调缺少AppSecret", func(cfg *types.Config) {
+			cfg.Server = types.ServerConfig{Enabled: true, Port: 8080, DingTalkCallback: types.DingTalkCallbackConfig{Enabled: true}}
+		}, []string{"server.dingtalk_callback.app_secret"}},
+		{"Bark配置问题", func(cfg *types.Config) {
+			cfg.Bark = types.BarkConfig{DeviceKey: "key", ServerURL: "api.day.app", Level: "urgent"}
+			cfg.Alert.Profiles = []types.AlertProfile{
+				{Name: "majors", Threshold: 1, MonitorPeriod: 5 * time.Minute, Channel: "bark"},
+			}
+		}, []string{"bark.server_url", "bark.level"}},
+		{"Webhook配置问题", func(cfg *types.Config) {
+			cfg.Webhook = types.WebhookConfig{URLs: []string{"https://example.com/hook", "example.com/hook"}, SignatureHeader: "X-Sentry-Signature"}
+		}, []string{"webhook.urls[1]"}},
+		{"未配置Webhook", func(cfg *types.Config) {
+			cfg.Alert.Profiles = []types.AlertProfile{
+				{Name: "majors", Threshold: 1, MonitorPeriod: 5 * time.Minute, Channel: "webhook"},
+			}
+		}, []string{"alert.profiles[0].channel"}},
+		{
+			"多个钉钉群和PushPlus账号",
+			func(cfg *types.Config) {
+				cfg.DingTalk.Targets = []types.DingTalkTarget{
+					{Label: "majors", WebhookURL: "https://oapi.dingtalk.com/robot/send?access_token=a", Symbols: []string{"BTC-USDT"}},
+					{Label: "alts", WebhookURL: "https://oapi.dingtalk.com/robot/send?access_token=b", Profiles: []string{"alts"}},
+				}
+				cfg.PushPlus.Targets = []types.PushPlusTarget{{Label: "alice", UserToken: "token"}}
+				cfg.Alert.Profiles = []types.AlertProfile{
+					{Name: "majors", Threshold: 1, MonitorPeriod: 5 * time.Minute, Channel: "dingtalk:majors"},
+					{Name: "alts", Threshold: 5, MonitorPeriod: 5 * time.Minute, Channel: "pushplus:alice"},
+				}
+			},
+			nil,
+		},
+		{
+			"多目标配置问题",
+			func(cfg *types.Config) {
+				cfg.DingTalk.Targets = []types.DingTalkTarget{
+					{Label: "ops", WebhookURL: "oapi.dingtalk.com/robot"},
+					{Label: "ops", WebhookURL: "https://oapi.dingtalk.com/robot/send"},
+				}
+				cfg.PushPlus.Targets = []types.PushPlusTarget{{UserToken: "token"}}
+				cfg.Alert.Profiles = []types.AlertProfile{
+					{Name: "majors", Threshold: 1, MonitorPeriod: 5 * time.Minute, Channel: "dingtalk:desk"},
+					{Name: "alts", Threshold: 5, MonitorPeriod: 5 * time.Minute, Channel: "feishu:ops"},
+				}
+			},
+			[]string{"dingtalk.targets[0].webhook_url", "dingtalk.targets[1].label", "pushplus.targets[0].label",
+				"alert.profiles[0].channel", "alert.profiles[1].channel"},
+		},
+		{"用户订阅配置问题", func(cfg *types.Config) {
+			cfg.Subscription = types.SubscriptionConfig{Enabled: true, MaxPerUser: 20, MinThreshold: 1,
+				DefaultPeriod: 2 * time.Hour, MaxPeriod: time.Hour, Telegram: types.TelegramBotConfig{APIURL: "https://api.telegram.org"}}
+		}, []string{"subscription.telegram.bot_token", "subscription.default_period"}},
+		{"静态分片", func(cfg *types.Config) { cfg.Shard = types.ShardConfig{Mode: "static", Index: 2, Count: 3} }, nil},
+		{"分片配置问题", func(cfg *types.Config) {
+			cfg.Shard = types.ShardConfig{Mode: "static", Index: 3, Count: 3}
+		}, []string{"shard.index"}},
+		{"Redis分片需配置Redis", func(cfg *types.Config) {
+			cfg.Shard = types.ShardConfig{Mode: "redis", Heartbeat: 10 * time.Second, VirtualNodes: 128}
+		}, []string{"shard.mode"}},
+		{"热力图摘要", func(cfg *types.Config) {
+			cfg.Email = types.EmailConfig{Host: "smtp.example.com", Port: 587, Security: "starttls", From: "sentry@example.com",
+				Recipients: []types.EmailRecipient{{Address: "ops@example.com"}}}
+			cfg.Digest = types.DigestConfig{Enabled: true, Schedule: "0 */4 * * *", Window: 4 * time.Hour, Channel: "email"}
+		}, nil},
+		{"热力图摘要配置问题", func(cfg *types.Config) {
+			cfg.Digest = types.DigestConfig{Enabled: true, Schedule: "every hour", Window: 30 * time.Second, Channel: "dingtalk", MaxSymbols: -1}
+		}, []string{"digest.schedule", "digest.window", "digest.max_symbols", "digest.channel"}},
+		{"热力图摘要渠道未配置", func(cfg *types.Config) {
+			cfg.Digest = types.DigestConfig{Enabled: true, Schedule: "0 * * * *", Window: time.Hour, Channel: "pushplus"}
+		}, []string{"digest.channel"}},
+		{"时区", func(cfg *types.Config) { cfg.Timezone = "Asia/Shanghai" }, nil},
+		{"无效时区", func(cfg *types.Config) { cfg.Timezone = "Mars/Olympus" }, []string{"timezone"}},
+		{"监控周期非整分钟", func(cfg *types.Config) { cfg.Alert.MonitorPeriod = 90 * time.Second }, []string{"alert.monitor_period"}},
+		{
+			"gRPC端口与HTTP端口相同",
+			func(cfg *types.Config) {
+				cfg.Server = types.ServerConfig{Enabled: true, Port: 8080, GRPCPort: 8080}
+			},
+			[]string{"server.grpc_port"},
+		},
+		{
+			"多个问题一次返回",
+			func(cfg *types.Config) {
+				cfg.Alert.Threshold = 0
+				cfg.Console.Mode = "fancy"
+				cfg.Network.Proxy = "127.0.0.1"
+				cfg.Server = types.ServerConfig{Enabled: true, Port: 70000}
+			},
+			[]string{"alert.threshold", "console.mode", "network.proxy", "server.port"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(&cfg)
+			err := Validate(&cfg)
+
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected err: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %v", tt.want)
+			}
+			if lines := strings.Count(err.Error(), "\n") + 1; lines != len(tt.want) {
+				t.Errorf("got %d problems, want %d:\n%v", lines, len(tt.want), err)
+			}
+			for _, key := range tt.want {
+				if !strings.Contains(err.Error(), key) {
+					t.Errorf("error missing %q:\n%v", key, err)
+				}
+			}
+		})
+	}
+}
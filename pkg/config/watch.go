@@ -0,0 +1,178 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"okx-market-sentry/pkg/types"
+)
+
+// Watcher 监听配置文件变化和SIGHUP信号，重新加载配置并通知订阅者
+type Watcher struct {
+	mutex       sync.Mutex
+	current     *types.Config
+	subscribers []func(oldConfig, newConfig *types.Config)
+}
+
+func NewWatcher(cfg *types.Config) *Watcher {
+	return &Watcher{
+		current: cfg,
+	}
+}
+
+// Subscribe 注册配置变更回调，回调在重新加载成功且配置确有变化时按注册顺序执行
+func (w *Watcher) Subscribe(fn func(oldConfig, newConfig *types.Config)) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Current 获取当前生效的配置
+func (w *Watcher) Current() *types.Config {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.current
+}
+
+// Start 开始监听，ctx取消时停止
+func (w *Watcher) Start(ctx context.Context) {
+	// 不使用viper.WatchConfig，它在自己的goroutine中读取配置，与Reload并发修改viper全局状态
+	var fileEvents <-chan fsnotify.Event
+	var fileErrors <-chan error
+	if file := viper.ConfigFileUsed(); file != "" {
+		watcher, err := watchFile(file)
+		if err != nil {
+			zap.L().Warn("⚠️ 监听配置文件失败，仅支持SIGHUP重新加载", zap.String("file", file), zap.Error(err))
+		} else {
+			defer watcher.Close()
+			fileEvents = watcher.Events
+			fileErrors = watcher.Errors
+			zap.L().Info("👁️ 已启用配置热加载", zap.String("file", file))
+		}
+	}
+
+	if rc, err := remoteSettings(); err == nil && rc != nil && rc.config.PollInterval > 0 {
+		go w.pollRemote(ctx, rc)
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+
+	// 编辑器保存时通常产生多个事件，合并为一次重新加载
+	debounce := time.NewTimer(0)
+	<-debounce.C
+	defer debounce.Stop()
+
+	file := filepath.Clean(viper.ConfigFileUsed())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigCh:
+			w.Reload("sighup")
+		case event := <-fileEvents:
+			if filepath.Clean(event.Name) == file && event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+				debounce.Reset(fileDebounce)
+			}
+		case err := <-fileErrors:
+			zap.L().Warn("⚠️ 监听配置文件出错", zap.Error(err))
+		case <-debounce.C:
+			w.Reload("file")
+		}
+	}
+}
+
+// fileDebounce 配置文件变更后等待的时间，合并编辑器保存产生的多个事件
+const fileDebounce = 200 * time.Millisecond
+
+// watchFile 监听配置文件所在目录，兼容编辑器先写临时文件再重命名的保存方式
+func watchFile(file string) (*fsnotify.Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(filepath.Dir(file)); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	return watcher, nil
+}
+
+// Reload 重新读取配置，校验失败时保留当前配置
+func (w *Watcher) Reload(source string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if err := viper.ReadInConfig(); err != nil {
+		var configFileNotFoundError viper.ConfigFileNotFoundError
+		if !errors.As(err, &configFileNotFoundError) {
+			zap.L().Error("❌ 重新读取配置失败，保留当前配置", zap.String("source", source), zap.Error(err))
+			return
+		}
+	}
+	if err := applyRemote(); err != nil {
+		zap.L().Error("❌ 重新读取配置失败，保留当前配置", zap.String("source", source), zap.Error(err))
+		return
+	}
+	newConfig, err := decode()
+	if err != nil {
+		zap.L().Error("❌ 新配置无效，保留当前配置", zap.String("source", source), zap.Error(err))
+		return
+	}
+	if reflect.DeepEqual(w.current, newConfig) {
+		return
+	}
+
+	oldConfig := w.current
+	w.current = newConfig
+	zap.L().Info("🔄 配置已重新加载", zap.String("source", source))
+
+	for _, fn := range w.subscribers {
+		fn(oldConfig, newConfig)
+	}
+}
+
+// pollRemote 定期轮询远程配置，内容变化时重新加载
+func (w *Watcher) pollRemote(ctx context.Context, rc *remoteClient) {
+	zap.L().Info("👁️ 已启用远程配置监听",
+		zap.String("provider", rc.config.Provider),
+		zap.String("key", rc.config.Key),
+		zap.Duration("interval", rc.config.PollInterval))
+
+	ticker := time.NewTicker(rc.config.PollInterval)
+	defer ticker.Stop()
+
+	var last []byte
+	for {
+		fetchCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		data, err := rc.fetch(fetchCtx)
+		cancel()
+
+		if err != nil {
+			zap.L().Warn("⚠️ 轮询远程配置失败", zap.Error(err))
+		} else {
+			if last != nil && !bytes.Equal(data, last) {
+				w.Reload("remote")
+			}
+			last = data
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
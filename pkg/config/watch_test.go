@@ -0,0 +1,128 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"okx-market-sentry/pkg/types"
+)
+
+// loadTestConfig 从临时文件加载配置，测试结束时重置viper全局状态
+func loadTestConfig(t *testing.T, content string) (string, *types.Config) {
+	t.Helper()
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, content)
+	cfg, err := Load(Options{ConfigFile: path})
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	return path, cfg
+}
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// thresholdConfig 只设置预警阈值的配置文件
+func thresholdConfig(threshold string) string {
+	return "alert:\n  threshold: " + threshold + "\n"
+}
+
+func TestWatcherReload(t *testing.T) {
+	path, cfg := loadTestConfig(t, thresholdConfig("3"))
+	watcher := NewWatcher(cfg)
+
+	var changes [][2]float64
+	watcher.Subscribe(func(oldConfig, newConfig *types.Config) {
+		changes = append(changes, [2]float64{oldConfig.Alert.Threshold, newConfig.Alert.Threshold})
+	})
+
+	writeConfig(t, path, thresholdConfig("4"))
+	watcher.Reload("test")
+	if len(changes) != 1 || changes[0] != [2]float64{3, 4} {
+		t.Fatalf("changes = %v, want [[3 4]]", changes)
+	}
+
+	// 配置未变化时不通知
+	watcher.Reload("test")
+	if len(changes) != 1 {
+		t.Errorf("配置未变化时通知了订阅者: %v", changes)
+	}
+
+	// 新配置无效或无法解析时保留当前配置
+	for _, content := range []string{thresholdConfig("-1"), "alert: [\n"} {
+		writeConfig(t, path, content)
+		watcher.Reload("test")
+		if len(changes) != 1 || watcher.Current().Alert.Threshold != 4 {
+			t.Errorf("无效配置 %q 生效了: changes=%v current=%v", content, changes, watcher.Current().Alert.Threshold)
+		}
+	}
+}
+
+func TestWatcherStart(t *testing.T) {
+	path, cfg := loadTestConfig(t, thresholdConfig("3"))
+	watcher := NewWatcher(cfg)
+
+	reloaded := make(chan float64, 4)
+	watcher.Subscribe(func(_, newConfig *types.Config) {
+		reloaded <- newConfig.Alert.Threshold
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		watcher.Start(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	wait := func(want float64) {
+		t.Helper()
+		select {
+		case got := <-reloaded:
+			if got != want {
+				t.Errorf("threshold = %v, want %v", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("等待重新加载超时，want threshold %v", want)
+		}
+	}
+
+	// 监听在后台启动，多次写入直到收到文件变更
+	deadline := time.After(5 * time.Second)
+	for changed := false; !changed; {
+		writeConfig(t, path, thresholdConfig("5"))
+		select {
+		case got := <-reloaded:
+			if got != 5 {
+				t.Fatalf("threshold = %v, want 5", got)
+			}
+			changed = true
+		case <-time.After(fileDebounce * 3):
+		case <-deadline:
+			t.Fatal("修改配置文件后未重新加载")
+		}
+	}
+
+	// 收到文件变更说明信号处理已注册，环境变量覆盖的值在SIGHUP后生效
+	t.Setenv("ALERT.THRESHOLD", "6")
+	process, _ := os.FindProcess(os.Getpid())
+	if err := process.Signal(syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	wait(6)
+}
@@ -16,32 +16,62 @@ type Logger struct {
 	*zap.Logger
 }
 
+// atomicLevel 全局日志级别，支持运行时调整
+var atomicLevel = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+
 // InitLogger 初始化zap日志器
 func InitLogger(config types.LogConfig) {
-	// 从配置文件中解析日志级别
-	var logMode = new(zapcore.Level)
-	if err := logMode.UnmarshalText([]byte(config.Level)); err != nil {
-		// 如果解析失败，使用默认的info级别
-		*logMode = zapcore.InfoLevel
-	}
+	// 从配置文件中解析日志级别，解析失败时使用默认的info级别
+	_ = SetLevel(config.Level)
 
 	// 创建编码器
 	encoder := getEncoder()
 	// 创建写入器
 	writeSyncer := getWriteSyncer(config)
 
-	// 创建核心
-	core := zapcore.NewTee(
-		// 日志写入文件 级别为配置文件中的级别
-		zapcore.NewCore(encoder, writeSyncer, *logMode),
-		// 日志写入控制台 zapcore.Lock(os.Stdout) 在写入日志前获取锁 保证日志不会被其他日志打断
-		zapcore.NewCore(getConsoleEncoder(), zapcore.Lock(os.Stdout), *logMode),
-	)
+	// 创建核心，底层对所有级别开放，由全局级别或模块级别过滤
+	cores := []zapcore.Core{
+		// 日志写入文件
+		zapcore.NewCore(encoder, writeSyncer, zapcore.DebugLevel),
+		// 日志写入控制台 zapcore.Lock 在写入日志前获取锁 保证日志不会被其他日志打断
+		zapcore.NewCore(getConsoleEncoder(), zapcore.Lock(consoleOutput(config)), zapcore.DebugLevel),
+	}
+	// 附加输出目标（syslog、journald、Loki、OTLP）
+	sinks, sinkErrs := buildSinks(config)
+	core := zapcore.NewTee(append(cores, sinks...)...)
+
+	modulesMutex.Lock()
+	baseCore = core
+	moduleLoggers = make(map[string]*zap.Logger)
+	modulesMutex.Unlock()
+	SetModuleLevels(config.Modules)
 
 	// AddCaller 将 Logger 配置为使用 zap 调用者的文件名、行号和函数名称注释每条消息
-	lg := zap.New(core, zap.AddCaller())
+	lg := zap.New(&levelCore{Core: core, level: atomicLevel}, zap.AddCaller())
 	// 替换全局的logger
 	zap.ReplaceGlobals(lg)
+
+	for _, err := range sinkErrs {
+		lg.Warn("⚠️ 附加日志输出目标不可用，已跳过", zap.Error(err))
+	}
+}
+
+// consoleOutput 控制台日志的输出目标，标准输出留给JSON格式的预警时写入标准错误
+func consoleOutput(config types.LogConfig) zapcore.WriteSyncer {
+	if config.ConsoleOutput == "stderr" {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// SetLevel 运行时调整日志级别
+func SetLevel(level string) error {
+	var l zapcore.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return err
+	}
+	atomicLevel.SetLevel(l)
+	return nil
 }
 
 // New 创建logger实例（兼容性保留）
@@ -66,6 +96,24 @@ func (l *Logger) Debug(v ...interface{}) {
 	zap.L().Sugar().Debug(v...)
 }
 
+// NewDecisionLogger 创建决策审计日志器，以JSON Lines格式单独写入文件，不输出到控制台
+func NewDecisionLogger(config types.DecisionLogConfig) *zap.Logger {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.TimeKey = "time"
+	encoderConfig.LevelKey = ""
+	encoderConfig.CallerKey = ""
+	encoderConfig.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+
+	writeSyncer := zapcore.AddSync(&lumberjack.Logger{
+		Filename:   config.FilePath,
+		MaxSize:    100,
+		MaxBackups: 7,
+	})
+
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), writeSyncer, zapcore.InfoLevel)
+	return zap.New(core)
+}
+
 // getEncoder 获取日志编码器
 func getEncoder() zapcore.Encoder {
 	// 编码器配置
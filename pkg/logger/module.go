@@ -0,0 +1,170 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// moduleLevel 模块日志级别，未单独设置时跟随全局级别
+type moduleLevel struct {
+	mutex   sync.RWMutex
+	level   zapcore.Level
+	inherit bool
+}
+
+func (ml *moduleLevel) Enabled(l zapcore.Level) bool {
+	ml.mutex.RLock()
+	defer ml.mutex.RUnlock()
+	if ml.inherit {
+		return atomicLevel.Enabled(l)
+	}
+	return ml.level.Enabled(l)
+}
+
+// levelCore 按级别过滤的core包装，底层core对所有级别开放
+type levelCore struct {
+	zapcore.Core
+	level zapcore.LevelEnabler
+}
+
+func (c *levelCore) Enabled(l zapcore.Level) bool {
+	return c.level.Enabled(l)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+func (c *levelCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.level.Enabled(entry.Level) {
+		return ce
+	}
+	return c.Core.Check(entry, ce)
+}
+
+var (
+	modulesMutex  sync.RWMutex
+	baseCore      zapcore.Core // InitLogger之前为nil
+	moduleLevels  = make(map[string]*moduleLevel)
+	moduleLoggers = make(map[string]*zap.Logger)
+	savedLevel    *zapcore.Level // ToggleDebug切换前的全局级别
+)
+
+// Named 获取模块日志器，级别可通过 log.modules.<module> 单独配置
+func Named(module string) *zap.Logger {
+	modulesMutex.RLock()
+	lg, ok := moduleLoggers[module]
+	modulesMutex.RUnlock()
+	if ok {
+		return lg
+	}
+
+	modulesMutex.Lock()
+	defer modulesMutex.Unlock()
+
+	if lg, ok := moduleLoggers[module]; ok {
+		return lg
+	}
+	if baseCore == nil {
+		return zap.L().Named(module) // 日志系统尚未初始化
+	}
+	lg = zap.New(&levelCore{Core: baseCore, level: levelOf(module)}, zap.AddCaller()).Named(module)
+	moduleLoggers[module] = lg
+	return lg
+}
+
+// levelOf 获取模块级别，不存在时创建为跟随全局，调用方需持有锁
+func levelOf(module string) *moduleLevel {
+	ml, ok := moduleLevels[module]
+	if !ok {
+		ml = &moduleLevel{inherit: true}
+		moduleLevels[module] = ml
+	}
+	return ml
+}
+
+// SetModuleLevel 运行时调整单个模块的日志级别，level为空时恢复跟随全局级别
+func SetModuleLevel(module, level string) error {
+	var l zapcore.Level
+	if level != "" {
+		if err := l.UnmarshalText([]byte(level)); err != nil {
+			return fmt.Errorf("无效的日志级别 %q", level)
+		}
+	}
+
+	modulesMutex.Lock()
+	ml := levelOf(module)
+	modulesMutex.Unlock()
+
+	ml.mutex.Lock()
+	defer ml.mutex.Unlock()
+	ml.level = l
+	ml.inherit = level == ""
+	return nil
+}
+
+// SetModuleLevels 按配置设置各模块级别，配置中移除的模块恢复跟随全局级别
+func SetModuleLevels(levels map[string]string) {
+	modulesMutex.RLock()
+	existing := make([]string, 0, len(moduleLevels))
+	for module := range moduleLevels {
+		existing = append(existing, module)
+	}
+	modulesMutex.RUnlock()
+
+	for _, module := range existing {
+		if _, ok := levels[module]; !ok {
+			_ = SetModuleLevel(module, "")
+		}
+	}
+	for module, level := range levels {
+		if err := SetModuleLevel(module, level); err != nil {
+			zap.L().Warn("模块日志级别配置无效", zap.String("module", module), zap.Error(err))
+		}
+	}
+}
+
+// Levels 获取全局和各模块当前的日志级别，跟随全局的模块不列出
+func Levels() map[string]string {
+	levels := map[string]string{
+		"global": atomicLevel.Level().String(),
+	}
+
+	modulesMutex.RLock()
+	defer modulesMutex.RUnlock()
+
+	modules := make([]string, 0, len(moduleLevels))
+	for module := range moduleLevels {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	for _, module := range modules {
+		ml := moduleLevels[module]
+		ml.mutex.RLock()
+		if !ml.inherit {
+			levels[module] = ml.level.String()
+		}
+		ml.mutex.RUnlock()
+	}
+	return levels
+}
+
+// ToggleDebug 在debug和原全局级别之间切换，返回切换后的级别，用于排查线上问题
+func ToggleDebug() string {
+	modulesMutex.Lock()
+	defer modulesMutex.Unlock()
+
+	if savedLevel != nil {
+		atomicLevel.SetLevel(*savedLevel)
+		savedLevel = nil
+	} else {
+		current := atomicLevel.Level()
+		savedLevel = &current
+		atomicLevel.SetLevel(zapcore.DebugLevel)
+	}
+	return atomicLevel.Level().String()
+}
@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSetModuleLevel(t *testing.T) {
+	t.Cleanup(func() {
+		_ = SetLevel("info")
+		SetModuleLevels(nil)
+	})
+	if err := SetLevel("info"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		module     string
+		level      string
+		wantErr    bool
+		wantLevels map[string]string
+	}{
+		{"设置模块级别", "fetcher", "debug", false, map[string]string{"global": "info", "fetcher": "debug"}},
+		{"无效级别不生效", "fetcher", "verbose", true, map[string]string{"global": "info", "fetcher": "debug"}},
+		{"再设置另一个模块", "notifier", "error", false, map[string]string{"global": "info", "fetcher": "debug", "notifier": "error"}},
+		{"恢复跟随全局", "fetcher", "", false, map[string]string{"global": "info", "notifier": "error"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SetModuleLevel(tt.module, tt.level)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := Levels(); !reflect.DeepEqual(got, tt.wantLevels) {
+				t.Errorf("Levels() = %v, want %v", got, tt.wantLevels)
+			}
+		})
+	}
+}
+
+func TestModuleLevelInheritsGlobal(t *testing.T) {
+	t.Cleanup(func() {
+		_ = SetLevel("info")
+		SetModuleLevels(nil)
+	})
+
+	SetModuleLevels(map[string]string{"analyzer": "warn"})
+	modulesMutex.Lock()
+	analyzer, scheduler := levelOf("analyzer"), levelOf("scheduler")
+	modulesMutex.Unlock()
+
+	_ = SetLevel("debug")
+	if analyzer.Enabled(zapcore.InfoLevel) {
+		t.Error("单独设置为warn的模块不应输出info")
+	}
+	if !scheduler.Enabled(zapcore.DebugLevel) {
+		t.Error("未单独设置的模块应跟随全局debug级别")
+	}
+
+	// 配置中移除后恢复跟随全局
+	SetModuleLevels(map[string]string{})
+	if !analyzer.Enabled(zapcore.DebugLevel) {
+		t.Error("从配置中移除的模块应恢复跟随全局级别")
+	}
+}
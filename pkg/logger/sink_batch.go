@@ -0,0 +1,268 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"okx-market-sentry/pkg/types"
+)
+
+const (
+	batchFlushInterval = 2 * time.Second
+	batchMaxSize       = 200   // 达到该条数立即推送
+	batchMaxBuffered   = 10000 // 推送端不可用时最多缓存的条数，超出后丢弃最旧的日志
+)
+
+// logRecord 待推送的日志
+type logRecord struct {
+	time   time.Time
+	level  zapcore.Level
+	logger string
+	line   string
+}
+
+// batchEncoder 将一批日志编码为推送请求体
+type batchEncoder interface {
+	Encode(records []logRecord) ([]byte, error)
+}
+
+// batchSink 批量推送日志到HTTP接收端（Loki、OTLP）
+type batchSink struct {
+	url        string
+	encoder    batchEncoder
+	httpClient *http.Client
+
+	mutex   sync.Mutex
+	records []logRecord
+	failing bool // 推送失败时只提示一次，恢复后再提示
+
+	flushCh chan struct{}
+}
+
+func newBatchSink(encoder batchEncoder, url string) *batchSink {
+	s := &batchSink{
+		url:        url,
+		encoder:    encoder,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		flushCh:    make(chan struct{}, 1),
+	}
+	go s.run()
+	return s
+}
+
+func (s *batchSink) Write(level zapcore.Level, entry zapcore.Entry, line []byte) error {
+	s.mutex.Lock()
+	s.records = append(s.records, logRecord{
+		time:   entry.Time,
+		level:  level,
+		logger: entry.LoggerName,
+		line:   string(bytes.TrimRight(line, "\n")),
+	})
+	if len(s.records) > batchMaxBuffered {
+		s.records = s.records[len(s.records)-batchMaxBuffered:]
+	}
+	full := len(s.records) >= batchMaxSize
+	s.mutex.Unlock()
+
+	if full {
+		select {
+		case s.flushCh <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+func (s *batchSink) Sync() error {
+	return s.flush()
+}
+
+func (s *batchSink) run() {
+	ticker := time.NewTicker(batchFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.flushCh:
+		}
+		_ = s.flush()
+	}
+}
+
+// flush 推送缓存的日志，失败时放回缓存等待下次推送
+func (s *batchSink) flush() error {
+	s.mutex.Lock()
+	records := s.records
+	s.records = nil
+	s.mutex.Unlock()
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	err := s.push(records)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err != nil {
+		s.records = append(records, s.records...)
+		if len(s.records) > batchMaxBuffered {
+			s.records = s.records[len(s.records)-batchMaxBuffered:]
+		}
+		// 不能写入zap，否则会递归产生新日志
+		if !s.failing {
+			fmt.Fprintf(os.Stderr, "日志推送失败(%s): %v\n", s.url, err)
+		}
+		s.failing = true
+		return err
+	}
+	if s.failing {
+		fmt.Fprintf(os.Stderr, "日志推送已恢复(%s)\n", s.url)
+	}
+	s.failing = false
+	return nil
+}
+
+func (s *batchSink) push(records []logRecord) error {
+	body, err := s.encoder.Encode(records)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.httpClient.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP状态码 %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// lokiEncoder 编码为Loki push API格式，按级别划分stream
+type lokiEncoder struct {
+	labels map[string]string
+}
+
+func newLokiEncoder(config types.LokiSinkConfig) *lokiEncoder {
+	labels := map[string]string{"job": "okx-market-sentry"}
+	for k, v := range config.Labels {
+		labels[k] = v
+	}
+	return &lokiEncoder{labels: labels}
+}
+
+func (e *lokiEncoder) Encode(records []logRecord) ([]byte, error) {
+	type stream struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	}
+
+	streams := make(map[zapcore.Level]*stream)
+	var order []zapcore.Level
+	for _, r := range records {
+		st, ok := streams[r.level]
+		if !ok {
+			labels := make(map[string]string, len(e.labels)+1)
+			for k, v := range e.labels {
+				labels[k] = v
+			}
+			labels["level"] = r.level.String()
+			st = &stream{Stream: labels}
+			streams[r.level] = st
+			order = append(order, r.level)
+		}
+		st.Values = append(st.Values, [2]string{strconv.FormatInt(r.time.UnixNano(), 10), r.line})
+	}
+
+	payload := struct {
+		Streams []*stream `json:"streams"`
+	}{}
+	for _, level := range order {
+		payload.Streams = append(payload.Streams, streams[level])
+	}
+	return json.Marshal(payload)
+}
+
+// otlpEncoder 编码为OTLP/HTTP JSON日志格式
+type otlpEncoder struct {
+	serviceName string
+}
+
+func newOTLPEncoder(config types.OTLPLogConfig) *otlpEncoder {
+	return &otlpEncoder{serviceName: config.ServiceName}
+}
+
+type otlpAttribute struct {
+	Key   string            `json:"key"`
+	Value map[string]string `json:"value"`
+}
+
+func (e *otlpEncoder) Encode(records []logRecord) ([]byte, error) {
+	type logRecordJSON struct {
+		TimeUnixNano   string            `json:"timeUnixNano"`
+		SeverityNumber int               `json:"severityNumber"`
+		SeverityText   string            `json:"severityText"`
+		Body           map[string]string `json:"body"`
+		Attributes     []otlpAttribute   `json:"attributes,omitempty"`
+	}
+
+	logRecords := make([]logRecordJSON, 0, len(records))
+	for _, r := range records {
+		record := logRecordJSON{
+			TimeUnixNano:   strconv.FormatInt(r.time.UnixNano(), 10),
+			SeverityNumber: otlpSeverity(r.level),
+			SeverityText:   r.level.CapitalString(),
+			Body:           map[string]string{"stringValue": r.line},
+		}
+		if r.logger != "" {
+			record.Attributes = []otlpAttribute{{Key: "logger", Value: map[string]string{"stringValue": r.logger}}}
+		}
+		logRecords = append(logRecords, record)
+	}
+
+	payload := map[string]interface{}{
+		"resourceLogs": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": []otlpAttribute{
+						{Key: "service.name", Value: map[string]string{"stringValue": e.serviceName}},
+					},
+				},
+				"scopeLogs": []interface{}{
+					map[string]interface{}{
+						"scope":      map[string]string{"name": "okx-market-sentry"},
+						"logRecords": logRecords,
+					},
+				},
+			},
+		},
+	}
+	return json.Marshal(payload)
+}
+
+// otlpSeverity 将zap级别映射为OTLP SeverityNumber
+func otlpSeverity(level zapcore.Level) int {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return 5
+	case level == zapcore.InfoLevel:
+		return 9
+	case level == zapcore.WarnLevel:
+		return 13
+	case level == zapcore.ErrorLevel:
+		return 17
+	default:
+		return 21
+	}
+}
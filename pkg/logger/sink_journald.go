@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+
+	"go.uber.org/zap/zapcore"
+	"okx-market-sentry/pkg/types"
+)
+
+// journaldSocket systemd-journald原生协议的socket路径
+const journaldSocket = "/run/systemd/journal/socket"
+
+// journaldSink 通过原生协议写入journald，保留日志级别和调用位置
+type journaldSink struct {
+	conn       net.Conn
+	identifier string
+}
+
+func newJournaldSink(config types.JournaldSinkConfig) (sinkWriter, error) {
+	conn, err := net.Dial("unixgram", journaldSocket)
+	if err != nil {
+		return nil, fmt.Errorf("连接journald失败: %w", err)
+	}
+	return &journaldSink{conn: conn, identifier: config.Identifier}, nil
+}
+
+func (s *journaldSink) Write(level zapcore.Level, entry zapcore.Entry, line []byte) error {
+	var buf bytes.Buffer
+	writeJournaldField(&buf, "MESSAGE", bytes.TrimRight(line, "\n"))
+	writeJournaldField(&buf, "PRIORITY", []byte(strconv.Itoa(journaldPriority(level))))
+	writeJournaldField(&buf, "SYSLOG_IDENTIFIER", []byte(s.identifier))
+	if entry.LoggerName != "" {
+		writeJournaldField(&buf, "LOGGER", []byte(entry.LoggerName))
+	}
+	if entry.Caller.Defined {
+		writeJournaldField(&buf, "CODE_FILE", []byte(entry.Caller.File))
+		writeJournaldField(&buf, "CODE_LINE", []byte(strconv.Itoa(entry.Caller.Line)))
+	}
+	_, err := s.conn.Write(buf.Bytes())
+	return err
+}
+
+func (s *journaldSink) Sync() error {
+	return nil
+}
+
+// writeJournaldField 按原生协议写入字段，值含换行时使用长度前缀的二进制格式
+func writeJournaldField(buf *bytes.Buffer, key string, value []byte) {
+	buf.WriteString(key)
+	if !bytes.ContainsRune(value, '\n') {
+		buf.WriteByte('=')
+		buf.Write(value)
+		buf.WriteByte('\n')
+		return
+	}
+	buf.WriteByte('\n')
+	_ = binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.Write(value)
+	buf.WriteByte('\n')
+}
+
+// journaldPriority 将zap级别映射为syslog优先级
+func journaldPriority(level zapcore.Level) int {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return 7
+	case level == zapcore.InfoLevel:
+		return 6
+	case level == zapcore.WarnLevel:
+		return 4
+	case level == zapcore.ErrorLevel:
+		return 3
+	default:
+		return 2
+	}
+}
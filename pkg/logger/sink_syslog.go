@@ -0,0 +1,45 @@
+//go:build !windows
+
+package logger
+
+import (
+	"fmt"
+	"log/syslog"
+
+	"go.uber.org/zap/zapcore"
+	"okx-market-sentry/pkg/types"
+)
+
+// syslogSink 写入本地或远程syslog
+type syslogSink struct {
+	writer *syslog.Writer
+}
+
+func newSyslogSink(config types.SyslogSinkConfig) (sinkWriter, error) {
+	// network和address为空时连接本地syslog
+	writer, err := syslog.Dial(config.Network, config.Address, syslog.LOG_INFO|syslog.LOG_DAEMON, config.Tag)
+	if err != nil {
+		return nil, fmt.Errorf("连接syslog失败: %w", err)
+	}
+	return &syslogSink{writer: writer}, nil
+}
+
+func (s *syslogSink) Write(level zapcore.Level, _ zapcore.Entry, line []byte) error {
+	msg := string(line)
+	switch {
+	case level <= zapcore.DebugLevel:
+		return s.writer.Debug(msg)
+	case level == zapcore.InfoLevel:
+		return s.writer.Info(msg)
+	case level == zapcore.WarnLevel:
+		return s.writer.Warning(msg)
+	case level == zapcore.ErrorLevel:
+		return s.writer.Err(msg)
+	default:
+		return s.writer.Crit(msg)
+	}
+}
+
+func (s *syslogSink) Sync() error {
+	return nil
+}
@@ -0,0 +1,37 @@
+//go:build !windows
+
+package logger
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"okx-market-sentry/pkg/types"
+)
+
+func TestSyslogSink(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	log, _ := newSinkLogger(t, types.LogConfig{Syslog: types.SyslogSinkConfig{
+		Enabled: true, Network: "udp", Address: conn.LocalAddr().String(), Tag: "okx-test",
+	}})
+	log.Warn("获取行情超时")
+
+	buf := make([]byte, 4096)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// LOG_DAEMON(3)*8 + LOG_WARNING(4) = 28
+	msg := string(buf[:n])
+	if !strings.HasPrefix(msg, "<28>") || !strings.Contains(msg, "okx-test") || !strings.Contains(msg, "获取行情超时") {
+		t.Errorf("syslog消息 = %q", msg)
+	}
+}
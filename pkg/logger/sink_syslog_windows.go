@@ -0,0 +1,13 @@
+//go:build windows
+
+package logger
+
+import (
+	"errors"
+
+	"okx-market-sentry/pkg/types"
+)
+
+func newSyslogSink(config types.SyslogSinkConfig) (sinkWriter, error) {
+	return nil, errors.New("Windows不支持syslog输出")
+}
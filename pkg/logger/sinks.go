@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"okx-market-sentry/pkg/types"
+)
+
+// sinkWriter 附加日志输出目标，按级别写入已编码的日志行
+type sinkWriter interface {
+	Write(level zapcore.Level, entry zapcore.Entry, line []byte) error
+	Sync() error
+}
+
+// sinkCore 将日志编码为JSON后写入附加输出目标
+type sinkCore struct {
+	encoder zapcore.Encoder
+	out     sinkWriter
+}
+
+func newSinkCore(out sinkWriter) zapcore.Core {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.TimeKey = "time"
+	encoderConfig.EncodeTime = zapcore.RFC3339TimeEncoder
+	return &sinkCore{
+		encoder: zapcore.NewJSONEncoder(encoderConfig),
+		out:     out,
+	}
+}
+
+func (c *sinkCore) Enabled(zapcore.Level) bool {
+	return true // 级别由外层levelCore过滤
+}
+
+func (c *sinkCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &sinkCore{encoder: c.encoder.Clone(), out: c.out}
+	for _, field := range fields {
+		field.AddTo(clone.encoder)
+	}
+	return clone
+}
+
+func (c *sinkCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(entry, c)
+}
+
+func (c *sinkCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+	return c.out.Write(entry.Level, entry, buf.Bytes())
+}
+
+func (c *sinkCore) Sync() error {
+	return c.out.Sync()
+}
+
+// buildSinks 根据配置创建附加输出目标，创建失败的目标跳过并返回错误列表
+func buildSinks(config types.LogConfig) ([]zapcore.Core, []error) {
+	var cores []zapcore.Core
+	var errs []error
+
+	if config.Syslog.Enabled {
+		if w, err := newSyslogSink(config.Syslog); err != nil {
+			errs = append(errs, err)
+		} else {
+			cores = append(cores, newSinkCore(w))
+		}
+	}
+	if config.Journald.Enabled {
+		if w, err := newJournaldSink(config.Journald); err != nil {
+			errs = append(errs, err)
+		} else {
+			cores = append(cores, newSinkCore(w))
+		}
+	}
+	if config.Loki.Enabled {
+		cores = append(cores, newSinkCore(newBatchSink(newLokiEncoder(config.Loki), config.Loki.URL)))
+	}
+	if config.OTLP.Enabled {
+		cores = append(cores, newSinkCore(newBatchSink(newOTLPEncoder(config.OTLP), config.OTLP.Endpoint)))
+	}
+	return cores, errs
+}
@@ -0,0 +1,160 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"okx-market-sentry/pkg/types"
+)
+
+// pushReceiver 记录收到的推送请求体，按顺序返回statuses中的状态码，用完后返回204
+type pushReceiver struct {
+	mutex    sync.Mutex
+	statuses []int
+	bodies   [][]byte
+}
+
+func (p *pushReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	status := http.StatusNoContent
+	if len(p.statuses) > 0 {
+		status, p.statuses = p.statuses[0], p.statuses[1:]
+	}
+	if status < 300 {
+		p.bodies = append(p.bodies, body)
+	}
+	w.WriteHeader(status)
+}
+
+func (p *pushReceiver) received() [][]byte {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.bodies
+}
+
+// newSinkLogger 创建只写入附加输出目标的日志器
+func newSinkLogger(t *testing.T, config types.LogConfig) (*zap.Logger, zapcore.Core) {
+	t.Helper()
+	cores, errs := buildSinks(config)
+	if len(errs) != 0 || len(cores) != 1 {
+		t.Fatalf("buildSinks: %d cores, errs %v", len(cores), errs)
+	}
+	return zap.New(cores[0]).Named("fetcher"), cores[0]
+}
+
+func TestLokiSink(t *testing.T) {
+	receiver := &pushReceiver{statuses: []int{http.StatusServiceUnavailable}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	log, core := newSinkLogger(t, types.LogConfig{Loki: types.LokiSinkConfig{
+		Enabled: true, URL: server.URL, Labels: map[string]string{"env": "test"},
+	}})
+	log.Info("获取行情", zap.Int("symbols", 3))
+	log.Warn("获取行情超时")
+
+	// 推送失败时日志保留在缓存中，下次推送一并发出
+	if err := core.Sync(); err == nil {
+		t.Fatal("接收端返回503时Sync应返回错误")
+	}
+	log.Error("获取行情失败")
+	if err := core.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	bodies := receiver.received()
+	if len(bodies) != 1 {
+		t.Fatalf("收到%d次推送, want 1", len(bodies))
+	}
+	var payload struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(bodies[0], &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	// 按级别划分stream，每个stream带有固定标签和配置的附加标签
+	lines := make(map[string]string)
+	for _, stream := range payload.Streams {
+		if stream.Stream["job"] != "okx-market-sentry" || stream.Stream["env"] != "test" || len(stream.Values) != 1 {
+			t.Errorf("stream = %+v", stream)
+			continue
+		}
+		lines[stream.Stream["level"]] = stream.Values[0][1]
+	}
+	if len(lines) != 3 || !strings.Contains(lines["info"], `"symbols":3`) ||
+		!strings.Contains(lines["warn"], "获取行情超时") || !strings.Contains(lines["error"], `"logger":"fetcher"`) {
+		t.Errorf("lines = %v", lines)
+	}
+}
+
+func TestOTLPSink(t *testing.T) {
+	receiver := &pushReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	log, core := newSinkLogger(t, types.LogConfig{OTLP: types.OTLPLogConfig{
+		Enabled: true, Endpoint: server.URL, ServiceName: "sentry-test",
+	}})
+	log.Debug("调试")
+	log.Warn("告警")
+	if err := core.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	bodies := receiver.received()
+	if len(bodies) != 1 {
+		t.Fatalf("收到%d次推送, want 1", len(bodies))
+	}
+	var payload struct {
+		ResourceLogs []struct {
+			Resource struct {
+				Attributes []otlpAttribute `json:"attributes"`
+			} `json:"resource"`
+			ScopeLogs []struct {
+				LogRecords []struct {
+					SeverityNumber int               `json:"severityNumber"`
+					SeverityText   string            `json:"severityText"`
+					Body           map[string]string `json:"body"`
+					Attributes     []otlpAttribute   `json:"attributes"`
+				} `json:"logRecords"`
+			} `json:"scopeLogs"`
+		} `json:"resourceLogs"`
+	}
+	if err := json.Unmarshal(bodies[0], &payload); err != nil || len(payload.ResourceLogs) != 1 || len(payload.ResourceLogs[0].ScopeLogs) != 1 {
+		t.Fatalf("payload: %s", bodies[0])
+	}
+	resource := payload.ResourceLogs[0]
+	if attrs := resource.Resource.Attributes; len(attrs) != 1 || attrs[0].Value["stringValue"] != "sentry-test" {
+		t.Errorf("resource attributes = %v", attrs)
+	}
+
+	records := resource.ScopeLogs[0].LogRecords
+	if len(records) != 2 {
+		t.Fatalf("records = %+v", records)
+	}
+	for i, want := range []struct {
+		severity int
+		text     string
+		body     string
+	}{{5, "DEBUG", "调试"}, {13, "WARN", "告警"}} {
+		record := records[i]
+		if record.SeverityNumber != want.severity || record.SeverityText != want.text ||
+			!strings.Contains(record.Body["stringValue"], want.body) ||
+			len(record.Attributes) != 1 || record.Attributes[0].Value["stringValue"] != "fetcher" {
+			t.Errorf("record[%d] = %+v", i, record)
+		}
+	}
+}
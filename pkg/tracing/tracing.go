@@ -0,0 +1,61 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"okx-market-sentry/pkg/types"
+)
+
+// instrumentationName 本项目的tracer名称
+const instrumentationName = "okx-market-sentry"
+
+// Init 初始化OpenTelemetry链路追踪，返回关闭函数
+// 未启用时不注册exporter，全局tracer保持为空实现，埋点几乎没有开销
+func Init(config types.TracingConfig) (func(context.Context) error, error) {
+	if !config.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(config.Endpoint)}
+	if config.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(config.ServiceName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	zap.L().Info("✅ 已启用OpenTelemetry链路追踪",
+		zap.String("endpoint", config.Endpoint),
+		zap.Float64("sample_ratio", config.SampleRatio))
+
+	return provider.Shutdown, nil
+}
+
+// Tracer 获取项目使用的tracer
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
@@ -11,23 +11,231 @@ type PriceDataPoint struct {
 // AlertData 预警数据
 type AlertData struct {
 	Symbol        string        `json:"symbol"`
+	Name          string        `json:"name,omitempty"`      // 项目名称，如 Solana，未知时为空
+	TickSize      float64       `json:"tick_size,omitempty"` // 价格最小变动单位，通知按此精度显示价格，未知时为0
 	CurrentPrice  float64       `json:"current_price"`
 	PastPrice     float64       `json:"past_price"`
 	ChangePercent float64       `json:"change_percent"`
 	AlertTime     time.Time     `json:"alert_time"`
-	MonitorPeriod time.Duration `json:"monitor_period"` // 监控周期
+	MonitorPeriod time.Duration `json:"monitor_period"`       // 监控周期
+	PriceTime     time.Time     `json:"price_time"`           // 当前价格对应的行情获取时间
+	Profile       string        `json:"profile,omitempty"`    // 触发预警的配置组
+	Partial       bool          `json:"partial,omitempty"`    // 启动时数据不足完整监控周期，MonitorPeriod为实际覆盖的时长
+	Kind          string        `json:"kind,omitempty"`       // 预警类型，为空时为窗口首尾涨跌幅预警，见 AlertKind* 常量
+	Momentum      *Momentum     `json:"momentum,omitempty"`   // 窗口内的动量变化，数据不足时为空
+	Conditions    []string      `json:"conditions,omitempty"` // 规则预警满足的条件及实际数值
+	Style         *AlertStyle   `json:"style,omitempty"`      // 配置组/规则指定的展示样式，未指定时为空
+
+	Event    *CalendarEvent `json:"event,omitempty"`     // 预警时间附近的重要经济事件
+	Stats24h *Ticker24h     `json:"stats_24h,omitempty"` // 行情接口的24小时统计，尚未获取到时为空
+
+	Correlation *CorrelationData `json:"correlation,omitempty"` // 相对基准的相关性，数据不足时为空
+}
+
+// 窗口首尾对比之外的预警类型，PastPrice为对应的窗口极值价格，ChangePercent为相对极值的涨跌幅
+const (
+	AlertKindDrawdown = "drawdown" // 从窗口内高点回撤
+	AlertKindBounce   = "bounce"   // 从窗口内低点反弹
+
+	// 近1/3窗口的涨跌速度明显快于前2/3窗口，PastPrice为2/3处的价格，MonitorPeriod为近1/3窗口时长
+	AlertKindAcceleration = "acceleration"
+
+	// 组合条件规则触发，Profile为规则名称，Conditions为满足的条件
+	AlertKindRule = "rule"
+
+	// 用户订阅触发，Profile为订阅ID，只发送给订阅的用户
+	AlertKindSubscription = "subscription"
+)
+
+// AlertStyle 预警在各通知渠道中的展示样式，来自配置组或规则
+type AlertStyle struct {
+	Title string `json:"title,omitempty"` // 替换标题中的预警类型名称，如“脱锚预警”
+	Emoji string `json:"emoji,omitempty"` // 替换标题前按涨跌显示的📈/📉
+	Body  string `json:"-"`               // 正文模板（text/template），为空时使用各渠道默认格式
+}
+
+// Momentum 窗口内前2/3和近1/3两段的涨跌幅，用于区分新启动的行情和已经走完的旧行情
+type Momentum struct {
+	EarlyChange  float64       `json:"early_change"`    // 前段涨跌幅（百分比）
+	EarlyPeriod  time.Duration `json:"early_period"`    // 前段时长
+	RecentChange float64       `json:"recent_change"`   // 近段涨跌幅（百分比）
+	RecentPeriod time.Duration `json:"recent_period"`   // 近段时长
+	Accelerating bool          `json:"accelerating"`    // 近段与前段同向（或前段持平）且速度更快
+	Ratio        float64       `json:"ratio,omitempty"` // 近段速度/前段速度，前段持平时为0
+}
+
+// Ticker24h 行情接口返回的24小时统计
+type Ticker24h struct {
+	Open        float64 `json:"open"`
+	High        float64 `json:"high"`
+	Low         float64 `json:"low"`
+	Volume      float64 `json:"volume"`       // 成交量（交易货币）
+	QuoteVolume float64 `json:"quote_volume"` // 成交额（计价货币，即USDT）
+}
+
+// CalendarEvent 经济日历事件
+type CalendarEvent struct {
+	Title   string    `json:"title"`
+	Country string    `json:"country"` // 国家/货币，如 USD
+	Time    time.Time `json:"time"`
+	Impact  string    `json:"impact"` // 影响程度：high、medium、low
+}
+
+// CorrelationData 交易对相对基准的相关性与Beta
+type CorrelationData struct {
+	Symbol      string        `json:"symbol"`
+	Benchmark   string        `json:"benchmark"`
+	Correlation float64       `json:"correlation"`
+	Beta        float64       `json:"beta"`
+	Samples     int           `json:"samples"`  // 参与计算的收益率样本数
+	Lookback    time.Duration `json:"lookback"` // 回看周期
+}
+
+// CycleMetrics 单轮分析的运行指标
+type CycleMetrics struct {
+	Time     time.Time     `json:"time"`
+	Symbols  int           `json:"symbols"`  // 参与分析的交易对数量
+	Alerts   int           `json:"alerts"`   // 本轮触发的预警数量
+	Duration time.Duration `json:"duration"` // 本轮分析耗时
+
+	SlowestSymbol   string        `json:"slowest_symbol,omitempty"`   // 本轮分析最慢的交易对
+	SlowestDuration time.Duration `json:"slowest_duration,omitempty"` // 最慢交易对的分析耗时
+}
+
+// DailySLO 单日服务质量统计
+type DailySLO struct {
+	Date              string        `json:"date"`
+	NotifyCount       int           `json:"notify_count"` // 成功通知的预警数
+	NotifyP50         time.Duration `json:"notify_p50"`   // 行情时间到通知成功的延迟分位数
+	NotifyP90         time.Duration `json:"notify_p90"`
+	NotifyP99         time.Duration `json:"notify_p99"`
+	NotifyMax         time.Duration `json:"notify_max"`
+	FetchAttempts     int           `json:"fetch_attempts"`
+	FetchSuccesses    int           `json:"fetch_successes"`
+	FeedUptimePercent float64       `json:"feed_uptime_percent"` // 行情获取成功率
+}
+
+// OpsAlert 运维告警，用于通知系统自身的异常
+type OpsAlert struct {
+	Component string    `json:"component"` // 异常组件，如 fetcher、redis、notifier、memory
+	Recovered bool      `json:"recovered"` // 是否为恢复通知
+	Message   string    `json:"message"`
+	AlertTime time.Time `json:"alert_time"`
+}
+
+// Notice 市场资讯类通知，如交易所维护计划、上新/下架公告
+type Notice struct {
+	Source   string    `json:"source"`   // 来源，如 okx
+	Category string    `json:"category"` // 分类，如 maintenance、new_listing、delisting
+	Title    string    `json:"title"`
+	Message  string    `json:"message,omitempty"` // 补充说明，如维护时间段
+	URL      string    `json:"url,omitempty"`
+	Symbols  []string  `json:"symbols,omitempty"` // 涉及的被监控交易对
+	Time     time.Time `json:"time"`              // 公告发布时间
+}
+
+// Digest 涨跌幅热力图摘要，定期汇总各交易对在统计周期内的涨跌幅
+type Digest struct {
+	Window time.Duration `json:"window"` // 涨跌幅的统计周期
+	Time   time.Time     `json:"time"`   // 生成时间
+	Total  int           `json:"total"`  // 有完整统计周期数据的交易对数量，超过展示上限时大于len(Cells)
+	Up     int           `json:"up"`     // 上涨的交易对数量，包含未展示的
+	Down   int           `json:"down"`   // 下跌的交易对数量，包含未展示的
+	Cells  []DigestCell  `json:"cells"`  // 按涨跌幅从高到低排列
+}
+
+// DigestCell 热力图中的一个交易对
+type DigestCell struct {
+	Symbol        string  `json:"symbol"`
+	Name          string  `json:"name,omitempty"` // 项目名称，未知时为空
+	Price         float64 `json:"price"`
+	TickSize      float64 `json:"tick_size,omitempty"` // 价格最小变动单位，未知时为0
+	ChangePercent float64 `json:"change_percent"`
+}
+
+// SymbolState 单个交易对的当前分析状态
+type SymbolState struct {
+	Symbol        string           `json:"symbol"`
+	Name          string           `json:"name,omitempty"` // 项目名称，未知时为空
+	CurrentPrice  float64          `json:"current_price"`
+	PastPrice     float64          `json:"past_price,omitempty"`
+	ChangePercent float64          `json:"change_percent"`
+	HasWindow     bool             `json:"has_window"` // 是否已有完整监控周期的数据
+	UpdatedAt     time.Time        `json:"updated_at"`
+	MonitorPeriod time.Duration    `json:"monitor_period"`
+	Threshold     float64          `json:"threshold"`
+	Profile       string           `json:"profile,omitempty"` // 监控周期和阈值所属的配置组
+	LastAlertTime *time.Time       `json:"last_alert_time,omitempty"`
+	Alerting      bool             `json:"alerting"` // 最近已触发预警，仍处于冷却期
+	Stats24h      *Ticker24h       `json:"stats_24h,omitempty"`
+	Correlation   *CorrelationData `json:"correlation,omitempty"`
+}
+
+// MarketSnapshot 全部监控交易对的即时快照
+type MarketSnapshot struct {
+	GeneratedAt time.Time             `json:"generated_at"`
+	Count       int                   `json:"count"`
+	Paused      map[string]*time.Time `json:"paused,omitempty"` // 暂停的配置组，键为空字符串表示全部暂停
+	Symbols     []*SymbolState        `json:"symbols"`          // 按交易对名称排序
+}
+
+// WarmupProgress 单项计算的数据预热进度
+type WarmupProgress struct {
+	Required time.Duration `json:"required"` // 需要覆盖的时间跨度
+	Percent  float64       `json:"percent"`  // 完成百分比，最大100
+	Ready    bool          `json:"ready"`
+}
+
+// WarmupStatus 交易对数据预热状态
+type WarmupStatus struct {
+	Symbol     string                    `json:"symbol"`
+	Points     int                       `json:"points"`     // 已收集的数据点数
+	Covered    time.Duration             `json:"covered"`    // 已覆盖的时间跨度
+	Indicators map[string]WarmupProgress `json:"indicators"` // 按计算项区分的预热进度
+}
+
+// Kline K线数据
+type Kline struct {
+	OpenTime time.Time `json:"open_time"`
+	Open     float64   `json:"open"`
+	High     float64   `json:"high"`
+	Low      float64   `json:"low"`
+	Close    float64   `json:"close"`
+	Volume   float64   `json:"volume"`
 }
 
 // Config 配置结构
 type Config struct {
 	LogLevel string         `mapstructure:"log_level"` // 兼容保留
+	DryRun   bool           `mapstructure:"dry_run"`   // 演练模式，通知只记录渲染后的内容而不实际发送
+	Timezone string         `mapstructure:"timezone"`  // IANA时区，如 Asia/Shanghai，为空时使用服务器本地时区
 	Log      LogConfig      `mapstructure:"log"`
 	Redis    RedisConfig    `mapstructure:"redis"`
 	DingTalk DingTalkConfig `mapstructure:"dingtalk"`
 	PushPlus PushPlusConfig `mapstructure:"pushplus"`
+	Feishu   FeishuConfig   `mapstructure:"feishu"`
+	Email    EmailConfig    `mapstructure:"email"`
+	Bark     BarkConfig     `mapstructure:"bark"`
+	Webhook  WebhookConfig  `mapstructure:"webhook"`
+	Console  ConsoleConfig  `mapstructure:"console"`
 	Alert    AlertConfig    `mapstructure:"alert"`
 	Fetch    FetchConfig    `mapstructure:"fetch"`
 	Network  NetworkConfig  `mapstructure:"network"`
+	Server   ServerConfig   `mapstructure:"server"`
+	Tracing  TracingConfig  `mapstructure:"tracing"`
+	OpsAlert OpsAlertConfig `mapstructure:"ops_alert"`
+	Remote   RemoteConfig   `mapstructure:"remote"`
+	Shutdown ShutdownConfig `mapstructure:"shutdown"`
+	Schedule ScheduleConfig `mapstructure:"schedule"`
+
+	Announcement AnnouncementConfig `mapstructure:"announcement"`
+	Calendar     CalendarConfig     `mapstructure:"calendar"`
+	Subscription SubscriptionConfig `mapstructure:"subscription"`
+	Shard        ShardConfig        `mapstructure:"shard"`
+	Digest       DigestConfig       `mapstructure:"digest"`
+
+	// 交易对的项目名称，键为基础币种（如 SOL）或交易对（如 SOL-USDT），补充或覆盖内置的常见币种名称
+	SymbolNames map[string]string `mapstructure:"symbol_names"`
 }
 
 type LogConfig struct {
@@ -37,27 +245,208 @@ type LogConfig struct {
 	MaxAge     int    `mapstructure:"max_age"`     // 日志文件存放时间 单位：天
 	MaxBackups int    `mapstructure:"max_backups"` // 日志文件备份数量
 	Compress   bool   `mapstructure:"compress"`    // 日志文件压缩
+
+	ConsoleOutput string `mapstructure:"console_output"` // 控制台日志输出到 stdout 或 stderr
+
+	Modules map[string]string `mapstructure:"modules"` // 模块单独的日志级别，如 fetcher: debug
+
+	// 附加输出目标，与文件和控制台同时输出
+	Syslog   SyslogSinkConfig   `mapstructure:"syslog"`
+	Journald JournaldSinkConfig `mapstructure:"journald"`
+	Loki     LokiSinkConfig     `mapstructure:"loki"`
+	OTLP     OTLPLogConfig      `mapstructure:"otlp"`
 }
 
+type SyslogSinkConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Network string `mapstructure:"network"` // udp/tcp，与address同时为空时写入本地syslog
+	Address string `mapstructure:"address"` // 远程syslog地址，如 10.0.0.1:514
+	Tag     string `mapstructure:"tag"`
+}
+
+type JournaldSinkConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	Identifier string `mapstructure:"identifier"` // SYSLOG_IDENTIFIER
+}
+
+type LokiSinkConfig struct {
+	Enabled bool              `mapstructure:"enabled"`
+	URL     string            `mapstructure:"url"`    // 如 http://localhost:3100/loki/api/v1/push
+	Labels  map[string]string `mapstructure:"labels"` // 附加的stream标签
+}
+
+type OTLPLogConfig struct {
+	Enabled     bool   `mapstructure:"enabled"`
+	Endpoint    string `mapstructure:"endpoint"` // OTLP/HTTP日志接收地址，如 http://localhost:4318/v1/logs
+	ServiceName string `mapstructure:"service_name"`
+}
+
+// 密钥类配置均支持 ${ENV_VAR} 引用环境变量，以及 *_file 从文件读取（如 docker secrets）
+
 type RedisConfig struct {
-	URL      string `mapstructure:"url"`
-	Password string `mapstructure:"password"`
-	DB       int    `mapstructure:"db"`
+	URL          string `mapstructure:"url"`
+	Password     string `mapstructure:"password"`
+	PasswordFile string `mapstructure:"password_file"`
+	DB           int    `mapstructure:"db"`
 }
 
 type DingTalkConfig struct {
-	WebhookURL string `mapstructure:"webhook_url"`
-	Secret     string `mapstructure:"secret"`
+	WebhookURL     string `mapstructure:"webhook_url"`
+	WebhookURLFile string `mapstructure:"webhook_url_file"`
+	Secret         string `mapstructure:"secret"`
+	SecretFile     string `mapstructure:"secret_file"`
+
+	// 额外的钉钉群，与上面的默认群同时接收通知，可按交易对/配置组过滤
+	Targets []DingTalkTarget `mapstructure:"targets"`
+}
+
+// DingTalkTarget 带标签的钉钉群，可通过 dingtalk:标签 单独作为通知渠道
+type DingTalkTarget struct {
+	Label          string   `mapstructure:"label"`
+	WebhookURL     string   `mapstructure:"webhook_url"`
+	WebhookURLFile string   `mapstructure:"webhook_url_file"`
+	Secret         string   `mapstructure:"secret"`
+	SecretFile     string   `mapstructure:"secret_file"`
+	Symbols        []string `mapstructure:"symbols"`  // 只接收这些交易对的预警，为空时接收全部
+	Profiles       []string `mapstructure:"profiles"` // 只接收这些配置组/规则的预警，为空时接收全部
 }
 
 type PushPlusConfig struct {
-	UserToken string `mapstructure:"user_token"`
-	To        string `mapstructure:"to"` // 好友令牌，多人用逗号分隔
+	UserToken     string `mapstructure:"user_token"`
+	UserTokenFile string `mapstructure:"user_token_file"`
+	To            string `mapstructure:"to"` // 好友令牌，多人用逗号分隔
+
+	// 额外的PushPlus账号，与上面的默认账号同时接收通知，可按交易对/配置组过滤
+	Targets []PushPlusTarget `mapstructure:"targets"`
+}
+
+// PushPlusTarget 带标签的PushPlus账号，可通过 pushplus:标签 单独作为通知渠道
+type PushPlusTarget struct {
+	Label         string   `mapstructure:"label"`
+	UserToken     string   `mapstructure:"user_token"`
+	UserTokenFile string   `mapstructure:"user_token_file"`
+	To            string   `mapstructure:"to"`
+	Symbols       []string `mapstructure:"symbols"`  // 只接收这些交易对的预警，为空时接收全部
+	Profiles      []string `mapstructure:"profiles"` // 只接收这些配置组/规则的预警，为空时接收全部
+}
+
+type FeishuConfig struct {
+	WebhookURL     string `mapstructure:"webhook_url"`
+	WebhookURLFile string `mapstructure:"webhook_url_file"`
+	Secret         string `mapstructure:"secret"` // 签名校验密钥，机器人安全设置中开启签名校验后获得
+	SecretFile     string `mapstructure:"secret_file"`
+}
+
+type EmailConfig struct {
+	Host         string           `mapstructure:"host"`     // SMTP服务器，为空时不启用邮件通知
+	Port         int              `mapstructure:"port"`     // 通常 starttls 为587，tls 为465
+	Security     string           `mapstructure:"security"` // starttls/tls/none，none仅适用于本机或内网中继
+	Username     string           `mapstructure:"username"` // 为空时不认证
+	Password     string           `mapstructure:"password"`
+	PasswordFile string           `mapstructure:"password_file"`
+	From         string           `mapstructure:"from"` // 发件人，如 "OKX Sentry <sentry@example.com>"
+	Recipients   []EmailRecipient `mapstructure:"recipients"`
+}
+
+// EmailRecipient 邮件收件人，可只接收部分交易对、配置组或涨跌幅较大的预警；运维告警和资讯通知发送给全部收件人
+type EmailRecipient struct {
+	Address   string   `mapstructure:"address"`
+	Symbols   []string `mapstructure:"symbols"`    // 只接收这些交易对的预警，为空时不限
+	Profiles  []string `mapstructure:"profiles"`   // 只接收这些配置组或规则的预警，为空时不限
+	MinChange float64  `mapstructure:"min_change"` // 只接收涨跌幅绝对值不小于该值（百分比）的预警，0为不限
+}
+
+type BarkConfig struct {
+	ServerURL     string `mapstructure:"server_url"` // Bark服务地址，自建服务时修改
+	DeviceKey     string `mapstructure:"device_key"` // Bark App中显示的设备密钥，为空时不启用
+	DeviceKeyFile string `mapstructure:"device_key_file"`
+	Sound         string `mapstructure:"sound"` // 推送铃声，如 alarm、minuet，为空时使用默认铃声
+	Level         string `mapstructure:"level"` // 预警的中断级别: active/timeSensitive/passive/critical
+	Group         string `mapstructure:"group"` // 推送分组，通知中心按分组折叠
+}
+
+type WebhookConfig struct {
+	URLs            []string `mapstructure:"urls"`   // 接收预警JSON的地址，为空时不启用
+	Secret          string   `mapstructure:"secret"` // HMAC-SHA256签名密钥，为空时不签名
+	SecretFile      string   `mapstructure:"secret_file"`
+	SignatureHeader string   `mapstructure:"signature_header"` // 签名所在的请求头
+}
+
+type ConsoleConfig struct {
+	Mode    string `mapstructure:"mode"`     // 控制台输出模式: auto, pretty, table, json, log
+	NoEmoji bool   `mapstructure:"no_emoji"` // 纯文本输出，去掉emoji并使用ASCII边框，适合重定向到文件
 }
 
 type AlertConfig struct {
-	Threshold     float64       `mapstructure:"threshold"`
-	MonitorPeriod time.Duration `mapstructure:"monitor_period"` // 监控周期，用于价格对比
+	Threshold           float64       `mapstructure:"threshold"`
+	Trailing            float64       `mapstructure:"trailing"`             // 回撤/反弹预警阈值百分比，0为关闭
+	Acceleration        float64       `mapstructure:"acceleration"`         // 加速预警的速度倍数，0为关闭
+	MonitorPeriod       time.Duration `mapstructure:"monitor_period"`       // 监控周期，用于价格对比
+	Benchmark           string        `mapstructure:"benchmark"`            // 相关性/Beta计算的基准交易对，为空时不计算
+	CorrelationLookback time.Duration `mapstructure:"correlation_lookback"` // 相关性/Beta计算的回看周期
+	Workers             int           `mapstructure:"workers"`              // 并发分析交易对的协程数
+
+	DecisionLog DecisionLogConfig `mapstructure:"decision_log"`
+
+	// 多个独立的预警配置组，为空时以上面的 threshold/monitor_period 作为覆盖全部交易对的唯一配置组
+	Profiles []AlertProfile `mapstructure:"profiles"`
+
+	// 组合条件预警规则，与配置组同时生效
+	Rules []AlertRule `mapstructure:"rules"`
+
+	// 预警正文模板（text/template），配置组和规则通过 template 按名称引用
+	Templates map[string]string `mapstructure:"templates"`
+
+	Batch BatchConfig `mapstructure:"batch"`
+}
+
+// BatchConfig 批量预警的展示：大量交易对同时触发时只逐条列出幅度最大的前N个，其余按涨跌幅分档计数
+type BatchConfig struct {
+	MaxDetail int       `mapstructure:"max_detail"` // 逐条列出的预警数量上限，0为不限制（各渠道按默认数量展示）
+	Buckets   []float64 `mapstructure:"buckets"`    // 未列出预警的涨跌幅分档边界（百分比，升序），如 [3, 5, 10]
+}
+
+// AlertRule 组合条件预警规则，全部条件同时满足时触发，有独立的通知渠道和冷却期
+type AlertRule struct {
+	Name       string          `mapstructure:"name"`    // 不能与配置组重名
+	Symbols    []string        `mapstructure:"symbols"` // 为空时匹配全部交易对
+	Period     time.Duration   `mapstructure:"period"`  // change、volume_ratio 的计算窗口
+	Conditions []RuleCondition `mapstructure:"conditions"`
+	Channel    string          `mapstructure:"channel"`  // dingtalk/pushplus/feishu/email/bark/console 或 dingtalk:标签，为空时使用默认通知渠道
+	Cooldown   time.Duration   `mapstructure:"cooldown"` // 同一交易对两次预警的最短间隔，为0时等于period
+	Title      string          `mapstructure:"title"`    // 通知标题中的预警名称，为空时为“规则预警”
+	Emoji      string          `mapstructure:"emoji"`    // 标题前的表情，为空时按涨跌显示
+	Template   string          `mapstructure:"template"` // 正文模板名称，引用 alert.templates
+}
+
+// RuleCondition 规则条件：指标 比较符 数值
+type RuleCondition struct {
+	// change: 窗口涨跌幅%  abs_change: 窗口涨跌幅绝对值%  change_24h: 24h涨跌幅%
+	// volume_ratio: 窗口成交额相对24h平均水平的倍数  vwap_distance: 相对24h成交均价的偏离%  volume_24h: 24h成交额(USDT)
+	Metric string  `mapstructure:"metric"`
+	Op     string  `mapstructure:"op"` // >、>=、<、<=
+	Value  float64 `mapstructure:"value"`
+}
+
+// AlertProfile 预警配置组，各组有独立的交易对范围、监控周期、阈值、通知渠道和冷却状态
+type AlertProfile struct {
+	Name      string   `mapstructure:"name"`
+	Symbols   []string `mapstructure:"symbols"` // 为空时匹配全部交易对
+	Threshold float64  `mapstructure:"threshold"`
+	Trailing  float64  `mapstructure:"trailing"` // 价格从窗口内高点回撤或从低点反弹超过该百分比时预警，0为关闭
+	// 近1/3窗口的涨跌速度达到前2/3窗口的该倍数、且近1/3窗口涨跌幅不小于threshold/3时预警，0为关闭
+	Acceleration  float64       `mapstructure:"acceleration"`
+	MonitorPeriod time.Duration `mapstructure:"monitor_period"`
+	Channel       string        `mapstructure:"channel"`  // dingtalk/pushplus/feishu/email/bark/console 或 dingtalk:标签，为空时使用默认通知渠道
+	Title         string        `mapstructure:"title"`    // 通知标题中的预警名称，为空时按预警类型生成
+	Emoji         string        `mapstructure:"emoji"`    // 标题前的表情，为空时按涨跌显示
+	Template      string        `mapstructure:"template"` // 正文模板名称，引用 alert.templates
+}
+
+type DecisionLogConfig struct {
+	Enabled   bool    `mapstructure:"enabled"`
+	FilePath  string  `mapstructure:"file_path"`  // 决策日志文件路径
+	NearRatio float64 `mapstructure:"near_ratio"` // 涨跌幅达到阈值的该比例时记录，如0.8表示80%
 }
 
 type FetchConfig struct {
@@ -67,4 +456,145 @@ type FetchConfig struct {
 type NetworkConfig struct {
 	Proxy   string        `mapstructure:"proxy"`   // HTTP代理地址，如 http://127.0.0.1:7890
 	Timeout time.Duration `mapstructure:"timeout"` // 网络超时时间
+	Chaos   ChaosConfig   `mapstructure:"chaos"`   // 故障注入，仅用于测试和预发环境
+}
+
+// ChaosConfig 访问OKX时随机注入的故障，用于演练重试、超时和解析错误的处理
+// 三种故障的比例之和不超过1
+type ChaosConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Latency       time.Duration `mapstructure:"latency"`        // 每个请求附加0到该时长的随机延迟
+	TimeoutRate   float64       `mapstructure:"timeout_rate"`   // 请求挂起直到超时的比例
+	ErrorRate     float64       `mapstructure:"error_rate"`     // 返回HTTP 503的比例
+	MalformedRate float64       `mapstructure:"malformed_rate"` // 返回截断响应体的比例
+	Seed          int64         `mapstructure:"seed"`           // 随机种子，0为按时间生成，固定种子可复现故障序列
+}
+
+type ServerConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	Port          int    `mapstructure:"port"`
+	AuthToken     string `mapstructure:"auth_token"` // 接口鉴权令牌，为空时不鉴权
+	AuthTokenFile string `mapstructure:"auth_token_file"`
+	GRPCPort      int    `mapstructure:"grpc_port"` // gRPC接口监听端口，0为不启用，与HTTP API共用鉴权令牌
+
+	DingTalkCallback DingTalkCallbackConfig `mapstructure:"dingtalk_callback"`
+}
+
+// DingTalkCallbackConfig 钉钉机器人回调（POST /dingtalk/callback），群成员@机器人查询状态或静音交易对
+// 回调不使用auth_token，以机器人应用的AppSecret校验请求签名
+type DingTalkCallbackConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	AppSecret     string `mapstructure:"app_secret"`
+	AppSecretFile string `mapstructure:"app_secret_file"`
+	AdminOnly     bool   `mapstructure:"admin_only"` // 静音/取消静音只允许群管理员执行
+}
+
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	Endpoint    string  `mapstructure:"endpoint"`     // OTLP HTTP接收地址，如 localhost:4318
+	Insecure    bool    `mapstructure:"insecure"`     // 是否使用HTTP明文传输
+	ServiceName string  `mapstructure:"service_name"` // 上报的服务名
+	SampleRatio float64 `mapstructure:"sample_ratio"` // 采样比例，0~1
+}
+
+type OpsAlertConfig struct {
+	Enabled             bool          `mapstructure:"enabled"`
+	CheckInterval       time.Duration `mapstructure:"check_interval"`        // 自检间隔
+	FetchFailureStreak  int           `mapstructure:"fetch_failure_streak"`  // 连续获取失败次数阈值
+	NotifyFailureStreak int           `mapstructure:"notify_failure_streak"` // 连续通知失败次数阈值
+	MemoryLimitMB       int           `mapstructure:"memory_limit_mb"`       // 堆内存告警阈值，0为不检查
+	RepeatInterval      time.Duration `mapstructure:"repeat_interval"`       // 异常持续时重复告警的间隔
+	StallGrace          time.Duration `mapstructure:"stall_grace"`           // 核心循环超过预期间隔多久无心跳视为卡死，0为不检查
+}
+
+// AnnouncementConfig OKX系统维护和上新/下架公告监控
+type AnnouncementConfig struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	PollInterval time.Duration `mapstructure:"poll_interval"` // 轮询间隔
+	Types        []string      `mapstructure:"types"`         // 关注的公告分类：maintenance、new_listing、delisting
+	Channel      string        `mapstructure:"channel"`       // 通知渠道，为空时使用默认渠道
+}
+
+// CalendarConfig 经济日历，预警发生在重要事件前后时附加说明，并可临时提高阈值
+type CalendarConfig struct {
+	Enabled             bool          `mapstructure:"enabled"`
+	Source              string        `mapstructure:"source"`               // JSON日历地址（http/https）或本地文件路径
+	RefreshInterval     time.Duration `mapstructure:"refresh_interval"`     // 重新加载日历的间隔
+	Window              time.Duration `mapstructure:"window"`               // 事件前后该时长内的预警视为可能受事件影响
+	MinImpact           string        `mapstructure:"min_impact"`           // 关注的最低影响程度：high、medium、low
+	ThresholdMultiplier float64       `mapstructure:"threshold_multiplier"` // 事件窗口内的阈值倍数，1为不调整
+}
+
+// DigestConfig 涨跌幅热力图摘要，定期将各交易对的涨跌幅渲染为按颜色分级的网格，通过PushPlus或邮件发送
+type DigestConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	Schedule   string        `mapstructure:"schedule"`    // 发送时间的cron表达式（分 时 日 月 周），如 0 * * * * 为每小时整点
+	Window     time.Duration `mapstructure:"window"`      // 涨跌幅的统计周期，内存中至少保留该时长的价格
+	Channel    string        `mapstructure:"channel"`     // 通知渠道：pushplus、email、console，或“pushplus:标签”
+	MaxSymbols int           `mapstructure:"max_symbols"` // 最多展示的交易对数量，超出时只展示涨跌幅绝对值最大的，0为不限制
+}
+
+// ShardConfig 多实例分片：各实例只获取和分析自己负责的交易对
+type ShardConfig struct {
+	Mode         string        `mapstructure:"mode"`          // 为空时不分片；static 按实例序号取模；redis 实例在Redis中登记，按一致性哈希分配
+	Index        int           `mapstructure:"index"`         // static模式下本实例的序号，从0开始，-1为取主机名末尾的序号
+	Count        int           `mapstructure:"count"`         // static模式下的实例总数
+	InstanceID   string        `mapstructure:"instance_id"`   // redis模式下的实例标识，为空时使用 主机名:进程号
+	Heartbeat    time.Duration `mapstructure:"heartbeat"`     // redis模式下的登记心跳间隔，超过3个间隔未更新的实例视为下线
+	VirtualNodes int           `mapstructure:"virtual_nodes"` // redis模式下每个实例在哈希环上的虚拟节点数，越多分配越均匀
+}
+
+// SubscriptionConfig 用户价格订阅：用户通过Telegram机器人或API订阅交易对和阈值，只接收自己订阅的预警
+type SubscriptionConfig struct {
+	Enabled       bool              `mapstructure:"enabled"`
+	MaxPerUser    int               `mapstructure:"max_per_user"`   // 每个用户最多订阅的交易对数量
+	MinThreshold  float64           `mapstructure:"min_threshold"`  // 用户可设置的最小阈值%，避免过于频繁的推送
+	DefaultPeriod time.Duration     `mapstructure:"default_period"` // 订阅未指定周期时的监控周期
+	MaxPeriod     time.Duration     `mapstructure:"max_period"`     // 订阅可设置的最长监控周期，内存中至少保留该时长的价格
+	Telegram      TelegramBotConfig `mapstructure:"telegram"`
+}
+
+// TelegramBotConfig Telegram机器人，接收订阅命令并向用户推送预警
+type TelegramBotConfig struct {
+	BotToken     string `mapstructure:"bot_token"`
+	BotTokenFile string `mapstructure:"bot_token_file"`
+	APIURL       string `mapstructure:"api_url"` // Bot API地址，使用自建Bot API服务时修改
+}
+
+// Subscription 用户订阅的交易对价格预警，窗口涨跌幅绝对值达到阈值时推送给该用户
+type Subscription struct {
+	ID        string        `json:"id"`
+	UserID    string        `json:"user_id"` // Telegram chat_id
+	Symbol    string        `json:"symbol"`
+	Threshold float64       `json:"threshold"` // 涨跌幅阈值%
+	Period    time.Duration `json:"period"`    // 监控周期，同时作为冷却期
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// RemoteConfig 远程配置中心，key中存放整份YAML配置，覆盖本地配置文件中的同名项
+type RemoteConfig struct {
+	Provider     string        `mapstructure:"provider"`      // consul 或 etcd，为空时不启用
+	Endpoint     string        `mapstructure:"endpoint"`      // 如 http://127.0.0.1:8500 或 etcd网关 http://127.0.0.1:2379
+	Key          string        `mapstructure:"key"`           // 配置所在的key，如 okx-sentry/config.yaml
+	Token        string        `mapstructure:"token"`         // Consul ACL令牌或etcd认证令牌
+	PollInterval time.Duration `mapstructure:"poll_interval"` // 轮询变更的间隔，0为不监听
+}
+
+// ShutdownConfig 优雅关闭各阶段的超时时间，按顺序执行，某阶段超时后继续下一阶段
+type ShutdownConfig struct {
+	IntakeTimeout  time.Duration `mapstructure:"intake_timeout"`  // 停止行情获取和分析循环
+	PersistTimeout time.Duration `mapstructure:"persist_timeout"` // 等待尚未完成的Redis写入
+	NotifyTimeout  time.Duration `mapstructure:"notify_timeout"`  // 等待正在发送的通知
+	CloseTimeout   time.Duration `mapstructure:"close_timeout"`   // 关闭Redis连接、上报剩余链路数据
+}
+
+// ScheduleConfig 定时任务的cron表达式（分 时 日 月 周），按 timezone 配置的时区执行
+type ScheduleConfig struct {
+	Analysis   string `mapstructure:"analysis"`     // 价格分析，为空时按监控周期对齐到K线收盘，如5m对应 */5 * * * *
+	SLOReport  string `mapstructure:"slo_report"`   // 输出前一天的SLO报告
+	RunOnStart bool   `mapstructure:"run_on_start"` // 启动后首次获取行情即分析一轮，不等待第一个分析时间点
+
+	// AnalysisInterval 固定分析间隔，支持秒级（如30s），按整点对齐执行；与analysis互斥
+	// 需不短于数据获取间隔，且不长于最短的监控周期
+	AnalysisInterval time.Duration `mapstructure:"analysis_interval"`
 }